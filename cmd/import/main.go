@@ -0,0 +1,205 @@
+// Command import creates users in bulk from a CSV or NDJSON file and reports on every row.
+//
+// Usage:
+//
+//	import -file users.csv
+//	import -file - -format ndjson < users.ndjson
+//	import -file users.csv -batch 1000 -report report.csv
+//
+// CSV files need a header naming the email and name columns; NDJSON files hold one
+// {"email": ..., "name": ...} object per line. The format is told from the file's extension
+// unless -format is given. Rows that are invalid, repeat an email earlier in the file or
+// belong to an existing user are rejected and listed; the rest are inserted in batches.
+// Stopping the command keeps the batches already inserted.
+//
+// The database is configured like the server: configs/config.yaml and GRAPHQL_SERVICE_*
+// environment variables. Migrate the schema first.
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"text/tabwriter"
+
+	appUser "github.com/captain-corgi/go-graphql-example/internal/application/user"
+	domainEvents "github.com/captain-corgi/go-graphql-example/internal/domain/events"
+	"github.com/captain-corgi/go-graphql-example/internal/domain/user"
+	"github.com/captain-corgi/go-graphql-example/internal/infrastructure/config"
+	"github.com/captain-corgi/go-graphql-example/internal/infrastructure/database"
+	"github.com/captain-corgi/go-graphql-example/internal/infrastructure/persistence/sql"
+	"github.com/captain-corgi/go-graphql-example/internal/infrastructure/persistence/sqlite"
+	"github.com/captain-corgi/go-graphql-example/migrations"
+)
+
+func main() {
+	file := flag.String("file", "", `file to import, or "-" for standard input`)
+	format := flag.String("format", "", "csv or ndjson instead of telling the format from the file name")
+	batch := flag.Int("batch", appUser.DefaultImportBatchSize, "rows checked and inserted at a time")
+	reportPath := flag.String("report", "", "write the result of every row to this CSV file")
+	flag.Parse()
+
+	if *file == "" {
+		fmt.Fprintln(os.Stderr, "import: -file is required")
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	if err := run(ctx, *file, *format, *batch, *reportPath); err != nil {
+		fmt.Fprintf(os.Stderr, "import: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// run imports one file into the configured database
+func run(ctx context.Context, fileName, formatName string, batchSize int, reportPath string) error {
+	if batchSize <= 0 {
+		return fmt.Errorf("batch must be positive, got %d", batchSize)
+	}
+
+	format, err := importFormat(fileName, formatName)
+	if err != nil {
+		return err
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if cfg.Database.Driver == config.DatabaseDriverMemory {
+		return fmt.Errorf("the memory driver keeps nothing imported between runs")
+	}
+
+	schema, err := migrations.For(cfg.Database.Driver)
+	if err != nil {
+		return err
+	}
+
+	var in io.Reader = os.Stdin
+	if fileName != "-" {
+		f, err := os.Open(fileName)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		in = f
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn}))
+	dbManager, err := database.NewManager(cfg.Database, logger)
+	if err != nil {
+		return err
+	}
+	defer dbManager.Close()
+
+	if err := dbManager.MigrationManager.CheckSchemaVersion(ctx, schema); err != nil {
+		return err
+	}
+
+	var repo user.ImportRepository
+	if cfg.Database.Driver == config.DatabaseDriverSQLite {
+		repo = sqlite.NewUserRepository(dbManager.DB, dbManager.TxManager, logger).(user.ImportRepository)
+	} else {
+		repo = sql.NewUserRepository(dbManager.DB, dbManager.TxManager, logger).(user.ImportRepository)
+	}
+
+	// No handlers are registered here and nothing subscribes to userCreated in this process;
+	// Postgres records the events in the outbox for the server's relay to publish
+	dispatcher := domainEvents.NewDomainEventDispatcher(logger)
+	importer := appUser.NewImporter(repo, dispatcher, nil, batchSize, logger)
+
+	processed := 0
+	report, importErr := importer.Import(ctx, in, format, func(rows []appUser.ImportRowResultDTO, _ int64) {
+		processed += len(rows)
+		fmt.Fprintf(os.Stderr, "\r%d rows processed", processed)
+	})
+	if processed > 0 {
+		fmt.Fprintln(os.Stderr)
+	}
+	dispatcher.Wait()
+
+	// A report is kept even when the import stopped part way
+	if report != nil {
+		if reportPath != "" {
+			if err := writeReport(reportPath, report); err != nil {
+				return err
+			}
+		}
+		printRejected(report)
+		fmt.Printf("%s: %d rows, %d created, %d rejected\n", fileName, len(report.Rows), report.Created, report.Rejected)
+	}
+
+	return importErr
+}
+
+// importFormat returns the format named by -format, or the one the file name tells
+func importFormat(fileName, formatName string) (appUser.ImportFormat, error) {
+	switch strings.ToLower(formatName) {
+	case "csv":
+		return appUser.ImportFormatCSV, nil
+	case "ndjson", "jsonl":
+		return appUser.ImportFormatNDJSON, nil
+	case "":
+		format, err := appUser.DetectImportFormat(fileName, "")
+		if err != nil {
+			return "", fmt.Errorf("cannot tell the format of %s; pass -format csv or -format ndjson", fileName)
+		}
+		return format, nil
+	}
+	return "", fmt.Errorf("unknown format %q; use csv or ndjson", formatName)
+}
+
+// printRejected lists the rows that did not create a user
+func printRejected(report *appUser.ImportReportDTO) {
+	if report.Rejected == 0 {
+		return
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "LINE\tEMAIL\tSTATUS\tERROR")
+	for _, row := range report.Rows {
+		if row.Status == appUser.ImportRowCreated {
+			continue
+		}
+		message := ""
+		if row.Error != nil {
+			message = row.Error.Message
+		}
+		fmt.Fprintf(w, "%d\t%s\t%s\t%s\n", row.Line, row.Email, row.Status, message)
+	}
+	w.Flush()
+}
+
+// writeReport writes the result of every row as CSV
+func writeReport(path string, report *appUser.ImportReportDTO) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create report: %w", err)
+	}
+	defer f.Close()
+
+	w := csv.NewWriter(f)
+	w.Write([]string{"line", "email", "status", "user_id", "error_code", "error_message"})
+	for _, row := range report.Rows {
+		var code, message string
+		if row.Error != nil {
+			code, message = row.Error.Code, row.Error.Message
+		}
+		w.Write([]string{strconv.Itoa(row.Line), row.Email, string(row.Status), row.UserID, code, message})
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return fmt.Errorf("failed to write report: %w", err)
+	}
+	return f.Close()
+}
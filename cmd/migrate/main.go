@@ -0,0 +1,265 @@
+// Command migrate applies and inspects the database migrations embedded in the binary.
+//
+// Usage:
+//
+//	migrate up              apply every pending migration
+//	migrate down N          roll back the last N migrations
+//	migrate goto V          migrate up or down to version V
+//	migrate force V         record version V without running anything, clearing a dirty state
+//	migrate recover         mark the migration that left the database dirty as not applied, to retry it
+//	migrate status          show the current version and the applied and pending migrations
+//	migrate create NAME     write an empty migration pair to -dir
+//
+// The database is configured like the server: configs/config.yaml and
+// GRAPHQL_SERVICE_* environment variables.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io/fs"
+	"log/slog"
+	"os"
+	"strconv"
+	"text/tabwriter"
+	"time"
+
+	"github.com/captain-corgi/go-graphql-example/internal/infrastructure/config"
+	"github.com/captain-corgi/go-graphql-example/internal/infrastructure/database"
+	"github.com/captain-corgi/go-graphql-example/migrations"
+)
+
+func main() {
+	dir := flag.String("dir", "migrations", "directory create writes new migrations to")
+	flag.Usage = usage
+	flag.Parse()
+
+	if err := run(context.Background(), flag.Args(), *dir); err != nil {
+		fmt.Fprintf(os.Stderr, "migrate: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// usage prints the command line help
+func usage() {
+	fmt.Fprint(flag.CommandLine.Output(), `Usage: migrate [-dir DIR] COMMAND [ARG]
+
+Commands:
+  up              apply every pending migration
+  down N          roll back the last N migrations
+  goto V          migrate up or down to version V
+  force V         record version V without running anything, clearing a dirty state
+  recover         mark the migration that left the database dirty as not applied, to retry it
+  status          show the current version and the applied and pending migrations
+  create NAME     write an empty migration pair to DIR (default "migrations")
+`)
+}
+
+// run executes one command
+func run(ctx context.Context, args []string, dir string) error {
+	if len(args) == 0 {
+		flag.Usage()
+		return fmt.Errorf("no command given")
+	}
+
+	command, args := args[0], args[1:]
+
+	// create only writes files, so it needs no configuration or database
+	if command == "create" {
+		if len(args) != 1 {
+			return fmt.Errorf("usage: migrate create NAME")
+		}
+		up, down, err := database.CreateMigration(dir, args[0])
+		if err != nil {
+			return err
+		}
+		fmt.Println(up)
+		fmt.Println(down)
+		return nil
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if err := cfg.Database.Validate(); err != nil {
+		return fmt.Errorf("database config validation failed: %w", err)
+	}
+	if cfg.Database.Driver == config.DatabaseDriverMemory {
+		return fmt.Errorf("the memory driver has no schema to migrate")
+	}
+
+	schema, err := migrations.For(cfg.Database.Driver)
+	if err != nil {
+		return err
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn}))
+	db, err := database.NewConnection(cfg.Database, logger)
+	if err != nil {
+		return err
+	}
+	defer db.Close()
+
+	mm := database.NewMigrationManager(db, logger)
+
+	switch command {
+	case "up":
+		if err := expectArgs(args, 0); err != nil {
+			return err
+		}
+		if err := mm.RunMigrations(ctx, schema); err != nil {
+			return err
+		}
+		return printVersion(ctx, mm, schema)
+
+	case "down":
+		if err := expectArgs(args, 1); err != nil {
+			return err
+		}
+		steps, err := strconv.Atoi(args[0])
+		if err != nil || steps <= 0 {
+			return fmt.Errorf("down takes a positive number of migrations, got %q", args[0])
+		}
+		if err := mm.MigrateDown(ctx, schema, steps); err != nil {
+			return err
+		}
+		return printVersion(ctx, mm, schema)
+
+	case "goto":
+		if err := expectArgs(args, 1); err != nil {
+			return err
+		}
+		version, err := strconv.ParseUint(args[0], 10, 0)
+		if err != nil {
+			return fmt.Errorf("goto takes a migration version, got %q", args[0])
+		}
+		if err := mm.MigrateTo(ctx, schema, uint(version)); err != nil {
+			return err
+		}
+		return printVersion(ctx, mm, schema)
+
+	case "force":
+		if err := expectArgs(args, 1); err != nil {
+			return err
+		}
+		version, err := strconv.Atoi(args[0])
+		if err != nil || version < -1 {
+			return fmt.Errorf("force takes a migration version or -1, got %q", args[0])
+		}
+		if err := mm.ForceVersion(ctx, schema, version); err != nil {
+			return err
+		}
+		return printVersion(ctx, mm, schema)
+
+	case "recover":
+		if err := expectArgs(args, 0); err != nil {
+			return err
+		}
+		return recoverDirty(ctx, mm, schema)
+
+	case "status":
+		if err := expectArgs(args, 0); err != nil {
+			return err
+		}
+		return printStatus(ctx, mm, schema)
+
+	default:
+		flag.Usage()
+		return fmt.Errorf("unknown command %q", command)
+	}
+}
+
+// expectArgs checks that a command was given exactly n arguments
+func expectArgs(args []string, n int) error {
+	if len(args) != n {
+		return fmt.Errorf("expected %d argument(s), got %d", n, len(args))
+	}
+	return nil
+}
+
+// printVersion prints the schema version a command left the database at
+func printVersion(ctx context.Context, mm *database.MigrationManager, schema fs.FS) error {
+	version, dirty, err := mm.GetMigrationVersion(ctx, schema)
+	if err != nil {
+		return err
+	}
+
+	if dirty {
+		fmt.Printf("version %d (dirty)\n", version)
+	} else {
+		fmt.Printf("version %d\n", version)
+	}
+	return nil
+}
+
+// printStatus prints the current version followed by every migration and whether it is applied
+func printStatus(ctx context.Context, mm *database.MigrationManager, schema fs.FS) error {
+	status, err := mm.Status(ctx, schema)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case status.Dirty:
+		fmt.Printf("version %d (dirty)\n", status.Version)
+		failure, err := mm.LastFailure(ctx, status.Version)
+		if err != nil {
+			return err
+		}
+		printFailure(failure)
+		fmt.Printf("undo its partial changes and run: migrate recover, or finish it by hand and run: migrate force %d\n", status.Version)
+	default:
+		fmt.Printf("version %d, %d pending\n", status.Version, len(status.Pending()))
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATE")
+	for _, m := range status.Migrations {
+		state := "pending"
+		if m.Applied {
+			state = "applied"
+		}
+		if status.Dirty && m.Version == status.Version {
+			state = "dirty"
+		}
+		fmt.Fprintf(w, "%03d\t%s\t%s\n", m.Version, m.Name, state)
+	}
+	return w.Flush()
+}
+
+// recoverDirty reports the migration that left the database dirty and marks it as not applied
+func recoverDirty(ctx context.Context, mm *database.MigrationManager, schema fs.FS) error {
+	version, dirty, err := mm.GetMigrationVersion(ctx, schema)
+	if err != nil {
+		return err
+	}
+	if !dirty {
+		return fmt.Errorf("database is not dirty; nothing to recover")
+	}
+
+	failure, err := mm.LastFailure(ctx, version)
+	if err != nil {
+		return err
+	}
+	printFailure(failure)
+
+	previous, err := mm.Recover(ctx, schema)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("version %d; run: migrate up to retry migration %03d\n", previous, version)
+	return nil
+}
+
+// printFailure prints which migration failed, where, when and why
+func printFailure(failure *database.MigrationFailure) {
+	if failure == nil {
+		fmt.Println("the failed migration was interrupted before its failure could be recorded")
+		return
+	}
+
+	fmt.Printf("migration %03d_%s failed on %s at %s:\n  %s\n",
+		failure.Version, failure.Name, failure.Instance, failure.FailedAt.Format(time.RFC3339), failure.Error)
+}
@@ -0,0 +1,122 @@
+// Command seed fills the database with sample users from a named profile.
+//
+// Usage:
+//
+//	seed -list                 list the profiles and where they may run
+//	seed [-profile dev]        seed the example users and a few dozen fake ones
+//	seed -profile loadtest -count 50000
+//
+// Profiles are gated by the configured environment, and none may run in production.
+// Fake users are generated deterministically from -seed and users already present are
+// skipped, so running the same command again changes nothing.
+//
+// The database is configured like the server: configs/config.yaml and GRAPHQL_SERVICE_*
+// environment variables. Migrate the schema first.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/captain-corgi/go-graphql-example/internal/domain/user"
+	"github.com/captain-corgi/go-graphql-example/internal/infrastructure/config"
+	"github.com/captain-corgi/go-graphql-example/internal/infrastructure/database"
+	"github.com/captain-corgi/go-graphql-example/internal/infrastructure/persistence/sql"
+	"github.com/captain-corgi/go-graphql-example/internal/infrastructure/persistence/sqlite"
+	"github.com/captain-corgi/go-graphql-example/internal/infrastructure/seed"
+	"github.com/captain-corgi/go-graphql-example/migrations"
+)
+
+func main() {
+	profile := flag.String("profile", "dev", "profile to seed")
+	count := flag.Int("count", -1, "number of fake users to generate instead of the profile's default")
+	seedValue := flag.Uint64("seed", 1, "seed of the fake user generator")
+	span := flag.Duration("span", 365*24*time.Hour, "how far back fake users signed up")
+	list := flag.Bool("list", false, "list the profiles and exit")
+	flag.Parse()
+
+	if *list {
+		printProfiles()
+		return
+	}
+
+	if err := run(context.Background(), *profile, *count, *seedValue, *span); err != nil {
+		fmt.Fprintf(os.Stderr, "seed: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+// printProfiles lists every profile with its default size and environments
+func printProfiles() {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "PROFILE\tFAKE USERS\tENVIRONMENTS\tDESCRIPTION")
+	for _, p := range seed.Profiles() {
+		fmt.Fprintf(w, "%s\t%d\t%s\t%s\n", p.Name, p.Generated, strings.Join(p.Environments, ", "), p.Description)
+	}
+	w.Flush()
+}
+
+// run seeds one profile into the configured database
+func run(ctx context.Context, profileName string, count int, seedValue uint64, span time.Duration) error {
+	profile, err := seed.LookupProfile(profileName)
+	if err != nil {
+		return err
+	}
+	if span <= 0 {
+		return fmt.Errorf("span must be positive, got %s", span)
+	}
+
+	cfg, err := config.Load()
+	if err != nil {
+		return fmt.Errorf("failed to load configuration: %w", err)
+	}
+	if cfg.Database.Driver == config.DatabaseDriverMemory {
+		return fmt.Errorf("the memory driver keeps nothing to seed between runs")
+	}
+
+	// Refuse early, before connecting, when the environment does not allow the profile
+	if err := profile.CheckEnvironment(cfg.Environment); err != nil {
+		return err
+	}
+
+	schema, err := migrations.For(cfg.Database.Driver)
+	if err != nil {
+		return err
+	}
+
+	logger := slog.New(slog.NewTextHandler(os.Stderr, &slog.HandlerOptions{Level: slog.LevelWarn}))
+	dbManager, err := database.NewManager(cfg.Database, logger)
+	if err != nil {
+		return err
+	}
+	defer dbManager.Close()
+
+	if err := dbManager.MigrationManager.CheckSchemaVersion(ctx, schema); err != nil {
+		return err
+	}
+
+	var repo user.Repository
+	if cfg.Database.Driver == config.DatabaseDriverSQLite {
+		repo = sqlite.NewUserRepository(dbManager.DB, dbManager.TxManager, logger)
+	} else {
+		repo = sql.NewUserRepository(dbManager.DB, dbManager.TxManager, logger)
+	}
+
+	now := time.Now()
+	seeder := seed.NewSeeder(repo, cfg.Environment, seed.NewGenerator(seedValue, now, span), now, logger)
+
+	result, err := seeder.Seed(ctx, profile, count)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("profile %s: %d created, %d already present, %d skipped because their email is taken\n",
+		profile.Name, result.Created, result.Existing, result.EmailTaken)
+	return nil
+}
@@ -10,20 +10,117 @@ import (
 	"syscall"
 	"time"
 
+	"github.com/captain-corgi/go-graphql-example/internal/application/unitofwork"
 	"github.com/captain-corgi/go-graphql-example/internal/application/user"
+	domainEvents "github.com/captain-corgi/go-graphql-example/internal/domain/events"
+	domainUser "github.com/captain-corgi/go-graphql-example/internal/domain/user"
 	"github.com/captain-corgi/go-graphql-example/internal/infrastructure/config"
 	"github.com/captain-corgi/go-graphql-example/internal/infrastructure/database"
+	"github.com/captain-corgi/go-graphql-example/internal/infrastructure/outbox"
+	"github.com/captain-corgi/go-graphql-example/internal/infrastructure/persistence/cache"
+	"github.com/captain-corgi/go-graphql-example/internal/infrastructure/persistence/memory"
 	"github.com/captain-corgi/go-graphql-example/internal/infrastructure/persistence/sql"
+	"github.com/captain-corgi/go-graphql-example/internal/infrastructure/persistence/sqlite"
+	"github.com/captain-corgi/go-graphql-example/internal/infrastructure/pubsub"
 	"github.com/captain-corgi/go-graphql-example/internal/interfaces/graphql/resolver"
 	httpserver "github.com/captain-corgi/go-graphql-example/internal/interfaces/http"
+	"github.com/captain-corgi/go-graphql-example/migrations"
 )
 
 // Application represents the main application with all its dependencies
+// dbManager is nil when users are kept in memory, relay is nil unless users are kept in Postgres,
+// and userCache is nil unless cache.enabled is set
 type Application struct {
-	config    *config.Config
-	logger    *slog.Logger
+	config     *config.Config
+	logger     *slog.Logger
+	dbManager  *database.Manager
+	userCache  *cache.UserRepository
+	events     *pubsub.Broker[*user.UserEventDTO]
+	dispatcher *domainEvents.DomainEventDispatcher
+	imports    *user.ImportJobs
+	relay      *outbox.Relay
+	server     *httpserver.Server
+}
+
+// storage holds the persistence dependencies chosen by database.driver
+type storage struct {
 	dbManager *database.Manager
-	server    *httpserver.Server
+	userRepo  domainUser.Repository
+	search    domainUser.SearchRepository
+	imports   domainUser.ImportRepository
+	uow       unitofwork.UnitOfWork
+	relay     *outbox.Relay
+}
+
+// initStorage sets up the configured user store
+// The memory driver needs no database, so migrations, database health checks and the outbox relay are skipped;
+// SQLite has its own migration set and no outbox. Migrations are embedded in the binary
+func initStorage(ctx context.Context, cfg *config.Config, logger *slog.Logger) (*storage, error) {
+	if cfg.Database.Driver == config.DatabaseDriverMemory {
+		logger.Warn("Using the in-memory user store; data is lost on restart")
+		userRepo := memory.NewUserRepository(logger)
+		return &storage{
+			userRepo: userRepo,
+			search:   userRepo.(domainUser.SearchRepository),
+			imports:  userRepo.(domainUser.ImportRepository),
+			uow:      unitofwork.Passthrough{},
+		}, nil
+	}
+
+	// Initialize database manager
+	dbManager, err := database.NewManager(cfg.Database, logger)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create database manager: %w", err)
+	}
+
+	schema, err := migrations.For(cfg.Database.Driver)
+	if err != nil {
+		dbManager.Close() // Clean up on error
+		return nil, err
+	}
+
+	// Initialize database (run the embedded migrations) unless migrations are run with cmd/migrate,
+	// in which case only check that they have been
+	if cfg.Database.AutoMigrate {
+		if err := dbManager.Initialize(ctx, schema); err != nil {
+			dbManager.Close() // Clean up on error
+			return nil, fmt.Errorf("failed to initialize database: %w", err)
+		}
+	} else {
+		logger.Info("Automatic migrations are disabled; checking the schema version")
+		if err := dbManager.MigrationManager.CheckSchemaVersion(ctx, schema); err != nil {
+			dbManager.Close() // Clean up on error
+			return nil, fmt.Errorf("database schema check failed: %w", err)
+		}
+	}
+
+	// Perform startup health checks
+	if err := performStartupHealthChecks(ctx, dbManager, cfg.Database, logger); err != nil {
+		dbManager.Close() // Clean up on error
+		return nil, fmt.Errorf("startup health checks failed: %w", err)
+	}
+
+	if cfg.Database.Driver == config.DatabaseDriverSQLite {
+		userRepo := sqlite.NewUserRepository(dbManager.DB, dbManager.TxManager, logger)
+		return &storage{
+			dbManager: dbManager,
+			userRepo:  userRepo,
+			search:    userRepo.(domainUser.SearchRepository),
+			imports:   userRepo.(domainUser.ImportRepository),
+			uow:       dbManager.TxManager,
+		}, nil
+	}
+
+	userRepo := sql.NewUserRepository(dbManager.DB, dbManager.TxManager, logger)
+	return &storage{
+		dbManager: dbManager,
+		userRepo:  userRepo,
+		search:    userRepo.(domainUser.SearchRepository),
+		imports:   userRepo.(domainUser.ImportRepository),
+		uow:       dbManager.TxManager,
+		// "log" is the only publisher config accepts
+		relay: outbox.NewRelay(dbManager.TxManager, outbox.NewLogPublisher(logger), cfg.Outbox, logger),
+	}, nil
 }
 
 // NewApplication creates a new application instance with all dependencies wired
@@ -42,30 +139,36 @@ func NewApplication() (*Application, error) {
 	// Initialize logger
 	logger := initLogger(cfg.Logging)
 
-	// Initialize database manager
-	dbManager, err := database.NewManager(cfg.Database, logger)
+	// Initialize the user store selected by database.driver
+	store, err := initStorage(context.Background(), cfg, logger)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create database manager: %w", err)
+		return nil, err
 	}
 
-	// Initialize database (run migrations)
-	ctx := context.Background()
-	if err := dbManager.Initialize(ctx, "migrations"); err != nil {
-		dbManager.Close() // Clean up on error
-		return nil, fmt.Errorf("failed to initialize database: %w", err)
+	// Serve hot user lookups from memory; searches go straight to the store
+	var userCache *cache.UserRepository
+	if cfg.Cache.Enabled {
+		userCache = cache.NewUserRepository(store.userRepo, cfg.Cache, logger)
+		store.userRepo = userCache
+		// Imported users must not be hidden by cached misses
+		store.imports = userCache.Imports(store.imports)
 	}
 
-	// Perform startup health checks
-	if err := performStartupHealthChecks(ctx, dbManager, logger); err != nil {
-		dbManager.Close() // Clean up on error
-		return nil, fmt.Errorf("startup health checks failed: %w", err)
-	}
+	// Initialize the in-process broker that feeds subscriptions
+	events := pubsub.NewBroker[*user.UserEventDTO](cfg.Subscriptions.BufferSize, logger)
 
-	// Initialize repositories
-	userRepo := sql.NewUserRepository(dbManager.DB, logger)
+	// Initialize the dispatcher other modules register domain event handlers with
+	dispatcher := domainEvents.NewDomainEventDispatcher(logger)
+
+	// Initialize the background runner for uploaded user imports; its jobs live in this process,
+	// which is why imports.enabled needs a single instance
+	var importJobs *user.ImportJobs
+	if cfg.Imports.Enabled {
+		importJobs = user.NewImportJobs(user.NewImporter(store.imports, dispatcher, events, user.DefaultImportBatchSize, logger), logger)
+	}
 
 	// Initialize application services
-	userService := user.NewService(userRepo, logger)
+	userService := user.NewService(store.userRepo, store.search, importJobs, user.NewCursorCodec([]byte(cfg.Pagination.CursorSecret)), events, dispatcher, store.uow, logger)
 
 	// Initialize resolver with all dependencies
 	resolver := resolver.NewResolver(userService, logger)
@@ -74,10 +177,15 @@ func NewApplication() (*Application, error) {
 	server := httpserver.NewServer(&cfg.Server, resolver, logger)
 
 	return &Application{
-		config:    cfg,
-		logger:    logger,
-		dbManager: dbManager,
-		server:    server,
+		config:     cfg,
+		logger:     logger,
+		dbManager:  store.dbManager,
+		userCache:  userCache,
+		events:     events,
+		dispatcher: dispatcher,
+		imports:    importJobs,
+		relay:      store.relay,
+		server:     server,
 	}, nil
 }
 
@@ -87,6 +195,11 @@ func (app *Application) Start() error {
 		slog.String("version", "1.0.0"),
 		slog.String("port", app.config.Server.Port))
 
+	// Start publishing outbox events
+	if app.relay != nil {
+		app.relay.Start()
+	}
+
 	// Channel to receive server errors
 	serverErrors := make(chan error, 1)
 
@@ -120,16 +233,45 @@ func (app *Application) Stop() error {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
+	// Complete open subscriptions; hijacked WebSocket connections are not drained by the HTTP server
+	app.events.Close()
+
 	// Stop HTTP server
 	if err := app.server.Stop(ctx); err != nil {
 		app.logger.Error("Failed to stop HTTP server gracefully", slog.String("error", err.Error()))
 		return fmt.Errorf("failed to stop server: %w", err)
 	}
 
+	// Interrupt running imports; the batches they have inserted stay
+	if app.imports != nil {
+		app.imports.Close()
+	}
+
+	// Let asynchronous domain event handlers finish before their dependencies close
+	app.dispatcher.Wait()
+
+	// Finish the outbox batch in progress; undelivered events are picked up on the next start
+	if app.relay != nil {
+		app.relay.Stop(ctx)
+	}
+
+	// Report how well the user cache did
+	if app.userCache != nil {
+		stats := app.userCache.Stats()
+		app.logger.Info("User cache statistics",
+			slog.Uint64("hits", stats.Hits),
+			slog.Uint64("misses", stats.Misses),
+			slog.Uint64("stale_hits", stats.StaleHits),
+			slog.Uint64("evictions", stats.Evictions),
+			slog.Int("size", stats.Size))
+	}
+
 	// Close database connections
-	if err := app.dbManager.Close(); err != nil {
-		app.logger.Error("Failed to close database manager", slog.String("error", err.Error()))
-		return fmt.Errorf("failed to close database: %w", err)
+	if app.dbManager != nil {
+		if err := app.dbManager.Close(); err != nil {
+			app.logger.Error("Failed to close database manager", slog.String("error", err.Error()))
+			return fmt.Errorf("failed to close database: %w", err)
+		}
 	}
 
 	app.logger.Info("Application shutdown completed successfully")
@@ -159,7 +301,7 @@ func main() {
 }
 
 // performStartupHealthChecks validates that all critical components are working
-func performStartupHealthChecks(ctx context.Context, dbManager *database.Manager, logger *slog.Logger) error {
+func performStartupHealthChecks(ctx context.Context, dbManager *database.Manager, cfg config.DatabaseConfig, logger *slog.Logger) error {
 	logger.Info("Performing startup health checks...")
 
 	// Check database connectivity
@@ -168,6 +310,11 @@ func performStartupHealthChecks(ctx context.Context, dbManager *database.Manager
 	}
 	logger.Info("Database health check passed")
 
+	// Check that nobody changed the tables behind the migrations' back
+	if err := dbManager.CheckSchemaDrift(ctx, cfg.SchemaDrift, sql.ExpectedSchema); err != nil {
+		return fmt.Errorf("database schema drift check failed: %w", err)
+	}
+
 	// Add more health checks here as needed
 	// For example: external service connectivity, cache availability, etc.
 
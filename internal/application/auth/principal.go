@@ -0,0 +1,49 @@
+package auth
+
+import "context"
+
+// Role is a privilege level granted to a caller
+type Role string
+
+const (
+	// RoleAdmin may run destructive operations such as purging users
+	RoleAdmin Role = "ADMIN"
+)
+
+// Principal identifies the caller of an operation
+type Principal struct {
+	// Subject names the caller in audit records
+	Subject string
+	Roles   []Role
+}
+
+// HasRole reports whether the principal was granted role
+func (p Principal) HasRole(role Role) bool {
+	for _, r := range p.Roles {
+		if r == role {
+			return true
+		}
+	}
+	return false
+}
+
+// contextKey is a custom type for context keys to avoid collisions
+type contextKey struct{}
+
+// WithPrincipal returns a copy of ctx carrying the principal
+func WithPrincipal(ctx context.Context, principal Principal) context.Context {
+	return context.WithValue(ctx, contextKey{}, principal)
+}
+
+// FromContext returns the principal carried by ctx, if any
+func FromContext(ctx context.Context) (Principal, bool) {
+	principal, ok := ctx.Value(contextKey{}).(Principal)
+	return principal, ok
+}
+
+// IsAdmin reports whether the caller in ctx holds the admin role
+// Callers without a principal are anonymous and never admins
+func IsAdmin(ctx context.Context) bool {
+	principal, ok := FromContext(ctx)
+	return ok && principal.HasRole(RoleAdmin)
+}
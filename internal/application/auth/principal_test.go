@@ -0,0 +1,38 @@
+package auth
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIsAdmin(t *testing.T) {
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want bool
+	}{
+		{
+			name: "anonymous caller",
+			ctx:  context.Background(),
+			want: false,
+		},
+		{
+			name: "principal without roles",
+			ctx:  WithPrincipal(context.Background(), Principal{}),
+			want: false,
+		},
+		{
+			name: "admin principal",
+			ctx:  WithPrincipal(context.Background(), Principal{Roles: []Role{RoleAdmin}}),
+			want: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsAdmin(tt.ctx))
+		})
+	}
+}
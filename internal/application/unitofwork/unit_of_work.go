@@ -0,0 +1,24 @@
+package unitofwork
+
+import "context"
+
+//go:generate go run github.com/golang/mock/mockgen -source=$GOFILE -destination=./mocks/mock_$GOFILE -package=mocks
+
+// UnitOfWork runs a multi-step use case atomically
+type UnitOfWork interface {
+	// Do calls fn with a context that carries the unit of work; repository calls made
+	// with that context take part in it. Everything fn wrote is kept if it returns nil
+	// and discarded otherwise. A Do nested inside another joins the outer unit of work.
+	// fn may run more than once when the store fails transiently, so its only effects
+	// should be the writes it makes through ctx
+	Do(ctx context.Context, fn func(ctx context.Context) error) error
+}
+
+// Passthrough runs work directly, without a transaction
+// It suits stores that have no transactions, and tests
+type Passthrough struct{}
+
+// Do calls fn with ctx
+func (Passthrough) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
@@ -0,0 +1,160 @@
+package user
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"strings"
+	"time"
+
+	"github.com/captain-corgi/go-graphql-example/internal/domain/errors"
+	"github.com/captain-corgi/go-graphql-example/internal/domain/user"
+)
+
+// cursorPayload is the signed content of a pagination cursor
+// The sort is embedded so a cursor cannot be replayed under a different ordering
+type cursorPayload struct {
+	Field     string `json:"f"`
+	Direction string `json:"d"`
+	Value     string `json:"v"`
+	ID        string `json:"i"`
+}
+
+// searchCursorPayload is the signed content of a search cursor
+// Query fingerprints the search so a cursor cannot be replayed against another one
+type searchCursorPayload struct {
+	Query string  `json:"q"`
+	Rank  float64 `json:"r"`
+	ID    string  `json:"i"`
+}
+
+// CursorCodec turns keyset positions into opaque, HMAC-signed pagination cursors
+// so clients cannot forge or tamper with the position they resume from
+type CursorCodec struct {
+	secret []byte
+}
+
+// NewCursorCodec creates a cursor codec that signs cursors with the given secret
+func NewCursorCodec(secret []byte) *CursorCodec {
+	return &CursorCodec{
+		secret: secret,
+	}
+}
+
+// Encode signs the keyset position taken under the given sort and returns it as an opaque string
+func (c *CursorCodec) Encode(sort user.Sort, cursor user.Cursor) string {
+	value, _ := cursor.Value.(string)
+	if t, ok := cursor.Value.(time.Time); ok {
+		value = t.UTC().Format(time.RFC3339Nano)
+	}
+
+	return c.seal(cursorPayload{
+		Field:     string(sort.Field),
+		Direction: string(sort.Direction),
+		Value:     value,
+		ID:        cursor.ID.String(),
+	})
+}
+
+// Decode verifies the signature of an opaque cursor and returns the keyset position it encodes
+// Cursors issued under a different sort than the given one are rejected
+func (c *CursorCodec) Decode(token string, sort user.Sort) (user.Cursor, error) {
+	var decoded cursorPayload
+	if err := c.open(token, &decoded); err != nil {
+		return user.Cursor{}, err
+	}
+
+	if decoded.Field != string(sort.Field) || decoded.Direction != string(sort.Direction) {
+		return user.Cursor{}, errors.ErrInvalidCursor
+	}
+
+	id, err := user.NewUserID(decoded.ID)
+	if err != nil {
+		return user.Cursor{}, errors.ErrInvalidCursor
+	}
+
+	var value interface{} = decoded.Value
+	if sort.Field.IsTimestamp() {
+		t, err := time.Parse(time.RFC3339Nano, decoded.Value)
+		if err != nil {
+			return user.Cursor{}, errors.ErrInvalidCursor
+		}
+		value = t
+	}
+
+	return user.Cursor{
+		Value: value,
+		ID:    id,
+	}, nil
+}
+
+// EncodeSearch signs a position in the ranking of the given search and returns it as an opaque string
+func (c *CursorCodec) EncodeSearch(query string, cursor user.SearchCursor) string {
+	return c.seal(searchCursorPayload{
+		Query: searchFingerprint(query),
+		Rank:  cursor.Rank,
+		ID:    cursor.ID.String(),
+	})
+}
+
+// DecodeSearch verifies the signature of an opaque search cursor and returns the ranking position it encodes
+// Cursors issued for a different search than the given query, and list cursors, are rejected
+func (c *CursorCodec) DecodeSearch(token string, query string) (user.SearchCursor, error) {
+	var decoded searchCursorPayload
+	if err := c.open(token, &decoded); err != nil {
+		return user.SearchCursor{}, err
+	}
+
+	if decoded.Query == "" || decoded.Query != searchFingerprint(query) {
+		return user.SearchCursor{}, errors.ErrInvalidCursor
+	}
+
+	id, err := user.NewUserID(decoded.ID)
+	if err != nil {
+		return user.SearchCursor{}, errors.ErrInvalidCursor
+	}
+
+	return user.SearchCursor{
+		Rank: decoded.Rank,
+		ID:   id,
+	}, nil
+}
+
+// seal signs the JSON encoding of a payload and returns it with its signature as an opaque string
+func (c *CursorCodec) seal(v interface{}) string {
+	payload, _ := json.Marshal(v)
+	return base64.RawURLEncoding.EncodeToString(append(payload, c.sign(payload)...))
+}
+
+// open verifies the signature of an opaque string made by seal and decodes its payload into v
+func (c *CursorCodec) open(token string, v interface{}) error {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil || len(raw) <= sha256.Size {
+		return errors.ErrInvalidCursor
+	}
+
+	payload, signature := raw[:len(raw)-sha256.Size], raw[len(raw)-sha256.Size:]
+	if !hmac.Equal(signature, c.sign(payload)) {
+		return errors.ErrInvalidCursor
+	}
+
+	if err := json.Unmarshal(payload, v); err != nil {
+		return errors.ErrInvalidCursor
+	}
+	return nil
+}
+
+// searchFingerprint identifies a search query in its cursors without repeating the query text
+// Queries differing only in case, spacing or punctuation share their terms and their ranking
+func searchFingerprint(query string) string {
+	sum := sha256.Sum256([]byte(strings.Join(user.SearchQuery{Text: query}.Terms(), " ")))
+	return base64.RawURLEncoding.EncodeToString(sum[:12])
+}
+
+// sign computes the HMAC-SHA256 of the payload
+func (c *CursorCodec) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,125 @@
+package user
+
+import (
+	"encoding/base64"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/captain-corgi/go-graphql-example/internal/domain/errors"
+	"github.com/captain-corgi/go-graphql-example/internal/domain/user"
+)
+
+const testCursorSecret = "test-only-cursor-secret-0123456789abcdef"
+
+func TestCursorCodec_RoundTrip(t *testing.T) {
+	codec := NewCursorCodec([]byte(testCursorSecret))
+
+	id, err := user.NewUserID("123e4567-e89b-12d3-a456-426614174000")
+	require.NoError(t, err)
+
+	t.Run("timestamp sort", func(t *testing.T) {
+		loc := time.FixedZone("UTC+2", 2*60*60)
+		createdAt := time.Date(2025, 1, 16, 11, 30, 0, 123456789, loc)
+		cursor := user.Cursor{Value: createdAt, ID: id}
+
+		token := codec.Encode(user.DefaultSort, cursor)
+		assert.NotContains(t, token, id.String(), "cursor should be opaque")
+
+		decoded, err := codec.Decode(token, user.DefaultSort)
+		require.NoError(t, err)
+		require.IsType(t, time.Time{}, decoded.Value)
+		assert.True(t, createdAt.Equal(decoded.Value.(time.Time)))
+		assert.Equal(t, cursor.ID, decoded.ID)
+	})
+
+	t.Run("text sort", func(t *testing.T) {
+		sort := user.Sort{Field: user.SortByName, Direction: user.SortAsc}
+		cursor := user.Cursor{Value: "Zoë O'Brien", ID: id}
+
+		decoded, err := codec.Decode(codec.Encode(sort, cursor), sort)
+		require.NoError(t, err)
+		assert.Equal(t, cursor, decoded)
+	})
+}
+
+func TestCursorCodec_Decode_Invalid(t *testing.T) {
+	codec := NewCursorCodec([]byte(testCursorSecret))
+
+	id, err := user.NewUserID("123e4567-e89b-12d3-a456-426614174000")
+	require.NoError(t, err)
+	cursor := user.Cursor{Value: time.Now(), ID: id}
+	valid := codec.Encode(user.DefaultSort, cursor)
+
+	raw, err := base64.RawURLEncoding.DecodeString(valid)
+	require.NoError(t, err)
+	raw[2] ^= 0xff
+	tampered := base64.RawURLEncoding.EncodeToString(raw)
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "empty", token: ""},
+		{name: "not base64", token: "not a cursor!"},
+		{name: "legacy plain id", token: "123e4567-e89b-12d3-a456-426614174000"},
+		{name: "too short", token: base64.RawURLEncoding.EncodeToString([]byte("short"))},
+		{name: "tampered payload", token: tampered},
+		{name: "signed with another secret", token: NewCursorCodec([]byte("another-secret-0123456789abcdefgh")).Encode(user.DefaultSort, cursor)},
+		{name: "issued under another direction", token: codec.Encode(user.Sort{Field: user.SortByCreatedAt, Direction: user.SortAsc}, cursor)},
+		{name: "issued under another field", token: codec.Encode(user.Sort{Field: user.SortByUpdatedAt, Direction: user.SortDesc}, cursor)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := codec.Decode(tt.token, user.DefaultSort)
+			assert.Equal(t, errors.ErrInvalidCursor, err)
+		})
+	}
+}
+
+func TestCursorCodec_Search(t *testing.T) {
+	codec := NewCursorCodec([]byte(testCursorSecret))
+
+	id, err := user.NewUserID("123e4567-e89b-12d3-a456-426614174000")
+	require.NoError(t, err)
+	cursor := user.SearchCursor{Rank: 0.6079271038, ID: id}
+
+	t.Run("round trip", func(t *testing.T) {
+		token := codec.EncodeSearch("john doe", cursor)
+		assert.NotContains(t, token, "john", "cursor should not repeat the query")
+
+		decoded, err := codec.DecodeSearch(token, "john doe")
+		require.NoError(t, err)
+		assert.Equal(t, cursor, decoded)
+	})
+
+	t.Run("same terms", func(t *testing.T) {
+		decoded, err := codec.DecodeSearch(codec.EncodeSearch("john doe", cursor), "  John, DOE ")
+		require.NoError(t, err)
+		assert.Equal(t, cursor, decoded)
+	})
+
+	tests := []struct {
+		name  string
+		token string
+	}{
+		{name: "issued for another query", token: codec.EncodeSearch("jane", cursor)},
+		{name: "list cursor", token: codec.Encode(user.DefaultSort, user.Cursor{Value: time.Now(), ID: id})},
+		{name: "signed with another secret", token: NewCursorCodec([]byte("another-secret-0123456789abcdefgh")).EncodeSearch("john doe", cursor)},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := codec.DecodeSearch(tt.token, "john doe")
+			assert.Equal(t, errors.ErrInvalidCursor, err)
+		})
+	}
+
+	t.Run("search cursor as list cursor", func(t *testing.T) {
+		_, err := codec.Decode(codec.EncodeSearch("john doe", cursor), user.DefaultSort)
+		assert.Equal(t, errors.ErrInvalidCursor, err)
+	})
+}
@@ -1,6 +1,9 @@
 package user
 
-import "time"
+import (
+	"io"
+	"time"
+)
 
 // Request DTOs
 
@@ -9,12 +12,71 @@ type GetUserRequest struct {
 	ID string `json:"id" validate:"required"`
 }
 
+// GetUsersByIDsRequest represents a request to get several users by ID at once
+type GetUsersByIDsRequest struct {
+	IDs []string `json:"ids" validate:"required,dive,required"`
+}
+
 // ListUsersRequest represents a request to list users with pagination
+// First/After page forward; Last/Before page backward
 type ListUsersRequest struct {
+	First   int            `json:"first" validate:"min=1,max=100"`
+	After   string         `json:"after"`
+	Last    int            `json:"last" validate:"min=1,max=100"`
+	Before  string         `json:"before"`
+	Filter  *UserFilterDTO `json:"filter,omitempty"`
+	OrderBy *UserOrderDTO  `json:"orderBy,omitempty"`
+
+	// IncludeTotalCount requests the number of users matching the filter
+	// Counting costs an extra query, so callers only ask for it when needed
+	IncludeTotalCount bool `json:"includeTotalCount,omitempty"`
+
+	// IncludeDeleted lists soft-deleted users alongside live ones
+	IncludeDeleted bool `json:"includeDeleted,omitempty"`
+}
+
+// UserFilterDTO represents the conditions a listed user must match
+type UserFilterDTO struct {
+	Email     *StringFilterDTO `json:"email,omitempty"`
+	Name      *StringFilterDTO `json:"name,omitempty"`
+	CreatedAt *TimeRangeDTO    `json:"createdAt,omitempty"`
+	UpdatedAt *TimeRangeDTO    `json:"updatedAt,omitempty"`
+	IDs       []string         `json:"ids,omitempty" validate:"max=100"`
+	Statuses  []string         `json:"status,omitempty"`
+}
+
+// StringFilterDTO matches a text field exactly or by case-insensitive substring
+type StringFilterDTO struct {
+	Equals   *string `json:"equals,omitempty"`
+	Contains *string `json:"contains,omitempty"`
+}
+
+// TimeRangeDTO matches timestamps from From (inclusive) to To (exclusive)
+type TimeRangeDTO struct {
+	From *time.Time `json:"from,omitempty"`
+	To   *time.Time `json:"to,omitempty"`
+}
+
+// UserOrderDTO represents the ordering of a user listing
+type UserOrderDTO struct {
+	Field     string `json:"field" validate:"oneof=NAME EMAIL CREATED_AT UPDATED_AT"`
+	Direction string `json:"direction" validate:"oneof=ASC DESC"`
+}
+
+// SearchUsersRequest represents a request to rank users by how well they match free text
+type SearchUsersRequest struct {
+	Query string `json:"query" validate:"required,max=200"`
 	First int    `json:"first" validate:"min=1,max=100"`
 	After string `json:"after"`
 }
 
+// GetUserStatsRequest represents a request for signup statistics over [From, To)
+type GetUserStatsRequest struct {
+	From     time.Time `json:"from" validate:"required"`
+	To       time.Time `json:"to" validate:"required"`
+	Interval string    `json:"interval" validate:"oneof=DAY WEEK MONTH"`
+}
+
 // CreateUserRequest represents a request to create a new user
 type CreateUserRequest struct {
 	Email string `json:"email" validate:"required,email"`
@@ -26,6 +88,10 @@ type UpdateUserRequest struct {
 	ID    string  `json:"id" validate:"required"`
 	Email *string `json:"email,omitempty" validate:"omitempty,email"`
 	Name  *string `json:"name,omitempty" validate:"omitempty,min=1,max=255"`
+
+	// ExpectedVersion, when set, makes the update fail with CONFLICT unless the
+	// user is still at this version
+	ExpectedVersion *int64 `json:"expectedVersion,omitempty"`
 }
 
 // DeleteUserRequest represents a request to delete a user
@@ -33,6 +99,48 @@ type DeleteUserRequest struct {
 	ID string `json:"id" validate:"required"`
 }
 
+// RestoreUserRequest represents a request to restore a soft-deleted user
+type RestoreUserRequest struct {
+	ID string `json:"id" validate:"required"`
+}
+
+// PurgeUserRequest represents a request to permanently remove a user
+type PurgeUserRequest struct {
+	ID string `json:"id" validate:"required"`
+}
+
+// ChangeUserStatusRequest represents a request to move a user to another account status
+type ChangeUserStatusRequest struct {
+	ID     string `json:"id" validate:"required"`
+	Status string `json:"status" validate:"oneof=INVITED ACTIVE SUSPENDED DEACTIVATED"`
+	Reason string `json:"reason" validate:"required,max=500"`
+}
+
+// SubscribeUserEventsRequest represents a subscription to user lifecycle events
+// An empty UserID matches events for every user
+type SubscribeUserEventsRequest struct {
+	Type   EventType `json:"type" validate:"oneof=USER_CREATED USER_UPDATED USER_DELETED"`
+	UserID string    `json:"userId,omitempty"`
+}
+
+// ImportUsersRequest represents a request to import users from an uploaded file
+// The format is told by the file name's extension, or failing that by the content type
+type ImportUsersRequest struct {
+	File        io.Reader `json:"-"`
+	FileName    string    `json:"fileName"`
+	ContentType string    `json:"contentType,omitempty"`
+}
+
+// GetImportJobRequest represents a request for the progress of an import
+// The job's Rows hold up to RowsFirst row results from RowsOffset on, counting only rows
+// with RowStatus when it is set; with RowsFirst zero no rows are returned
+type GetImportJobRequest struct {
+	ID         string `json:"id" validate:"required"`
+	RowStatus  string `json:"rowStatus,omitempty" validate:"omitempty,oneof=CREATED INVALID DUPLICATE_IN_FILE DUPLICATE_EXISTING FAILED"`
+	RowsFirst  int    `json:"rowsFirst" validate:"min=0,max=1000"`
+	RowsOffset int    `json:"rowsOffset" validate:"min=0"`
+}
+
 // Response DTOs
 
 // UserDTO represents a user in the application layer
@@ -42,6 +150,11 @@ type UserDTO struct {
 	Name      string    `json:"name"`
 	CreatedAt time.Time `json:"createdAt"`
 	UpdatedAt time.Time `json:"updatedAt"`
+	Status    string    `json:"status"`
+	Version   int64     `json:"version"`
+
+	// DeletedAt is set when the user has been soft-deleted
+	DeletedAt *time.Time `json:"deletedAt,omitempty"`
 }
 
 // ErrorDTO represents an error in the application layer
@@ -49,6 +162,9 @@ type ErrorDTO struct {
 	Message string `json:"message"`
 	Field   string `json:"field,omitempty"`
 	Code    string `json:"code"`
+
+	// CurrentVersion is set on CONFLICT errors to the version the user is now at
+	CurrentVersion *int64 `json:"currentVersion,omitempty"`
 }
 
 // PageInfoDTO represents pagination information
@@ -66,9 +182,122 @@ type UserEdgeDTO struct {
 }
 
 // UserConnectionDTO represents a paginated list of users
+// TotalCount is only populated when the request asked for it
 type UserConnectionDTO struct {
-	Edges    []*UserEdgeDTO `json:"edges"`
-	PageInfo *PageInfoDTO   `json:"pageInfo"`
+	Edges      []*UserEdgeDTO `json:"edges"`
+	PageInfo   *PageInfoDTO   `json:"pageInfo"`
+	TotalCount *int64         `json:"totalCount,omitempty"`
+}
+
+// UserSearchHighlightsDTO holds the user's name and email with the parts matching a search
+// wrapped in <mark> tags and everything else HTML-escaped; a field is nil when nothing in it matched
+type UserSearchHighlightsDTO struct {
+	Name  *string `json:"name"`
+	Email *string `json:"email"`
+}
+
+// UserSearchEdgeDTO represents a ranked user in search results
+type UserSearchEdgeDTO struct {
+	Node       *UserDTO                 `json:"node"`
+	Cursor     string                   `json:"cursor"`
+	Rank       float64                  `json:"rank"`
+	Highlights *UserSearchHighlightsDTO `json:"highlights"`
+}
+
+// UserSearchConnectionDTO represents a page of search results, best match first
+type UserSearchConnectionDTO struct {
+	Edges    []*UserSearchEdgeDTO `json:"edges"`
+	PageInfo *PageInfoDTO         `json:"pageInfo"`
+}
+
+// StatusTransitionDTO represents a recorded change of account status
+type StatusTransitionDTO struct {
+	From       string    `json:"from"`
+	To         string    `json:"to"`
+	Actor      string    `json:"actor"`
+	Reason     string    `json:"reason"`
+	OccurredAt time.Time `json:"occurredAt"`
+}
+
+// SignupBucketDTO represents the number of signups within one interval
+type SignupBucketDTO struct {
+	Start time.Time `json:"start"`
+	Count int64     `json:"count"`
+}
+
+// UserStatsDTO represents signup statistics over a time range
+type UserStatsDTO struct {
+	From         time.Time          `json:"from"`
+	To           time.Time          `json:"to"`
+	Interval     string             `json:"interval"`
+	TotalSignups int64              `json:"totalSignups"`
+	Signups      []*SignupBucketDTO `json:"signups"`
+}
+
+// ImportFormat is the encoding of an import file
+type ImportFormat string
+
+const (
+	// ImportFormatCSV is comma-separated values under a header naming the email and name columns
+	ImportFormatCSV ImportFormat = "CSV"
+	// ImportFormatNDJSON is one JSON object with email and name per line
+	ImportFormatNDJSON ImportFormat = "NDJSON"
+)
+
+// ImportRowStatus is what became of one row of an import file
+type ImportRowStatus string
+
+const (
+	ImportRowCreated           ImportRowStatus = "CREATED"
+	ImportRowInvalid           ImportRowStatus = "INVALID"
+	ImportRowDuplicateInFile   ImportRowStatus = "DUPLICATE_IN_FILE"
+	ImportRowDuplicateExisting ImportRowStatus = "DUPLICATE_EXISTING"
+	ImportRowFailed            ImportRowStatus = "FAILED"
+)
+
+// ImportRowResultDTO represents the outcome of one row of an import file
+// Line is where the row starts in the file. UserID is set on created rows and Error on the others
+type ImportRowResultDTO struct {
+	Line   int             `json:"line"`
+	Email  string          `json:"email"`
+	Status ImportRowStatus `json:"status"`
+	UserID string          `json:"userId,omitempty"`
+	Error  *ErrorDTO       `json:"error,omitempty"`
+}
+
+// ImportReportDTO represents the outcome of every row of an import, in file order
+type ImportReportDTO struct {
+	Rows     []ImportRowResultDTO `json:"rows"`
+	Created  int                  `json:"created"`
+	Rejected int                  `json:"rejected"`
+}
+
+// ImportJobStatus is the state of a background import
+type ImportJobStatus string
+
+const (
+	ImportJobPending   ImportJobStatus = "PENDING"
+	ImportJobRunning   ImportJobStatus = "RUNNING"
+	ImportJobCompleted ImportJobStatus = "COMPLETED"
+	ImportJobFailed    ImportJobStatus = "FAILED"
+)
+
+// ImportJobDTO represents a background import and its progress so far
+// Progress is the share of the file read, from 0 to 1. Rows grows as batches complete;
+// a failed job keeps the rows handled before it stopped and says why in Error
+type ImportJobDTO struct {
+	ID         string               `json:"id"`
+	FileName   string               `json:"fileName"`
+	Format     ImportFormat         `json:"format"`
+	Status     ImportJobStatus      `json:"status"`
+	Progress   float64              `json:"progress"`
+	Created    int                  `json:"created"`
+	Rejected   int                  `json:"rejected"`
+	Rows       []ImportRowResultDTO `json:"rows"`
+	Error      *ErrorDTO            `json:"error,omitempty"`
+	CreatedAt  time.Time            `json:"createdAt"`
+	StartedAt  *time.Time           `json:"startedAt,omitempty"`
+	FinishedAt *time.Time           `json:"finishedAt,omitempty"`
 }
 
 // Response DTOs
@@ -79,12 +308,31 @@ type GetUserResponse struct {
 	Errors []ErrorDTO `json:"errors,omitempty"`
 }
 
+// GetUsersByIDsResponse represents the response for getting several users by ID
+// Users that do not exist are absent from Users
+type GetUsersByIDsResponse struct {
+	Users  []*UserDTO `json:"users"`
+	Errors []ErrorDTO `json:"errors,omitempty"`
+}
+
 // ListUsersResponse represents the response for listing users
 type ListUsersResponse struct {
 	Users  *UserConnectionDTO `json:"users"`
 	Errors []ErrorDTO         `json:"errors,omitempty"`
 }
 
+// SearchUsersResponse represents the response for searching users
+type SearchUsersResponse struct {
+	Results *UserSearchConnectionDTO `json:"results"`
+	Errors  []ErrorDTO               `json:"errors,omitempty"`
+}
+
+// GetUserStatsResponse represents the response for getting user statistics
+type GetUserStatsResponse struct {
+	Stats  *UserStatsDTO `json:"stats"`
+	Errors []ErrorDTO    `json:"errors,omitempty"`
+}
+
 // CreateUserResponse represents the response for creating a user
 type CreateUserResponse struct {
 	User   *UserDTO   `json:"user"`
@@ -97,8 +345,46 @@ type UpdateUserResponse struct {
 	Errors []ErrorDTO `json:"errors,omitempty"`
 }
 
+// SubscribeUserEventsResponse represents the response for subscribing to user events
+// Events is closed when the subscription ends
+type SubscribeUserEventsResponse struct {
+	Events <-chan *UserEventDTO `json:"-"`
+	Errors []ErrorDTO           `json:"errors,omitempty"`
+}
+
 // DeleteUserResponse represents the response for deleting a user
 type DeleteUserResponse struct {
 	Success bool       `json:"success"`
 	Errors  []ErrorDTO `json:"errors,omitempty"`
 }
+
+// RestoreUserResponse represents the response for restoring a user
+type RestoreUserResponse struct {
+	User   *UserDTO   `json:"user"`
+	Errors []ErrorDTO `json:"errors,omitempty"`
+}
+
+// PurgeUserResponse represents the response for permanently removing a user
+type PurgeUserResponse struct {
+	Success bool       `json:"success"`
+	Errors  []ErrorDTO `json:"errors,omitempty"`
+}
+
+// ChangeUserStatusResponse represents the response for changing a user's status
+type ChangeUserStatusResponse struct {
+	User       *UserDTO             `json:"user"`
+	Transition *StatusTransitionDTO `json:"transition"`
+	Errors     []ErrorDTO           `json:"errors,omitempty"`
+}
+
+// ImportUsersResponse represents the response for starting an import
+type ImportUsersResponse struct {
+	Job    *ImportJobDTO `json:"job"`
+	Errors []ErrorDTO    `json:"errors,omitempty"`
+}
+
+// GetImportJobResponse represents the response for getting the progress of an import
+type GetImportJobResponse struct {
+	Job    *ImportJobDTO `json:"job"`
+	Errors []ErrorDTO    `json:"errors,omitempty"`
+}
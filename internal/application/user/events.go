@@ -0,0 +1,34 @@
+package user
+
+import (
+	"context"
+	"time"
+)
+
+// EventType identifies a user lifecycle event
+type EventType string
+
+const (
+	EventUserCreated EventType = "USER_CREATED"
+	EventUserUpdated EventType = "USER_UPDATED"
+	EventUserDeleted EventType = "USER_DELETED"
+)
+
+// UserEventDTO describes a change to a user, published after the mutation succeeded
+// For deletions User holds the user as it was immediately before it was removed
+type UserEventDTO struct {
+	Type       EventType `json:"type"`
+	User       *UserDTO  `json:"user"`
+	OccurredAt time.Time `json:"occurredAt"`
+}
+
+// EventBroker fans user events out to in-process subscribers
+type EventBroker interface {
+	// Publish delivers the event to every matching subscriber without blocking
+	Publish(event *UserEventDTO)
+
+	// Subscribe returns a channel of the events accepted by match
+	// The channel is closed when ctx is done, when the broker closes, or when
+	// the subscriber falls too far behind and is dropped
+	Subscribe(ctx context.Context, match func(*UserEventDTO) bool) <-chan *UserEventDTO
+}
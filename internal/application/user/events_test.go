@@ -0,0 +1,345 @@
+package user
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/captain-corgi/go-graphql-example/internal/application/auth"
+	"github.com/captain-corgi/go-graphql-example/internal/application/unitofwork"
+	"github.com/captain-corgi/go-graphql-example/internal/domain/errors"
+	domainEvents "github.com/captain-corgi/go-graphql-example/internal/domain/events"
+	"github.com/captain-corgi/go-graphql-example/internal/domain/user"
+	"github.com/captain-corgi/go-graphql-example/internal/domain/user/mocks"
+)
+
+// recordingBroker is an EventBroker that records published events and the last subscription filter
+type recordingBroker struct {
+	mu        sync.Mutex
+	published []*UserEventDTO
+	match     func(*UserEventDTO) bool
+}
+
+func (b *recordingBroker) Publish(event *UserEventDTO) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.published = append(b.published, event)
+}
+
+func (b *recordingBroker) Subscribe(_ context.Context, match func(*UserEventDTO) bool) <-chan *UserEventDTO {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.match = match
+	return make(chan *UserEventDTO)
+}
+
+func (b *recordingBroker) events() []*UserEventDTO {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.published
+}
+
+func TestService_PublishesUserEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	broker := &recordingBroker{}
+	service := NewService(mockRepo, nil, nil, NewCursorCodec([]byte(testCursorSecret)), broker, domainEvents.NewDomainEventDispatcher(slog.Default()), unitofwork.Passthrough{}, slog.Default())
+
+	ctx := context.Background()
+
+	// Create
+	mockRepo.EXPECT().ExistsByEmail(gomock.Any(), gomock.Any()).Return(false, nil)
+	mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+
+	created, err := service.CreateUser(ctx, CreateUserRequest{Email: "new@example.com", Name: "New User"})
+	require.NoError(t, err)
+	require.Empty(t, created.Errors)
+
+	// Update
+	existing, err := user.NewUserWithID(created.User.ID, "new@example.com", "New User", time.Now(), time.Now())
+	require.NoError(t, err)
+	mockRepo.EXPECT().FindByID(gomock.Any(), existing.ID()).Return(existing, nil)
+	mockRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+
+	newName := "Renamed User"
+	updated, err := service.UpdateUser(ctx, UpdateUserRequest{ID: created.User.ID, Name: &newName})
+	require.NoError(t, err)
+	require.Empty(t, updated.Errors)
+
+	// Delete
+	mockRepo.EXPECT().FindByID(gomock.Any(), existing.ID()).Return(existing, nil)
+	mockRepo.EXPECT().Delete(gomock.Any(), existing).Return(nil)
+
+	deleted, err := service.DeleteUser(ctx, DeleteUserRequest{ID: created.User.ID})
+	require.NoError(t, err)
+	require.True(t, deleted.Success)
+
+	// A failed mutation publishes nothing
+	mockRepo.EXPECT().ExistsByEmail(gomock.Any(), gomock.Any()).Return(true, nil)
+	failed, err := service.CreateUser(ctx, CreateUserRequest{Email: "new@example.com", Name: "New User"})
+	require.NoError(t, err)
+	require.NotEmpty(t, failed.Errors)
+
+	events := broker.events()
+	require.Len(t, events, 3)
+
+	assert.Equal(t, EventUserCreated, events[0].Type)
+	assert.Equal(t, created.User, events[0].User)
+
+	assert.Equal(t, EventUserUpdated, events[1].Type)
+	assert.Equal(t, "Renamed User", events[1].User.Name)
+
+	assert.Equal(t, EventUserDeleted, events[2].Type)
+	assert.Equal(t, created.User.ID, events[2].User.ID)
+	assert.Equal(t, "Renamed User", events[2].User.Name)
+
+	for _, event := range events {
+		assert.False(t, event.OccurredAt.IsZero())
+	}
+}
+
+func TestService_DispatchesDomainEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var dispatched []domainEvents.Event
+	dispatcher := domainEvents.NewDomainEventDispatcher(slog.Default())
+	dispatcher.Subscribe(domainEvents.AnyEvent, func(_ context.Context, event domainEvents.Event) error {
+		dispatched = append(dispatched, event)
+		return nil
+	})
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	service := NewService(mockRepo, nil, nil, NewCursorCodec([]byte(testCursorSecret)), &recordingBroker{}, dispatcher, unitofwork.Passthrough{}, slog.Default())
+
+	ctx := context.Background()
+
+	// Create
+	mockRepo.EXPECT().ExistsByEmail(gomock.Any(), gomock.Any()).Return(false, nil)
+	mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+
+	created, err := service.CreateUser(ctx, CreateUserRequest{Email: "new@example.com", Name: "New User"})
+	require.NoError(t, err)
+	require.Empty(t, created.Errors)
+
+	// An update that fails to persist dispatches nothing
+	existing, err := user.NewUserWithID(created.User.ID, "new@example.com", "New User", time.Now(), time.Now())
+	require.NoError(t, err)
+	mockRepo.EXPECT().FindByID(gomock.Any(), existing.ID()).Return(existing, nil)
+	mockRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(errors.ErrRepositoryOperation)
+
+	newName := "Renamed User"
+	failed, err := service.UpdateUser(ctx, UpdateUserRequest{ID: created.User.ID, Name: &newName})
+	require.NoError(t, err)
+	require.NotEmpty(t, failed.Errors)
+
+	// Update
+	existing, err = user.NewUserWithID(created.User.ID, "new@example.com", "New User", time.Now(), time.Now())
+	require.NoError(t, err)
+	mockRepo.EXPECT().FindByID(gomock.Any(), existing.ID()).Return(existing, nil)
+	mockRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(nil)
+
+	updated, err := service.UpdateUser(ctx, UpdateUserRequest{ID: created.User.ID, Name: &newName})
+	require.NoError(t, err)
+	require.Empty(t, updated.Errors)
+
+	// Delete
+	mockRepo.EXPECT().FindByID(gomock.Any(), existing.ID()).Return(existing, nil)
+	mockRepo.EXPECT().Delete(gomock.Any(), existing).Return(nil)
+
+	deleted, err := service.DeleteUser(ctx, DeleteUserRequest{ID: created.User.ID})
+	require.NoError(t, err)
+	require.True(t, deleted.Success)
+
+	require.Len(t, dispatched, 3)
+
+	registered, ok := dispatched[0].(user.UserRegistered)
+	require.True(t, ok)
+	assert.Equal(t, created.User.ID, registered.AggregateID())
+	assert.Equal(t, "new@example.com", registered.Email.String())
+
+	renamed, ok := dispatched[1].(user.UserRenamed)
+	require.True(t, ok)
+	assert.Equal(t, "New User", renamed.From.String())
+	assert.Equal(t, "Renamed User", renamed.To.String())
+
+	deletion, ok := dispatched[2].(user.UserDeleted)
+	require.True(t, ok)
+	assert.Equal(t, "Renamed User", deletion.Name.String())
+}
+
+func TestService_AccountChangesRaiseEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	var dispatched []domainEvents.Event
+	dispatcher := domainEvents.NewDomainEventDispatcher(slog.Default())
+	dispatcher.Subscribe(domainEvents.AnyEvent, func(_ context.Context, event domainEvents.Event) error {
+		dispatched = append(dispatched, event)
+		return nil
+	})
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	broker := &recordingBroker{}
+	service := NewService(mockRepo, nil, nil, NewCursorCodec([]byte(testCursorSecret)), broker, dispatcher, unitofwork.Passthrough{}, slog.Default())
+
+	ctx := auth.WithPrincipal(context.Background(), auth.Principal{Subject: "jane.ops", Roles: []auth.Role{auth.RoleAdmin}})
+
+	deletedAt := time.Now()
+	snapshot := user.Snapshot{
+		ID:        "123e4567-e89b-12d3-a456-426614174000",
+		Email:     "test@example.com",
+		Name:      "Test User",
+		CreatedAt: time.Now().Add(-time.Hour),
+		UpdatedAt: time.Now().Add(-time.Hour),
+		DeletedAt: &deletedAt,
+		Status:    user.StatusActive,
+		Version:   2,
+	}
+	existing, err := user.NewUserFromSnapshot(snapshot)
+	require.NoError(t, err)
+	id := existing.ID().String()
+
+	// A restore that fails to persist raises nothing
+	mockRepo.EXPECT().FindAll(gomock.Any(), gomock.Any(), gomock.Any()).Return(&user.Page{Users: []*user.User{existing}}, nil)
+	mockRepo.EXPECT().Restore(gomock.Any(), existing).Return(errors.ErrDuplicateEmail)
+
+	failed, err := service.RestoreUser(ctx, RestoreUserRequest{ID: id})
+	require.NoError(t, err)
+	require.NotEmpty(t, failed.Errors)
+	assert.Empty(t, broker.events())
+
+	// Restore
+	existing, err = user.NewUserFromSnapshot(snapshot)
+	require.NoError(t, err)
+	mockRepo.EXPECT().FindAll(gomock.Any(), gomock.Any(), gomock.Any()).Return(&user.Page{Users: []*user.User{existing}}, nil)
+	mockRepo.EXPECT().Restore(gomock.Any(), existing).Return(nil)
+
+	restored, err := service.RestoreUser(ctx, RestoreUserRequest{ID: id})
+	require.NoError(t, err)
+	require.Empty(t, restored.Errors)
+
+	// Status change
+	mockRepo.EXPECT().FindByID(gomock.Any(), existing.ID()).Return(existing, nil)
+	mockRepo.EXPECT().UpdateStatus(gomock.Any(), existing, gomock.Any()).Return(nil)
+
+	changed, err := service.ChangeUserStatus(ctx, ChangeUserStatusRequest{ID: id, Status: string(user.StatusSuspended), Reason: "Spam"})
+	require.NoError(t, err)
+	require.Empty(t, changed.Errors)
+
+	// Purge
+	mockRepo.EXPECT().FindAll(gomock.Any(), gomock.Any(), gomock.Any()).Return(&user.Page{Users: []*user.User{existing}}, nil)
+	mockRepo.EXPECT().Purge(gomock.Any(), existing).Return(nil)
+
+	purged, err := service.PurgeUser(ctx, PurgeUserRequest{ID: id})
+	require.NoError(t, err)
+	require.True(t, purged.Success)
+
+	require.Len(t, dispatched, 3)
+
+	restoration, ok := dispatched[0].(user.UserRestored)
+	require.True(t, ok)
+	assert.Equal(t, id, restoration.AggregateID())
+
+	statusChange, ok := dispatched[1].(user.UserStatusChanged)
+	require.True(t, ok)
+	assert.Equal(t, user.StatusActive, statusChange.From)
+	assert.Equal(t, user.StatusSuspended, statusChange.To)
+	assert.Equal(t, "jane.ops", statusChange.Actor)
+
+	purge, ok := dispatched[2].(user.UserPurged)
+	require.True(t, ok)
+	assert.Equal(t, "test@example.com", purge.Email.String())
+
+	events := broker.events()
+	require.Len(t, events, 3)
+
+	assert.Equal(t, EventUserUpdated, events[0].Type)
+	assert.Nil(t, events[0].User.DeletedAt)
+
+	assert.Equal(t, EventUserUpdated, events[1].Type)
+	assert.Equal(t, string(user.StatusSuspended), events[1].User.Status)
+
+	assert.Equal(t, EventUserDeleted, events[2].Type)
+	assert.Equal(t, id, events[2].User.ID)
+}
+
+func TestService_SubscribeUserEvents(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	const userID = "123e4567-e89b-12d3-a456-426614174000"
+
+	event := func(eventType EventType, id string) *UserEventDTO {
+		return &UserEventDTO{Type: eventType, User: &UserDTO{ID: id}}
+	}
+
+	tests := []struct {
+		name     string
+		request  SubscribeUserEventsRequest
+		wantCode string
+		accepts  []*UserEventDTO
+		rejects  []*UserEventDTO
+	}{
+		{
+			name:    "all created users",
+			request: SubscribeUserEventsRequest{Type: EventUserCreated},
+			accepts: []*UserEventDTO{event(EventUserCreated, userID)},
+			rejects: []*UserEventDTO{event(EventUserUpdated, userID), event(EventUserDeleted, userID)},
+		},
+		{
+			name:    "updates to one user",
+			request: SubscribeUserEventsRequest{Type: EventUserUpdated, UserID: "123E4567-E89B-12D3-A456-426614174000"},
+			accepts: []*UserEventDTO{event(EventUserUpdated, userID)},
+			rejects: []*UserEventDTO{
+				event(EventUserUpdated, "123e4567-e89b-12d3-a456-426614174001"),
+				event(EventUserDeleted, userID),
+			},
+		},
+		{
+			name:     "invalid user ID",
+			request:  SubscribeUserEventsRequest{Type: EventUserUpdated, UserID: "not-a-uuid"},
+			wantCode: "INVALID_USER_ID",
+		},
+		{
+			name:     "unknown event type",
+			request:  SubscribeUserEventsRequest{Type: "USER_RENAMED"},
+			wantCode: "INVALID_EVENT_TYPE",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			broker := &recordingBroker{}
+			service := NewService(mocks.NewMockRepository(ctrl), nil, nil, NewCursorCodec([]byte(testCursorSecret)), broker, domainEvents.NewDomainEventDispatcher(slog.Default()), unitofwork.Passthrough{}, slog.Default())
+
+			got, err := service.SubscribeUserEvents(context.Background(), tt.request)
+			require.NoError(t, err)
+
+			if tt.wantCode != "" {
+				require.Len(t, got.Errors, 1)
+				assert.Equal(t, tt.wantCode, got.Errors[0].Code)
+				assert.Nil(t, got.Events)
+				assert.Nil(t, broker.match)
+				return
+			}
+
+			require.Empty(t, got.Errors)
+			require.NotNil(t, got.Events)
+			for _, e := range tt.accepts {
+				assert.True(t, broker.match(e), "expected %s for %s to match", e.Type, e.User.ID)
+			}
+			for _, e := range tt.rejects {
+				assert.False(t, broker.match(e), "expected %s for %s not to match", e.Type, e.User.ID)
+			}
+		})
+	}
+}
@@ -0,0 +1,460 @@
+package user
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/captain-corgi/go-graphql-example/internal/domain/errors"
+	domainEvents "github.com/captain-corgi/go-graphql-example/internal/domain/events"
+	"github.com/captain-corgi/go-graphql-example/internal/domain/user"
+)
+
+// DefaultImportBatchSize is how many rows an import checks and inserts at a time
+const DefaultImportBatchSize = 500
+
+// maxImportLineBytes bounds the length of an NDJSON line
+const maxImportLineBytes = 64 * 1024
+
+// utf8BOM is the byte order mark spreadsheet tools put at the start of exported files
+const utf8BOM = "\ufeff"
+
+// DetectImportFormat tells the format of an import file from its name's extension,
+// or failing that from its content type
+func DetectImportFormat(fileName, contentType string) (ImportFormat, error) {
+	switch strings.ToLower(filepath.Ext(fileName)) {
+	case ".csv":
+		return ImportFormatCSV, nil
+	case ".ndjson", ".jsonl":
+		return ImportFormatNDJSON, nil
+	}
+
+	mediaType, _, _ := strings.Cut(strings.ToLower(contentType), ";")
+	switch strings.TrimSpace(mediaType) {
+	case "text/csv":
+		return ImportFormatCSV, nil
+	case "application/x-ndjson", "application/ndjson", "application/jsonl":
+		return ImportFormatNDJSON, nil
+	}
+
+	return "", errors.ErrInvalidImportFile
+}
+
+// ImportProgress is told the results of each batch's rows and how many bytes of the file were read so far
+type ImportProgress func(rows []ImportRowResultDTO, bytesRead int64)
+
+// Importer creates users in bulk from CSV or NDJSON files
+// Rows are validated like createUser input and checked for emails repeated earlier in the file
+// or already held by live users; the rest are inserted batchSize at a time. The domain events of
+// created users are dispatched and, when events is set, published as userCreated after each batch
+type Importer struct {
+	repo       user.ImportRepository
+	dispatcher *domainEvents.DomainEventDispatcher
+	events     EventBroker
+	batchSize  int
+	logger     *slog.Logger
+}
+
+// NewImporter creates an importer; a batchSize of zero or less means DefaultImportBatchSize
+// A nil events publishes nothing, as suits a process without subscribers
+func NewImporter(repo user.ImportRepository, dispatcher *domainEvents.DomainEventDispatcher, events EventBroker, batchSize int, logger *slog.Logger) *Importer {
+	if batchSize <= 0 {
+		batchSize = DefaultImportBatchSize
+	}
+	return &Importer{
+		repo:       repo,
+		dispatcher: dispatcher,
+		events:     events,
+		batchSize:  batchSize,
+		logger:     logger,
+	}
+}
+
+// Import streams the file and creates a user for every acceptable row, reporting on each row in file order
+// Rows that cannot be read or fail validation are reported, not fatal. A file that cannot be parsed at all
+// fails with ErrInvalidImportFile, as does one that breaks off, once the rows before the break are imported.
+// When ctx is done or the store fails, Import stops and returns the error along with the report of the rows
+// handled so far; progress, if not nil, is called after every batch
+func (im *Importer) Import(ctx context.Context, r io.Reader, format ImportFormat, progress ImportProgress) (*ImportReportDTO, error) {
+	im.logger.InfoContext(ctx, "Importing users", "format", format, "batchSize", im.batchSize)
+
+	counter := &countingReader{r: r}
+	rows, err := newImportRowReader(counter, format)
+	if err != nil {
+		im.logger.WarnContext(ctx, "Unreadable import file", "error", err)
+		return nil, err
+	}
+
+	run := &importRun{importer: im, report: &ImportReportDTO{}, seen: make(map[string]int)}
+	flush := func() error {
+		err := run.flush(ctx)
+		if progress != nil && len(run.window) > 0 {
+			progress(run.window, counter.n)
+		}
+		run.window, run.pending = nil, nil
+		return err
+	}
+
+	for {
+		row, err := rows.next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			// Rows read before the unreadable part are still imported
+			im.logger.WarnContext(ctx, "Import stopped at an unreadable part of the file", "error", err)
+			if flushErr := flush(); flushErr != nil {
+				return run.report, flushErr
+			}
+			return run.report, err
+		}
+
+		run.add(row)
+		if len(run.window) == im.batchSize {
+			if err := flush(); err != nil {
+				return run.report, err
+			}
+		}
+	}
+	if err := flush(); err != nil {
+		return run.report, err
+	}
+
+	im.logger.InfoContext(ctx, "Successfully imported users",
+		"rows", len(run.report.Rows),
+		"created", run.report.Created,
+		"rejected", run.report.Rejected,
+	)
+	return run.report, nil
+}
+
+// importRow is one record of an import file; err is set when the record cannot be read as a user
+type importRow struct {
+	line  int
+	email string
+	name  string
+	err   error
+}
+
+// importRowReader reads the records of an import file in order, returning io.EOF after the last
+type importRowReader interface {
+	next() (importRow, error)
+}
+
+// newImportRowReader reads records of the given format from r
+func newImportRowReader(r io.Reader, format ImportFormat) (importRowReader, error) {
+	switch format {
+	case ImportFormatCSV:
+		return newCSVRowReader(r)
+	case ImportFormatNDJSON:
+		return newNDJSONRowReader(r), nil
+	default:
+		return nil, errors.ErrInvalidImportFile
+	}
+}
+
+// csvRowReader reads CSV records by the columns its header names; other columns are ignored
+type csvRowReader struct {
+	r       *csv.Reader
+	email   int
+	name    int
+	columns int
+}
+
+// newCSVRowReader reads the header and finds the email and name columns, in any order and case
+func newCSVRowReader(r io.Reader) (*csvRowReader, error) {
+	cr := csv.NewReader(r)
+	cr.FieldsPerRecord = -1
+	cr.TrimLeadingSpace = true
+
+	header, err := cr.Read()
+	if err == io.EOF {
+		return nil, invalidImportFile("The file is empty")
+	}
+	if err != nil {
+		return nil, invalidImportFile(err.Error())
+	}
+
+	c := &csvRowReader{r: cr, email: -1, name: -1, columns: len(header)}
+	for i, column := range header {
+		switch strings.ToLower(strings.TrimSpace(strings.TrimPrefix(column, utf8BOM))) {
+		case "email":
+			c.email = i
+		case "name":
+			c.name = i
+		}
+	}
+	if c.email < 0 || c.name < 0 {
+		return nil, invalidImportFile("The CSV header must name an email and a name column")
+	}
+	return c, nil
+}
+
+func (c *csvRowReader) next() (importRow, error) {
+	record, err := c.r.Read()
+	if err == io.EOF {
+		return importRow{}, io.EOF
+	}
+	if err != nil {
+		return importRow{}, invalidImportFile(err.Error())
+	}
+
+	line, _ := c.r.FieldPos(0)
+	if len(record) <= max(c.email, c.name) {
+		return importRow{line: line, err: invalidImportRow(
+			fmt.Sprintf("Row has %d columns but the header has %d", len(record), c.columns),
+		)}, nil
+	}
+	return importRow{line: line, email: record[c.email], name: record[c.name]}, nil
+}
+
+// ndjsonRowReader reads one JSON object per line; blank lines are skipped
+type ndjsonRowReader struct {
+	s    *bufio.Scanner
+	line int
+}
+
+func newNDJSONRowReader(r io.Reader) *ndjsonRowReader {
+	s := bufio.NewScanner(r)
+	s.Buffer(make([]byte, 0, 4096), maxImportLineBytes)
+	return &ndjsonRowReader{s: s}
+}
+
+func (n *ndjsonRowReader) next() (importRow, error) {
+	for n.s.Scan() {
+		n.line++
+		text := bytes.TrimSpace(n.s.Bytes())
+		if n.line == 1 {
+			text = bytes.TrimPrefix(text, []byte(utf8BOM))
+		}
+		if len(text) == 0 {
+			continue
+		}
+
+		var fields struct {
+			Email *string `json:"email"`
+			Name  *string `json:"name"`
+		}
+		if err := json.Unmarshal(text, &fields); err != nil || fields.Email == nil || fields.Name == nil {
+			return importRow{line: n.line, err: invalidImportRow("Row must be a JSON object with email and name strings")}, nil
+		}
+		return importRow{line: n.line, email: *fields.Email, name: *fields.Name}, nil
+	}
+
+	if err := n.s.Err(); err != nil {
+		if err == bufio.ErrTooLong {
+			return importRow{}, invalidImportFile(fmt.Sprintf("Line %d is longer than %d bytes", n.line+1, maxImportLineBytes))
+		}
+		return importRow{}, fmt.Errorf("failed to read import file: %w", err)
+	}
+	return importRow{}, io.EOF
+}
+
+// invalidImportFile explains why a file cannot be imported
+func invalidImportFile(message string) error {
+	err := errors.ErrInvalidImportFile
+	err.Message = message
+	return err
+}
+
+// invalidImportRow explains why a row cannot be read as a user
+func invalidImportRow(message string) error {
+	err := errors.ErrInvalidImportRow
+	err.Message = message
+	return err
+}
+
+// pendingUser is a valid row of the current window waiting to be inserted
+type pendingUser struct {
+	index int
+	user  *user.User
+}
+
+// importRun holds the state of one import
+// Rows collect in window until a batch is full; the valid ones among them are also in pending
+type importRun struct {
+	importer *Importer
+	report   *ImportReportDTO
+	seen     map[string]int // email -> line of the first valid row with it
+	window   []ImportRowResultDTO
+	pending  []pendingUser
+}
+
+// add validates a row and settles it unless it has to wait for the batch to be inserted
+func (run *importRun) add(row importRow) {
+	result := ImportRowResultDTO{Line: row.line, Email: strings.TrimSpace(row.email)}
+
+	err := row.err
+	var u *user.User
+	if err == nil {
+		u, err = user.NewUser(row.email, row.name)
+	}
+
+	switch {
+	case err != nil:
+		result.Status = ImportRowInvalid
+		result.Error = errorDTO(err)
+	case run.seen[u.Email().String()] > 0:
+		result.Status = ImportRowDuplicateInFile
+		result.Error = &ErrorDTO{
+			Code:    errors.ErrDuplicateEmail.Code,
+			Field:   errors.ErrDuplicateEmail.Field,
+			Message: fmt.Sprintf("Email already appears on line %d", run.seen[u.Email().String()]),
+		}
+	default:
+		run.seen[u.Email().String()] = row.line
+		run.pending = append(run.pending, pendingUser{index: len(run.window), user: u})
+	}
+
+	run.window = append(run.window, result)
+}
+
+// flush inserts the pending users of the window and adds its rows to the report
+// Every row of the window is settled, even when the store fails
+func (run *importRun) flush(ctx context.Context) error {
+	var err error
+	if len(run.pending) > 0 {
+		err = run.insert(ctx)
+	}
+
+	for _, row := range run.window {
+		if row.Status == ImportRowCreated {
+			run.report.Created++
+		} else {
+			run.report.Rejected++
+		}
+	}
+	run.report.Rows = append(run.report.Rows, run.window...)
+	return err
+}
+
+// insert creates the pending users whose emails are free
+func (run *importRun) insert(ctx context.Context) error {
+	im := run.importer
+
+	if err := ctx.Err(); err != nil {
+		run.fail(run.pending, err)
+		return err
+	}
+
+	emails := make([]user.Email, len(run.pending))
+	for i, p := range run.pending {
+		emails[i] = p.user.Email()
+	}
+	existing, err := im.repo.ExistingEmails(ctx, emails)
+	if err != nil {
+		im.logger.ErrorContext(ctx, "Failed to check imported emails", "error", err)
+		run.fail(run.pending, err)
+		return err
+	}
+
+	taken := make(map[string]bool, len(existing))
+	for _, email := range existing {
+		taken[email.String()] = true
+	}
+
+	var fresh []pendingUser
+	for _, p := range run.pending {
+		if taken[p.user.Email().String()] {
+			run.settle(p, ImportRowDuplicateExisting, errors.ErrDuplicateEmail)
+			continue
+		}
+		fresh = append(fresh, p)
+	}
+	if len(fresh) == 0 {
+		return nil
+	}
+
+	users := make([]*user.User, len(fresh))
+	for i, p := range fresh {
+		users[i] = p.user
+	}
+
+	switch err := im.repo.CreateBatch(ctx, users); err {
+	case nil:
+		for _, p := range fresh {
+			run.created(ctx, p)
+		}
+		return nil
+	case errors.ErrDuplicateEmail:
+		// An email was taken after it was checked; insert one at a time to tell which
+		im.logger.WarnContext(ctx, "Import batch raced another write; inserting its users one by one", "count", len(fresh))
+		for i, p := range fresh {
+			switch err := im.repo.CreateBatch(ctx, []*user.User{p.user}); err {
+			case nil:
+				run.created(ctx, p)
+			case errors.ErrDuplicateEmail:
+				run.settle(p, ImportRowDuplicateExisting, err)
+			default:
+				im.logger.ErrorContext(ctx, "Failed to create imported user", "error", err, "line", run.window[p.index].Line)
+				run.fail(fresh[i:], err)
+				return err
+			}
+		}
+		return nil
+	default:
+		im.logger.ErrorContext(ctx, "Failed to create imported users", "error", err, "count", len(fresh))
+		run.fail(fresh, err)
+		return err
+	}
+}
+
+// created settles a row whose user was inserted, dispatches the user's events and publishes userCreated
+func (run *importRun) created(ctx context.Context, p pendingUser) {
+	run.window[p.index].Status = ImportRowCreated
+	run.window[p.index].UserID = p.user.ID().String()
+
+	if err := run.importer.dispatcher.Dispatch(ctx, p.user.DrainEvents()...); err != nil {
+		run.importer.logger.ErrorContext(ctx, "Domain event handlers failed", "error", err, "userID", p.user.ID().String())
+	}
+
+	if run.importer.events != nil {
+		run.importer.events.Publish(&UserEventDTO{
+			Type:       EventUserCreated,
+			User:       mapDomainUserToDTO(p.user),
+			OccurredAt: time.Now().UTC(),
+		})
+	}
+}
+
+// settle records the outcome of a pending row that was not inserted
+func (run *importRun) settle(p pendingUser, status ImportRowStatus, err error) {
+	run.window[p.index].Status = status
+	run.window[p.index].Error = errorDTO(err)
+}
+
+// fail marks pending rows as failed by err
+func (run *importRun) fail(pending []pendingUser, err error) {
+	if err == context.Canceled || err == context.DeadlineExceeded {
+		err = errors.ErrImportInterrupted
+	}
+	for _, p := range pending {
+		run.settle(p, ImportRowFailed, err)
+	}
+}
+
+// errorDTO maps err to a DTO a row result can point to
+func errorDTO(err error) *ErrorDTO {
+	dto := mapDomainErrorToDTO(err)
+	return &dto
+}
+
+// countingReader counts the bytes read through it
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (c *countingReader) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	c.n += int64(n)
+	return n, err
+}
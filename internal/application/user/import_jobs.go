@@ -0,0 +1,246 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/captain-corgi/go-graphql-example/internal/domain/errors"
+	"github.com/google/uuid"
+)
+
+// maxFinishedImportJobs bounds how many finished jobs are remembered; the oldest are forgotten first
+const maxFinishedImportJobs = 100
+
+// ImportJobs runs imports in the background, one at a time, and remembers their progress
+// Uploads are spooled to temporary files so the request can end before its import does.
+// Jobs live in process memory: other instances do not see them and a restart forgets them
+type ImportJobs struct {
+	importer *Importer
+	ctx      context.Context
+	cancel   context.CancelFunc
+	slot     chan struct{}
+	wg       sync.WaitGroup
+	mu       sync.Mutex
+	jobs     map[string]*ImportJobDTO
+	order    []string // job IDs, oldest first
+	logger   *slog.Logger
+}
+
+// NewImportJobs creates an empty job registry running imports with importer
+func NewImportJobs(importer *Importer, logger *slog.Logger) *ImportJobs {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &ImportJobs{
+		importer: importer,
+		ctx:      ctx,
+		cancel:   cancel,
+		slot:     make(chan struct{}, 1),
+		jobs:     make(map[string]*ImportJobDTO),
+		logger:   logger,
+	}
+}
+
+// Start spools file and queues its import, returning the pending job
+// The job keeps the values of ctx, such as the caller's identity, but not its cancellation
+func (j *ImportJobs) Start(ctx context.Context, file io.Reader, fileName string, format ImportFormat) (*ImportJobDTO, error) {
+	if err := j.ctx.Err(); err != nil {
+		return nil, errors.ErrImportInterrupted
+	}
+
+	spool, err := os.CreateTemp("", "user-import-*")
+	if err != nil {
+		return nil, fmt.Errorf("failed to spool import file: %w", err)
+	}
+	size, err := io.Copy(spool, file)
+	if err == nil {
+		_, err = spool.Seek(0, io.SeekStart)
+	}
+	if err != nil {
+		spool.Close()
+		os.Remove(spool.Name())
+		return nil, fmt.Errorf("failed to spool import file: %w", err)
+	}
+
+	job := &ImportJobDTO{
+		ID:        uuid.NewString(),
+		FileName:  fileName,
+		Format:    format,
+		Status:    ImportJobPending,
+		Rows:      []ImportRowResultDTO{},
+		CreatedAt: time.Now().UTC(),
+	}
+
+	// Register under the lock Close takes, so no job starts once Close is waiting
+	j.mu.Lock()
+	if j.ctx.Err() != nil {
+		j.mu.Unlock()
+		spool.Close()
+		os.Remove(spool.Name())
+		return nil, errors.ErrImportInterrupted
+	}
+	j.jobs[job.ID] = job
+	j.order = append(j.order, job.ID)
+	started := *job
+	j.wg.Add(1)
+	j.mu.Unlock()
+
+	jobCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+	stop := context.AfterFunc(j.ctx, cancel)
+
+	go func() {
+		defer j.wg.Done()
+		defer cancel()
+		defer stop()
+		defer func() {
+			spool.Close()
+			os.Remove(spool.Name())
+		}()
+		j.run(jobCtx, job.ID, spool, size, format)
+	}()
+
+	j.logger.InfoContext(ctx, "Queued user import", "jobID", job.ID, "fileName", fileName, "format", format, "bytes", size)
+	return &started, nil
+}
+
+// Get returns a snapshot of the job with the given ID
+func (j *ImportJobs) Get(id string) (*ImportJobDTO, bool) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	job, ok := j.jobs[id]
+	if !ok {
+		return nil, false
+	}
+	// Rows only ever grow, so the snapshot may share their backing array
+	snapshot := *job
+	return &snapshot, true
+}
+
+// Close interrupts running and queued imports and waits for them to stop
+// Batches already inserted stay; the jobs are marked failed with IMPORT_INTERRUPTED
+func (j *ImportJobs) Close() {
+	j.mu.Lock()
+	j.cancel()
+	j.mu.Unlock()
+
+	j.wg.Wait()
+}
+
+// run waits for its turn and imports the spooled file
+func (j *ImportJobs) run(ctx context.Context, id string, file io.Reader, size int64, format ImportFormat) {
+	select {
+	case j.slot <- struct{}{}:
+		defer func() { <-j.slot }()
+	case <-ctx.Done():
+		j.finish(id, ctx.Err())
+		return
+	}
+
+	// Close cancels job contexts asynchronously, so a job let through by the one before it
+	// checks the registry itself
+	if err := j.ctx.Err(); err != nil {
+		j.finish(id, err)
+		return
+	}
+
+	j.update(id, func(job *ImportJobDTO) {
+		now := time.Now().UTC()
+		job.Status = ImportJobRunning
+		job.StartedAt = &now
+	})
+
+	_, err := j.importer.Import(ctx, file, format, func(rows []ImportRowResultDTO, bytesRead int64) {
+		j.update(id, func(job *ImportJobDTO) {
+			job.Rows = append(job.Rows, rows...)
+			for _, row := range rows {
+				if row.Status == ImportRowCreated {
+					job.Created++
+				} else {
+					job.Rejected++
+				}
+			}
+			if size > 0 {
+				job.Progress = min(float64(bytesRead)/float64(size), 1)
+			}
+		})
+	})
+	j.finish(id, err)
+}
+
+// finish records the outcome of a job and forgets the oldest finished jobs beyond the limit
+func (j *ImportJobs) finish(id string, err error) {
+	if err == context.Canceled {
+		err = errors.ErrImportInterrupted
+	}
+
+	j.update(id, func(job *ImportJobDTO) {
+		now := time.Now().UTC()
+		job.FinishedAt = &now
+		if err != nil {
+			job.Status = ImportJobFailed
+			job.Error = errorDTO(err)
+			return
+		}
+		job.Status = ImportJobCompleted
+		job.Progress = 1
+	})
+
+	if err != nil {
+		j.logger.Warn("User import failed", "jobID", id, "error", err)
+	} else {
+		j.logger.Info("User import completed", "jobID", id)
+	}
+
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	finished := 0
+	for _, jobID := range j.order {
+		if j.jobs[jobID].FinishedAt != nil {
+			finished++
+		}
+	}
+	kept := j.order[:0]
+	for _, jobID := range j.order {
+		if finished > maxFinishedImportJobs && j.jobs[jobID].FinishedAt != nil {
+			delete(j.jobs, jobID)
+			finished--
+			continue
+		}
+		kept = append(kept, jobID)
+	}
+	j.order = kept
+}
+
+// update changes a job under the registry lock
+func (j *ImportJobs) update(id string, change func(job *ImportJobDTO)) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+
+	if job, ok := j.jobs[id]; ok {
+		change(job)
+	}
+}
+
+// selectImportRows returns up to first rows from offset on, counting only rows with status when it is set
+func selectImportRows(rows []ImportRowResultDTO, status ImportRowStatus, first, offset int) []ImportRowResultDTO {
+	selected := []ImportRowResultDTO{}
+	for _, row := range rows {
+		if len(selected) == first {
+			break
+		}
+		if status != "" && row.Status != status {
+			continue
+		}
+		if offset > 0 {
+			offset--
+			continue
+		}
+		selected = append(selected, row)
+	}
+	return selected
+}
@@ -0,0 +1,420 @@
+package user
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/captain-corgi/go-graphql-example/internal/application/auth"
+	"github.com/captain-corgi/go-graphql-example/internal/application/unitofwork"
+	"github.com/captain-corgi/go-graphql-example/internal/domain/errors"
+	domainEvents "github.com/captain-corgi/go-graphql-example/internal/domain/events"
+	"github.com/captain-corgi/go-graphql-example/internal/domain/user"
+	"github.com/captain-corgi/go-graphql-example/internal/domain/user/mocks"
+)
+
+// newTestImporter returns an importer over a mock store
+func newTestImporter(ctrl *gomock.Controller, batchSize int) (*Importer, *mocks.MockImportRepository) {
+	repo := mocks.NewMockImportRepository(ctrl)
+	return NewImporter(repo, domainEvents.NewDomainEventDispatcher(slog.Default()), nil, batchSize, slog.Default()), repo
+}
+
+// rowStatuses lists the status of every row of a report
+func rowStatuses(report *ImportReportDTO) []ImportRowStatus {
+	statuses := make([]ImportRowStatus, len(report.Rows))
+	for i, row := range report.Rows {
+		statuses[i] = row.Status
+	}
+	return statuses
+}
+
+// emailsOf lists the emails of users
+func emailsOf(users []*user.User) []string {
+	emails := make([]string, len(users))
+	for i, u := range users {
+		emails[i] = u.Email().String()
+	}
+	return emails
+}
+
+func TestDetectImportFormat(t *testing.T) {
+	tests := []struct {
+		fileName    string
+		contentType string
+		want        ImportFormat
+		wantErr     error
+	}{
+		{"users.csv", "", ImportFormatCSV, nil},
+		{"USERS.CSV", "application/octet-stream", ImportFormatCSV, nil},
+		{"users.ndjson", "", ImportFormatNDJSON, nil},
+		{"users.jsonl", "", ImportFormatNDJSON, nil},
+		{"upload", "text/csv; charset=utf-8", ImportFormatCSV, nil},
+		{"upload", "application/x-ndjson", ImportFormatNDJSON, nil},
+		{"users.xlsx", "application/vnd.ms-excel", "", errors.ErrInvalidImportFile},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fileName+" "+tt.contentType, func(t *testing.T) {
+			got, err := DetectImportFormat(tt.fileName, tt.contentType)
+			assert.Equal(t, tt.wantErr, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestImporter_ImportCSV(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	importer, repo := newTestImporter(ctrl, 0)
+
+	file := "\ufeffName,Email,Team\n" +
+		"Alice,alice@example.com,red\n" +
+		"Bob,not-an-email,blue\n" +
+		"Alice Again,ALICE@example.com,red\n" +
+		"Carol,carol@example.com,green\n" +
+		"\"Dave\nDavidson\",dave@example.com\n" +
+		"Erin\n"
+
+	repo.EXPECT().ExistingEmails(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, emails []user.Email) ([]user.Email, error) {
+		require.Len(t, emails, 3)
+		return []user.Email{emails[1]}, nil
+	})
+	repo.EXPECT().CreateBatch(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, users []*user.User) error {
+		assert.Equal(t, []string{"alice@example.com", "dave@example.com"}, emailsOf(users))
+		return nil
+	})
+
+	report, err := importer.Import(ctx, strings.NewReader(file), ImportFormatCSV, nil)
+	require.NoError(t, err)
+
+	assert.Equal(t, []ImportRowStatus{
+		ImportRowCreated,
+		ImportRowInvalid,
+		ImportRowDuplicateInFile,
+		ImportRowDuplicateExisting,
+		ImportRowCreated,
+		ImportRowInvalid,
+	}, rowStatuses(report))
+	assert.Equal(t, 2, report.Created)
+	assert.Equal(t, 4, report.Rejected)
+
+	// Lines count from the header, and a quoted line break keeps the row on the line it starts
+	assert.Equal(t, 2, report.Rows[0].Line)
+	assert.Equal(t, 6, report.Rows[4].Line)
+	assert.Equal(t, 8, report.Rows[5].Line)
+
+	assert.NotEmpty(t, report.Rows[0].UserID)
+	assert.Nil(t, report.Rows[0].Error)
+	assert.Equal(t, "INVALID_EMAIL", report.Rows[1].Error.Code)
+	assert.Equal(t, "Email already appears on line 2", report.Rows[2].Error.Message)
+	assert.Equal(t, "DUPLICATE_EMAIL", report.Rows[3].Error.Code)
+	assert.Equal(t, "INVALID_IMPORT_ROW", report.Rows[5].Error.Code)
+}
+
+func TestImporter_ImportNDJSONInBatches(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	importer, repo := newTestImporter(ctrl, 2)
+
+	file := `{"email": "a@example.com", "name": "A"}` + "\n" +
+		"\n" +
+		`{"email": "b@example.com"}` + "\n" +
+		`not json` + "\n" +
+		`{"email": "c@example.com", "name": "C", "role": "admin"}` + "\n"
+
+	repo.EXPECT().ExistingEmails(ctx, gomock.Any()).Return(nil, nil).Times(2)
+	var batches [][]string
+	repo.EXPECT().CreateBatch(ctx, gomock.Any()).DoAndReturn(func(_ context.Context, users []*user.User) error {
+		batches = append(batches, emailsOf(users))
+		return nil
+	}).Times(2)
+
+	var progressed []int
+	report, err := importer.Import(ctx, strings.NewReader(file), ImportFormatNDJSON, func(rows []ImportRowResultDTO, bytesRead int64) {
+		progressed = append(progressed, len(rows))
+		assert.Positive(t, bytesRead)
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, [][]string{{"a@example.com"}, {"c@example.com"}}, batches)
+	assert.Equal(t, []int{2, 2}, progressed)
+	assert.Equal(t, []ImportRowStatus{ImportRowCreated, ImportRowInvalid, ImportRowInvalid, ImportRowCreated}, rowStatuses(report))
+	assert.Equal(t, []int{1, 3, 4, 5}, []int{report.Rows[0].Line, report.Rows[1].Line, report.Rows[2].Line, report.Rows[3].Line})
+}
+
+func TestImporter_ImportRetriesRacedBatchOneByOne(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	importer, repo := newTestImporter(ctrl, 0)
+
+	file := "email,name\nfirst@example.com,First\nraced@example.com,Raced\n"
+
+	repo.EXPECT().ExistingEmails(ctx, gomock.Any()).Return(nil, nil)
+	gomock.InOrder(
+		repo.EXPECT().CreateBatch(ctx, gomock.Len(2)).Return(errors.ErrDuplicateEmail),
+		repo.EXPECT().CreateBatch(ctx, gomock.Len(1)).Return(nil),
+		repo.EXPECT().CreateBatch(ctx, gomock.Len(1)).Return(errors.ErrDuplicateEmail),
+	)
+
+	report, err := importer.Import(ctx, strings.NewReader(file), ImportFormatCSV, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []ImportRowStatus{ImportRowCreated, ImportRowDuplicateExisting}, rowStatuses(report))
+}
+
+func TestImporter_ImportStopsWhenTheStoreFails(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	importer, repo := newTestImporter(ctrl, 1)
+
+	file := "email,name\na@example.com,A\nb@example.com,B\nc@example.com,C\n"
+
+	repo.EXPECT().ExistingEmails(ctx, gomock.Any()).Return(nil, nil).Times(2)
+	gomock.InOrder(
+		repo.EXPECT().CreateBatch(ctx, gomock.Any()).Return(nil),
+		repo.EXPECT().CreateBatch(ctx, gomock.Any()).Return(fmt.Errorf("connection refused")),
+	)
+
+	report, err := importer.Import(ctx, strings.NewReader(file), ImportFormatCSV, nil)
+	require.Error(t, err)
+	assert.Equal(t, []ImportRowStatus{ImportRowCreated, ImportRowFailed}, rowStatuses(report))
+	assert.Equal(t, "INTERNAL_ERROR", report.Rows[1].Error.Code)
+}
+
+func TestImporter_ImportRejectsUnreadableFiles(t *testing.T) {
+	tests := []struct {
+		name   string
+		file   string
+		format ImportFormat
+	}{
+		{"empty", "", ImportFormatCSV},
+		{"missing column", "email,full_name\na@example.com,A\n", ImportFormatCSV},
+		{"line too long", strings.Repeat("x", maxImportLineBytes+1), ImportFormatNDJSON},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			importer, _ := newTestImporter(ctrl, 0)
+			_, err := importer.Import(context.Background(), strings.NewReader(tt.file), tt.format, nil)
+
+			domainErr, ok := err.(errors.DomainError)
+			require.True(t, ok, "want a domain error, got %v", err)
+			assert.Equal(t, errors.ErrInvalidImportFile.Code, domainErr.Code)
+		})
+	}
+}
+
+func TestImporter_ImportsRowsBeforeABrokenRecord(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	importer, repo := newTestImporter(ctrl, 0)
+
+	file := "email,name\na@example.com,A\nb@example.com,\"B\n"
+
+	repo.EXPECT().ExistingEmails(ctx, gomock.Any()).Return(nil, nil)
+	repo.EXPECT().CreateBatch(ctx, gomock.Len(1)).Return(nil)
+
+	report, err := importer.Import(ctx, strings.NewReader(file), ImportFormatCSV, nil)
+	assert.Equal(t, errors.ErrInvalidImportFile.Code, err.(errors.DomainError).Code)
+	assert.Equal(t, []ImportRowStatus{ImportRowCreated}, rowStatuses(report))
+}
+
+func TestImportJobs_RunsJobsInTheBackground(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	importer, repo := newTestImporter(ctrl, 0)
+	repo.EXPECT().ExistingEmails(gomock.Any(), gomock.Any()).Return(nil, nil)
+	repo.EXPECT().CreateBatch(gomock.Any(), gomock.Any()).Return(nil)
+
+	jobs := NewImportJobs(importer, slog.Default())
+	defer jobs.Close()
+
+	started, err := jobs.Start(context.Background(), strings.NewReader("email,name\na@example.com,A\nbad,B\n"), "users.csv", ImportFormatCSV)
+	require.NoError(t, err)
+	assert.Equal(t, ImportJobPending, started.Status)
+	assert.Equal(t, "users.csv", started.FileName)
+
+	var job *ImportJobDTO
+	require.Eventually(t, func() bool {
+		job, _ = jobs.Get(started.ID)
+		return job.Status == ImportJobCompleted
+	}, 5*time.Second, 10*time.Millisecond)
+
+	assert.Equal(t, 1.0, job.Progress)
+	assert.Equal(t, 1, job.Created)
+	assert.Equal(t, 1, job.Rejected)
+	assert.Len(t, job.Rows, 2)
+	assert.NotNil(t, job.StartedAt)
+	assert.NotNil(t, job.FinishedAt)
+
+	_, ok := jobs.Get("unknown")
+	assert.False(t, ok)
+}
+
+func TestImportJobs_CloseInterruptsJobs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	importer, repo := newTestImporter(ctrl, 0)
+	release := make(chan struct{})
+	repo.EXPECT().ExistingEmails(gomock.Any(), gomock.Any()).DoAndReturn(func(ctx context.Context, _ []user.Email) ([]user.Email, error) {
+		close(release)
+		<-ctx.Done()
+		return nil, ctx.Err()
+	})
+
+	jobs := NewImportJobs(importer, slog.Default())
+	first, err := jobs.Start(context.Background(), strings.NewReader("email,name\na@example.com,A\n"), "first.csv", ImportFormatCSV)
+	require.NoError(t, err)
+	<-release
+
+	// The second job waits behind the first
+	second, err := jobs.Start(context.Background(), strings.NewReader("email,name\nb@example.com,B\n"), "second.csv", ImportFormatCSV)
+	require.NoError(t, err)
+
+	jobs.Close()
+
+	for _, id := range []string{first.ID, second.ID} {
+		job, ok := jobs.Get(id)
+		require.True(t, ok)
+		assert.Equal(t, ImportJobFailed, job.Status)
+		assert.Equal(t, errors.ErrImportInterrupted.Code, job.Error.Code)
+	}
+
+	_, err = jobs.Start(context.Background(), strings.NewReader(""), "late.csv", ImportFormatCSV)
+	assert.Equal(t, errors.ErrImportInterrupted, err)
+}
+
+func TestService_ImportUsers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	logger := slog.Default()
+	dispatcher := domainEvents.NewDomainEventDispatcher(logger)
+	broker := &recordingBroker{}
+	repo := mocks.NewMockImportRepository(ctrl)
+	repo.EXPECT().ExistingEmails(gomock.Any(), gomock.Any()).Return(nil, nil).AnyTimes()
+	repo.EXPECT().CreateBatch(gomock.Any(), gomock.Any()).Return(nil).AnyTimes()
+
+	jobs := NewImportJobs(NewImporter(repo, dispatcher, broker, 0, logger), logger)
+	defer jobs.Close()
+
+	service := NewService(mocks.NewMockRepository(ctrl), nil, jobs, NewCursorCodec([]byte(testCursorSecret)), broker, dispatcher, unitofwork.Passthrough{}, logger)
+	admin := auth.WithPrincipal(context.Background(), auth.Principal{Roles: []auth.Role{auth.RoleAdmin}})
+	file := "email,name\na@example.com,A\n"
+
+	t.Run("non-admin callers are refused", func(t *testing.T) {
+		resp, err := service.ImportUsers(context.Background(), ImportUsersRequest{File: strings.NewReader(file), FileName: "users.csv"})
+		require.NoError(t, err)
+		assert.Nil(t, resp.Job)
+		assert.Equal(t, []ErrorDTO{mapDomainErrorToDTO(errors.ErrForbidden)}, resp.Errors)
+
+		getResp, err := service.GetImportJob(context.Background(), GetImportJobRequest{ID: "any"})
+		require.NoError(t, err)
+		assert.Equal(t, []ErrorDTO{mapDomainErrorToDTO(errors.ErrForbidden)}, getResp.Errors)
+	})
+
+	t.Run("unsupported files are refused", func(t *testing.T) {
+		resp, err := service.ImportUsers(admin, ImportUsersRequest{File: strings.NewReader(file), FileName: "users.xlsx"})
+		require.NoError(t, err)
+		assert.Equal(t, []ErrorDTO{mapDomainErrorToDTO(errors.ErrInvalidImportFile)}, resp.Errors)
+	})
+
+	t.Run("admins start jobs and follow them", func(t *testing.T) {
+		resp, err := service.ImportUsers(admin, ImportUsersRequest{File: strings.NewReader(file), FileName: "upload", ContentType: "text/csv"})
+		require.NoError(t, err)
+		require.Empty(t, resp.Errors)
+		assert.Equal(t, ImportFormatCSV, resp.Job.Format)
+
+		require.Eventually(t, func() bool {
+			getResp, err := service.GetImportJob(admin, GetImportJobRequest{ID: resp.Job.ID})
+			return err == nil && getResp.Job != nil && getResp.Job.Status == ImportJobCompleted
+		}, 5*time.Second, 10*time.Millisecond)
+
+		getResp, err := service.GetImportJob(admin, GetImportJobRequest{ID: resp.Job.ID})
+		require.NoError(t, err)
+		assert.Empty(t, getResp.Job.Rows)
+		assert.Equal(t, 1, getResp.Job.Created)
+
+		getResp, err = service.GetImportJob(admin, GetImportJobRequest{ID: resp.Job.ID, RowStatus: "CREATED", RowsFirst: 10})
+		require.NoError(t, err)
+		require.Len(t, getResp.Job.Rows, 1)
+		assert.Equal(t, "a@example.com", getResp.Job.Rows[0].Email)
+
+		getResp, err = service.GetImportJob(admin, GetImportJobRequest{ID: resp.Job.ID, RowStatus: "INVALID", RowsFirst: 10})
+		require.NoError(t, err)
+		assert.Empty(t, getResp.Job.Rows)
+
+		getResp, err = service.GetImportJob(admin, GetImportJobRequest{ID: resp.Job.ID, RowsFirst: 1001})
+		require.NoError(t, err)
+		assert.Equal(t, "INVALID_FIRST", getResp.Errors[0].Code)
+
+		// Imported users reach userCreated subscribers like created ones
+		events := broker.events()
+		require.Len(t, events, 1)
+		assert.Equal(t, EventUserCreated, events[0].Type)
+		assert.Equal(t, "a@example.com", events[0].User.Email)
+	})
+
+	t.Run("unknown jobs are not found", func(t *testing.T) {
+		resp, err := service.GetImportJob(admin, GetImportJobRequest{ID: "missing"})
+		require.NoError(t, err)
+		assert.Equal(t, []ErrorDTO{mapDomainErrorToDTO(errors.ErrImportJobNotFound)}, resp.Errors)
+	})
+
+	t.Run("disabled imports are refused", func(t *testing.T) {
+		disabled := NewService(mocks.NewMockRepository(ctrl), nil, nil, NewCursorCodec([]byte(testCursorSecret)), broker, dispatcher, unitofwork.Passthrough{}, logger)
+
+		resp, err := disabled.ImportUsers(admin, ImportUsersRequest{File: strings.NewReader(file), FileName: "users.csv"})
+		require.NoError(t, err)
+		assert.Nil(t, resp.Job)
+		assert.Equal(t, []ErrorDTO{mapDomainErrorToDTO(errors.ErrImportsDisabled)}, resp.Errors)
+
+		getResp, err := disabled.GetImportJob(admin, GetImportJobRequest{ID: "5b0c0a8e-8f53-4a43-9d0c-0f1d6f4b1f7e"})
+		require.NoError(t, err)
+		assert.Equal(t, []ErrorDTO{mapDomainErrorToDTO(errors.ErrImportsDisabled)}, getResp.Errors)
+	})
+}
+
+func TestSelectImportRows(t *testing.T) {
+	rows := []ImportRowResultDTO{
+		{Line: 2, Status: ImportRowCreated},
+		{Line: 3, Status: ImportRowInvalid},
+		{Line: 4, Status: ImportRowCreated},
+		{Line: 5, Status: ImportRowInvalid},
+		{Line: 6, Status: ImportRowCreated},
+	}
+
+	lines := func(rows []ImportRowResultDTO) []int {
+		out := []int{}
+		for _, row := range rows {
+			out = append(out, row.Line)
+		}
+		return out
+	}
+
+	assert.Equal(t, []int{2, 3}, lines(selectImportRows(rows, "", 2, 0)))
+	assert.Equal(t, []int{4, 5, 6}, lines(selectImportRows(rows, "", 10, 2)))
+	assert.Equal(t, []int{4, 6}, lines(selectImportRows(rows, ImportRowCreated, 10, 1)))
+	assert.Equal(t, []int{}, lines(selectImportRows(rows, "", 0, 0)))
+}
@@ -1,6 +1,9 @@
 package user
 
 import (
+	"html"
+	"strings"
+
 	"github.com/captain-corgi/go-graphql-example/internal/domain/errors"
 	"github.com/captain-corgi/go-graphql-example/internal/domain/user"
 )
@@ -17,9 +20,45 @@ func mapDomainUserToDTO(domainUser *user.User) *UserDTO {
 		Name:      domainUser.Name().String(),
 		CreatedAt: domainUser.CreatedAt(),
 		UpdatedAt: domainUser.UpdatedAt(),
+		Status:    string(domainUser.Status()),
+		Version:   domainUser.Version(),
+		DeletedAt: domainUser.DeletedAt(),
+	}
+}
+
+// mapStatusTransitionToDTO converts a domain status transition to a StatusTransitionDTO
+func mapStatusTransitionToDTO(t user.StatusTransition) *StatusTransitionDTO {
+	return &StatusTransitionDTO{
+		From:       string(t.From),
+		To:         string(t.To),
+		Actor:      t.Actor,
+		Reason:     t.Reason,
+		OccurredAt: t.OccurredAt,
 	}
 }
 
+// mapHighlightToDTO renders text with the matched spans wrapped in <mark> tags and everything
+// else HTML-escaped, so clients can show it as markup; nil when nothing matched
+func mapHighlightToDTO(text string, spans []user.Span) *string {
+	if len(spans) == 0 {
+		return nil
+	}
+
+	var b strings.Builder
+	last := 0
+	for _, span := range spans {
+		b.WriteString(html.EscapeString(text[last:span.Start]))
+		b.WriteString("<mark>")
+		b.WriteString(html.EscapeString(text[span.Start:span.End]))
+		b.WriteString("</mark>")
+		last = span.End
+	}
+	b.WriteString(html.EscapeString(text[last:]))
+
+	highlighted := b.String()
+	return &highlighted
+}
+
 // mapDomainErrorToDTO converts a domain error to an ErrorDTO
 func mapDomainErrorToDTO(err error) ErrorDTO {
 	if err == nil {
@@ -27,7 +66,15 @@ func mapDomainErrorToDTO(err error) ErrorDTO {
 	}
 
 	// Check if it's a domain error
-	if domainErr, ok := err.(errors.DomainError); ok {
+	switch domainErr := err.(type) {
+	case errors.ConflictError:
+		currentVersion := domainErr.CurrentVersion
+		return ErrorDTO{
+			Message:        domainErr.Message,
+			Code:           domainErr.Code,
+			CurrentVersion: &currentVersion,
+		}
+	case errors.DomainError:
 		return ErrorDTO{
 			Message: domainErr.Message,
 			Field:   domainErr.Field,
@@ -41,3 +88,27 @@ func mapDomainErrorToDTO(err error) ErrorDTO {
 		Code:    "INTERNAL_ERROR",
 	}
 }
+
+// mapStringFilter converts a StringFilterDTO to a domain text filter
+func mapStringFilter(dto *StringFilterDTO) *user.TextFilter {
+	if dto == nil {
+		return nil
+	}
+
+	return &user.TextFilter{
+		Equals:   dto.Equals,
+		Contains: dto.Contains,
+	}
+}
+
+// mapTimeRange converts a TimeRangeDTO to a domain time range
+func mapTimeRange(dto *TimeRangeDTO) *user.TimeRange {
+	if dto == nil {
+		return nil
+	}
+
+	return &user.TimeRange{
+		From: dto.From,
+		To:   dto.To,
+	}
+}
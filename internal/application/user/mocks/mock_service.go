@@ -35,6 +35,21 @@ func (m *MockService) EXPECT() *MockServiceMockRecorder {
 	return m.recorder
 }
 
+// ChangeUserStatus mocks base method.
+func (m *MockService) ChangeUserStatus(ctx context.Context, req user.ChangeUserStatusRequest) (*user.ChangeUserStatusResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ChangeUserStatus", ctx, req)
+	ret0, _ := ret[0].(*user.ChangeUserStatusResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ChangeUserStatus indicates an expected call of ChangeUserStatus.
+func (mr *MockServiceMockRecorder) ChangeUserStatus(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ChangeUserStatus", reflect.TypeOf((*MockService)(nil).ChangeUserStatus), ctx, req)
+}
+
 // CreateUser mocks base method.
 func (m *MockService) CreateUser(ctx context.Context, req user.CreateUserRequest) (*user.CreateUserResponse, error) {
 	m.ctrl.T.Helper()
@@ -65,6 +80,21 @@ func (mr *MockServiceMockRecorder) DeleteUser(ctx, req interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "DeleteUser", reflect.TypeOf((*MockService)(nil).DeleteUser), ctx, req)
 }
 
+// GetImportJob mocks base method.
+func (m *MockService) GetImportJob(ctx context.Context, req user.GetImportJobRequest) (*user.GetImportJobResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetImportJob", ctx, req)
+	ret0, _ := ret[0].(*user.GetImportJobResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetImportJob indicates an expected call of GetImportJob.
+func (mr *MockServiceMockRecorder) GetImportJob(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetImportJob", reflect.TypeOf((*MockService)(nil).GetImportJob), ctx, req)
+}
+
 // GetUser mocks base method.
 func (m *MockService) GetUser(ctx context.Context, req user.GetUserRequest) (*user.GetUserResponse, error) {
 	m.ctrl.T.Helper()
@@ -80,6 +110,51 @@ func (mr *MockServiceMockRecorder) GetUser(ctx, req interface{}) *gomock.Call {
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUser", reflect.TypeOf((*MockService)(nil).GetUser), ctx, req)
 }
 
+// GetUserStats mocks base method.
+func (m *MockService) GetUserStats(ctx context.Context, req user.GetUserStatsRequest) (*user.GetUserStatsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUserStats", ctx, req)
+	ret0, _ := ret[0].(*user.GetUserStatsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUserStats indicates an expected call of GetUserStats.
+func (mr *MockServiceMockRecorder) GetUserStats(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUserStats", reflect.TypeOf((*MockService)(nil).GetUserStats), ctx, req)
+}
+
+// GetUsersByIDs mocks base method.
+func (m *MockService) GetUsersByIDs(ctx context.Context, req user.GetUsersByIDsRequest) (*user.GetUsersByIDsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "GetUsersByIDs", ctx, req)
+	ret0, _ := ret[0].(*user.GetUsersByIDsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// GetUsersByIDs indicates an expected call of GetUsersByIDs.
+func (mr *MockServiceMockRecorder) GetUsersByIDs(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "GetUsersByIDs", reflect.TypeOf((*MockService)(nil).GetUsersByIDs), ctx, req)
+}
+
+// ImportUsers mocks base method.
+func (m *MockService) ImportUsers(ctx context.Context, req user.ImportUsersRequest) (*user.ImportUsersResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ImportUsers", ctx, req)
+	ret0, _ := ret[0].(*user.ImportUsersResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ImportUsers indicates an expected call of ImportUsers.
+func (mr *MockServiceMockRecorder) ImportUsers(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ImportUsers", reflect.TypeOf((*MockService)(nil).ImportUsers), ctx, req)
+}
+
 // ListUsers mocks base method.
 func (m *MockService) ListUsers(ctx context.Context, req user.ListUsersRequest) (*user.ListUsersResponse, error) {
 	m.ctrl.T.Helper()
@@ -95,6 +170,66 @@ func (mr *MockServiceMockRecorder) ListUsers(ctx, req interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ListUsers", reflect.TypeOf((*MockService)(nil).ListUsers), ctx, req)
 }
 
+// PurgeUser mocks base method.
+func (m *MockService) PurgeUser(ctx context.Context, req user.PurgeUserRequest) (*user.PurgeUserResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "PurgeUser", ctx, req)
+	ret0, _ := ret[0].(*user.PurgeUserResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// PurgeUser indicates an expected call of PurgeUser.
+func (mr *MockServiceMockRecorder) PurgeUser(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "PurgeUser", reflect.TypeOf((*MockService)(nil).PurgeUser), ctx, req)
+}
+
+// RestoreUser mocks base method.
+func (m *MockService) RestoreUser(ctx context.Context, req user.RestoreUserRequest) (*user.RestoreUserResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "RestoreUser", ctx, req)
+	ret0, _ := ret[0].(*user.RestoreUserResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// RestoreUser indicates an expected call of RestoreUser.
+func (mr *MockServiceMockRecorder) RestoreUser(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "RestoreUser", reflect.TypeOf((*MockService)(nil).RestoreUser), ctx, req)
+}
+
+// SearchUsers mocks base method.
+func (m *MockService) SearchUsers(ctx context.Context, req user.SearchUsersRequest) (*user.SearchUsersResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SearchUsers", ctx, req)
+	ret0, _ := ret[0].(*user.SearchUsersResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SearchUsers indicates an expected call of SearchUsers.
+func (mr *MockServiceMockRecorder) SearchUsers(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SearchUsers", reflect.TypeOf((*MockService)(nil).SearchUsers), ctx, req)
+}
+
+// SubscribeUserEvents mocks base method.
+func (m *MockService) SubscribeUserEvents(ctx context.Context, req user.SubscribeUserEventsRequest) (*user.SubscribeUserEventsResponse, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "SubscribeUserEvents", ctx, req)
+	ret0, _ := ret[0].(*user.SubscribeUserEventsResponse)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// SubscribeUserEvents indicates an expected call of SubscribeUserEvents.
+func (mr *MockServiceMockRecorder) SubscribeUserEvents(ctx, req interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "SubscribeUserEvents", reflect.TypeOf((*MockService)(nil).SubscribeUserEvents), ctx, req)
+}
+
 // UpdateUser mocks base method.
 func (m *MockService) UpdateUser(ctx context.Context, req user.UpdateUserRequest) (*user.UpdateUserResponse, error) {
 	m.ctrl.T.Helper()
@@ -3,8 +3,13 @@ package user
 import (
 	"context"
 	"log/slog"
+	"strings"
+	"time"
 
+	"github.com/captain-corgi/go-graphql-example/internal/application/auth"
+	"github.com/captain-corgi/go-graphql-example/internal/application/unitofwork"
 	"github.com/captain-corgi/go-graphql-example/internal/domain/errors"
+	domainEvents "github.com/captain-corgi/go-graphql-example/internal/domain/events"
 	"github.com/captain-corgi/go-graphql-example/internal/domain/user"
 )
 
@@ -15,30 +20,83 @@ type Service interface {
 	// GetUser retrieves a user by ID
 	GetUser(ctx context.Context, req GetUserRequest) (*GetUserResponse, error)
 
+	// GetUsersByIDs retrieves several users by ID in a single repository call
+	GetUsersByIDs(ctx context.Context, req GetUsersByIDsRequest) (*GetUsersByIDsResponse, error)
+
 	// ListUsers retrieves a paginated list of users
 	ListUsers(ctx context.Context, req ListUsersRequest) (*ListUsersResponse, error)
 
+	// SearchUsers ranks live users by how well their name and email match free text, best first
+	SearchUsers(ctx context.Context, req SearchUsersRequest) (*SearchUsersResponse, error)
+
+	// GetUserStats retrieves signup statistics bucketed by day, week or month
+	GetUserStats(ctx context.Context, req GetUserStatsRequest) (*GetUserStatsResponse, error)
+
 	// CreateUser creates a new user
 	CreateUser(ctx context.Context, req CreateUserRequest) (*CreateUserResponse, error)
 
 	// UpdateUser updates an existing user
 	UpdateUser(ctx context.Context, req UpdateUserRequest) (*UpdateUserResponse, error)
 
-	// DeleteUser deletes a user by ID
+	// DeleteUser soft-deletes a user by ID; the user can be restored until purged
 	DeleteUser(ctx context.Context, req DeleteUserRequest) (*DeleteUserResponse, error)
+
+	// RestoreUser brings a soft-deleted user back
+	RestoreUser(ctx context.Context, req RestoreUserRequest) (*RestoreUserResponse, error)
+
+	// PurgeUser permanently removes a user, deleted or not; callers must be admins
+	PurgeUser(ctx context.Context, req PurgeUserRequest) (*PurgeUserResponse, error)
+
+	// ChangeUserStatus moves a user to another account status, recording who did it and why;
+	// callers must be admins
+	ChangeUserStatus(ctx context.Context, req ChangeUserStatusRequest) (*ChangeUserStatusResponse, error)
+
+	// SubscribeUserEvents streams user lifecycle events published after successful mutations
+	SubscribeUserEvents(ctx context.Context, req SubscribeUserEventsRequest) (*SubscribeUserEventsResponse, error)
+
+	// ImportUsers starts importing users from a CSV or NDJSON file in the background and returns
+	// the job to follow it by; callers must be admins
+	ImportUsers(ctx context.Context, req ImportUsersRequest) (*ImportUsersResponse, error)
+
+	// GetImportJob reports the progress and row results of an import; callers must be admins
+	GetImportJob(ctx context.Context, req GetImportJobRequest) (*GetImportJobResponse, error)
 }
 
 // service implements the Service interface
 type service struct {
-	userRepo user.Repository
-	logger   *slog.Logger
+	userRepo   user.Repository
+	search     user.SearchRepository
+	imports    *ImportJobs
+	cursors    *CursorCodec
+	events     EventBroker
+	dispatcher *domainEvents.DomainEventDispatcher
+	uow        unitofwork.UnitOfWork
+	logger     *slog.Logger
 }
 
 // NewService creates a new user service
-func NewService(userRepo user.Repository, logger *slog.Logger) Service {
+// Domain events raised by users are handed to dispatcher after the changes are persisted;
+// multi-step mutations run inside uow so their reads and writes are atomic. Imports run on imports;
+// a nil imports disables them
+func NewService(
+	userRepo user.Repository,
+	search user.SearchRepository,
+	imports *ImportJobs,
+	cursors *CursorCodec,
+	events EventBroker,
+	dispatcher *domainEvents.DomainEventDispatcher,
+	uow unitofwork.UnitOfWork,
+	logger *slog.Logger,
+) Service {
 	return &service{
-		userRepo: userRepo,
-		logger:   logger,
+		userRepo:   userRepo,
+		search:     search,
+		imports:    imports,
+		cursors:    cursors,
+		events:     events,
+		dispatcher: dispatcher,
+		uow:        uow,
+		logger:     logger,
 	}
 }
 
@@ -78,9 +136,46 @@ func (s *service) GetUser(ctx context.Context, req GetUserRequest) (*GetUserResp
 	}, nil
 }
 
+// GetUsersByIDs retrieves several users by ID in a single repository call
+func (s *service) GetUsersByIDs(ctx context.Context, req GetUsersByIDsRequest) (*GetUsersByIDsResponse, error) {
+	s.logger.InfoContext(ctx, "Getting users by IDs", "count", len(req.IDs))
+
+	// Convert string IDs to domain UserIDs
+	ids := make([]user.UserID, len(req.IDs))
+	for i, raw := range req.IDs {
+		id, err := user.NewUserID(raw)
+		if err != nil {
+			s.logger.WarnContext(ctx, "Invalid user ID format", "error", err, "userID", raw)
+			return &GetUsersByIDsResponse{
+				Errors: []ErrorDTO{mapDomainErrorToDTO(err)},
+			}, nil
+		}
+		ids[i] = id
+	}
+
+	// Retrieve users from repository
+	domainUsers, err := s.userRepo.FindByIDs(ctx, ids)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to get users from repository", "error", err)
+		return &GetUsersByIDsResponse{
+			Errors: []ErrorDTO{mapDomainErrorToDTO(err)},
+		}, nil
+	}
+
+	users := make([]*UserDTO, len(domainUsers))
+	for i, u := range domainUsers {
+		users[i] = mapDomainUserToDTO(u)
+	}
+
+	s.logger.InfoContext(ctx, "Successfully retrieved users by IDs", "requested", len(req.IDs), "found", len(users))
+	return &GetUsersByIDsResponse{
+		Users: users,
+	}, nil
+}
+
 // ListUsers retrieves a paginated list of users
 func (s *service) ListUsers(ctx context.Context, req ListUsersRequest) (*ListUsersResponse, error) {
-	s.logger.InfoContext(ctx, "Listing users", "first", req.First, "after", req.After)
+	s.logger.InfoContext(ctx, "Listing users", "first", req.First, "after", req.After, "last", req.Last, "before", req.Before)
 
 	// Validate request
 	if err := s.validateListUsersRequest(req); err != nil {
@@ -90,14 +185,26 @@ func (s *service) ListUsers(ctx context.Context, req ListUsersRequest) (*ListUse
 		}, nil
 	}
 
-	// Set default limit if not provided
-	limit := req.First
-	if limit == 0 {
-		limit = 10 // Default page size
+	// Translate filter and ordering into domain criteria
+	criteria, err := s.buildCriteria(req)
+	if err != nil {
+		s.logger.WarnContext(ctx, "Invalid list users criteria", "error", err)
+		return &ListUsersResponse{
+			Errors: []ErrorDTO{mapDomainErrorToDTO(err)},
+		}, nil
+	}
+
+	// Build the keyset page request from the opaque cursors
+	page, err := s.buildPageRequest(req, criteria.Sort)
+	if err != nil {
+		s.logger.WarnContext(ctx, "Invalid pagination cursor", "error", err)
+		return &ListUsersResponse{
+			Errors: []ErrorDTO{mapDomainErrorToDTO(err)},
+		}, nil
 	}
 
 	// Retrieve users from repository
-	domainUsers, nextCursor, err := s.userRepo.FindAll(ctx, limit+1, req.After) // +1 to check if there's a next page
+	result, err := s.userRepo.FindAll(ctx, criteria, page)
 	if err != nil {
 		s.logger.ErrorContext(ctx, "Failed to list users from repository", "error", err)
 		return &ListUsersResponse{
@@ -106,7 +213,18 @@ func (s *service) ListUsers(ctx context.Context, req ListUsersRequest) (*ListUse
 	}
 
 	// Build connection response
-	connection := s.buildUserConnection(domainUsers, limit, req.After, nextCursor)
+	connection := s.buildUserConnection(result, criteria.Sort)
+
+	if req.IncludeTotalCount {
+		total, err := s.userRepo.Count(ctx, criteria.Filter)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "Failed to count users in repository", "error", err)
+			return &ListUsersResponse{
+				Errors: []ErrorDTO{mapDomainErrorToDTO(err)},
+			}, nil
+		}
+		connection.TotalCount = &total
+	}
 
 	s.logger.InfoContext(ctx, "Successfully listed users", "count", len(connection.Edges))
 	return &ListUsersResponse{
@@ -114,62 +232,169 @@ func (s *service) ListUsers(ctx context.Context, req ListUsersRequest) (*ListUse
 	}, nil
 }
 
-// CreateUser creates a new user
-func (s *service) CreateUser(ctx context.Context, req CreateUserRequest) (*CreateUserResponse, error) {
-	s.logger.InfoContext(ctx, "Creating user", "email", req.Email, "name", req.Name)
+// SearchUsers ranks live users by how well their name and email match free text, best first
+func (s *service) SearchUsers(ctx context.Context, req SearchUsersRequest) (*SearchUsersResponse, error) {
+	s.logger.InfoContext(ctx, "Searching users", "query", req.Query, "first", req.First, "after", req.After)
 
 	// Validate request
-	if err := s.validateCreateUserRequest(req); err != nil {
-		s.logger.WarnContext(ctx, "Invalid create user request", "error", err)
-		return &CreateUserResponse{
+	if err := s.validateSearchUsersRequest(req); err != nil {
+		s.logger.WarnContext(ctx, "Invalid search users request", "error", err)
+		return &SearchUsersResponse{
 			Errors: []ErrorDTO{mapDomainErrorToDTO(err)},
 		}, nil
 	}
 
-	// Check if user with email already exists
-	email, err := user.NewEmail(req.Email)
+	query := user.SearchQuery{
+		Text:  strings.TrimSpace(req.Query),
+		First: req.First,
+	}
+	if query.First == 0 {
+		query.First = 10 // Default page size
+	}
+
+	if err := query.Validate(); err != nil {
+		s.logger.WarnContext(ctx, "Invalid search query", "error", err)
+		return &SearchUsersResponse{
+			Errors: []ErrorDTO{mapDomainErrorToDTO(err)},
+		}, nil
+	}
+
+	// Resume the ranking from the opaque cursor, which only fits the search it came from
+	if req.After != "" {
+		after, err := s.cursors.DecodeSearch(req.After, query.Text)
+		if err != nil {
+			s.logger.WarnContext(ctx, "Invalid search cursor", "error", err)
+			return &SearchUsersResponse{
+				Errors: []ErrorDTO{mapDomainErrorToDTO(err)},
+			}, nil
+		}
+		query.After = &after
+	}
+
+	// Rank users in the repository
+	page, err := s.search.Search(ctx, query)
 	if err != nil {
-		s.logger.WarnContext(ctx, "Invalid email format", "error", err, "email", req.Email)
-		return &CreateUserResponse{
+		s.logger.ErrorContext(ctx, "Failed to search users in repository", "error", err)
+		return &SearchUsersResponse{
+			Errors: []ErrorDTO{mapDomainErrorToDTO(err)},
+		}, nil
+	}
+
+	results := s.buildSearchConnection(page, query)
+
+	s.logger.InfoContext(ctx, "Successfully searched users", "count", len(results.Edges))
+	return &SearchUsersResponse{
+		Results: results,
+	}, nil
+}
+
+// GetUserStats retrieves signup statistics bucketed by day, week or month
+func (s *service) GetUserStats(ctx context.Context, req GetUserStatsRequest) (*GetUserStatsResponse, error) {
+	s.logger.InfoContext(ctx, "Getting user stats", "from", req.From, "to", req.To, "interval", req.Interval)
+
+	query := user.SignupStatsQuery{
+		From:     req.From,
+		To:       req.To,
+		Interval: user.StatsInterval(req.Interval),
+	}
+
+	// Validate request
+	if err := query.Validate(); err != nil {
+		s.logger.WarnContext(ctx, "Invalid user stats request", "error", err)
+		return &GetUserStatsResponse{
 			Errors: []ErrorDTO{mapDomainErrorToDTO(err)},
 		}, nil
 	}
 
-	exists, err := s.userRepo.ExistsByEmail(ctx, email)
+	buckets, err := s.userRepo.CountSignups(ctx, query)
 	if err != nil {
-		s.logger.ErrorContext(ctx, "Failed to check if user exists", "error", err, "email", req.Email)
-		return &CreateUserResponse{
+		s.logger.ErrorContext(ctx, "Failed to count signups in repository", "error", err)
+		return &GetUserStatsResponse{
 			Errors: []ErrorDTO{mapDomainErrorToDTO(err)},
 		}, nil
 	}
 
-	if exists {
-		s.logger.WarnContext(ctx, "User with email already exists", "email", req.Email)
+	stats := &UserStatsDTO{
+		From:     req.From,
+		To:       req.To,
+		Interval: req.Interval,
+		Signups:  make([]*SignupBucketDTO, len(buckets)),
+	}
+	for i, bucket := range buckets {
+		stats.Signups[i] = &SignupBucketDTO{
+			Start: bucket.Start,
+			Count: bucket.Count,
+		}
+		stats.TotalSignups += bucket.Count
+	}
+
+	s.logger.InfoContext(ctx, "Successfully retrieved user stats", "buckets", len(buckets), "totalSignups", stats.TotalSignups)
+	return &GetUserStatsResponse{
+		Stats: stats,
+	}, nil
+}
+
+// CreateUser creates a new user
+func (s *service) CreateUser(ctx context.Context, req CreateUserRequest) (*CreateUserResponse, error) {
+	s.logger.InfoContext(ctx, "Creating user", "email", req.Email, "name", req.Name)
+
+	// Validate request
+	if err := s.validateCreateUserRequest(req); err != nil {
+		s.logger.WarnContext(ctx, "Invalid create user request", "error", err)
 		return &CreateUserResponse{
-			Errors: []ErrorDTO{mapDomainErrorToDTO(errors.ErrDuplicateEmail)},
+			Errors: []ErrorDTO{mapDomainErrorToDTO(err)},
 		}, nil
 	}
 
-	// Create domain user
-	domainUser, err := user.NewUser(req.Email, req.Name)
+	// Check if user with email already exists
+	email, err := user.NewEmail(req.Email)
 	if err != nil {
-		s.logger.WarnContext(ctx, "Failed to create domain user", "error", err)
+		s.logger.WarnContext(ctx, "Invalid email format", "error", err, "email", req.Email)
 		return &CreateUserResponse{
 			Errors: []ErrorDTO{mapDomainErrorToDTO(err)},
 		}, nil
 	}
 
-	// Persist user
-	if err := s.userRepo.Create(ctx, domainUser); err != nil {
-		s.logger.ErrorContext(ctx, "Failed to create user in repository", "error", err)
+	// Check the email is free and create the user in one unit of work
+	var domainUser *user.User
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		exists, err := s.userRepo.ExistsByEmail(ctx, email)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "Failed to check if user exists", "error", err, "email", req.Email)
+			return err
+		}
+
+		if exists {
+			s.logger.WarnContext(ctx, "User with email already exists", "email", req.Email)
+			return errors.ErrDuplicateEmail
+		}
+
+		// Create domain user
+		domainUser, err = user.NewUser(req.Email, req.Name)
+		if err != nil {
+			s.logger.WarnContext(ctx, "Failed to create domain user", "error", err)
+			return err
+		}
+
+		// Persist user
+		if err := s.userRepo.Create(ctx, domainUser); err != nil {
+			s.logger.ErrorContext(ctx, "Failed to create user in repository", "error", err)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
 		return &CreateUserResponse{
 			Errors: []ErrorDTO{mapDomainErrorToDTO(err)},
 		}, nil
 	}
 
 	s.logger.InfoContext(ctx, "Successfully created user", "userID", domainUser.ID().String(), "email", req.Email)
+	created := mapDomainUserToDTO(domainUser)
+	s.dispatch(ctx, domainUser)
+	s.publish(EventUserCreated, created)
 	return &CreateUserResponse{
-		User: mapDomainUserToDTO(domainUser),
+		User: created,
 	}, nil
 }
 
@@ -194,24 +419,54 @@ func (s *service) UpdateUser(ctx context.Context, req UpdateUserRequest) (*Updat
 		}, nil
 	}
 
-	// Retrieve existing user
-	domainUser, err := s.userRepo.FindByID(ctx, userID)
+	// Read, check and write the user in one unit of work
+	var domainUser *user.User
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		var err error
+		domainUser, err = s.applyUserUpdate(ctx, userID, req)
+		return err
+	})
 	if err != nil {
-		s.logger.ErrorContext(ctx, "Failed to get user for update", "error", err, "userID", req.ID)
 		return &UpdateUserResponse{
 			Errors: []ErrorDTO{mapDomainErrorToDTO(err)},
 		}, nil
 	}
 
+	s.logger.InfoContext(ctx, "Successfully updated user", "userID", req.ID)
+	updated := mapDomainUserToDTO(domainUser)
+	s.dispatch(ctx, domainUser)
+	s.publish(EventUserUpdated, updated)
+	return &UpdateUserResponse{
+		User: updated,
+	}, nil
+}
+
+// applyUserUpdate loads the user, applies the requested changes and saves them
+func (s *service) applyUserUpdate(ctx context.Context, userID user.UserID, req UpdateUserRequest) (*user.User, error) {
+	// Retrieve existing user
+	domainUser, err := s.userRepo.FindByID(ctx, userID)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to get user for update", "error", err, "userID", req.ID)
+		return nil, err
+	}
+
+	// Reject edits made against a stale copy before touching anything
+	if req.ExpectedVersion != nil && *req.ExpectedVersion != domainUser.Version() {
+		s.logger.WarnContext(ctx, "User version mismatch",
+			"userID", req.ID,
+			"expectedVersion", *req.ExpectedVersion,
+			"currentVersion", domainUser.Version(),
+		)
+		return nil, errors.NewConflictError(domainUser.Version())
+	}
+
 	// Update email if provided
 	if req.Email != nil {
 		// Check if new email is already taken by another user
 		newEmail, err := user.NewEmail(*req.Email)
 		if err != nil {
 			s.logger.WarnContext(ctx, "Invalid email format", "error", err, "email", *req.Email)
-			return &UpdateUserResponse{
-				Errors: []ErrorDTO{mapDomainErrorToDTO(err)},
-			}, nil
+			return nil, err
 		}
 
 		// Only check for duplicates if the email is actually changing
@@ -219,24 +474,18 @@ func (s *service) UpdateUser(ctx context.Context, req UpdateUserRequest) (*Updat
 			exists, err := s.userRepo.ExistsByEmail(ctx, newEmail)
 			if err != nil {
 				s.logger.ErrorContext(ctx, "Failed to check if email exists", "error", err, "email", *req.Email)
-				return &UpdateUserResponse{
-					Errors: []ErrorDTO{mapDomainErrorToDTO(err)},
-				}, nil
+				return nil, err
 			}
 
 			if exists {
 				s.logger.WarnContext(ctx, "Email already exists", "email", *req.Email)
-				return &UpdateUserResponse{
-					Errors: []ErrorDTO{mapDomainErrorToDTO(errors.ErrDuplicateEmail)},
-				}, nil
+				return nil, errors.ErrDuplicateEmail
 			}
 		}
 
 		if err := domainUser.UpdateEmail(*req.Email); err != nil {
 			s.logger.WarnContext(ctx, "Failed to update user email", "error", err)
-			return &UpdateUserResponse{
-				Errors: []ErrorDTO{mapDomainErrorToDTO(err)},
-			}, nil
+			return nil, err
 		}
 	}
 
@@ -244,27 +493,20 @@ func (s *service) UpdateUser(ctx context.Context, req UpdateUserRequest) (*Updat
 	if req.Name != nil {
 		if err := domainUser.UpdateName(*req.Name); err != nil {
 			s.logger.WarnContext(ctx, "Failed to update user name", "error", err)
-			return &UpdateUserResponse{
-				Errors: []ErrorDTO{mapDomainErrorToDTO(err)},
-			}, nil
+			return nil, err
 		}
 	}
 
 	// Persist updated user
 	if err := s.userRepo.Update(ctx, domainUser); err != nil {
 		s.logger.ErrorContext(ctx, "Failed to update user in repository", "error", err)
-		return &UpdateUserResponse{
-			Errors: []ErrorDTO{mapDomainErrorToDTO(err)},
-		}, nil
+		return nil, err
 	}
 
-	s.logger.InfoContext(ctx, "Successfully updated user", "userID", req.ID)
-	return &UpdateUserResponse{
-		User: mapDomainUserToDTO(domainUser),
-	}, nil
+	return domainUser, nil
 }
 
-// DeleteUser deletes a user by ID
+// DeleteUser soft-deletes a user by ID; the user can be restored until purged
 func (s *service) DeleteUser(ctx context.Context, req DeleteUserRequest) (*DeleteUserResponse, error) {
 	s.logger.InfoContext(ctx, "Deleting user", "userID", req.ID)
 
@@ -287,31 +529,365 @@ func (s *service) DeleteUser(ctx context.Context, req DeleteUserRequest) (*Delet
 		}, nil
 	}
 
-	// Check if user exists before deletion
-	_, err = s.userRepo.FindByID(ctx, userID)
+	// Load and delete the user in one unit of work
+	var domainUser *user.User
+	var deleted *UserDTO
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		// Check if user exists before deletion
+		var err error
+		domainUser, err = s.userRepo.FindByID(ctx, userID)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "User not found for deletion", "error", err, "userID", req.ID)
+			return err
+		}
+
+		// Subscribers see the user as it was immediately before deletion
+		deleted = mapDomainUserToDTO(domainUser)
+
+		if err := domainUser.Delete(); err != nil {
+			s.logger.WarnContext(ctx, "User cannot be deleted", "error", err, "userID", req.ID)
+			return err
+		}
+
+		// Delete user
+		if err := s.userRepo.Delete(ctx, domainUser); err != nil {
+			s.logger.ErrorContext(ctx, "Failed to delete user from repository", "error", err)
+			return err
+		}
+		return nil
+	})
 	if err != nil {
-		s.logger.ErrorContext(ctx, "User not found for deletion", "error", err, "userID", req.ID)
 		return &DeleteUserResponse{
 			Success: false,
 			Errors:  []ErrorDTO{mapDomainErrorToDTO(err)},
 		}, nil
 	}
 
-	// Delete user
-	if err := s.userRepo.Delete(ctx, userID); err != nil {
-		s.logger.ErrorContext(ctx, "Failed to delete user from repository", "error", err)
-		return &DeleteUserResponse{
+	s.logger.InfoContext(ctx, "Successfully deleted user", "userID", req.ID)
+	s.dispatch(ctx, domainUser)
+	s.publish(EventUserDeleted, deleted)
+	return &DeleteUserResponse{
+		Success: true,
+	}, nil
+}
+
+// RestoreUser brings a soft-deleted user back
+func (s *service) RestoreUser(ctx context.Context, req RestoreUserRequest) (*RestoreUserResponse, error) {
+	s.logger.InfoContext(ctx, "Restoring user", "userID", req.ID)
+
+	// Validate request
+	if err := s.validateRestoreUserRequest(req); err != nil {
+		s.logger.WarnContext(ctx, "Invalid restore user request", "error", err)
+		return &RestoreUserResponse{
+			Errors: []ErrorDTO{mapDomainErrorToDTO(err)},
+		}, nil
+	}
+
+	// Convert string ID to domain UserID
+	userID, err := user.NewUserID(req.ID)
+	if err != nil {
+		s.logger.WarnContext(ctx, "Invalid user ID format", "error", err, "userID", req.ID)
+		return &RestoreUserResponse{
+			Errors: []ErrorDTO{mapDomainErrorToDTO(err)},
+		}, nil
+	}
+
+	// Load and restore the user in one unit of work
+	var domainUser *user.User
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		// Get the user, deleted or not
+		var err error
+		domainUser, err = s.findUserIncludingDeleted(ctx, userID)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "Failed to get user for restore", "error", err, "userID", req.ID)
+			return err
+		}
+
+		if err := domainUser.Restore(); err != nil {
+			s.logger.WarnContext(ctx, "User cannot be restored", "error", err, "userID", req.ID)
+			return err
+		}
+
+		// Restore user
+		if err := s.userRepo.Restore(ctx, domainUser); err != nil {
+			s.logger.ErrorContext(ctx, "Failed to restore user in repository", "error", err, "userID", req.ID)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return &RestoreUserResponse{
+			Errors: []ErrorDTO{mapDomainErrorToDTO(err)},
+		}, nil
+	}
+
+	s.logger.InfoContext(ctx, "Successfully restored user", "userID", req.ID)
+	s.dispatch(ctx, domainUser)
+	restored := mapDomainUserToDTO(domainUser)
+	s.publish(EventUserUpdated, restored)
+	return &RestoreUserResponse{
+		User: restored,
+	}, nil
+}
+
+// PurgeUser permanently removes a user, deleted or not; callers must be admins
+func (s *service) PurgeUser(ctx context.Context, req PurgeUserRequest) (*PurgeUserResponse, error) {
+	s.logger.InfoContext(ctx, "Purging user", "userID", req.ID)
+
+	if !auth.IsAdmin(ctx) {
+		s.logger.WarnContext(ctx, "Purge user denied for non-admin caller", "userID", req.ID)
+		return &PurgeUserResponse{
+			Success: false,
+			Errors:  []ErrorDTO{mapDomainErrorToDTO(errors.ErrForbidden)},
+		}, nil
+	}
+
+	// Validate request
+	if err := s.validatePurgeUserRequest(req); err != nil {
+		s.logger.WarnContext(ctx, "Invalid purge user request", "error", err)
+		return &PurgeUserResponse{
 			Success: false,
 			Errors:  []ErrorDTO{mapDomainErrorToDTO(err)},
 		}, nil
 	}
 
-	s.logger.InfoContext(ctx, "Successfully deleted user", "userID", req.ID)
-	return &DeleteUserResponse{
+	// Convert string ID to domain UserID
+	userID, err := user.NewUserID(req.ID)
+	if err != nil {
+		s.logger.WarnContext(ctx, "Invalid user ID format", "error", err, "userID", req.ID)
+		return &PurgeUserResponse{
+			Success: false,
+			Errors:  []ErrorDTO{mapDomainErrorToDTO(err)},
+		}, nil
+	}
+
+	// Load and purge the user in one unit of work
+	var domainUser *user.User
+	var purged *UserDTO
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		// Get the user, deleted or not
+		var err error
+		domainUser, err = s.findUserIncludingDeleted(ctx, userID)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "Failed to get user for purge", "error", err, "userID", req.ID)
+			return err
+		}
+
+		// Subscribers see the user as it was immediately before removal
+		purged = mapDomainUserToDTO(domainUser)
+		domainUser.Purge()
+
+		// Purge user
+		if err := s.userRepo.Purge(ctx, domainUser); err != nil {
+			s.logger.ErrorContext(ctx, "Failed to purge user from repository", "error", err, "userID", req.ID)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return &PurgeUserResponse{
+			Success: false,
+			Errors:  []ErrorDTO{mapDomainErrorToDTO(err)},
+		}, nil
+	}
+
+	s.logger.InfoContext(ctx, "Successfully purged user", "userID", req.ID)
+	s.dispatch(ctx, domainUser)
+	s.publish(EventUserDeleted, purged)
+	return &PurgeUserResponse{
 		Success: true,
 	}, nil
 }
 
+// ImportUsers starts importing users from a CSV or NDJSON file in the background; callers must be admins
+func (s *service) ImportUsers(ctx context.Context, req ImportUsersRequest) (*ImportUsersResponse, error) {
+	s.logger.InfoContext(ctx, "Importing users", "fileName", req.FileName, "contentType", req.ContentType)
+
+	if !auth.IsAdmin(ctx) {
+		s.logger.WarnContext(ctx, "Import users denied for non-admin caller", "fileName", req.FileName)
+		return &ImportUsersResponse{
+			Errors: []ErrorDTO{mapDomainErrorToDTO(errors.ErrForbidden)},
+		}, nil
+	}
+
+	if s.imports == nil {
+		s.logger.WarnContext(ctx, "Import users refused while imports are disabled", "fileName", req.FileName)
+		return &ImportUsersResponse{
+			Errors: []ErrorDTO{mapDomainErrorToDTO(errors.ErrImportsDisabled)},
+		}, nil
+	}
+
+	format, err := DetectImportFormat(req.FileName, req.ContentType)
+	if err != nil {
+		s.logger.WarnContext(ctx, "Unsupported import file", "fileName", req.FileName, "contentType", req.ContentType)
+		return &ImportUsersResponse{
+			Errors: []ErrorDTO{mapDomainErrorToDTO(err)},
+		}, nil
+	}
+
+	job, err := s.imports.Start(ctx, req.File, req.FileName, format)
+	if err != nil {
+		s.logger.ErrorContext(ctx, "Failed to start user import", "error", err, "fileName", req.FileName)
+		return &ImportUsersResponse{
+			Errors: []ErrorDTO{mapDomainErrorToDTO(err)},
+		}, nil
+	}
+
+	s.logger.InfoContext(ctx, "Successfully started user import", "jobID", job.ID, "fileName", req.FileName)
+	return &ImportUsersResponse{
+		Job: job,
+	}, nil
+}
+
+// GetImportJob reports the progress and row results of an import; callers must be admins
+func (s *service) GetImportJob(ctx context.Context, req GetImportJobRequest) (*GetImportJobResponse, error) {
+	s.logger.InfoContext(ctx, "Getting import job", "jobID", req.ID)
+
+	if !auth.IsAdmin(ctx) {
+		s.logger.WarnContext(ctx, "Get import job denied for non-admin caller", "jobID", req.ID)
+		return &GetImportJobResponse{
+			Errors: []ErrorDTO{mapDomainErrorToDTO(errors.ErrForbidden)},
+		}, nil
+	}
+
+	// Validate request
+	if err := s.validateGetImportJobRequest(req); err != nil {
+		s.logger.WarnContext(ctx, "Invalid get import job request", "error", err, "jobID", req.ID)
+		return &GetImportJobResponse{
+			Errors: []ErrorDTO{mapDomainErrorToDTO(err)},
+		}, nil
+	}
+
+	if s.imports == nil {
+		s.logger.WarnContext(ctx, "Get import job refused while imports are disabled", "jobID", req.ID)
+		return &GetImportJobResponse{
+			Errors: []ErrorDTO{mapDomainErrorToDTO(errors.ErrImportsDisabled)},
+		}, nil
+	}
+
+	job, ok := s.imports.Get(req.ID)
+	if !ok {
+		s.logger.WarnContext(ctx, "Import job not found", "jobID", req.ID)
+		return &GetImportJobResponse{
+			Errors: []ErrorDTO{mapDomainErrorToDTO(errors.ErrImportJobNotFound)},
+		}, nil
+	}
+	job.Rows = selectImportRows(job.Rows, ImportRowStatus(req.RowStatus), req.RowsFirst, req.RowsOffset)
+
+	s.logger.InfoContext(ctx, "Successfully retrieved import job", "jobID", req.ID, "status", job.Status)
+	return &GetImportJobResponse{
+		Job: job,
+	}, nil
+}
+
+// ChangeUserStatus moves a user to another account status, recording who did it and why
+func (s *service) ChangeUserStatus(ctx context.Context, req ChangeUserStatusRequest) (*ChangeUserStatusResponse, error) {
+	s.logger.InfoContext(ctx, "Changing user status", "userID", req.ID, "status", req.Status)
+
+	principal, ok := auth.FromContext(ctx)
+	if !ok || !principal.HasRole(auth.RoleAdmin) {
+		s.logger.WarnContext(ctx, "Change user status denied for non-admin caller", "userID", req.ID)
+		return &ChangeUserStatusResponse{
+			Errors: []ErrorDTO{mapDomainErrorToDTO(errors.ErrForbidden)},
+		}, nil
+	}
+
+	// Validate request
+	if err := s.validateChangeUserStatusRequest(req); err != nil {
+		s.logger.WarnContext(ctx, "Invalid change user status request", "error", err)
+		return &ChangeUserStatusResponse{
+			Errors: []ErrorDTO{mapDomainErrorToDTO(err)},
+		}, nil
+	}
+
+	// Convert string ID to domain UserID
+	userID, err := user.NewUserID(req.ID)
+	if err != nil {
+		s.logger.WarnContext(ctx, "Invalid user ID format", "error", err, "userID", req.ID)
+		return &ChangeUserStatusResponse{
+			Errors: []ErrorDTO{mapDomainErrorToDTO(err)},
+		}, nil
+	}
+
+	// Read, transition and save the user in one unit of work
+	var domainUser *user.User
+	var transition user.StatusTransition
+	err = s.uow.Do(ctx, func(ctx context.Context) error {
+		// Get existing user
+		var err error
+		domainUser, err = s.userRepo.FindByID(ctx, userID)
+		if err != nil {
+			s.logger.ErrorContext(ctx, "Failed to get user for status change", "error", err, "userID", req.ID)
+			return err
+		}
+
+		// Apply the transition table
+		transition, err = domainUser.ChangeStatus(user.Status(req.Status), principal.Subject, req.Reason)
+		if err != nil {
+			s.logger.WarnContext(ctx, "Status transition rejected", "error", err, "userID", req.ID, "from", domainUser.Status())
+			return err
+		}
+
+		// Save status and transition
+		if err := s.userRepo.UpdateStatus(ctx, domainUser, transition); err != nil {
+			s.logger.ErrorContext(ctx, "Failed to update user status in repository", "error", err, "userID", req.ID)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return &ChangeUserStatusResponse{
+			Errors: []ErrorDTO{mapDomainErrorToDTO(err)},
+		}, nil
+	}
+
+	s.logger.InfoContext(ctx, "Successfully changed user status",
+		"userID", req.ID,
+		"from", transition.From,
+		"to", transition.To,
+		"actor", transition.Actor,
+	)
+	s.dispatch(ctx, domainUser)
+	updated := mapDomainUserToDTO(domainUser)
+	s.publish(EventUserUpdated, updated)
+	return &ChangeUserStatusResponse{
+		User:       updated,
+		Transition: mapStatusTransitionToDTO(transition),
+	}, nil
+}
+
+// SubscribeUserEvents streams user lifecycle events published after successful mutations
+func (s *service) SubscribeUserEvents(ctx context.Context, req SubscribeUserEventsRequest) (*SubscribeUserEventsResponse, error) {
+	s.logger.InfoContext(ctx, "Subscribing to user events", "type", req.Type, "userID", req.UserID)
+
+	// Validate request
+	if err := s.validateSubscribeUserEventsRequest(req); err != nil {
+		s.logger.WarnContext(ctx, "Invalid subscribe user events request", "error", err)
+		return &SubscribeUserEventsResponse{
+			Errors: []ErrorDTO{mapDomainErrorToDTO(err)},
+		}, nil
+	}
+
+	// Validate the optional user ID
+	if req.UserID != "" {
+		if _, err := user.NewUserID(req.UserID); err != nil {
+			s.logger.WarnContext(ctx, "Invalid user ID format", "error", err, "userID", req.UserID)
+			return &SubscribeUserEventsResponse{
+				Errors: []ErrorDTO{mapDomainErrorToDTO(err)},
+			}, nil
+		}
+	}
+
+	// UUIDs compare case-insensitively
+	events := s.events.Subscribe(ctx, func(event *UserEventDTO) bool {
+		return event.Type == req.Type && (req.UserID == "" || strings.EqualFold(event.User.ID, req.UserID))
+	})
+
+	return &SubscribeUserEventsResponse{
+		Events: events,
+	}, nil
+}
+
 // Validation methods
 
 func (s *service) validateGetUserRequest(req GetUserRequest) error {
@@ -336,6 +912,62 @@ func (s *service) validateListUsersRequest(req ListUsersRequest) error {
 			Field:   "first",
 		}
 	}
+	if req.Last < 0 {
+		return errors.DomainError{
+			Code:    "INVALID_LAST",
+			Message: "Last parameter must be non-negative",
+			Field:   "last",
+		}
+	}
+	if req.Last > 100 {
+		return errors.DomainError{
+			Code:    "INVALID_LAST",
+			Message: "Last parameter cannot exceed 100",
+			Field:   "last",
+		}
+	}
+	if req.First > 0 && req.Last > 0 {
+		return errors.ErrInvalidPagination
+	}
+	return nil
+}
+
+func (s *service) validateSearchUsersRequest(req SearchUsersRequest) error {
+	if req.First < 0 {
+		return errors.DomainError{
+			Code:    "INVALID_FIRST",
+			Message: "First parameter must be non-negative",
+			Field:   "first",
+		}
+	}
+	if req.First > 100 {
+		return errors.DomainError{
+			Code:    "INVALID_FIRST",
+			Message: "First parameter cannot exceed 100",
+			Field:   "first",
+		}
+	}
+	return nil
+}
+
+func (s *service) validateGetImportJobRequest(req GetImportJobRequest) error {
+	if req.ID == "" {
+		return errors.ErrImportJobNotFound
+	}
+	if req.RowsFirst < 0 || req.RowsFirst > 1000 {
+		return errors.DomainError{
+			Code:    "INVALID_FIRST",
+			Message: "First parameter must be between 0 and 1000",
+			Field:   "first",
+		}
+	}
+	if req.RowsOffset < 0 {
+		return errors.DomainError{
+			Code:    "INVALID_OFFSET",
+			Message: "Offset parameter must be non-negative",
+			Field:   "offset",
+		}
+	}
 	return nil
 }
 
@@ -369,19 +1001,167 @@ func (s *service) validateDeleteUserRequest(req DeleteUserRequest) error {
 	return nil
 }
 
+func (s *service) validateRestoreUserRequest(req RestoreUserRequest) error {
+	if req.ID == "" {
+		return errors.ErrInvalidUserID
+	}
+	return nil
+}
+
+func (s *service) validatePurgeUserRequest(req PurgeUserRequest) error {
+	if req.ID == "" {
+		return errors.ErrInvalidUserID
+	}
+	return nil
+}
+
+func (s *service) validateChangeUserStatusRequest(req ChangeUserStatusRequest) error {
+	if req.ID == "" {
+		return errors.ErrInvalidUserID
+	}
+	if !user.Status(req.Status).IsValid() {
+		return errors.ErrInvalidStatus
+	}
+	return nil
+}
+
+func (s *service) validateSubscribeUserEventsRequest(req SubscribeUserEventsRequest) error {
+	switch req.Type {
+	case EventUserCreated, EventUserUpdated, EventUserDeleted:
+		return nil
+	default:
+		return errors.DomainError{
+			Code:    "INVALID_EVENT_TYPE",
+			Message: "Unknown user event type",
+			Field:   "type",
+		}
+	}
+}
+
 // Helper methods
 
-func (s *service) buildUserConnection(users []*user.User, limit int, after string, nextCursor string) *UserConnectionDTO {
-	hasNextPage := len(users) > limit
-	if hasNextPage {
-		users = users[:limit] // Remove the extra user we fetched
+// dispatch hands the domain events u has recorded to the dispatcher
+// The changes are already persisted, so handler failures are logged rather than failing the request
+func (s *service) dispatch(ctx context.Context, u *user.User) {
+	if err := s.dispatcher.Dispatch(ctx, u.DrainEvents()...); err != nil {
+		s.logger.ErrorContext(ctx, "Domain event handlers failed", "error", err, "userID", u.ID().String())
 	}
+}
+
+// findUserIncludingDeleted retrieves a user whether soft-deleted or not
+func (s *service) findUserIncludingDeleted(ctx context.Context, id user.UserID) (*user.User, error) {
+	criteria := user.Criteria{Sort: user.DefaultSort}
+	criteria.Filter.IDs = []user.UserID{id}
+	criteria.Filter.IncludeDeleted = true
+
+	page, err := s.userRepo.FindAll(ctx, criteria, user.PageRequest{First: 1})
+	if err != nil {
+		return nil, err
+	}
+	if len(page.Users) == 0 {
+		return nil, errors.ErrUserNotFound
+	}
+	return page.Users[0], nil
+}
+
+// publish notifies subscribers of a completed mutation
+func (s *service) publish(eventType EventType, u *UserDTO) {
+	s.events.Publish(&UserEventDTO{
+		Type:       eventType,
+		User:       u,
+		OccurredAt: time.Now().UTC(),
+	})
+}
 
-	edges := make([]*UserEdgeDTO, len(users))
-	for i, u := range users {
+func (s *service) buildCriteria(req ListUsersRequest) (user.Criteria, error) {
+	criteria := user.Criteria{Sort: user.DefaultSort}
+	criteria.Filter.IncludeDeleted = req.IncludeDeleted
+
+	if req.OrderBy != nil {
+		criteria.Sort = user.Sort{
+			Field:     user.SortField(req.OrderBy.Field),
+			Direction: user.SortDirection(req.OrderBy.Direction),
+		}
+	}
+
+	if f := req.Filter; f != nil {
+		criteria.Filter.Name = mapStringFilter(f.Name)
+		criteria.Filter.CreatedAt = mapTimeRange(f.CreatedAt)
+		criteria.Filter.UpdatedAt = mapTimeRange(f.UpdatedAt)
+
+		// Emails are stored normalized, so exact matches are normalized the same way
+		criteria.Filter.Email = mapStringFilter(f.Email)
+		if criteria.Filter.Email != nil && criteria.Filter.Email.Equals != nil {
+			normalized := strings.ToLower(strings.TrimSpace(*criteria.Filter.Email.Equals))
+			criteria.Filter.Email.Equals = &normalized
+		}
+
+		if f.Statuses != nil {
+			criteria.Filter.Statuses = make([]user.Status, len(f.Statuses))
+			for i, status := range f.Statuses {
+				criteria.Filter.Statuses[i] = user.Status(status)
+			}
+		}
+
+		if f.IDs != nil {
+			criteria.Filter.IDs = make([]user.UserID, len(f.IDs))
+			for i, raw := range f.IDs {
+				id, err := user.NewUserID(raw)
+				if err != nil {
+					invalid := errors.ErrInvalidUserID
+					invalid.Field = "filter.ids"
+					return user.Criteria{}, invalid
+				}
+				criteria.Filter.IDs[i] = id
+			}
+		}
+	}
+
+	if err := criteria.Validate(); err != nil {
+		return user.Criteria{}, err
+	}
+
+	return criteria, nil
+}
+
+func (s *service) buildPageRequest(req ListUsersRequest, sort user.Sort) (user.PageRequest, error) {
+	page := user.PageRequest{
+		First: req.First,
+		Last:  req.Last,
+	}
+
+	// Default to the first page when no size is requested
+	if page.First == 0 && page.Last == 0 {
+		page.First = 10 // Default page size
+	}
+
+	if req.After != "" {
+		after, err := s.cursors.Decode(req.After, sort)
+		if err != nil {
+			return user.PageRequest{}, err
+		}
+		page.After = &after
+	}
+
+	if req.Before != "" {
+		before, err := s.cursors.Decode(req.Before, sort)
+		if err != nil {
+			invalid := errors.ErrInvalidCursor
+			invalid.Field = "before"
+			return user.PageRequest{}, invalid
+		}
+		page.Before = &before
+	}
+
+	return page, nil
+}
+
+func (s *service) buildUserConnection(page *user.Page, sort user.Sort) *UserConnectionDTO {
+	edges := make([]*UserEdgeDTO, len(page.Users))
+	for i, u := range page.Users {
 		edges[i] = &UserEdgeDTO{
 			Node:   mapDomainUserToDTO(u),
-			Cursor: u.ID().String(), // Using user ID as cursor for simplicity
+			Cursor: s.cursors.Encode(sort, user.CursorOf(u, sort)),
 		}
 	}
 
@@ -396,8 +1176,42 @@ func (s *service) buildUserConnection(users []*user.User, limit int, after strin
 	return &UserConnectionDTO{
 		Edges: edges,
 		PageInfo: &PageInfoDTO{
-			HasNextPage:     hasNextPage,
-			HasPreviousPage: after != "",
+			HasNextPage:     page.HasNextPage,
+			HasPreviousPage: page.HasPreviousPage,
+			StartCursor:     startCursor,
+			EndCursor:       endCursor,
+		},
+	}
+}
+
+func (s *service) buildSearchConnection(page *user.SearchPage, query user.SearchQuery) *UserSearchConnectionDTO {
+	edges := make([]*UserSearchEdgeDTO, len(page.Hits))
+	for i, hit := range page.Hits {
+		edges[i] = &UserSearchEdgeDTO{
+			Node:   mapDomainUserToDTO(hit.User),
+			Cursor: s.cursors.EncodeSearch(query.Text, hit.Cursor()),
+			Rank:   hit.Rank,
+			Highlights: &UserSearchHighlightsDTO{
+				Name:  mapHighlightToDTO(hit.User.Name().String(), query.Highlight(hit.User.Name().String())),
+				Email: mapHighlightToDTO(hit.User.Email().String(), query.Highlight(hit.User.Email().String())),
+			},
+		}
+	}
+
+	var startCursor, endCursor *string
+	if len(edges) > 0 {
+		start := edges[0].Cursor
+		end := edges[len(edges)-1].Cursor
+		startCursor = &start
+		endCursor = &end
+	}
+
+	// Search only pages forward, so anything before is exactly what the cursor skipped
+	return &UserSearchConnectionDTO{
+		Edges: edges,
+		PageInfo: &PageInfoDTO{
+			HasNextPage:     page.HasNextPage,
+			HasPreviousPage: query.After != nil,
 			StartCursor:     startCursor,
 			EndCursor:       endCursor,
 		},
@@ -11,7 +11,11 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/captain-corgi/go-graphql-example/internal/application/auth"
+	"github.com/captain-corgi/go-graphql-example/internal/application/unitofwork"
+	uowMocks "github.com/captain-corgi/go-graphql-example/internal/application/unitofwork/mocks"
 	"github.com/captain-corgi/go-graphql-example/internal/domain/errors"
+	domainEvents "github.com/captain-corgi/go-graphql-example/internal/domain/events"
 	"github.com/captain-corgi/go-graphql-example/internal/domain/user"
 	"github.com/captain-corgi/go-graphql-example/internal/domain/user/mocks"
 )
@@ -22,7 +26,7 @@ func TestService_CreateUser(t *testing.T) {
 
 	mockRepo := mocks.NewMockRepository(ctrl)
 	logger := slog.Default()
-	service := NewService(mockRepo, logger)
+	service := NewService(mockRepo, nil, nil, NewCursorCodec([]byte(testCursorSecret)), &recordingBroker{}, domainEvents.NewDomainEventDispatcher(logger), unitofwork.Passthrough{}, logger)
 
 	tests := []struct {
 		name    string
@@ -154,7 +158,7 @@ func TestService_GetUser(t *testing.T) {
 
 	mockRepo := mocks.NewMockRepository(ctrl)
 	logger := slog.Default()
-	service := NewService(mockRepo, logger)
+	service := NewService(mockRepo, nil, nil, NewCursorCodec([]byte(testCursorSecret)), &recordingBroker{}, domainEvents.NewDomainEventDispatcher(logger), unitofwork.Passthrough{}, logger)
 
 	// Create a test user
 	testUser, err := user.NewUserWithID(
@@ -264,13 +268,66 @@ func TestService_GetUser(t *testing.T) {
 	}
 }
 
+func TestService_GetUsersByIDs(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	service := NewService(mockRepo, nil, nil, NewCursorCodec([]byte(testCursorSecret)), &recordingBroker{}, domainEvents.NewDomainEventDispatcher(slog.Default()), unitofwork.Passthrough{}, slog.Default())
+
+	testUser, err := user.NewUserWithID(
+		"123e4567-e89b-12d3-a456-426614174001",
+		"user1@example.com",
+		"User One",
+		time.Now(),
+		time.Now(),
+	)
+	require.NoError(t, err)
+
+	missingID, err := user.NewUserID("123e4567-e89b-12d3-a456-426614174999")
+	require.NoError(t, err)
+
+	t.Run("returns the users that exist", func(t *testing.T) {
+		mockRepo.EXPECT().
+			FindByIDs(gomock.Any(), []user.UserID{testUser.ID(), missingID}).
+			Return([]*user.User{testUser}, nil)
+
+		got, err := service.GetUsersByIDs(context.Background(), GetUsersByIDsRequest{
+			IDs: []string{testUser.ID().String(), missingID.String()},
+		})
+		require.NoError(t, err)
+		require.Empty(t, got.Errors)
+		require.Len(t, got.Users, 1)
+		assert.Equal(t, "User One", got.Users[0].Name)
+	})
+
+	t.Run("invalid id", func(t *testing.T) {
+		got, err := service.GetUsersByIDs(context.Background(), GetUsersByIDsRequest{IDs: []string{"user-1"}})
+		require.NoError(t, err)
+		require.Len(t, got.Errors, 1)
+		assert.Equal(t, "INVALID_USER_ID", got.Errors[0].Code)
+	})
+
+	t.Run("repository error", func(t *testing.T) {
+		mockRepo.EXPECT().
+			FindByIDs(gomock.Any(), []user.UserID{testUser.ID()}).
+			Return(nil, errors.ErrRepositoryOperation)
+
+		got, err := service.GetUsersByIDs(context.Background(), GetUsersByIDsRequest{IDs: []string{testUser.ID().String()}})
+		require.NoError(t, err)
+		require.Len(t, got.Errors, 1)
+		assert.Equal(t, "REPOSITORY_OPERATION", got.Errors[0].Code)
+	})
+}
+
 func TestService_ListUsers(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockRepository(ctrl)
 	logger := slog.Default()
-	service := NewService(mockRepo, logger)
+	codec := NewCursorCodec([]byte(testCursorSecret))
+	service := NewService(mockRepo, nil, nil, codec, &recordingBroker{}, domainEvents.NewDomainEventDispatcher(logger), unitofwork.Passthrough{}, logger)
 
 	// Create test users
 	testUser1, err := user.NewUserWithID(
@@ -291,6 +348,26 @@ func TestService_ListUsers(t *testing.T) {
 	)
 	require.NoError(t, err)
 
+	defaultCriteria := user.Criteria{Sort: user.DefaultSort}
+	byName := user.Sort{Field: user.SortByName, Direction: user.SortAsc}
+
+	encoded1 := codec.Encode(user.DefaultSort, user.CursorOf(testUser1, user.DefaultSort))
+	encoded2 := codec.Encode(user.DefaultSort, user.CursorOf(testUser2, user.DefaultSort))
+	forged := NewCursorCodec([]byte("another-secret-0123456789abcdefgh")).Encode(user.DefaultSort, user.CursorOf(testUser1, user.DefaultSort))
+	byNameEncoded1 := codec.Encode(byName, user.CursorOf(testUser1, byName))
+
+	// Decoded cursors carry no monotonic clock reading, so compare against what the codec yields
+	cursor1, err := codec.Decode(encoded1, user.DefaultSort)
+	require.NoError(t, err)
+	cursor2, err := codec.Decode(encoded2, user.DefaultSort)
+	require.NoError(t, err)
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 1, 0)
+	mixedCaseEmail := "  User1@Example.COM "
+	normalizedEmail := "user1@example.com"
+	nameContains := "one"
+
 	tests := []struct {
 		name    string
 		request ListUsersRequest
@@ -305,7 +382,8 @@ func TestService_ListUsers(t *testing.T) {
 				After: "",
 			},
 			setup: func() {
-				mockRepo.EXPECT().FindAll(gomock.Any(), 11, "").Return([]*user.User{testUser1, testUser2}, "", nil)
+				mockRepo.EXPECT().FindAll(gomock.Any(), defaultCriteria, user.PageRequest{First: 10}).
+					Return(&user.Page{Users: []*user.User{testUser1, testUser2}}, nil)
 			},
 			want: &ListUsersResponse{
 				Users: &UserConnectionDTO{
@@ -316,7 +394,7 @@ func TestService_ListUsers(t *testing.T) {
 								Email: "user1@example.com",
 								Name:  "User One",
 							},
-							Cursor: "123e4567-e89b-12d3-a456-426614174001",
+							Cursor: encoded1,
 						},
 						{
 							Node: &UserDTO{
@@ -324,14 +402,14 @@ func TestService_ListUsers(t *testing.T) {
 								Email: "user2@example.com",
 								Name:  "User Two",
 							},
-							Cursor: "123e4567-e89b-12d3-a456-426614174002",
+							Cursor: encoded2,
 						},
 					},
 					PageInfo: &PageInfoDTO{
 						HasNextPage:     false,
 						HasPreviousPage: false,
-						StartCursor:     stringPtr("123e4567-e89b-12d3-a456-426614174001"),
-						EndCursor:       stringPtr("123e4567-e89b-12d3-a456-426614174002"),
+						StartCursor:     stringPtr(encoded1),
+						EndCursor:       stringPtr(encoded2),
 					},
 				},
 			},
@@ -344,7 +422,8 @@ func TestService_ListUsers(t *testing.T) {
 				After: "",
 			},
 			setup: func() {
-				mockRepo.EXPECT().FindAll(gomock.Any(), 6, "").Return([]*user.User{testUser1}, "", nil)
+				mockRepo.EXPECT().FindAll(gomock.Any(), defaultCriteria, user.PageRequest{First: 5}).
+					Return(&user.Page{Users: []*user.User{testUser1}}, nil)
 			},
 			want: &ListUsersResponse{
 				Users: &UserConnectionDTO{
@@ -355,14 +434,14 @@ func TestService_ListUsers(t *testing.T) {
 								Email: "user1@example.com",
 								Name:  "User One",
 							},
-							Cursor: "123e4567-e89b-12d3-a456-426614174001",
+							Cursor: encoded1,
 						},
 					},
 					PageInfo: &PageInfoDTO{
 						HasNextPage:     false,
 						HasPreviousPage: false,
-						StartCursor:     stringPtr("123e4567-e89b-12d3-a456-426614174001"),
-						EndCursor:       stringPtr("123e4567-e89b-12d3-a456-426614174001"),
+						StartCursor:     stringPtr(encoded1),
+						EndCursor:       stringPtr(encoded1),
 					},
 				},
 			},
@@ -372,11 +451,11 @@ func TestService_ListUsers(t *testing.T) {
 			name: "successful user listing with pagination",
 			request: ListUsersRequest{
 				First: 1,
-				After: "cursor123",
+				After: encoded2,
 			},
 			setup: func() {
-				// Return 2 users to simulate hasNextPage = true
-				mockRepo.EXPECT().FindAll(gomock.Any(), 2, "cursor123").Return([]*user.User{testUser1, testUser2}, "", nil)
+				mockRepo.EXPECT().FindAll(gomock.Any(), defaultCriteria, user.PageRequest{First: 1, After: &cursor2}).
+					Return(&user.Page{Users: []*user.User{testUser1}, HasNextPage: true, HasPreviousPage: true}, nil)
 			},
 			want: &ListUsersResponse{
 				Users: &UserConnectionDTO{
@@ -387,14 +466,208 @@ func TestService_ListUsers(t *testing.T) {
 								Email: "user1@example.com",
 								Name:  "User One",
 							},
-							Cursor: "123e4567-e89b-12d3-a456-426614174001",
+							Cursor: encoded1,
 						},
 					},
 					PageInfo: &PageInfoDTO{
 						HasNextPage:     true,
 						HasPreviousPage: true,
-						StartCursor:     stringPtr("123e4567-e89b-12d3-a456-426614174001"),
-						EndCursor:       stringPtr("123e4567-e89b-12d3-a456-426614174001"),
+						StartCursor:     stringPtr(encoded1),
+						EndCursor:       stringPtr(encoded1),
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "backward pagination",
+			request: ListUsersRequest{
+				Last:   1,
+				Before: encoded1,
+			},
+			setup: func() {
+				mockRepo.EXPECT().FindAll(gomock.Any(), defaultCriteria, user.PageRequest{Last: 1, Before: &cursor1}).
+					Return(&user.Page{Users: []*user.User{testUser2}, HasNextPage: true, HasPreviousPage: false}, nil)
+			},
+			want: &ListUsersResponse{
+				Users: &UserConnectionDTO{
+					Edges: []*UserEdgeDTO{
+						{
+							Node: &UserDTO{
+								ID:    "123e4567-e89b-12d3-a456-426614174002",
+								Email: "user2@example.com",
+								Name:  "User Two",
+							},
+							Cursor: encoded2,
+						},
+					},
+					PageInfo: &PageInfoDTO{
+						HasNextPage:     true,
+						HasPreviousPage: false,
+						StartCursor:     stringPtr(encoded2),
+						EndCursor:       stringPtr(encoded2),
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "ordered by name with after cursor",
+			request: ListUsersRequest{
+				First:   1,
+				After:   byNameEncoded1,
+				OrderBy: &UserOrderDTO{Field: "NAME", Direction: "ASC"},
+			},
+			setup: func() {
+				after := user.Cursor{Value: "User One", ID: testUser1.ID()}
+				mockRepo.EXPECT().FindAll(gomock.Any(), user.Criteria{Sort: byName}, user.PageRequest{First: 1, After: &after}).
+					Return(&user.Page{Users: []*user.User{testUser2}, HasPreviousPage: true}, nil)
+			},
+			want: &ListUsersResponse{
+				Users: &UserConnectionDTO{
+					Edges: []*UserEdgeDTO{
+						{
+							Node: &UserDTO{
+								ID:    "123e4567-e89b-12d3-a456-426614174002",
+								Email: "user2@example.com",
+								Name:  "User Two",
+							},
+							Cursor: codec.Encode(byName, user.CursorOf(testUser2, byName)),
+						},
+					},
+					PageInfo: &PageInfoDTO{
+						HasNextPage:     false,
+						HasPreviousPage: true,
+						StartCursor:     stringPtr(codec.Encode(byName, user.CursorOf(testUser2, byName))),
+						EndCursor:       stringPtr(codec.Encode(byName, user.CursorOf(testUser2, byName))),
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "filtered listing",
+			request: ListUsersRequest{
+				First: 10,
+				Filter: &UserFilterDTO{
+					Email:     &StringFilterDTO{Equals: &mixedCaseEmail},
+					Name:      &StringFilterDTO{Contains: &nameContains},
+					CreatedAt: &TimeRangeDTO{From: &from, To: &to},
+					IDs:       []string{"123e4567-e89b-12d3-a456-426614174001"},
+				},
+			},
+			setup: func() {
+				criteria := user.Criteria{
+					Sort: user.DefaultSort,
+					Filter: user.Filter{
+						Email:     &user.TextFilter{Equals: &normalizedEmail},
+						Name:      &user.TextFilter{Contains: &nameContains},
+						CreatedAt: &user.TimeRange{From: &from, To: &to},
+						IDs:       []user.UserID{testUser1.ID()},
+					},
+				}
+				mockRepo.EXPECT().FindAll(gomock.Any(), criteria, user.PageRequest{First: 10}).
+					Return(&user.Page{Users: []*user.User{testUser1}}, nil)
+			},
+			want: &ListUsersResponse{
+				Users: &UserConnectionDTO{
+					Edges: []*UserEdgeDTO{
+						{
+							Node: &UserDTO{
+								ID:    "123e4567-e89b-12d3-a456-426614174001",
+								Email: "user1@example.com",
+								Name:  "User One",
+							},
+							Cursor: encoded1,
+						},
+					},
+					PageInfo: &PageInfoDTO{
+						StartCursor: stringPtr(encoded1),
+						EndCursor:   stringPtr(encoded1),
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "cursor issued under another sort",
+			request: ListUsersRequest{
+				First: 10,
+				After: encoded1,
+				OrderBy: &UserOrderDTO{
+					Field:     "NAME",
+					Direction: "ASC",
+				},
+			},
+			setup: func() {
+				// No mock setup needed as the cursor is rejected before repository call
+			},
+			want: &ListUsersResponse{
+				Errors: []ErrorDTO{
+					{
+						Message: "Invalid pagination cursor",
+						Field:   "after",
+						Code:    "INVALID_CURSOR",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "unknown sort field",
+			request: ListUsersRequest{
+				First:   10,
+				OrderBy: &UserOrderDTO{Field: "PASSWORD", Direction: "ASC"},
+			},
+			setup: func() {
+				// No mock setup needed as criteria are rejected before repository call
+			},
+			want: &ListUsersResponse{
+				Errors: []ErrorDTO{
+					{
+						Message: "Invalid sort order",
+						Field:   "orderBy",
+						Code:    "INVALID_SORT",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid id in filter",
+			request: ListUsersRequest{
+				First:  10,
+				Filter: &UserFilterDTO{IDs: []string{"not-a-uuid"}},
+			},
+			setup: func() {
+				// No mock setup needed as criteria are rejected before repository call
+			},
+			want: &ListUsersResponse{
+				Errors: []ErrorDTO{
+					{
+						Message: "Invalid user ID format",
+						Field:   "filter.ids",
+						Code:    "INVALID_USER_ID",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "empty time range in filter",
+			request: ListUsersRequest{
+				First:  10,
+				Filter: &UserFilterDTO{UpdatedAt: &TimeRangeDTO{From: &to, To: &from}},
+			},
+			setup: func() {
+				// No mock setup needed as criteria are rejected before repository call
+			},
+			want: &ListUsersResponse{
+				Errors: []ErrorDTO{
+					{
+						Message: "Invalid filter",
+						Field:   "filter.updatedAt",
+						Code:    "INVALID_FILTER",
 					},
 				},
 			},
@@ -407,7 +680,8 @@ func TestService_ListUsers(t *testing.T) {
 				After: "",
 			},
 			setup: func() {
-				mockRepo.EXPECT().FindAll(gomock.Any(), 11, "").Return([]*user.User{}, "", nil)
+				mockRepo.EXPECT().FindAll(gomock.Any(), defaultCriteria, user.PageRequest{First: 10}).
+					Return(&user.Page{Users: []*user.User{}}, nil)
 			},
 			want: &ListUsersResponse{
 				Users: &UserConnectionDTO{
@@ -462,6 +736,85 @@ func TestService_ListUsers(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "first combined with last",
+			request: ListUsersRequest{
+				First: 5,
+				Last:  5,
+			},
+			setup: func() {
+				// No mock setup needed as validation happens before repository call
+			},
+			want: &ListUsersResponse{
+				Errors: []ErrorDTO{
+					{
+						Message: "Cannot combine first and last",
+						Code:    "INVALID_PAGINATION",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "malformed after cursor",
+			request: ListUsersRequest{
+				First: 10,
+				After: "cursor123",
+			},
+			setup: func() {
+				// No mock setup needed as the cursor is rejected before repository call
+			},
+			want: &ListUsersResponse{
+				Errors: []ErrorDTO{
+					{
+						Message: "Invalid pagination cursor",
+						Field:   "after",
+						Code:    "INVALID_CURSOR",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "after cursor signed with another secret",
+			request: ListUsersRequest{
+				First: 10,
+				After: forged,
+			},
+			setup: func() {
+				// No mock setup needed as the cursor is rejected before repository call
+			},
+			want: &ListUsersResponse{
+				Errors: []ErrorDTO{
+					{
+						Message: "Invalid pagination cursor",
+						Field:   "after",
+						Code:    "INVALID_CURSOR",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "malformed before cursor",
+			request: ListUsersRequest{
+				Last:   10,
+				Before: "123e4567-e89b-12d3-a456-426614174001",
+			},
+			setup: func() {
+				// No mock setup needed as the cursor is rejected before repository call
+			},
+			want: &ListUsersResponse{
+				Errors: []ErrorDTO{
+					{
+						Message: "Invalid pagination cursor",
+						Field:   "before",
+						Code:    "INVALID_CURSOR",
+					},
+				},
+			},
+			wantErr: false,
+		},
 		{
 			name: "repository error",
 			request: ListUsersRequest{
@@ -469,7 +822,7 @@ func TestService_ListUsers(t *testing.T) {
 				After: "",
 			},
 			setup: func() {
-				mockRepo.EXPECT().FindAll(gomock.Any(), 11, "").Return(nil, "", errors.ErrRepositoryOperation)
+				mockRepo.EXPECT().FindAll(gomock.Any(), defaultCriteria, user.PageRequest{First: 10}).Return(nil, errors.ErrRepositoryOperation)
 			},
 			want: &ListUsersResponse{
 				Errors: []ErrorDTO{
@@ -538,13 +891,239 @@ func TestService_ListUsers(t *testing.T) {
 	}
 }
 
+func TestService_ListUsers_TotalCount(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	service := NewService(mockRepo, nil, nil, NewCursorCodec([]byte(testCursorSecret)), &recordingBroker{}, domainEvents.NewDomainEventDispatcher(slog.Default()), unitofwork.Passthrough{}, slog.Default())
+
+	contains := "example"
+	filter := user.Filter{Email: &user.TextFilter{Contains: &contains}}
+	criteria := user.Criteria{Filter: filter, Sort: user.DefaultSort}
+	request := ListUsersRequest{
+		First:             10,
+		Filter:            &UserFilterDTO{Email: &StringFilterDTO{Contains: &contains}},
+		IncludeTotalCount: true,
+	}
+
+	t.Run("counts users matching the filter", func(t *testing.T) {
+		mockRepo.EXPECT().FindAll(gomock.Any(), criteria, user.PageRequest{First: 10}).Return(&user.Page{}, nil)
+		mockRepo.EXPECT().Count(gomock.Any(), filter).Return(int64(42), nil)
+
+		got, err := service.ListUsers(context.Background(), request)
+		require.NoError(t, err)
+		require.Empty(t, got.Errors)
+		require.NotNil(t, got.Users.TotalCount)
+		assert.Equal(t, int64(42), *got.Users.TotalCount)
+	})
+
+	t.Run("does not count unless asked", func(t *testing.T) {
+		mockRepo.EXPECT().FindAll(gomock.Any(), criteria, user.PageRequest{First: 10}).Return(&user.Page{}, nil)
+
+		withoutCount := request
+		withoutCount.IncludeTotalCount = false
+		got, err := service.ListUsers(context.Background(), withoutCount)
+		require.NoError(t, err)
+		assert.Nil(t, got.Users.TotalCount)
+	})
+
+	t.Run("count error", func(t *testing.T) {
+		mockRepo.EXPECT().FindAll(gomock.Any(), criteria, user.PageRequest{First: 10}).Return(&user.Page{}, nil)
+		mockRepo.EXPECT().Count(gomock.Any(), filter).Return(int64(0), errors.ErrRepositoryOperation)
+
+		got, err := service.ListUsers(context.Background(), request)
+		require.NoError(t, err)
+		assert.Nil(t, got.Users)
+		require.Len(t, got.Errors, 1)
+		assert.Equal(t, "REPOSITORY_OPERATION", got.Errors[0].Code)
+	})
+}
+
+func TestService_SearchUsers(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockSearch := mocks.NewMockSearchRepository(ctrl)
+	codec := NewCursorCodec([]byte(testCursorSecret))
+	service := NewService(mocks.NewMockRepository(ctrl), mockSearch, nil, codec, &recordingBroker{}, domainEvents.NewDomainEventDispatcher(slog.Default()), unitofwork.Passthrough{}, slog.Default())
+
+	john, err := user.NewUserWithID("123e4567-e89b-12d3-a456-426614174001", "john.doe@example.com", "John <Doe>", time.Now(), time.Now())
+	require.NoError(t, err)
+	hit := user.SearchHit{User: john, Rank: 0.75}
+
+	t.Run("ranks and highlights matches", func(t *testing.T) {
+		mockSearch.EXPECT().Search(gomock.Any(), user.SearchQuery{Text: "doe", First: 10}).
+			Return(&user.SearchPage{Hits: []user.SearchHit{hit}, HasNextPage: true}, nil)
+
+		got, err := service.SearchUsers(context.Background(), SearchUsersRequest{Query: "  doe "})
+		require.NoError(t, err)
+		require.Empty(t, got.Errors)
+		require.Len(t, got.Results.Edges, 1)
+
+		edge := got.Results.Edges[0]
+		assert.Equal(t, john.ID().String(), edge.Node.ID)
+		assert.Equal(t, 0.75, edge.Rank)
+		require.NotNil(t, edge.Highlights.Name)
+		assert.Equal(t, "John &lt;<mark>Doe</mark>&gt;", *edge.Highlights.Name)
+		require.NotNil(t, edge.Highlights.Email)
+		assert.Equal(t, "john.<mark>doe</mark>@example.com", *edge.Highlights.Email)
+
+		assert.True(t, got.Results.PageInfo.HasNextPage)
+		assert.False(t, got.Results.PageInfo.HasPreviousPage)
+		assert.Equal(t, &edge.Cursor, got.Results.PageInfo.EndCursor)
+
+		cursor, err := codec.DecodeSearch(edge.Cursor, "doe")
+		require.NoError(t, err)
+		assert.Equal(t, hit.Cursor(), cursor)
+	})
+
+	t.Run("resumes after a cursor", func(t *testing.T) {
+		after := hit.Cursor()
+		mockSearch.EXPECT().Search(gomock.Any(), user.SearchQuery{Text: "doe", First: 5, After: &after}).
+			Return(&user.SearchPage{}, nil)
+
+		got, err := service.SearchUsers(context.Background(), SearchUsersRequest{Query: "doe", First: 5, After: codec.EncodeSearch("doe", after)})
+		require.NoError(t, err)
+		require.Empty(t, got.Errors)
+		assert.Empty(t, got.Results.Edges)
+		assert.True(t, got.Results.PageInfo.HasPreviousPage)
+		assert.Nil(t, got.Results.PageInfo.StartCursor)
+	})
+
+	t.Run("leaves fields without matches unhighlighted", func(t *testing.T) {
+		mockSearch.EXPECT().Search(gomock.Any(), gomock.Any()).
+			Return(&user.SearchPage{Hits: []user.SearchHit{hit}}, nil)
+
+		got, err := service.SearchUsers(context.Background(), SearchUsersRequest{Query: "example"})
+		require.NoError(t, err)
+		require.Len(t, got.Results.Edges, 1)
+		assert.Nil(t, got.Results.Edges[0].Highlights.Name)
+		assert.NotNil(t, got.Results.Edges[0].Highlights.Email)
+	})
+
+	errorTests := []struct {
+		name     string
+		request  SearchUsersRequest
+		wantCode string
+	}{
+		{name: "no words", request: SearchUsersRequest{Query: "@."}, wantCode: "INVALID_SEARCH_QUERY"},
+		{name: "too many results", request: SearchUsersRequest{Query: "doe", First: 101}, wantCode: "INVALID_FIRST"},
+		{name: "cursor of another search", request: SearchUsersRequest{Query: "doe", After: codec.EncodeSearch("jane", hit.Cursor())}, wantCode: "INVALID_CURSOR"},
+		{name: "list cursor", request: SearchUsersRequest{Query: "doe", After: codec.Encode(user.DefaultSort, user.CursorOf(john, user.DefaultSort))}, wantCode: "INVALID_CURSOR"},
+	}
+
+	for _, tt := range errorTests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := service.SearchUsers(context.Background(), tt.request)
+			require.NoError(t, err)
+			assert.Nil(t, got.Results)
+			require.Len(t, got.Errors, 1)
+			assert.Equal(t, tt.wantCode, got.Errors[0].Code)
+		})
+	}
+
+	t.Run("repository error", func(t *testing.T) {
+		mockSearch.EXPECT().Search(gomock.Any(), gomock.Any()).Return(nil, errors.ErrRepositoryOperation)
+
+		got, err := service.SearchUsers(context.Background(), SearchUsersRequest{Query: "doe"})
+		require.NoError(t, err)
+		assert.Nil(t, got.Results)
+		require.Len(t, got.Errors, 1)
+		assert.Equal(t, "REPOSITORY_OPERATION", got.Errors[0].Code)
+	})
+}
+
+func TestService_GetUserStats(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	service := NewService(mockRepo, nil, nil, NewCursorCodec([]byte(testCursorSecret)), &recordingBroker{}, domainEvents.NewDomainEventDispatcher(slog.Default()), unitofwork.Passthrough{}, slog.Default())
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.AddDate(0, 0, 3)
+
+	tests := []struct {
+		name    string
+		request GetUserStatsRequest
+		setup   func()
+		want    *GetUserStatsResponse
+	}{
+		{
+			name:    "daily signups",
+			request: GetUserStatsRequest{From: from, To: to, Interval: "DAY"},
+			setup: func() {
+				mockRepo.EXPECT().
+					CountSignups(gomock.Any(), user.SignupStatsQuery{From: from, To: to, Interval: user.StatsIntervalDay}).
+					Return([]user.SignupBucket{
+						{Start: from, Count: 3},
+						{Start: from.AddDate(0, 0, 1), Count: 0},
+						{Start: from.AddDate(0, 0, 2), Count: 4},
+					}, nil)
+			},
+			want: &GetUserStatsResponse{
+				Stats: &UserStatsDTO{
+					From:         from,
+					To:           to,
+					Interval:     "DAY",
+					TotalSignups: 7,
+					Signups: []*SignupBucketDTO{
+						{Start: from, Count: 3},
+						{Start: from.AddDate(0, 0, 1), Count: 0},
+						{Start: from.AddDate(0, 0, 2), Count: 4},
+					},
+				},
+			},
+		},
+		{
+			name:    "unknown interval",
+			request: GetUserStatsRequest{From: from, To: to, Interval: "YEAR"},
+			setup:   func() {},
+			want: &GetUserStatsResponse{
+				Errors: []ErrorDTO{{Code: "INVALID_STATS_INTERVAL", Message: "Invalid stats interval", Field: "interval"}},
+			},
+		},
+		{
+			name:    "inverted range",
+			request: GetUserStatsRequest{From: to, To: from, Interval: "WEEK"},
+			setup:   func() {},
+			want: &GetUserStatsResponse{
+				Errors: []ErrorDTO{{Code: "INVALID_STATS_RANGE", Message: "Stats range start must be before its end", Field: "from"}},
+			},
+		},
+		{
+			name:    "repository error",
+			request: GetUserStatsRequest{From: from, To: to, Interval: "MONTH"},
+			setup: func() {
+				mockRepo.EXPECT().
+					CountSignups(gomock.Any(), user.SignupStatsQuery{From: from, To: to, Interval: user.StatsIntervalMonth}).
+					Return(nil, errors.ErrRepositoryOperation)
+			},
+			want: &GetUserStatsResponse{
+				Errors: []ErrorDTO{{Code: "REPOSITORY_OPERATION", Message: "Repository operation failed"}},
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			got, err := service.GetUserStats(context.Background(), tt.request)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
 func TestService_UpdateUser(t *testing.T) {
 	ctrl := gomock.NewController(t)
 	defer ctrl.Finish()
 
 	mockRepo := mocks.NewMockRepository(ctrl)
 	logger := slog.Default()
-	service := NewService(mockRepo, logger)
+	service := NewService(mockRepo, nil, nil, NewCursorCodec([]byte(testCursorSecret)), &recordingBroker{}, domainEvents.NewDomainEventDispatcher(logger), unitofwork.Passthrough{}, logger)
 
 	// Helper function to create a fresh test user for each test
 	createTestUser := func() *user.User {
@@ -735,60 +1314,133 @@ func TestService_UpdateUser(t *testing.T) {
 				Name:  stringPtr(""),
 			},
 			setup: func() {
-				// No mock setup needed as validation happens before repository call
+				// No mock setup needed as validation happens before repository call
+			},
+			want: &UpdateUserResponse{
+				Errors: []ErrorDTO{
+					{
+						Message: "Name cannot be empty",
+						Field:   "name",
+						Code:    "INVALID_NAME",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "invalid user ID",
+			request: UpdateUserRequest{
+				ID:    "",
+				Email: stringPtr("test@example.com"),
+				Name:  nil,
+			},
+			setup: func() {
+				// No mock setup needed as validation happens before repository call
+			},
+			want: &UpdateUserResponse{
+				Errors: []ErrorDTO{
+					{
+						Message: "Invalid user ID format",
+						Field:   "id",
+						Code:    "INVALID_USER_ID",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "repository update error",
+			request: UpdateUserRequest{
+				ID:    "123e4567-e89b-12d3-a456-426614174000",
+				Email: stringPtr("newemail@example.com"),
+				Name:  nil,
+			},
+			setup: func() {
+				userID, _ := user.NewUserID("123e4567-e89b-12d3-a456-426614174000")
+				newEmail, _ := user.NewEmail("newemail@example.com")
+
+				mockRepo.EXPECT().FindByID(gomock.Any(), userID).Return(createTestUser(), nil)
+				mockRepo.EXPECT().ExistsByEmail(gomock.Any(), newEmail).Return(false, nil)
+				mockRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(errors.ErrRepositoryOperation)
+			},
+			want: &UpdateUserResponse{
+				Errors: []ErrorDTO{
+					{
+						Message: "Repository operation failed",
+						Code:    "REPOSITORY_OPERATION",
+					},
+				},
+			},
+			wantErr: false,
+		},
+		{
+			name: "successful user update - matching expected version",
+			request: UpdateUserRequest{
+				ID:              "123e4567-e89b-12d3-a456-426614174000",
+				Name:            stringPtr("New Name"),
+				ExpectedVersion: int64Ptr(1),
+			},
+			setup: func() {
+				userID, _ := user.NewUserID("123e4567-e89b-12d3-a456-426614174000")
+
+				mockRepo.EXPECT().FindByID(gomock.Any(), userID).Return(createTestUser(), nil)
+				mockRepo.EXPECT().Update(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, u *user.User) error {
+						u.IncrementVersion()
+						return nil
+					})
 			},
 			want: &UpdateUserResponse{
-				Errors: []ErrorDTO{
-					{
-						Message: "Name cannot be empty",
-						Field:   "name",
-						Code:    "INVALID_NAME",
-					},
+				User: &UserDTO{
+					ID:      "123e4567-e89b-12d3-a456-426614174000",
+					Email:   "test@example.com",
+					Name:    "New Name",
+					Version: 2,
 				},
 			},
 			wantErr: false,
 		},
 		{
-			name: "invalid user ID",
+			name: "stale expected version",
 			request: UpdateUserRequest{
-				ID:    "",
-				Email: stringPtr("test@example.com"),
-				Name:  nil,
+				ID:              "123e4567-e89b-12d3-a456-426614174000",
+				Name:            stringPtr("New Name"),
+				ExpectedVersion: int64Ptr(3),
 			},
 			setup: func() {
-				// No mock setup needed as validation happens before repository call
+				userID, _ := user.NewUserID("123e4567-e89b-12d3-a456-426614174000")
+
+				mockRepo.EXPECT().FindByID(gomock.Any(), userID).Return(createTestUser(), nil)
 			},
 			want: &UpdateUserResponse{
 				Errors: []ErrorDTO{
 					{
-						Message: "Invalid user ID format",
-						Field:   "id",
-						Code:    "INVALID_USER_ID",
+						Message:        "User was modified concurrently; current version is 1",
+						Code:           "CONFLICT",
+						CurrentVersion: int64Ptr(1),
 					},
 				},
 			},
 			wantErr: false,
 		},
 		{
-			name: "repository update error",
+			name: "concurrent write between read and update",
 			request: UpdateUserRequest{
-				ID:    "123e4567-e89b-12d3-a456-426614174000",
-				Email: stringPtr("newemail@example.com"),
-				Name:  nil,
+				ID:   "123e4567-e89b-12d3-a456-426614174000",
+				Name: stringPtr("New Name"),
 			},
 			setup: func() {
 				userID, _ := user.NewUserID("123e4567-e89b-12d3-a456-426614174000")
-				newEmail, _ := user.NewEmail("newemail@example.com")
 
 				mockRepo.EXPECT().FindByID(gomock.Any(), userID).Return(createTestUser(), nil)
-				mockRepo.EXPECT().ExistsByEmail(gomock.Any(), newEmail).Return(false, nil)
-				mockRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(errors.ErrRepositoryOperation)
+				mockRepo.EXPECT().Update(gomock.Any(), gomock.Any()).Return(errors.NewConflictError(2))
 			},
 			want: &UpdateUserResponse{
 				Errors: []ErrorDTO{
 					{
-						Message: "Repository operation failed",
-						Code:    "REPOSITORY_OPERATION",
+						Message:        "User was modified concurrently; current version is 2",
+						Code:           "CONFLICT",
+						CurrentVersion: int64Ptr(2),
 					},
 				},
 			},
@@ -817,6 +1469,9 @@ func TestService_UpdateUser(t *testing.T) {
 				assert.Equal(t, tt.want.User.Name, got.User.Name)
 				assert.False(t, got.User.CreatedAt.IsZero())
 				assert.False(t, got.User.UpdatedAt.IsZero())
+				if tt.want.User.Version != 0 {
+					assert.Equal(t, tt.want.User.Version, got.User.Version)
+				}
 			}
 
 			if len(tt.want.Errors) > 0 {
@@ -825,6 +1480,7 @@ func TestService_UpdateUser(t *testing.T) {
 					assert.Equal(t, expectedErr.Code, got.Errors[i].Code)
 					assert.Equal(t, expectedErr.Message, got.Errors[i].Message)
 					assert.Equal(t, expectedErr.Field, got.Errors[i].Field)
+					assert.Equal(t, expectedErr.CurrentVersion, got.Errors[i].CurrentVersion)
 				}
 			}
 		})
@@ -837,17 +1493,20 @@ func TestService_DeleteUser(t *testing.T) {
 
 	mockRepo := mocks.NewMockRepository(ctrl)
 	logger := slog.Default()
-	service := NewService(mockRepo, logger)
+	service := NewService(mockRepo, nil, nil, NewCursorCodec([]byte(testCursorSecret)), &recordingBroker{}, domainEvents.NewDomainEventDispatcher(logger), unitofwork.Passthrough{}, logger)
 
-	// Create a test user
-	testUser, err := user.NewUserWithID(
-		"123e4567-e89b-12d3-a456-426614174000",
-		"test@example.com",
-		"Test User",
-		time.Now().Add(-time.Hour),
-		time.Now(),
-	)
-	require.NoError(t, err)
+	// Helper function to create a fresh test user for each test, since deleting marks it deleted
+	createTestUser := func() *user.User {
+		testUser, err := user.NewUserWithID(
+			"123e4567-e89b-12d3-a456-426614174000",
+			"test@example.com",
+			"Test User",
+			time.Now().Add(-time.Hour),
+			time.Now(),
+		)
+		require.NoError(t, err)
+		return testUser
+	}
 
 	tests := []struct {
 		name    string
@@ -863,8 +1522,8 @@ func TestService_DeleteUser(t *testing.T) {
 			},
 			setup: func() {
 				userID, _ := user.NewUserID("123e4567-e89b-12d3-a456-426614174000")
-				mockRepo.EXPECT().FindByID(gomock.Any(), userID).Return(testUser, nil)
-				mockRepo.EXPECT().Delete(gomock.Any(), userID).Return(nil)
+				mockRepo.EXPECT().FindByID(gomock.Any(), userID).Return(createTestUser(), nil)
+				mockRepo.EXPECT().Delete(gomock.Any(), gomock.Any()).Return(nil)
 			},
 			want: &DeleteUserResponse{
 				Success: true,
@@ -918,8 +1577,8 @@ func TestService_DeleteUser(t *testing.T) {
 			},
 			setup: func() {
 				userID, _ := user.NewUserID("123e4567-e89b-12d3-a456-426614174000")
-				mockRepo.EXPECT().FindByID(gomock.Any(), userID).Return(testUser, nil)
-				mockRepo.EXPECT().Delete(gomock.Any(), userID).Return(errors.ErrRepositoryOperation)
+				mockRepo.EXPECT().FindByID(gomock.Any(), userID).Return(createTestUser(), nil)
+				mockRepo.EXPECT().Delete(gomock.Any(), gomock.Any()).Return(errors.ErrRepositoryOperation)
 			},
 			want: &DeleteUserResponse{
 				Success: false,
@@ -964,6 +1623,366 @@ func TestService_DeleteUser(t *testing.T) {
 	}
 }
 
+func TestService_ListUsers_IncludeDeleted(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	service := NewService(mockRepo, nil, nil, NewCursorCodec([]byte(testCursorSecret)), &recordingBroker{}, domainEvents.NewDomainEventDispatcher(slog.Default()), unitofwork.Passthrough{}, slog.Default())
+
+	deletedAt := time.Now()
+	deletedUser, err := user.NewUserFromSnapshot(user.Snapshot{
+		ID:        "123e4567-e89b-12d3-a456-426614174000",
+		Email:     "deleted@example.com",
+		Name:      "Deleted User",
+		CreatedAt: deletedAt.Add(-time.Hour),
+		UpdatedAt: deletedAt,
+		DeletedAt: &deletedAt,
+		Status:    user.StatusActive,
+	})
+	require.NoError(t, err)
+
+	criteria := user.Criteria{Filter: user.Filter{IncludeDeleted: true}, Sort: user.DefaultSort}
+	mockRepo.EXPECT().FindAll(gomock.Any(), criteria, user.PageRequest{First: 10}).
+		Return(&user.Page{Users: []*user.User{deletedUser}}, nil)
+
+	got, err := service.ListUsers(context.Background(), ListUsersRequest{First: 10, IncludeDeleted: true})
+	require.NoError(t, err)
+	require.Empty(t, got.Errors)
+	require.Len(t, got.Users.Edges, 1)
+	require.NotNil(t, got.Users.Edges[0].Node.DeletedAt)
+	assert.True(t, deletedAt.Equal(*got.Users.Edges[0].Node.DeletedAt))
+}
+
+func TestService_RestoreUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	service := NewService(mockRepo, nil, nil, NewCursorCodec([]byte(testCursorSecret)), &recordingBroker{}, domainEvents.NewDomainEventDispatcher(slog.Default()), unitofwork.Passthrough{}, slog.Default())
+
+	const id = "123e4567-e89b-12d3-a456-426614174000"
+	userID, err := user.NewUserID(id)
+	require.NoError(t, err)
+
+	// found returns a page holding a fresh copy of the user, deleted or not
+	found := func(deleted bool) *user.Page {
+		snapshot := user.Snapshot{
+			ID:        id,
+			Email:     "test@example.com",
+			Name:      "Test User",
+			CreatedAt: time.Now().Add(-time.Hour),
+			UpdatedAt: time.Now(),
+			Status:    user.StatusActive,
+			Version:   2,
+		}
+		if deleted {
+			deletedAt := time.Now()
+			snapshot.DeletedAt = &deletedAt
+		}
+		u, err := user.NewUserFromSnapshot(snapshot)
+		require.NoError(t, err)
+		return &user.Page{Users: []*user.User{u}}
+	}
+
+	tests := []struct {
+		name      string
+		request   RestoreUserRequest
+		setup     func()
+		wantUser  bool
+		wantError string
+	}{
+		{
+			name:    "successful restore",
+			request: RestoreUserRequest{ID: userID.String()},
+			setup: func() {
+				mockRepo.EXPECT().FindAll(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, criteria user.Criteria, _ user.PageRequest) (*user.Page, error) {
+						assert.True(t, criteria.Filter.IncludeDeleted)
+						assert.Equal(t, []user.UserID{userID}, criteria.Filter.IDs)
+						return found(true), nil
+					})
+				mockRepo.EXPECT().Restore(gomock.Any(), gomock.Any()).DoAndReturn(
+					func(_ context.Context, u *user.User) error {
+						assert.False(t, u.IsDeleted())
+						return nil
+					})
+			},
+			wantUser: true,
+		},
+		{
+			name:    "user is not deleted",
+			request: RestoreUserRequest{ID: userID.String()},
+			setup: func() {
+				mockRepo.EXPECT().FindAll(gomock.Any(), gomock.Any(), gomock.Any()).Return(found(false), nil)
+			},
+			wantError: "USER_NOT_DELETED",
+		},
+		{
+			name:    "email taken by a live user",
+			request: RestoreUserRequest{ID: userID.String()},
+			setup: func() {
+				mockRepo.EXPECT().FindAll(gomock.Any(), gomock.Any(), gomock.Any()).Return(found(true), nil)
+				mockRepo.EXPECT().Restore(gomock.Any(), gomock.Any()).Return(errors.ErrDuplicateEmail)
+			},
+			wantError: "DUPLICATE_EMAIL",
+		},
+		{
+			name:    "user not found",
+			request: RestoreUserRequest{ID: userID.String()},
+			setup: func() {
+				mockRepo.EXPECT().FindAll(gomock.Any(), gomock.Any(), gomock.Any()).Return(&user.Page{}, nil)
+			},
+			wantError: "USER_NOT_FOUND",
+		},
+		{
+			name:      "invalid user ID",
+			request:   RestoreUserRequest{ID: ""},
+			setup:     func() {},
+			wantError: "INVALID_USER_ID",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			got, err := service.RestoreUser(context.Background(), tt.request)
+			require.NoError(t, err)
+			require.NotNil(t, got)
+
+			if tt.wantError != "" {
+				require.Len(t, got.Errors, 1)
+				assert.Equal(t, tt.wantError, got.Errors[0].Code)
+				assert.Nil(t, got.User)
+				return
+			}
+
+			assert.Empty(t, got.Errors)
+			require.NotNil(t, got.User)
+			assert.Equal(t, userID.String(), got.User.ID)
+			assert.Nil(t, got.User.DeletedAt)
+		})
+	}
+}
+
+func TestService_PurgeUser(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	service := NewService(mockRepo, nil, nil, NewCursorCodec([]byte(testCursorSecret)), &recordingBroker{}, domainEvents.NewDomainEventDispatcher(slog.Default()), unitofwork.Passthrough{}, slog.Default())
+
+	testUser, err := user.NewUserWithID(
+		"123e4567-e89b-12d3-a456-426614174000",
+		"test@example.com",
+		"Test User",
+		time.Now().Add(-time.Hour),
+		time.Now(),
+	)
+	require.NoError(t, err)
+	userID := testUser.ID()
+
+	admin := auth.WithPrincipal(context.Background(), auth.Principal{Roles: []auth.Role{auth.RoleAdmin}})
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		request   PurgeUserRequest
+		setup     func()
+		wantError string
+	}{
+		{
+			name:    "admin purges user",
+			ctx:     admin,
+			request: PurgeUserRequest{ID: userID.String()},
+			setup: func() {
+				mockRepo.EXPECT().FindAll(gomock.Any(), gomock.Any(), gomock.Any()).Return(&user.Page{Users: []*user.User{testUser}}, nil)
+				mockRepo.EXPECT().Purge(gomock.Any(), testUser).Return(nil)
+			},
+		},
+		{
+			name:      "anonymous caller is forbidden",
+			ctx:       context.Background(),
+			request:   PurgeUserRequest{ID: userID.String()},
+			setup:     func() {},
+			wantError: "FORBIDDEN",
+		},
+		{
+			name:    "user not found",
+			ctx:     admin,
+			request: PurgeUserRequest{ID: userID.String()},
+			setup: func() {
+				mockRepo.EXPECT().FindAll(gomock.Any(), gomock.Any(), gomock.Any()).Return(&user.Page{}, nil)
+			},
+			wantError: "USER_NOT_FOUND",
+		},
+		{
+			name:      "invalid user ID",
+			ctx:       admin,
+			request:   PurgeUserRequest{ID: "not-a-uuid"},
+			setup:     func() {},
+			wantError: "INVALID_USER_ID",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+
+			got, err := service.PurgeUser(tt.ctx, tt.request)
+			require.NoError(t, err)
+			require.NotNil(t, got)
+
+			if tt.wantError != "" {
+				assert.False(t, got.Success)
+				require.Len(t, got.Errors, 1)
+				assert.Equal(t, tt.wantError, got.Errors[0].Code)
+				return
+			}
+
+			assert.True(t, got.Success)
+			assert.Empty(t, got.Errors)
+		})
+	}
+}
+
+func TestService_ChangeUserStatus(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	broker := &recordingBroker{}
+	service := NewService(mockRepo, nil, nil, NewCursorCodec([]byte(testCursorSecret)), broker, domainEvents.NewDomainEventDispatcher(slog.Default()), unitofwork.Passthrough{}, slog.Default())
+
+	const id = "123e4567-e89b-12d3-a456-426614174000"
+	userID, err := user.NewUserID(id)
+	require.NoError(t, err)
+
+	// newUser returns a fresh user in the given status for each case
+	newUser := func(status user.Status) *user.User {
+		u, err := user.NewUserFromSnapshot(user.Snapshot{
+			ID:        id,
+			Email:     "test@example.com",
+			Name:      "Test User",
+			CreatedAt: time.Now().Add(-time.Hour),
+			UpdatedAt: time.Now().Add(-time.Hour),
+			Status:    status,
+		})
+		require.NoError(t, err)
+		return u
+	}
+
+	admin := auth.WithPrincipal(context.Background(), auth.Principal{Subject: "support-agent", Roles: []auth.Role{auth.RoleAdmin}})
+
+	tests := []struct {
+		name      string
+		ctx       context.Context
+		request   ChangeUserStatusRequest
+		setup     func()
+		wantError string
+	}{
+		{
+			name:    "suspend active user",
+			ctx:     admin,
+			request: ChangeUserStatusRequest{ID: id, Status: "SUSPENDED", Reason: "Chargeback investigation"},
+			setup: func() {
+				mockRepo.EXPECT().FindByID(gomock.Any(), userID).Return(newUser(user.StatusActive), nil)
+				mockRepo.EXPECT().UpdateStatus(gomock.Any(), gomock.Any(), gomock.Any()).
+					DoAndReturn(func(_ context.Context, u *user.User, transition user.StatusTransition) error {
+						assert.Equal(t, user.StatusSuspended, u.Status())
+						assert.Equal(t, user.StatusActive, transition.From)
+						assert.Equal(t, "support-agent", transition.Actor)
+						return nil
+					})
+			},
+		},
+		{
+			name:    "illegal transition",
+			ctx:     admin,
+			request: ChangeUserStatusRequest{ID: id, Status: "SUSPENDED", Reason: "Spam"},
+			setup: func() {
+				mockRepo.EXPECT().FindByID(gomock.Any(), userID).Return(newUser(user.StatusDeactivated), nil)
+			},
+			wantError: "INVALID_STATUS_TRANSITION",
+		},
+		{
+			name:    "concurrent transition",
+			ctx:     admin,
+			request: ChangeUserStatusRequest{ID: id, Status: "ACTIVE", Reason: "Resolved"},
+			setup: func() {
+				mockRepo.EXPECT().FindByID(gomock.Any(), userID).Return(newUser(user.StatusSuspended), nil)
+				mockRepo.EXPECT().UpdateStatus(gomock.Any(), gomock.Any(), gomock.Any()).
+					Return(user.NewInvalidStatusTransitionError(user.StatusActive, user.StatusActive))
+			},
+			wantError: "INVALID_STATUS_TRANSITION",
+		},
+		{
+			name:    "missing reason",
+			ctx:     admin,
+			request: ChangeUserStatusRequest{ID: id, Status: "DEACTIVATED"},
+			setup: func() {
+				mockRepo.EXPECT().FindByID(gomock.Any(), userID).Return(newUser(user.StatusActive), nil)
+			},
+			wantError: "INVALID_STATUS_REASON",
+		},
+		{
+			name:      "unknown status",
+			ctx:       admin,
+			request:   ChangeUserStatusRequest{ID: id, Status: "BANNED", Reason: "Spam"},
+			setup:     func() {},
+			wantError: "INVALID_STATUS",
+		},
+		{
+			name:      "anonymous caller is forbidden",
+			ctx:       context.Background(),
+			request:   ChangeUserStatusRequest{ID: id, Status: "SUSPENDED", Reason: "Spam"},
+			setup:     func() {},
+			wantError: "FORBIDDEN",
+		},
+		{
+			name:    "user not found",
+			ctx:     admin,
+			request: ChangeUserStatusRequest{ID: id, Status: "SUSPENDED", Reason: "Spam"},
+			setup: func() {
+				mockRepo.EXPECT().FindByID(gomock.Any(), userID).Return(nil, errors.ErrUserNotFound)
+			},
+			wantError: "USER_NOT_FOUND",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.setup()
+			published := len(broker.published)
+
+			got, err := service.ChangeUserStatus(tt.ctx, tt.request)
+			require.NoError(t, err)
+			require.NotNil(t, got)
+
+			if tt.wantError != "" {
+				require.Len(t, got.Errors, 1)
+				assert.Equal(t, tt.wantError, got.Errors[0].Code)
+				assert.Nil(t, got.User)
+				assert.Len(t, broker.published, published)
+				return
+			}
+
+			assert.Empty(t, got.Errors)
+			require.NotNil(t, got.User)
+			assert.Equal(t, tt.request.Status, got.User.Status)
+			require.NotNil(t, got.Transition)
+			assert.Equal(t, tt.request.Status, got.Transition.To)
+			assert.Equal(t, tt.request.Reason, got.Transition.Reason)
+			assert.Equal(t, "support-agent", got.Transition.Actor)
+
+			// Status changes notify userUpdated subscribers
+			require.Len(t, broker.published, published+1)
+			assert.Equal(t, EventUserUpdated, broker.published[published].Type)
+		})
+	}
+}
+
 // Test helper functions
 
 func TestMapDomainUserToDTO(t *testing.T) {
@@ -1129,6 +2148,26 @@ func TestService_ValidateListUsersRequest(t *testing.T) {
 			request: ListUsersRequest{First: 101, After: ""},
 			wantErr: errors.DomainError{Code: "INVALID_FIRST", Message: "First parameter cannot exceed 100", Field: "first"},
 		},
+		{
+			name:    "valid last",
+			request: ListUsersRequest{Last: 10, Before: ""},
+			wantErr: nil,
+		},
+		{
+			name:    "negative last",
+			request: ListUsersRequest{Last: -1},
+			wantErr: errors.DomainError{Code: "INVALID_LAST", Message: "Last parameter must be non-negative", Field: "last"},
+		},
+		{
+			name:    "last too large",
+			request: ListUsersRequest{Last: 101},
+			wantErr: errors.DomainError{Code: "INVALID_LAST", Message: "Last parameter cannot exceed 100", Field: "last"},
+		},
+		{
+			name:    "first and last combined",
+			request: ListUsersRequest{First: 10, Last: 10},
+			wantErr: errors.ErrInvalidPagination,
+		},
 	}
 
 	for _, tt := range tests {
@@ -1259,7 +2298,125 @@ func TestService_ValidateDeleteUserRequest(t *testing.T) {
 	}
 }
 
+// uowKey marks contexts handed out by the test unit of work
+type uowKey struct{}
+
+func TestService_CreateUserRunsInUnitOfWork(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	mockUoW := uowMocks.NewMockUnitOfWork(ctrl)
+	broker := &recordingBroker{}
+	service := NewService(mockRepo, nil, nil, NewCursorCodec([]byte(testCursorSecret)), broker, domainEvents.NewDomainEventDispatcher(slog.Default()), mockUoW, slog.Default())
+
+	inUnit := func(ctx context.Context) {
+		assert.Equal(t, true, ctx.Value(uowKey{}), "repository called outside the unit of work")
+	}
+
+	t.Run("check and create share the unit of work", func(t *testing.T) {
+		mockUoW.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, fn func(context.Context) error) error {
+				return fn(context.WithValue(ctx, uowKey{}, true))
+			})
+		mockRepo.EXPECT().ExistsByEmail(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, _ user.Email) (bool, error) {
+				inUnit(ctx)
+				return false, nil
+			})
+		mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, _ *user.User) error {
+				inUnit(ctx)
+				return nil
+			})
+
+		resp, err := service.CreateUser(context.Background(), CreateUserRequest{Email: "uow@example.com", Name: "Unit Of Work"})
+		require.NoError(t, err)
+		assert.Empty(t, resp.Errors)
+		assert.Len(t, broker.events(), 1)
+	})
+
+	t.Run("failed commit reports an error and publishes nothing", func(t *testing.T) {
+		before := len(broker.events())
+
+		mockUoW.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(
+			func(ctx context.Context, fn func(context.Context) error) error {
+				if err := fn(context.WithValue(ctx, uowKey{}, true)); err != nil {
+					return err
+				}
+				return fmt.Errorf("failed to commit transaction: %w", assert.AnError)
+			})
+		mockRepo.EXPECT().ExistsByEmail(gomock.Any(), gomock.Any()).Return(false, nil)
+		mockRepo.EXPECT().Create(gomock.Any(), gomock.Any()).Return(nil)
+
+		resp, err := service.CreateUser(context.Background(), CreateUserRequest{Email: "uow@example.com", Name: "Unit Of Work"})
+		require.NoError(t, err)
+		require.Len(t, resp.Errors, 1)
+		assert.Equal(t, "INTERNAL_ERROR", resp.Errors[0].Code)
+		assert.Nil(t, resp.User)
+		assert.Len(t, broker.events(), before)
+	})
+}
+
+func TestService_RestoreAndPurgeRunInUnitOfWork(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockRepo := mocks.NewMockRepository(ctrl)
+	mockUoW := uowMocks.NewMockUnitOfWork(ctrl)
+	service := NewService(mockRepo, nil, nil, NewCursorCodec([]byte(testCursorSecret)), &recordingBroker{}, domainEvents.NewDomainEventDispatcher(slog.Default()), mockUoW, slog.Default())
+
+	inUnit := func(ctx context.Context) {
+		assert.Equal(t, true, ctx.Value(uowKey{}), "repository called outside the unit of work")
+	}
+	deletedAt := time.Now()
+	deleted, err := user.NewUserFromSnapshot(user.Snapshot{
+		ID:        "123e4567-e89b-12d3-a456-426614174000",
+		Email:     "test@example.com",
+		Name:      "Test User",
+		CreatedAt: time.Now().Add(-time.Hour),
+		UpdatedAt: time.Now().Add(-time.Hour),
+		DeletedAt: &deletedAt,
+		Status:    user.StatusActive,
+		Version:   2,
+	})
+	require.NoError(t, err)
+
+	mockUoW.EXPECT().Do(gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, fn func(context.Context) error) error {
+			return fn(context.WithValue(ctx, uowKey{}, true))
+		}).Times(2)
+	mockRepo.EXPECT().FindAll(gomock.Any(), gomock.Any(), gomock.Any()).DoAndReturn(
+		func(ctx context.Context, _ user.Criteria, _ user.PageRequest) (*user.Page, error) {
+			inUnit(ctx)
+			return &user.Page{Users: []*user.User{deleted}}, nil
+		}).Times(2)
+	mockRepo.EXPECT().Restore(gomock.Any(), deleted).DoAndReturn(
+		func(ctx context.Context, _ *user.User) error {
+			inUnit(ctx)
+			return nil
+		})
+	mockRepo.EXPECT().Purge(gomock.Any(), deleted).DoAndReturn(
+		func(ctx context.Context, _ *user.User) error {
+			inUnit(ctx)
+			return nil
+		})
+
+	restored, err := service.RestoreUser(context.Background(), RestoreUserRequest{ID: deleted.ID().String()})
+	require.NoError(t, err)
+	assert.Empty(t, restored.Errors)
+
+	admin := auth.WithPrincipal(context.Background(), auth.Principal{Roles: []auth.Role{auth.RoleAdmin}})
+	purged, err := service.PurgeUser(admin, PurgeUserRequest{ID: deleted.ID().String()})
+	require.NoError(t, err)
+	assert.True(t, purged.Success)
+}
+
 // Helper functions for tests
 func stringPtr(s string) *string {
 	return &s
 }
+
+func int64Ptr(v int64) *int64 {
+	return &v
+}
@@ -24,6 +24,67 @@ var (
 	ErrInvalidName       = DomainError{Code: "INVALID_NAME", Message: "Name cannot be empty", Field: "name"}
 	ErrInvalidUserID     = DomainError{Code: "INVALID_USER_ID", Message: "Invalid user ID format", Field: "id"}
 	ErrUserAlreadyExists = DomainError{Code: "USER_ALREADY_EXISTS", Message: "User already exists"}
+	ErrUserNotDeleted    = DomainError{Code: "USER_NOT_DELETED", Message: "User is not deleted"}
+)
+
+// Status errors
+var (
+	ErrInvalidStatus           = DomainError{Code: "INVALID_STATUS", Message: "Invalid user status", Field: "status"}
+	ErrInvalidStatusTransition = DomainError{Code: "INVALID_STATUS_TRANSITION", Message: "Status transition is not allowed", Field: "status"}
+	ErrInvalidStatusReason     = DomainError{Code: "INVALID_STATUS_REASON", Message: "Reason is required and cannot exceed 500 characters", Field: "reason"}
+	ErrStatusActorRequired     = DomainError{Code: "STATUS_ACTOR_REQUIRED", Message: "Status changes must record who made them"}
+)
+
+// ConflictError reports that a user changed after the caller read it
+// CurrentVersion is the version now stored; callers refetch and retry against it
+type ConflictError struct {
+	DomainError
+	CurrentVersion int64
+}
+
+// NewConflictError creates a conflict error carrying the stored version
+func NewConflictError(currentVersion int64) ConflictError {
+	return ConflictError{
+		DomainError: DomainError{
+			Code:    "CONFLICT",
+			Message: fmt.Sprintf("User was modified concurrently; current version is %d", currentVersion),
+		},
+		CurrentVersion: currentVersion,
+	}
+}
+
+// Pagination errors
+var (
+	ErrInvalidCursor     = DomainError{Code: "INVALID_CURSOR", Message: "Invalid pagination cursor", Field: "after"}
+	ErrInvalidPagination = DomainError{Code: "INVALID_PAGINATION", Message: "Cannot combine first and last"}
+	ErrInvalidFilter     = DomainError{Code: "INVALID_FILTER", Message: "Invalid filter", Field: "filter"}
+	ErrInvalidSort       = DomainError{Code: "INVALID_SORT", Message: "Invalid sort order", Field: "orderBy"}
+)
+
+// Stats errors
+var (
+	ErrInvalidStatsInterval = DomainError{Code: "INVALID_STATS_INTERVAL", Message: "Invalid stats interval", Field: "interval"}
+	ErrInvalidStatsRange    = DomainError{Code: "INVALID_STATS_RANGE", Message: "Stats range start must be before its end", Field: "from"}
+	ErrStatsRangeTooLarge   = DomainError{Code: "STATS_RANGE_TOO_LARGE", Message: "Stats range produces too many buckets", Field: "to"}
+)
+
+// Search errors
+var (
+	ErrInvalidSearchQuery = DomainError{Code: "INVALID_SEARCH_QUERY", Message: "Search query must contain a letter or digit and be at most 200 characters", Field: "query"}
+)
+
+// Import errors
+var (
+	ErrInvalidImportFile = DomainError{Code: "INVALID_IMPORT_FILE", Message: "Import file must be CSV with email and name columns, or NDJSON", Field: "file"}
+	ErrInvalidImportRow  = DomainError{Code: "INVALID_IMPORT_ROW", Message: "Row cannot be read", Field: "row"}
+	ErrImportJobNotFound = DomainError{Code: "IMPORT_JOB_NOT_FOUND", Message: "Import job not found", Field: "id"}
+	ErrImportInterrupted = DomainError{Code: "IMPORT_INTERRUPTED", Message: "Import was interrupted before it finished"}
+	ErrImportsDisabled   = DomainError{Code: "IMPORTS_DISABLED", Message: "Imports are disabled on this server"}
+)
+
+// Authorization errors
+var (
+	ErrForbidden = DomainError{Code: "FORBIDDEN", Message: "Operation requires administrator privileges"}
 )
 
 // Repository errors
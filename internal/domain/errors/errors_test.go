@@ -102,6 +102,8 @@ func TestPredefinedErrors(t *testing.T) {
 		{"ErrInvalidName", ErrInvalidName, "INVALID_NAME", true},
 		{"ErrInvalidUserID", ErrInvalidUserID, "INVALID_USER_ID", true},
 		{"ErrUserAlreadyExists", ErrUserAlreadyExists, "USER_ALREADY_EXISTS", false},
+		{"ErrUserNotDeleted", ErrUserNotDeleted, "USER_NOT_DELETED", false},
+		{"ErrForbidden", ErrForbidden, "FORBIDDEN", false},
 		{"ErrRepositoryConnection", ErrRepositoryConnection, "REPOSITORY_CONNECTION", false},
 		{"ErrRepositoryOperation", ErrRepositoryOperation, "REPOSITORY_OPERATION", false},
 	}
@@ -221,3 +223,22 @@ func TestDomainError_Comparison(t *testing.T) {
 		})
 	}
 }
+
+func TestNewConflictError(t *testing.T) {
+	err := NewConflictError(4)
+
+	if err.Code != "CONFLICT" {
+		t.Errorf("NewConflictError() Code = %v, want CONFLICT", err.Code)
+	}
+	if err.CurrentVersion != 4 {
+		t.Errorf("NewConflictError() CurrentVersion = %d, want 4", err.CurrentVersion)
+	}
+	if want := "User was modified concurrently; current version is 4"; err.Error() != want {
+		t.Errorf("NewConflictError() Error() = %v, want %v", err.Error(), want)
+	}
+
+	var asError error = err
+	if _, ok := asError.(DomainError); ok {
+		t.Error("ConflictError should stay distinguishable from a plain DomainError")
+	}
+}
@@ -0,0 +1,158 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// AnyEvent registers a handler for every event name
+const AnyEvent = "*"
+
+// Event is something that happened to an aggregate
+type Event interface {
+	// EventName identifies the kind of event, e.g. "user.renamed"
+	EventName() string
+
+	// AggregateID identifies the aggregate the event happened to
+	AggregateID() string
+
+	// OccurredAt is when the aggregate recorded the event
+	OccurredAt() time.Time
+}
+
+// Base carries the fields every event shares; embed it to implement Event
+type Base struct {
+	name        string
+	aggregateID string
+	occurredAt  time.Time
+}
+
+// NewBase creates the shared part of an event
+func NewBase(name, aggregateID string, occurredAt time.Time) Base {
+	return Base{
+		name:        name,
+		aggregateID: aggregateID,
+		occurredAt:  occurredAt,
+	}
+}
+
+// EventName returns the kind of event
+func (b Base) EventName() string {
+	return b.name
+}
+
+// AggregateID returns the ID of the aggregate the event happened to
+func (b Base) AggregateID() string {
+	return b.aggregateID
+}
+
+// OccurredAt returns when the event was recorded
+func (b Base) OccurredAt() time.Time {
+	return b.occurredAt
+}
+
+// Handler reacts to a dispatched event
+type Handler func(ctx context.Context, event Event) error
+
+// DomainEventDispatcher routes events raised by aggregates to the handlers registered for them.
+//
+// Synchronous handlers run in registration order before Dispatch returns, and
+// their errors are returned to the caller. Asynchronous handlers run on their
+// own goroutines with a context that outlives the caller's; their errors are
+// only logged. Handler panics are recovered and treated as errors.
+type DomainEventDispatcher struct {
+	logger *slog.Logger
+
+	mu    sync.RWMutex
+	sync  map[string][]Handler
+	async map[string][]Handler
+
+	inFlight sync.WaitGroup
+}
+
+// NewDomainEventDispatcher creates a dispatcher with no handlers
+func NewDomainEventDispatcher(logger *slog.Logger) *DomainEventDispatcher {
+	return &DomainEventDispatcher{
+		logger: logger,
+		sync:   make(map[string][]Handler),
+		async:  make(map[string][]Handler),
+	}
+}
+
+// Subscribe registers a handler that runs synchronously for events with the given name,
+// or for every event when name is AnyEvent
+func (d *DomainEventDispatcher) Subscribe(name string, handler Handler) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.sync[name] = append(d.sync[name], handler)
+}
+
+// SubscribeAsync registers a handler that runs on its own goroutine for events with the given name,
+// or for every event when name is AnyEvent
+func (d *DomainEventDispatcher) SubscribeAsync(name string, handler Handler) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.async[name] = append(d.async[name], handler)
+}
+
+// Dispatch delivers events in order to their handlers.
+// Every synchronous handler runs even if an earlier one fails; the failures are joined into the returned error.
+func (d *DomainEventDispatcher) Dispatch(ctx context.Context, events ...Event) error {
+	var errs []error
+
+	for _, event := range events {
+		syncHandlers, asyncHandlers := d.handlers(event.EventName())
+
+		for _, handler := range asyncHandlers {
+			d.inFlight.Add(1)
+			go func() {
+				defer d.inFlight.Done()
+				if err := call(context.WithoutCancel(ctx), handler, event); err != nil {
+					d.logger.ErrorContext(ctx, "Asynchronous domain event handler failed",
+						"event", event.EventName(),
+						"aggregate_id", event.AggregateID(),
+						"error", err,
+					)
+				}
+			}()
+		}
+
+		for _, handler := range syncHandlers {
+			if err := call(ctx, handler, event); err != nil {
+				errs = append(errs, fmt.Errorf("handling %s: %w", event.EventName(), err))
+			}
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// Wait blocks until every asynchronous handler started so far has returned
+func (d *DomainEventDispatcher) Wait() {
+	d.inFlight.Wait()
+}
+
+// handlers returns copies of the handlers registered for name and for AnyEvent
+func (d *DomainEventDispatcher) handlers(name string) (syncHandlers, asyncHandlers []Handler) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+
+	syncHandlers = append(append([]Handler(nil), d.sync[name]...), d.sync[AnyEvent]...)
+	asyncHandlers = append(append([]Handler(nil), d.async[name]...), d.async[AnyEvent]...)
+	return syncHandlers, asyncHandlers
+}
+
+// call runs a handler, turning a panic into an error
+func call(ctx context.Context, handler Handler, event Event) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("handler panicked: %v", r)
+		}
+	}()
+
+	return handler(ctx, event)
+}
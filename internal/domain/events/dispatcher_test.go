@@ -0,0 +1,121 @@
+package events
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testEvent is a minimal Event
+type testEvent struct {
+	Base
+}
+
+func newTestEvent(name string) testEvent {
+	return testEvent{Base: NewBase(name, "aggregate-1", time.Now())}
+}
+
+func TestDomainEventDispatcher_SyncHandlers(t *testing.T) {
+	d := NewDomainEventDispatcher(slog.Default())
+
+	var calls []string
+	d.Subscribe("thing.created", func(_ context.Context, e Event) error {
+		calls = append(calls, "first:"+e.EventName())
+		return nil
+	})
+	d.Subscribe("thing.created", func(_ context.Context, e Event) error {
+		calls = append(calls, "second:"+e.EventName())
+		return nil
+	})
+	d.Subscribe(AnyEvent, func(_ context.Context, e Event) error {
+		calls = append(calls, "any:"+e.EventName())
+		return nil
+	})
+
+	if err := d.Dispatch(context.Background(), newTestEvent("thing.created"), newTestEvent("thing.renamed")); err != nil {
+		t.Fatalf("Dispatch() unexpected error = %v", err)
+	}
+
+	want := []string{"first:thing.created", "second:thing.created", "any:thing.created", "any:thing.renamed"}
+	if len(calls) != len(want) {
+		t.Fatalf("Dispatch() calls = %v, want %v", calls, want)
+	}
+	for i := range want {
+		if calls[i] != want[i] {
+			t.Errorf("Dispatch() call %d = %v, want %v", i, calls[i], want[i])
+		}
+	}
+}
+
+func TestDomainEventDispatcher_SyncErrors(t *testing.T) {
+	d := NewDomainEventDispatcher(slog.Default())
+
+	errBoom := errors.New("boom")
+	ranAfterFailure := false
+
+	d.Subscribe("thing.created", func(context.Context, Event) error { return errBoom })
+	d.Subscribe("thing.created", func(context.Context, Event) error { panic("kaboom") })
+	d.Subscribe("thing.created", func(context.Context, Event) error {
+		ranAfterFailure = true
+		return nil
+	})
+
+	err := d.Dispatch(context.Background(), newTestEvent("thing.created"))
+	if !errors.Is(err, errBoom) {
+		t.Errorf("Dispatch() error = %v, want it to wrap %v", err, errBoom)
+	}
+	if err == nil || !strings.Contains(err.Error(), "kaboom") {
+		t.Errorf("Dispatch() error = %v, want the recovered panic included", err)
+	}
+	if !ranAfterFailure {
+		t.Error("Dispatch() stopped at the first failing handler")
+	}
+}
+
+func TestDomainEventDispatcher_AsyncHandlers(t *testing.T) {
+	d := NewDomainEventDispatcher(slog.Default())
+
+	var mu sync.Mutex
+	var received []string
+	d.SubscribeAsync("thing.created", func(ctx context.Context, e Event) error {
+		// Async handlers must not be cut short by the caller's context
+		if ctx.Err() != nil {
+			t.Errorf("async handler context already done: %v", ctx.Err())
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		received = append(received, e.AggregateID())
+		return nil
+	})
+	d.SubscribeAsync("thing.created", func(context.Context, Event) error {
+		return errors.New("logged, not returned")
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	if err := d.Dispatch(ctx, newTestEvent("thing.created")); err != nil {
+		t.Fatalf("Dispatch() unexpected error = %v", err)
+	}
+	cancel()
+	d.Wait()
+
+	mu.Lock()
+	defer mu.Unlock()
+	if len(received) != 1 || received[0] != "aggregate-1" {
+		t.Errorf("async handler received %v, want [aggregate-1]", received)
+	}
+}
+
+func TestDomainEventDispatcher_NoHandlers(t *testing.T) {
+	d := NewDomainEventDispatcher(slog.Default())
+
+	if err := d.Dispatch(context.Background(), newTestEvent("thing.created")); err != nil {
+		t.Errorf("Dispatch() unexpected error = %v", err)
+	}
+	if err := d.Dispatch(context.Background()); err != nil {
+		t.Errorf("Dispatch() with no events unexpected error = %v", err)
+	}
+}
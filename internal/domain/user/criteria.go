@@ -0,0 +1,145 @@
+package user
+
+import (
+	"time"
+
+	"github.com/captain-corgi/go-graphql-example/internal/domain/errors"
+)
+
+// MaxFilterIDs bounds the number of IDs a single id-in filter may carry
+const MaxFilterIDs = 100
+
+// TextFilter matches a text attribute exactly or by case-insensitive substring
+// Both conditions must hold when both are set
+type TextFilter struct {
+	Equals   *string
+	Contains *string
+}
+
+// TimeRange matches timestamps within [From, To); either bound may be open
+type TimeRange struct {
+	From *time.Time
+	To   *time.Time
+}
+
+// isEmpty reports whether both bounds are set and no instant can fall between them
+func (r *TimeRange) isEmpty() bool {
+	return r != nil && r.From != nil && r.To != nil && !r.From.Before(*r.To)
+}
+
+// Filter narrows the set of users returned by a listing; all set conditions must hold
+type Filter struct {
+	Email     *TextFilter
+	Name      *TextFilter
+	CreatedAt *TimeRange
+	UpdatedAt *TimeRange
+	IDs       []UserID
+	// Statuses matches users in any of the listed statuses; nil matches every status
+	Statuses []Status
+
+	// IncludeDeleted widens the filter to soft-deleted users
+	IncludeDeleted bool
+}
+
+// SortField is an attribute users can be ordered by
+type SortField string
+
+const (
+	SortByName      SortField = "NAME"
+	SortByEmail     SortField = "EMAIL"
+	SortByCreatedAt SortField = "CREATED_AT"
+	SortByUpdatedAt SortField = "UPDATED_AT"
+)
+
+// IsValid reports whether the field is a known sort field
+func (f SortField) IsValid() bool {
+	switch f {
+	case SortByName, SortByEmail, SortByCreatedAt, SortByUpdatedAt:
+		return true
+	}
+	return false
+}
+
+// IsTimestamp reports whether the field orders by a time.Time value
+func (f SortField) IsTimestamp() bool {
+	return f == SortByCreatedAt || f == SortByUpdatedAt
+}
+
+// ValueOf returns the user's value for the sort field:
+// a time.Time for timestamp fields and a string otherwise
+func (f SortField) ValueOf(u *User) interface{} {
+	switch f {
+	case SortByName:
+		return u.Name().String()
+	case SortByEmail:
+		return u.Email().String()
+	case SortByUpdatedAt:
+		return u.UpdatedAt()
+	default:
+		return u.CreatedAt()
+	}
+}
+
+// SortDirection is the direction of an ordering
+type SortDirection string
+
+const (
+	SortAsc  SortDirection = "ASC"
+	SortDesc SortDirection = "DESC"
+)
+
+// IsValid reports whether the direction is ASC or DESC
+func (d SortDirection) IsValid() bool {
+	return d == SortAsc || d == SortDesc
+}
+
+// Sort orders users by a field; ties are broken by id in the same direction
+// so that every ordering is total and usable as a keyset
+type Sort struct {
+	Field     SortField
+	Direction SortDirection
+}
+
+// DefaultSort lists the newest users first
+var DefaultSort = Sort{Field: SortByCreatedAt, Direction: SortDesc}
+
+// Criteria describes which users to list and in what order
+type Criteria struct {
+	Filter Filter
+	Sort   Sort
+}
+
+// Validate checks that the criteria are well-formed
+func (c Criteria) Validate() error {
+	if !c.Sort.Field.IsValid() || !c.Sort.Direction.IsValid() {
+		return errors.ErrInvalidSort
+	}
+
+	if len(c.Filter.IDs) > MaxFilterIDs {
+		invalid := errors.ErrInvalidFilter
+		invalid.Field = "filter.ids"
+		return invalid
+	}
+
+	for _, status := range c.Filter.Statuses {
+		if !status.IsValid() {
+			invalid := errors.ErrInvalidFilter
+			invalid.Field = "filter.status"
+			return invalid
+		}
+	}
+
+	if c.Filter.CreatedAt.isEmpty() {
+		invalid := errors.ErrInvalidFilter
+		invalid.Field = "filter.createdAt"
+		return invalid
+	}
+
+	if c.Filter.UpdatedAt.isEmpty() {
+		invalid := errors.ErrInvalidFilter
+		invalid.Field = "filter.updatedAt"
+		return invalid
+	}
+
+	return nil
+}
@@ -0,0 +1,118 @@
+package user
+
+import (
+	"testing"
+	"time"
+
+	"github.com/captain-corgi/go-graphql-example/internal/domain/errors"
+)
+
+func TestCriteria_Validate(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	to := from.Add(24 * time.Hour)
+
+	tooManyIDs := make([]UserID, MaxFilterIDs+1)
+	for i := range tooManyIDs {
+		tooManyIDs[i] = GenerateUserID()
+	}
+
+	withField := func(err errors.DomainError, field string) errors.DomainError {
+		err.Field = field
+		return err
+	}
+
+	tests := []struct {
+		name     string
+		criteria Criteria
+		wantErr  error
+	}{
+		{
+			name:     "default sort without filter",
+			criteria: Criteria{Sort: DefaultSort},
+			wantErr:  nil,
+		},
+		{
+			name: "bounded and open ranges",
+			criteria: Criteria{
+				Sort: Sort{Field: SortByName, Direction: SortAsc},
+				Filter: Filter{
+					CreatedAt: &TimeRange{From: &from, To: &to},
+					UpdatedAt: &TimeRange{From: &from},
+					IDs:       []UserID{},
+				},
+			},
+			wantErr: nil,
+		},
+		{
+			name:     "zero sort",
+			criteria: Criteria{},
+			wantErr:  errors.ErrInvalidSort,
+		},
+		{
+			name:     "unknown sort direction",
+			criteria: Criteria{Sort: Sort{Field: SortByEmail, Direction: "SIDEWAYS"}},
+			wantErr:  errors.ErrInvalidSort,
+		},
+		{
+			name:     "too many ids",
+			criteria: Criteria{Sort: DefaultSort, Filter: Filter{IDs: tooManyIDs}},
+			wantErr:  withField(errors.ErrInvalidFilter, "filter.ids"),
+		},
+		{
+			name:     "known statuses",
+			criteria: Criteria{Sort: DefaultSort, Filter: Filter{Statuses: []Status{StatusSuspended, StatusDeactivated}}},
+			wantErr:  nil,
+		},
+		{
+			name:     "unknown status",
+			criteria: Criteria{Sort: DefaultSort, Filter: Filter{Statuses: []Status{"BANNED"}}},
+			wantErr:  withField(errors.ErrInvalidFilter, "filter.status"),
+		},
+		{
+			name:     "created range with from after to",
+			criteria: Criteria{Sort: DefaultSort, Filter: Filter{CreatedAt: &TimeRange{From: &to, To: &from}}},
+			wantErr:  withField(errors.ErrInvalidFilter, "filter.createdAt"),
+		},
+		{
+			name:     "updated range with from equal to to",
+			criteria: Criteria{Sort: DefaultSort, Filter: Filter{UpdatedAt: &TimeRange{From: &from, To: &from}}},
+			wantErr:  withField(errors.ErrInvalidFilter, "filter.updatedAt"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.criteria.Validate()
+			if err != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSortField_ValueOf(t *testing.T) {
+	createdAt := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	updatedAt := createdAt.Add(time.Hour)
+	u, err := NewUserWithID("123e4567-e89b-12d3-a456-426614174000", "jane@example.com", "Jane", createdAt, updatedAt)
+	if err != nil {
+		t.Fatalf("NewUserWithID() error = %v", err)
+	}
+
+	tests := []struct {
+		field SortField
+		want  interface{}
+	}{
+		{field: SortByName, want: "Jane"},
+		{field: SortByEmail, want: "jane@example.com"},
+		{field: SortByCreatedAt, want: createdAt},
+		{field: SortByUpdatedAt, want: updatedAt},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.field), func(t *testing.T) {
+			if got := tt.field.ValueOf(u); got != tt.want {
+				t.Errorf("ValueOf() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,103 @@
+package user
+
+import (
+	"time"
+
+	"github.com/captain-corgi/go-graphql-example/internal/domain/events"
+)
+
+// Names of the events raised by the User aggregate
+const (
+	EventUserRegistered    = "user.registered"
+	EventUserEmailChanged  = "user.email_changed"
+	EventUserRenamed       = "user.renamed"
+	EventUserDeleted       = "user.deleted"
+	EventUserRestored      = "user.restored"
+	EventUserPurged        = "user.purged"
+	EventUserStatusChanged = "user.status_changed"
+)
+
+// UserRegistered is raised when a new user is created
+type UserRegistered struct {
+	events.Base
+	UserID UserID
+	Email  Email
+	Name   Name
+}
+
+// UserEmailChanged is raised when a user's email changes
+type UserEmailChanged struct {
+	events.Base
+	UserID UserID
+	From   Email
+	To     Email
+}
+
+// UserRenamed is raised when a user's name changes
+type UserRenamed struct {
+	events.Base
+	UserID UserID
+	From   Name
+	To     Name
+}
+
+// UserDeleted is raised when a user is soft-deleted
+// Email and Name hold the user's details at the time of deletion
+type UserDeleted struct {
+	events.Base
+	UserID UserID
+	Email  Email
+	Name   Name
+}
+
+// UserRestored is raised when a soft-deleted user is brought back
+type UserRestored struct {
+	events.Base
+	UserID UserID
+	Email  Email
+	Name   Name
+}
+
+// UserPurged is raised when a user is permanently removed
+// Email and Name hold the user's details at the time of removal
+type UserPurged struct {
+	events.Base
+	UserID UserID
+	Email  Email
+	Name   Name
+}
+
+// UserStatusChanged is raised when a user's account status changes
+// Actor and Reason are those of the transition
+type UserStatusChanged struct {
+	events.Base
+	UserID UserID
+	From   Status
+	To     Status
+	Actor  string
+	Reason string
+}
+
+// PendingEvents returns the events recorded since the last drain without forgetting them
+// Repositories store them in the same transaction as the change that raised them
+func (u *User) PendingEvents() []events.Event {
+	return append([]events.Event(nil), u.pending...)
+}
+
+// DrainEvents returns the events recorded since the last drain and forgets them
+// Callers dispatch them once the changes that raised them have been persisted
+func (u *User) DrainEvents() []events.Event {
+	pending := u.pending
+	u.pending = nil
+	return pending
+}
+
+// record appends an event to the user's pending events
+func (u *User) record(event events.Event) {
+	u.pending = append(u.pending, event)
+}
+
+// base builds the shared part of an event about this user
+func (u *User) base(name string, occurredAt time.Time) events.Base {
+	return events.NewBase(name, u.id.String(), occurredAt)
+}
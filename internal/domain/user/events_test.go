@@ -0,0 +1,136 @@
+package user
+
+import (
+	"testing"
+	"time"
+)
+
+func TestUser_RecordsEvents(t *testing.T) {
+	u, err := NewUser("old@example.com", "Old Name")
+	if err != nil {
+		t.Fatalf("NewUser() unexpected error = %v", err)
+	}
+
+	registered := u.DrainEvents()
+	if len(registered) != 1 {
+		t.Fatalf("NewUser() recorded %d events, want 1", len(registered))
+	}
+	if e, ok := registered[0].(UserRegistered); !ok || e.Email.String() != "old@example.com" || e.AggregateID() != u.ID().String() {
+		t.Errorf("NewUser() recorded %#v, want UserRegistered for the new user", registered[0])
+	}
+	if len(u.DrainEvents()) != 0 {
+		t.Error("DrainEvents() did not forget drained events")
+	}
+
+	// Unchanged values record nothing
+	if err := u.UpdateEmail("old@example.com"); err != nil {
+		t.Fatalf("UpdateEmail() unexpected error = %v", err)
+	}
+	if err := u.UpdateName("Old Name"); err != nil {
+		t.Fatalf("UpdateName() unexpected error = %v", err)
+	}
+	if events := u.DrainEvents(); len(events) != 0 {
+		t.Errorf("no-op updates recorded %v", events)
+	}
+
+	// Failed updates record nothing
+	if err := u.UpdateEmail("not-an-email"); err == nil {
+		t.Fatal("UpdateEmail() expected error for invalid email")
+	}
+
+	if err := u.UpdateEmail("new@example.com"); err != nil {
+		t.Fatalf("UpdateEmail() unexpected error = %v", err)
+	}
+	if err := u.UpdateName("New Name"); err != nil {
+		t.Fatalf("UpdateName() unexpected error = %v", err)
+	}
+	if err := u.Delete(); err != nil {
+		t.Fatalf("Delete() unexpected error = %v", err)
+	}
+
+	events := u.DrainEvents()
+	if len(events) != 3 {
+		t.Fatalf("recorded %d events, want 3", len(events))
+	}
+
+	emailChanged, ok := events[0].(UserEmailChanged)
+	if !ok || emailChanged.From.String() != "old@example.com" || emailChanged.To.String() != "new@example.com" {
+		t.Errorf("events[0] = %#v, want UserEmailChanged old -> new", events[0])
+	}
+	if emailChanged.EventName() != EventUserEmailChanged {
+		t.Errorf("events[0].EventName() = %v, want %v", emailChanged.EventName(), EventUserEmailChanged)
+	}
+
+	renamed, ok := events[1].(UserRenamed)
+	if !ok || renamed.From.String() != "Old Name" || renamed.To.String() != "New Name" {
+		t.Errorf("events[1] = %#v, want UserRenamed Old Name -> New Name", events[1])
+	}
+
+	deleted, ok := events[2].(UserDeleted)
+	if !ok || deleted.Email.String() != "new@example.com" || deleted.Name.String() != "New Name" {
+		t.Errorf("events[2] = %#v, want UserDeleted with the final details", events[2])
+	}
+	if !u.IsDeleted() {
+		t.Error("Delete() did not mark the user deleted")
+	}
+}
+
+func TestUser_DeleteTwice(t *testing.T) {
+	u, err := NewUserWithID("123e4567-e89b-12d3-a456-426614174000", "test@example.com", "Test User", time.Now(), time.Now())
+	if err != nil {
+		t.Fatalf("NewUserWithID() unexpected error = %v", err)
+	}
+	if events := u.DrainEvents(); len(events) != 0 {
+		t.Errorf("reconstruction recorded %v", events)
+	}
+
+	if err := u.Delete(); err != nil {
+		t.Fatalf("Delete() unexpected error = %v", err)
+	}
+	if err := u.Delete(); err == nil {
+		t.Error("Delete() on a deleted user expected error, got nil")
+	}
+	if events := u.DrainEvents(); len(events) != 1 {
+		t.Errorf("recorded %d events, want 1", len(events))
+	}
+}
+
+func TestUser_RestoreAndPurge(t *testing.T) {
+	u, err := NewUserWithID("123e4567-e89b-12d3-a456-426614174000", "test@example.com", "Test User", time.Now(), time.Now())
+	if err != nil {
+		t.Fatalf("NewUserWithID() unexpected error = %v", err)
+	}
+
+	if err := u.Restore(); err == nil {
+		t.Error("Restore() on a live user expected error, got nil")
+	}
+	if events := u.DrainEvents(); len(events) != 0 {
+		t.Errorf("failed restore recorded %v", events)
+	}
+
+	if err := u.Delete(); err != nil {
+		t.Fatalf("Delete() unexpected error = %v", err)
+	}
+	if err := u.Restore(); err != nil {
+		t.Fatalf("Restore() unexpected error = %v", err)
+	}
+	if u.IsDeleted() {
+		t.Error("Restore() did not bring the user back")
+	}
+	u.Purge()
+
+	events := u.DrainEvents()
+	if len(events) != 3 {
+		t.Fatalf("recorded %d events, want 3", len(events))
+	}
+
+	restored, ok := events[1].(UserRestored)
+	if !ok || restored.Email.String() != "test@example.com" || restored.EventName() != EventUserRestored {
+		t.Errorf("events[1] = %#v, want UserRestored with the user's details", events[1])
+	}
+
+	purged, ok := events[2].(UserPurged)
+	if !ok || purged.Name.String() != "Test User" || purged.EventName() != EventUserPurged {
+		t.Errorf("events[2] = %#v, want UserPurged with the user's details", events[2])
+	}
+}
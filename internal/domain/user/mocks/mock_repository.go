@@ -36,18 +36,33 @@ func (m *MockRepository) EXPECT() *MockRepositoryMockRecorder {
 }
 
 // Count mocks base method.
-func (m *MockRepository) Count(ctx context.Context) (int64, error) {
+func (m *MockRepository) Count(ctx context.Context, filter user.Filter) (int64, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Count", ctx)
+	ret := m.ctrl.Call(m, "Count", ctx, filter)
 	ret0, _ := ret[0].(int64)
 	ret1, _ := ret[1].(error)
 	return ret0, ret1
 }
 
 // Count indicates an expected call of Count.
-func (mr *MockRepositoryMockRecorder) Count(ctx interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) Count(ctx, filter interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockRepository)(nil).Count), ctx)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Count", reflect.TypeOf((*MockRepository)(nil).Count), ctx, filter)
+}
+
+// CountSignups mocks base method.
+func (m *MockRepository) CountSignups(ctx context.Context, query user.SignupStatsQuery) ([]user.SignupBucket, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CountSignups", ctx, query)
+	ret0, _ := ret[0].([]user.SignupBucket)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// CountSignups indicates an expected call of CountSignups.
+func (mr *MockRepositoryMockRecorder) CountSignups(ctx, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CountSignups", reflect.TypeOf((*MockRepository)(nil).CountSignups), ctx, query)
 }
 
 // Create mocks base method.
@@ -65,17 +80,17 @@ func (mr *MockRepositoryMockRecorder) Create(ctx, user interface{}) *gomock.Call
 }
 
 // Delete mocks base method.
-func (m *MockRepository) Delete(ctx context.Context, id user.UserID) error {
+func (m *MockRepository) Delete(ctx context.Context, user *user.User) error {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "Delete", ctx, id)
+	ret := m.ctrl.Call(m, "Delete", ctx, user)
 	ret0, _ := ret[0].(error)
 	return ret0
 }
 
 // Delete indicates an expected call of Delete.
-func (mr *MockRepositoryMockRecorder) Delete(ctx, id interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) Delete(ctx, user interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRepository)(nil).Delete), ctx, id)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Delete", reflect.TypeOf((*MockRepository)(nil).Delete), ctx, user)
 }
 
 // ExistsByEmail mocks base method.
@@ -94,19 +109,18 @@ func (mr *MockRepositoryMockRecorder) ExistsByEmail(ctx, email interface{}) *gom
 }
 
 // FindAll mocks base method.
-func (m *MockRepository) FindAll(ctx context.Context, limit int, cursor string) ([]*user.User, string, error) {
+func (m *MockRepository) FindAll(ctx context.Context, criteria user.Criteria, page user.PageRequest) (*user.Page, error) {
 	m.ctrl.T.Helper()
-	ret := m.ctrl.Call(m, "FindAll", ctx, limit, cursor)
-	ret0, _ := ret[0].([]*user.User)
-	ret1, _ := ret[1].(string)
-	ret2, _ := ret[2].(error)
-	return ret0, ret1, ret2
+	ret := m.ctrl.Call(m, "FindAll", ctx, criteria, page)
+	ret0, _ := ret[0].(*user.Page)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
 }
 
 // FindAll indicates an expected call of FindAll.
-func (mr *MockRepositoryMockRecorder) FindAll(ctx, limit, cursor interface{}) *gomock.Call {
+func (mr *MockRepositoryMockRecorder) FindAll(ctx, criteria, page interface{}) *gomock.Call {
 	mr.mock.ctrl.T.Helper()
-	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockRepository)(nil).FindAll), ctx, limit, cursor)
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindAll", reflect.TypeOf((*MockRepository)(nil).FindAll), ctx, criteria, page)
 }
 
 // FindByEmail mocks base method.
@@ -139,6 +153,49 @@ func (mr *MockRepositoryMockRecorder) FindByID(ctx, id interface{}) *gomock.Call
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByID", reflect.TypeOf((*MockRepository)(nil).FindByID), ctx, id)
 }
 
+// FindByIDs mocks base method.
+func (m *MockRepository) FindByIDs(ctx context.Context, ids []user.UserID) ([]*user.User, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "FindByIDs", ctx, ids)
+	ret0, _ := ret[0].([]*user.User)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// FindByIDs indicates an expected call of FindByIDs.
+func (mr *MockRepositoryMockRecorder) FindByIDs(ctx, ids interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "FindByIDs", reflect.TypeOf((*MockRepository)(nil).FindByIDs), ctx, ids)
+}
+
+// Purge mocks base method.
+func (m *MockRepository) Purge(ctx context.Context, user *user.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Purge", ctx, user)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Purge indicates an expected call of Purge.
+func (mr *MockRepositoryMockRecorder) Purge(ctx, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Purge", reflect.TypeOf((*MockRepository)(nil).Purge), ctx, user)
+}
+
+// Restore mocks base method.
+func (m *MockRepository) Restore(ctx context.Context, user *user.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Restore", ctx, user)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// Restore indicates an expected call of Restore.
+func (mr *MockRepositoryMockRecorder) Restore(ctx, user interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Restore", reflect.TypeOf((*MockRepository)(nil).Restore), ctx, user)
+}
+
 // Update mocks base method.
 func (m *MockRepository) Update(ctx context.Context, user *user.User) error {
 	m.ctrl.T.Helper()
@@ -152,3 +209,107 @@ func (mr *MockRepositoryMockRecorder) Update(ctx, user interface{}) *gomock.Call
 	mr.mock.ctrl.T.Helper()
 	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Update", reflect.TypeOf((*MockRepository)(nil).Update), ctx, user)
 }
+
+// UpdateStatus mocks base method.
+func (m *MockRepository) UpdateStatus(ctx context.Context, user *user.User, transition user.StatusTransition) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "UpdateStatus", ctx, user, transition)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// UpdateStatus indicates an expected call of UpdateStatus.
+func (mr *MockRepositoryMockRecorder) UpdateStatus(ctx, user, transition interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "UpdateStatus", reflect.TypeOf((*MockRepository)(nil).UpdateStatus), ctx, user, transition)
+}
+
+// MockSearchRepository is a mock of SearchRepository interface.
+type MockSearchRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockSearchRepositoryMockRecorder
+}
+
+// MockSearchRepositoryMockRecorder is the mock recorder for MockSearchRepository.
+type MockSearchRepositoryMockRecorder struct {
+	mock *MockSearchRepository
+}
+
+// NewMockSearchRepository creates a new mock instance.
+func NewMockSearchRepository(ctrl *gomock.Controller) *MockSearchRepository {
+	mock := &MockSearchRepository{ctrl: ctrl}
+	mock.recorder = &MockSearchRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockSearchRepository) EXPECT() *MockSearchRepositoryMockRecorder {
+	return m.recorder
+}
+
+// Search mocks base method.
+func (m *MockSearchRepository) Search(ctx context.Context, query user.SearchQuery) (*user.SearchPage, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "Search", ctx, query)
+	ret0, _ := ret[0].(*user.SearchPage)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// Search indicates an expected call of Search.
+func (mr *MockSearchRepositoryMockRecorder) Search(ctx, query interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "Search", reflect.TypeOf((*MockSearchRepository)(nil).Search), ctx, query)
+}
+
+// MockImportRepository is a mock of ImportRepository interface.
+type MockImportRepository struct {
+	ctrl     *gomock.Controller
+	recorder *MockImportRepositoryMockRecorder
+}
+
+// MockImportRepositoryMockRecorder is the mock recorder for MockImportRepository.
+type MockImportRepositoryMockRecorder struct {
+	mock *MockImportRepository
+}
+
+// NewMockImportRepository creates a new mock instance.
+func NewMockImportRepository(ctrl *gomock.Controller) *MockImportRepository {
+	mock := &MockImportRepository{ctrl: ctrl}
+	mock.recorder = &MockImportRepositoryMockRecorder{mock}
+	return mock
+}
+
+// EXPECT returns an object that allows the caller to indicate expected use.
+func (m *MockImportRepository) EXPECT() *MockImportRepositoryMockRecorder {
+	return m.recorder
+}
+
+// CreateBatch mocks base method.
+func (m *MockImportRepository) CreateBatch(ctx context.Context, users []*user.User) error {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "CreateBatch", ctx, users)
+	ret0, _ := ret[0].(error)
+	return ret0
+}
+
+// CreateBatch indicates an expected call of CreateBatch.
+func (mr *MockImportRepositoryMockRecorder) CreateBatch(ctx, users interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "CreateBatch", reflect.TypeOf((*MockImportRepository)(nil).CreateBatch), ctx, users)
+}
+
+// ExistingEmails mocks base method.
+func (m *MockImportRepository) ExistingEmails(ctx context.Context, emails []user.Email) ([]user.Email, error) {
+	m.ctrl.T.Helper()
+	ret := m.ctrl.Call(m, "ExistingEmails", ctx, emails)
+	ret0, _ := ret[0].([]user.Email)
+	ret1, _ := ret[1].(error)
+	return ret0, ret1
+}
+
+// ExistingEmails indicates an expected call of ExistingEmails.
+func (mr *MockImportRepositoryMockRecorder) ExistingEmails(ctx, emails interface{}) *gomock.Call {
+	mr.mock.ctrl.T.Helper()
+	return mr.mock.ctrl.RecordCallWithMethodType(mr.mock, "ExistingEmails", reflect.TypeOf((*MockImportRepository)(nil).ExistingEmails), ctx, emails)
+}
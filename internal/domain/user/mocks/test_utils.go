@@ -153,11 +153,11 @@ func (u *RepositoryTestUtils) ExpectExistsByEmailFalse(email user.Email) {
 		Times(1)
 }
 
-// ExpectFindAllSuccess sets up mock to return users list successfully
-func (u *RepositoryTestUtils) ExpectFindAllSuccess(limit int, cursor string, returnUsers []*user.User, nextCursor string) {
+// ExpectFindAllSuccess sets up mock to return a page of users successfully
+func (u *RepositoryTestUtils) ExpectFindAllSuccess(criteria user.Criteria, page user.PageRequest, returnPage *user.Page) {
 	u.mockRepo.EXPECT().
-		FindAll(gomock.Any(), limit, cursor).
-		Return(returnUsers, nextCursor, nil).
+		FindAll(gomock.Any(), criteria, page).
+		Return(returnPage, nil).
 		Times(1)
 }
 
@@ -0,0 +1,38 @@
+package user
+
+// Cursor identifies a user's position in a keyset ordering
+// Value holds the user's value for the sort field (see SortField.ValueOf)
+type Cursor struct {
+	Value interface{}
+	ID    UserID
+}
+
+// CursorOf returns the keyset position of the given user under the given sort
+func CursorOf(u *User, sort Sort) Cursor {
+	return Cursor{
+		Value: sort.Field.ValueOf(u),
+		ID:    u.ID(),
+	}
+}
+
+// PageRequest describes a window over the users keyset.
+// Forward pages set First (and optionally After); backward pages set Last (and optionally Before).
+// After and Before are exclusive bounds and may be combined to slice a range.
+type PageRequest struct {
+	First  int
+	After  *Cursor
+	Last   int
+	Before *Cursor
+}
+
+// IsBackward reports whether the page is requested from the end of the window
+func (p PageRequest) IsBackward() bool {
+	return p.Last > 0
+}
+
+// Page is a window of users in keyset order along with whether more users exist on either side
+type Page struct {
+	Users           []*User
+	HasNextPage     bool
+	HasPreviousPage bool
+}
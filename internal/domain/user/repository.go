@@ -5,31 +5,77 @@ import "context"
 //go:generate go run github.com/golang/mock/mockgen -source=$GOFILE -destination=./mocks/mock_$GOFILE -package=mocks
 
 // Repository defines the interface for user persistence operations
+// Lookups only see live users; soft-deleted users are reachable through
+// FindAll with Filter.IncludeDeleted, Restore and Purge.
+// Writes taking a *User store the user's pending domain events in the same
+// transaction as the change; callers drain them once the write succeeds
 type Repository interface {
 	// FindByID retrieves a user by their ID
 	FindByID(ctx context.Context, id UserID) (*User, error)
 
+	// FindByIDs retrieves the users with the given IDs in a single round trip
+	// Users that do not exist are omitted; the result order is unspecified
+	FindByIDs(ctx context.Context, ids []UserID) ([]*User, error)
+
 	// FindByEmail retrieves a user by their email address
 	FindByEmail(ctx context.Context, email Email) (*User, error)
 
-	// FindAll retrieves users with pagination support
-	// limit: maximum number of users to return
-	// cursor: pagination cursor (empty string for first page)
-	// Returns users and next cursor (empty if no more pages)
-	FindAll(ctx context.Context, limit int, cursor string) ([]*User, string, error)
+	// FindAll retrieves a keyset page of the users matching the criteria, in the criteria's sort order
+	// Page cursors must have been taken under the same sort
+	// The returned page reports whether matching users exist before and after the window
+	FindAll(ctx context.Context, criteria Criteria, page PageRequest) (*Page, error)
 
 	// Create persists a new user
 	Create(ctx context.Context, user *User) error
 
-	// Update modifies an existing user
+	// Update modifies an existing user if the stored version still equals user.Version(),
+	// then increments the user's version. A ConflictError carrying the stored version
+	// is returned when another write got there first
 	Update(ctx context.Context, user *User) error
 
-	// Delete removes a user by their ID
-	Delete(ctx context.Context, id UserID) error
+	// UpdateStatus stores the user's new status and records the transition atomically
+	// The update only applies while the stored status still equals transition.From;
+	// otherwise ErrInvalidStatusTransition is returned for the status actually stored.
+	// Like Update it is versioned and increments the user's version
+	UpdateStatus(ctx context.Context, user *User, transition StatusTransition) error
+
+	// Delete soft-deletes a live user, freeing their email for reuse
+	Delete(ctx context.Context, user *User) error
+
+	// Restore brings a soft-deleted user back and increments the user's version
+	// Returns ErrUserNotDeleted if the user is live and ErrDuplicateEmail if
+	// their email has since been taken by another live user
+	Restore(ctx context.Context, user *User) error
+
+	// Purge permanently removes a user, whether deleted or not
+	Purge(ctx context.Context, user *User) error
 
 	// ExistsByEmail checks if a user with the given email exists
 	ExistsByEmail(ctx context.Context, email Email) (bool, error)
 
-	// Count returns the total number of users
-	Count(ctx context.Context) (int64, error)
+	// Count returns the number of users matching the filter
+	Count(ctx context.Context, filter Filter) (int64, error)
+
+	// CountSignups returns the number of users created in each interval of the query range
+	// Every interval in the range is present, in chronological order, including empty ones
+	// Soft-deleted users still count as signups
+	CountSignups(ctx context.Context, query SignupStatsQuery) ([]SignupBucket, error)
+}
+
+// SearchRepository finds live users by free text matched against their name and email
+type SearchRepository interface {
+	// Search returns up to query.First live users matching the query, best first, resuming
+	// after query.After. Equal ranks are ordered by ascending ID so the ranking is a keyset
+	Search(ctx context.Context, query SearchQuery) (*SearchPage, error)
+}
+
+// ImportRepository stores users in bulk for imports
+type ImportRepository interface {
+	// ExistingEmails returns those of the given emails already held by live users
+	ExistingEmails(ctx context.Context, emails []Email) ([]Email, error)
+
+	// CreateBatch persists new users and their pending events, all of them or none
+	// ErrDuplicateEmail is returned when any of their emails is taken, by a live user
+	// or by another user of the batch
+	CreateBatch(ctx context.Context, users []*User) error
 }
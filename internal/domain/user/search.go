@@ -0,0 +1,276 @@
+package user
+
+import (
+	"slices"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/captain-corgi/go-graphql-example/internal/domain/errors"
+)
+
+// MaxSearchQueryLength bounds the length of a search query in characters
+const MaxSearchQueryLength = 200
+
+// MaxSearchTerms bounds how many distinct words of a query are matched; later words are ignored
+const MaxSearchTerms = 10
+
+// WordSimilarityThreshold is the trigram word similarity from which the whole query matches
+// a name or email despite typos; it is pg_trgm's default word_similarity_threshold
+const WordSimilarityThreshold = 0.6
+
+// typoSimilarity is the trigram similarity from which a word is highlighted as a typo of a term
+const typoSimilarity = 0.3
+
+// Full-text weights of a term found in the name and in the email; the name counts more
+const (
+	nameTermWeight  = 1.0
+	emailTermWeight = 0.4
+)
+
+// SearchCursor identifies a hit's position in a search ranking: by descending rank, then by id
+type SearchCursor struct {
+	Rank float64
+	ID   UserID
+}
+
+// SearchQuery asks for the First live users best matching Text, after the After position
+type SearchQuery struct {
+	Text  string
+	First int
+	After *SearchCursor
+}
+
+// Validate checks that the query text is short enough and has something to match
+func (q SearchQuery) Validate() error {
+	if utf8.RuneCountInString(q.Text) > MaxSearchQueryLength || len(q.Terms()) == 0 {
+		return errors.ErrInvalidSearchQuery
+	}
+	return nil
+}
+
+// Terms returns the distinct lowercase words of the query text, at most MaxSearchTerms of them
+// Words are runs of letters and digits, so terms are safe to embed in a full-text query
+func (q SearchQuery) Terms() []string {
+	var terms []string
+	for _, w := range splitWords(q.Text) {
+		term := strings.ToLower(w.text)
+		if slices.Contains(terms, term) {
+			continue
+		}
+		terms = append(terms, term)
+		if len(terms) == MaxSearchTerms {
+			break
+		}
+	}
+	return terms
+}
+
+// Rank scores how well u matches the query and reports whether it matches at all
+// It approximates the Postgres ranking for stores that search in process: each term that
+// starts a word of the name or email adds that field's weight, averaged over the terms, and
+// the trigram word similarity of the whole query to the closer of name and email is added on top.
+// A user matches when a term is found or that similarity reaches WordSimilarityThreshold
+func (q SearchQuery) Rank(u *User) (float64, bool) {
+	terms := q.Terms()
+	if len(terms) == 0 {
+		return 0, false
+	}
+
+	name, email := u.Name().String(), u.Email().String()
+
+	var found float64
+	for _, term := range terms {
+		switch {
+		case prefixesWord(term, name):
+			found += nameTermWeight
+		case prefixesWord(term, email):
+			found += emailTermWeight
+		}
+	}
+	textRank := found / float64(len(terms))
+
+	closeness := max(wordSimilarity(q.Text, name), wordSimilarity(q.Text, email))
+
+	return textRank + closeness, textRank > 0 || closeness >= WordSimilarityThreshold
+}
+
+// Span is the byte range [Start, End) of a text
+type Span struct {
+	Start int
+	End   int
+}
+
+// Highlight returns the parts of text that match the query, in order: the start of every
+// word that a term begins, and the whole of every word that looks like a typo of a term
+func (q SearchQuery) Highlight(text string) []Span {
+	terms := q.Terms()
+
+	var spans []Span
+	for _, w := range splitWords(text) {
+		word := strings.ToLower(w.text)
+		for _, term := range terms {
+			if strings.HasPrefix(word, term) {
+				spans = append(spans, Span{Start: w.start, End: w.start + runePrefixLen(w.text, utf8.RuneCountInString(term))})
+				break
+			}
+			if utf8.RuneCountInString(term) >= 3 && similarity(term, word) >= typoSimilarity {
+				spans = append(spans, Span{Start: w.start, End: w.end})
+				break
+			}
+		}
+	}
+	return spans
+}
+
+// SearchHit is a user matching a search query and how well it matches
+type SearchHit struct {
+	User *User
+	Rank float64
+}
+
+// Cursor returns the hit's position in the search ranking
+func (h SearchHit) Cursor() SearchCursor {
+	return SearchCursor{Rank: h.Rank, ID: h.User.ID()}
+}
+
+// SearchPage is a window of hits in ranking order and whether more hits follow
+type SearchPage struct {
+	Hits        []SearchHit
+	HasNextPage bool
+}
+
+// RankUsers ranks the given users against the query and returns the requested page of matches,
+// for stores that search in process
+func (q SearchQuery) RankUsers(users []*User) *SearchPage {
+	var hits []SearchHit
+	for _, u := range users {
+		rank, ok := q.Rank(u)
+		if !ok {
+			continue
+		}
+		hit := SearchHit{User: u, Rank: rank}
+		if q.After != nil && !ranksAfter(hit.Cursor(), *q.After) {
+			continue
+		}
+		hits = append(hits, hit)
+	}
+
+	slices.SortFunc(hits, func(a, b SearchHit) int {
+		if ranksAfter(a.Cursor(), b.Cursor()) {
+			return 1
+		}
+		if ranksAfter(b.Cursor(), a.Cursor()) {
+			return -1
+		}
+		return 0
+	})
+
+	page := &SearchPage{Hits: hits}
+	if len(hits) > q.First {
+		page.Hits = hits[:q.First]
+		page.HasNextPage = true
+	}
+	return page
+}
+
+// ranksAfter reports whether position a comes after position b in a search ranking
+func ranksAfter(a, b SearchCursor) bool {
+	if a.Rank != b.Rank {
+		return a.Rank < b.Rank
+	}
+	return a.ID.String() > b.ID.String()
+}
+
+// word is a run of letters and digits and its byte range within the text it was split from
+type word struct {
+	text       string
+	start, end int
+}
+
+// splitWords splits text into runs of letters and digits, the way pg_trgm and the
+// full-text parser separate words
+func splitWords(text string) []word {
+	var words []word
+	start := -1
+	for i, r := range text {
+		inWord := unicode.IsLetter(r) || unicode.IsDigit(r)
+		switch {
+		case inWord && start < 0:
+			start = i
+		case !inWord && start >= 0:
+			words = append(words, word{text: text[start:i], start: start, end: i})
+			start = -1
+		}
+	}
+	if start >= 0 {
+		words = append(words, word{text: text[start:], start: start, end: len(text)})
+	}
+	return words
+}
+
+// prefixesWord reports whether term begins any word of text, ignoring case
+func prefixesWord(term, text string) bool {
+	for _, w := range splitWords(text) {
+		if strings.HasPrefix(strings.ToLower(w.text), term) {
+			return true
+		}
+	}
+	return false
+}
+
+// runePrefixLen returns the length in bytes of the first n runes of s
+func runePrefixLen(s string, n int) int {
+	for i := range s {
+		if n == 0 {
+			return i
+		}
+		n--
+	}
+	return len(s)
+}
+
+// trigrams returns the trigrams of text as pg_trgm extracts them: each lowercase word
+// padded with two spaces in front and one behind
+func trigrams(text string) map[string]bool {
+	set := make(map[string]bool)
+	for _, w := range splitWords(text) {
+		padded := []rune("  " + strings.ToLower(w.text) + " ")
+		for i := 0; i+3 <= len(padded); i++ {
+			set[string(padded[i:i+3])] = true
+		}
+	}
+	return set
+}
+
+// similarity is the share of their trigrams two texts have in common, like pg_trgm's similarity
+func similarity(a, b string) float64 {
+	ta, tb := trigrams(a), trigrams(b)
+	common := countCommon(ta, tb)
+	union := len(ta) + len(tb) - common
+	if union == 0 {
+		return 0
+	}
+	return float64(common) / float64(union)
+}
+
+// wordSimilarity is the share of the query's trigrams found in text, an upper bound of
+// pg_trgm's word_similarity that ignores where in text they are found
+func wordSimilarity(query, text string) float64 {
+	tq := trigrams(query)
+	if len(tq) == 0 {
+		return 0
+	}
+	return float64(countCommon(tq, trigrams(text))) / float64(len(tq))
+}
+
+// countCommon counts the trigrams present in both sets
+func countCommon(a, b map[string]bool) int {
+	var n int
+	for t := range a {
+		if b[t] {
+			n++
+		}
+	}
+	return n
+}
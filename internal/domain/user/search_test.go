@@ -0,0 +1,155 @@
+package user
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/captain-corgi/go-graphql-example/internal/domain/errors"
+)
+
+func searchUser(t *testing.T, n int, email, name string) *User {
+	t.Helper()
+	now := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	u, err := NewUserWithID(fmt.Sprintf("550e8400-e29b-41d4-a716-4466554400%02d", n), email, name, now, now)
+	if err != nil {
+		t.Fatalf("NewUserWithID() unexpected error = %v", err)
+	}
+	return u
+}
+
+func TestSearchQuery_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		wantErr error
+	}{
+		{"word", "john", nil},
+		{"email", "john.doe@example.com", nil},
+		{"punctuation only", " @.- ", errors.ErrInvalidSearchQuery},
+		{"empty", "", errors.ErrInvalidSearchQuery},
+		{"longest", strings.Repeat("a", MaxSearchQueryLength), nil},
+		{"too long", strings.Repeat("a", MaxSearchQueryLength+1), errors.ErrInvalidSearchQuery},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := (SearchQuery{Text: tt.text, First: 10}).Validate(); err != tt.wantErr {
+				t.Errorf("Validate() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestSearchQuery_Terms(t *testing.T) {
+	got := SearchQuery{Text: "John.Doe@Example.com john O'Brien"}.Terms()
+	want := []string{"john", "doe", "example", "com", "o", "brien"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Errorf("Terms() = %v, want %v", got, want)
+	}
+
+	many := SearchQuery{Text: "a b c d e f g h i j k l"}.Terms()
+	if len(many) != MaxSearchTerms {
+		t.Errorf("Terms() returned %d terms, want %d", len(many), MaxSearchTerms)
+	}
+}
+
+func TestSearchQuery_Rank(t *testing.T) {
+	john := searchUser(t, 1, "john.doe@example.com", "John Doe")
+	jane := searchUser(t, 2, "jane.smith@example.com", "Jane Smith")
+
+	tests := []struct {
+		name      string
+		text      string
+		user      *User
+		wantMatch bool
+	}{
+		{"name prefix", "jo", john, true},
+		{"full name", "john doe", john, true},
+		{"email", "john.doe@example.com", john, true},
+		{"typo", "jonh doe", john, true},
+		{"other user", "smith", john, false},
+		{"unrelated", "zebra", jane, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rank, ok := SearchQuery{Text: tt.text}.Rank(tt.user)
+			if ok != tt.wantMatch {
+				t.Errorf("Rank() match = %v (rank %v), want %v", ok, rank, tt.wantMatch)
+			}
+		})
+	}
+
+	nameRank, _ := SearchQuery{Text: "doe"}.Rank(john)
+	other := searchUser(t, 3, "doe.fan@example.com", "Bob Wilson")
+	emailRank, _ := SearchQuery{Text: "doe"}.Rank(other)
+	if nameRank <= emailRank {
+		t.Errorf("Rank() of a name match = %v, want above the email match %v", nameRank, emailRank)
+	}
+}
+
+func TestSearchQuery_Highlight(t *testing.T) {
+	tests := []struct {
+		name  string
+		query string
+		text  string
+		want  []string
+	}{
+		{"prefix", "jo", "John Doe", []string{"Jo"}},
+		{"several words", "john doe", "John Doe", []string{"John", "Doe"}},
+		{"email parts", "doe example", "john.doe@example.com", []string{"doe", "example"}},
+		{"typo", "smyth", "Jane Smith", []string{"Smith"}},
+		{"multibyte", "jos", "José Núñez", []string{"Jos"}},
+		{"nothing", "zebra", "Jane Smith", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, span := range (SearchQuery{Text: tt.query}).Highlight(tt.text) {
+				got = append(got, tt.text[span.Start:span.End])
+			}
+			if strings.Join(got, "|") != strings.Join(tt.want, "|") {
+				t.Errorf("Highlight() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSearchQuery_RankUsers(t *testing.T) {
+	users := []*User{
+		searchUser(t, 1, "john.doe@example.com", "John Doe"),
+		searchUser(t, 2, "jane.smith@example.com", "Jane Smith"),
+		searchUser(t, 3, "johnny.cash@example.com", "Johnny Cash"),
+		searchUser(t, 4, "jo.march@example.com", "Jo March"),
+	}
+
+	query := SearchQuery{Text: "jo", First: 2}
+	first := query.RankUsers(users)
+	if len(first.Hits) != 2 || !first.HasNextPage {
+		t.Fatalf("RankUsers() first page = %d hits, hasNext %v; want 2 hits and a next page", len(first.Hits), first.HasNextPage)
+	}
+	if first.Hits[0].Rank < first.Hits[1].Rank {
+		t.Errorf("RankUsers() hits out of order: %v before %v", first.Hits[0].Rank, first.Hits[1].Rank)
+	}
+
+	after := first.Hits[1].Cursor()
+	query.After = &after
+	second := query.RankUsers(users)
+	if len(second.Hits) != 1 || second.HasNextPage {
+		t.Fatalf("RankUsers() second page = %d hits, hasNext %v; want 1 hit and no next page", len(second.Hits), second.HasNextPage)
+	}
+
+	seen := map[string]bool{}
+	for _, hit := range append(first.Hits, second.Hits...) {
+		if seen[hit.User.ID().String()] {
+			t.Errorf("RankUsers() returned %s twice", hit.User.ID())
+		}
+		seen[hit.User.ID().String()] = true
+	}
+	if seen[users[1].ID().String()] {
+		t.Error("RankUsers() returned a user that does not match")
+	}
+}
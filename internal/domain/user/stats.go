@@ -0,0 +1,117 @@
+package user
+
+import (
+	"time"
+
+	"github.com/captain-corgi/go-graphql-example/internal/domain/errors"
+)
+
+// MaxStatsBuckets bounds how many buckets a single stats query may produce
+const MaxStatsBuckets = 1000
+
+// StatsInterval is the width of a stats bucket
+type StatsInterval string
+
+const (
+	StatsIntervalDay   StatsInterval = "DAY"
+	StatsIntervalWeek  StatsInterval = "WEEK"
+	StatsIntervalMonth StatsInterval = "MONTH"
+)
+
+// IsValid reports whether the interval is a known bucket width
+func (i StatsInterval) IsValid() bool {
+	switch i {
+	case StatsIntervalDay, StatsIntervalWeek, StatsIntervalMonth:
+		return true
+	}
+	return false
+}
+
+// SignupBucket counts the users created within one interval
+// Start is the beginning of the interval in UTC; weeks start on Monday
+type SignupBucket struct {
+	Start time.Time
+	Count int64
+}
+
+// SignupStatsQuery asks for signups in [From, To) bucketed by Interval
+type SignupStatsQuery struct {
+	From     time.Time
+	To       time.Time
+	Interval StatsInterval
+}
+
+// Validate checks that the query is well-formed and does not produce too many buckets
+func (q SignupStatsQuery) Validate() error {
+	if !q.Interval.IsValid() {
+		return errors.ErrInvalidStatsInterval
+	}
+
+	if !q.From.Before(q.To) {
+		return errors.ErrInvalidStatsRange
+	}
+
+	var buckets int
+	switch q.Interval {
+	case StatsIntervalDay:
+		buckets = int(q.To.Sub(q.From).Hours()/24) + 1
+	case StatsIntervalWeek:
+		buckets = int(q.To.Sub(q.From).Hours()/(24*7)) + 1
+	case StatsIntervalMonth:
+		from, to := q.From.UTC(), q.To.UTC()
+		buckets = (to.Year()-from.Year())*12 + int(to.Month()-from.Month()) + 1
+	}
+
+	if buckets > MaxStatsBuckets {
+		return errors.ErrStatsRangeTooLarge
+	}
+
+	return nil
+}
+
+// Start returns the beginning of the UTC interval containing t; weeks start on Monday
+func (i StatsInterval) Start(t time.Time) time.Time {
+	t = t.UTC()
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+
+	switch i {
+	case StatsIntervalWeek:
+		return day.AddDate(0, 0, -((int(day.Weekday()) + 6) % 7))
+	case StatsIntervalMonth:
+		return time.Date(t.Year(), t.Month(), 1, 0, 0, 0, 0, time.UTC)
+	default:
+		return day
+	}
+}
+
+// Next returns the beginning of the interval following the one that begins at start
+func (i StatsInterval) Next(start time.Time) time.Time {
+	switch i {
+	case StatsIntervalWeek:
+		return start.AddDate(0, 0, 7)
+	case StatsIntervalMonth:
+		return start.AddDate(0, 1, 0)
+	default:
+		return start.AddDate(0, 0, 1)
+	}
+}
+
+// Bucket counts the creation times that fall within the query range
+// Every interval from the one holding From to the one holding the last instant before To
+// is returned, including empty ones; for stores that cannot group by date themselves
+func (q SignupStatsQuery) Bucket(createdAt []time.Time) []SignupBucket {
+	counts := make(map[time.Time]int64)
+	for _, t := range createdAt {
+		if !t.Before(q.From) && t.Before(q.To) {
+			counts[q.Interval.Start(t)]++
+		}
+	}
+
+	var buckets []SignupBucket
+	last := q.Interval.Start(q.To.Add(-time.Microsecond))
+	for start := q.Interval.Start(q.From); !start.After(last); start = q.Interval.Next(start) {
+		buckets = append(buckets, SignupBucket{Start: start, Count: counts[start]})
+	}
+
+	return buckets
+}
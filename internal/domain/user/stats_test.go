@@ -0,0 +1,107 @@
+package user
+
+import (
+	"testing"
+	"time"
+
+	"github.com/captain-corgi/go-graphql-example/internal/domain/errors"
+)
+
+func TestSignupStatsQuery_Validate(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		name    string
+		query   SignupStatsQuery
+		wantErr error
+	}{
+		{
+			name:    "one day",
+			query:   SignupStatsQuery{From: from, To: from.AddDate(0, 0, 1), Interval: StatsIntervalDay},
+			wantErr: nil,
+		},
+		{
+			name:    "two years of days",
+			query:   SignupStatsQuery{From: from, To: from.AddDate(2, 0, 0), Interval: StatsIntervalDay},
+			wantErr: nil,
+		},
+		{
+			name:    "three years of days",
+			query:   SignupStatsQuery{From: from, To: from.AddDate(3, 0, 0), Interval: StatsIntervalDay},
+			wantErr: errors.ErrStatsRangeTooLarge,
+		},
+		{
+			name:    "ten years of weeks",
+			query:   SignupStatsQuery{From: from, To: from.AddDate(10, 0, 0), Interval: StatsIntervalWeek},
+			wantErr: nil,
+		},
+		{
+			name:    "a century of months",
+			query:   SignupStatsQuery{From: from, To: from.AddDate(100, 0, 0), Interval: StatsIntervalMonth},
+			wantErr: errors.ErrStatsRangeTooLarge,
+		},
+		{
+			name:    "unknown interval",
+			query:   SignupStatsQuery{From: from, To: from.AddDate(0, 0, 1), Interval: "HOUR"},
+			wantErr: errors.ErrInvalidStatsInterval,
+		},
+		{
+			name:    "empty range",
+			query:   SignupStatsQuery{From: from, To: from, Interval: StatsIntervalDay},
+			wantErr: errors.ErrInvalidStatsRange,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.query.Validate(); err != tt.wantErr {
+				t.Errorf("Validate() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestStatsInterval_Start(t *testing.T) {
+	// Sunday 19 January 2025, late evening in UTC-5, which is already Monday in UTC
+	at := time.Date(2025, 1, 19, 22, 30, 0, 0, time.FixedZone("UTC-5", -5*60*60))
+
+	tests := []struct {
+		interval StatsInterval
+		want     time.Time
+	}{
+		{StatsIntervalDay, time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC)},
+		{StatsIntervalWeek, time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC)},
+		{StatsIntervalMonth, time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.interval), func(t *testing.T) {
+			if got := tt.interval.Start(at); !got.Equal(tt.want) {
+				t.Errorf("Start() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSignupStatsQuery_Bucket(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	query := SignupStatsQuery{From: from, To: from.AddDate(0, 0, 3), Interval: StatsIntervalDay}
+
+	buckets := query.Bucket([]time.Time{
+		from.Add(time.Hour),
+		from.Add(2 * time.Hour),
+		from.AddDate(0, 0, 2),
+		from.Add(-time.Second), // before the range
+		from.AddDate(0, 0, 3),  // at To, which is exclusive
+	})
+
+	want := []int64{2, 0, 1}
+	if len(buckets) != len(want) {
+		t.Fatalf("Bucket() returned %d buckets, want %d", len(buckets), len(want))
+	}
+	for i, b := range buckets {
+		if !b.Start.Equal(from.AddDate(0, 0, i)) || b.Count != want[i] {
+			t.Errorf("bucket %d = %v/%d, want %v/%d", i, b.Start, b.Count, from.AddDate(0, 0, i), want[i])
+		}
+	}
+}
@@ -0,0 +1,112 @@
+package user
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/captain-corgi/go-graphql-example/internal/domain/errors"
+)
+
+// MaxStatusReasonLength bounds the reason recorded with a status transition
+const MaxStatusReasonLength = 500
+
+// Status is the lifecycle state of a user account
+type Status string
+
+const (
+	// StatusInvited accounts have been created on the user's behalf but not yet taken up
+	StatusInvited Status = "INVITED"
+	// StatusActive accounts are in normal use
+	StatusActive Status = "ACTIVE"
+	// StatusSuspended accounts are temporarily disabled, typically by support
+	StatusSuspended Status = "SUSPENDED"
+	// StatusDeactivated accounts are closed but kept, and can be reactivated
+	StatusDeactivated Status = "DEACTIVATED"
+)
+
+// statusTransitions lists the states each status may move to
+var statusTransitions = map[Status][]Status{
+	StatusInvited:     {StatusActive, StatusDeactivated},
+	StatusActive:      {StatusSuspended, StatusDeactivated},
+	StatusSuspended:   {StatusActive, StatusDeactivated},
+	StatusDeactivated: {StatusActive},
+}
+
+// IsValid reports whether the status is a known status
+func (s Status) IsValid() bool {
+	_, ok := statusTransitions[s]
+	return ok
+}
+
+// CanTransitionTo reports whether the transition table allows moving from s to target
+func (s Status) CanTransitionTo(target Status) bool {
+	for _, allowed := range statusTransitions[s] {
+		if allowed == target {
+			return true
+		}
+	}
+	return false
+}
+
+// StatusTransition records a change of account status: who made it, why and when
+type StatusTransition struct {
+	UserID     UserID
+	From       Status
+	To         Status
+	Actor      string
+	Reason     string
+	OccurredAt time.Time
+}
+
+// ChangeStatus moves the user to the target status if the transition table allows it
+// and returns the transition to record. Actor and reason are required.
+func (u *User) ChangeStatus(target Status, actor, reason string) (StatusTransition, error) {
+	if !target.IsValid() {
+		return StatusTransition{}, errors.ErrInvalidStatus
+	}
+
+	if !u.status.CanTransitionTo(target) {
+		return StatusTransition{}, NewInvalidStatusTransitionError(u.status, target)
+	}
+
+	actor = strings.TrimSpace(actor)
+	if actor == "" {
+		return StatusTransition{}, errors.ErrStatusActorRequired
+	}
+
+	reason = strings.TrimSpace(reason)
+	if reason == "" || len(reason) > MaxStatusReasonLength {
+		return StatusTransition{}, errors.ErrInvalidStatusReason
+	}
+
+	now := time.Now()
+	transition := StatusTransition{
+		UserID:     u.id,
+		From:       u.status,
+		To:         target,
+		Actor:      actor,
+		Reason:     reason,
+		OccurredAt: now,
+	}
+
+	u.status = target
+	u.updatedAt = now
+
+	u.record(UserStatusChanged{
+		Base:   u.base(EventUserStatusChanged, now),
+		UserID: u.id,
+		From:   transition.From,
+		To:     transition.To,
+		Actor:  actor,
+		Reason: reason,
+	})
+	return transition, nil
+}
+
+// NewInvalidStatusTransitionError describes a transition the table does not allow
+func NewInvalidStatusTransitionError(from, to Status) errors.DomainError {
+	invalid := errors.ErrInvalidStatusTransition
+	invalid.Message = fmt.Sprintf("Cannot change status from %s to %s", from, to)
+	return invalid
+}
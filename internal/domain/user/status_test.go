@@ -0,0 +1,187 @@
+package user
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/captain-corgi/go-graphql-example/internal/domain/errors"
+)
+
+func TestStatus_CanTransitionTo(t *testing.T) {
+	tests := []struct {
+		from Status
+		to   Status
+		want bool
+	}{
+		{StatusInvited, StatusActive, true},
+		{StatusInvited, StatusSuspended, false},
+		{StatusInvited, StatusDeactivated, true},
+		{StatusActive, StatusSuspended, true},
+		{StatusActive, StatusDeactivated, true},
+		{StatusActive, StatusActive, false},
+		{StatusActive, StatusInvited, false},
+		{StatusSuspended, StatusActive, true},
+		{StatusSuspended, StatusSuspended, false},
+		{StatusSuspended, StatusDeactivated, true},
+		{StatusDeactivated, StatusActive, true},
+		{StatusDeactivated, StatusSuspended, false},
+		{StatusDeactivated, StatusDeactivated, false},
+		{Status("UNKNOWN"), StatusActive, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from)+"->"+string(tt.to), func(t *testing.T) {
+			if got := tt.from.CanTransitionTo(tt.to); got != tt.want {
+				t.Errorf("%s.CanTransitionTo(%s) = %v, want %v", tt.from, tt.to, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestUser_ChangeStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		from     Status
+		to       Status
+		actor    string
+		reason   string
+		wantCode string
+	}{
+		{
+			name:   "suspend active user",
+			from:   StatusActive,
+			to:     StatusSuspended,
+			actor:  "admin",
+			reason: "Chargeback investigation",
+		},
+		{
+			name:   "reactivate suspended user",
+			from:   StatusSuspended,
+			to:     StatusActive,
+			actor:  "admin",
+			reason: "Investigation closed",
+		},
+		{
+			name:     "suspend invited user",
+			from:     StatusInvited,
+			to:       StatusSuspended,
+			actor:    "admin",
+			reason:   "Spam",
+			wantCode: "INVALID_STATUS_TRANSITION",
+		},
+		{
+			name:     "unknown target",
+			from:     StatusActive,
+			to:       Status("BANNED"),
+			actor:    "admin",
+			reason:   "Spam",
+			wantCode: "INVALID_STATUS",
+		},
+		{
+			name:     "missing reason",
+			from:     StatusActive,
+			to:       StatusSuspended,
+			actor:    "admin",
+			reason:   "   ",
+			wantCode: "INVALID_STATUS_REASON",
+		},
+		{
+			name:     "reason too long",
+			from:     StatusActive,
+			to:       StatusSuspended,
+			actor:    "admin",
+			reason:   strings.Repeat("a", MaxStatusReasonLength+1),
+			wantCode: "INVALID_STATUS_REASON",
+		},
+		{
+			name:     "missing actor",
+			from:     StatusActive,
+			to:       StatusSuspended,
+			reason:   "Spam",
+			wantCode: "STATUS_ACTOR_REQUIRED",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			u, err := NewUser("test@example.com", "Test User")
+			if err != nil {
+				t.Fatalf("NewUser() unexpected error = %v", err)
+			}
+			u.status = tt.from
+			u.DrainEvents()
+			updatedAt := u.UpdatedAt()
+
+			transition, err := u.ChangeStatus(tt.to, tt.actor, tt.reason)
+
+			if tt.wantCode != "" {
+				domainErr, ok := err.(errors.DomainError)
+				if !ok || domainErr.Code != tt.wantCode {
+					t.Fatalf("ChangeStatus() error = %v, want code %s", err, tt.wantCode)
+				}
+				if u.Status() != tt.from {
+					t.Errorf("ChangeStatus() changed status to %s on failure", u.Status())
+				}
+				if events := u.DrainEvents(); len(events) != 0 {
+					t.Errorf("ChangeStatus() recorded %v on failure", events)
+				}
+				return
+			}
+
+			if err != nil {
+				t.Fatalf("ChangeStatus() unexpected error = %v", err)
+			}
+			if u.Status() != tt.to {
+				t.Errorf("ChangeStatus() status = %s, want %s", u.Status(), tt.to)
+			}
+			if u.UpdatedAt().Before(updatedAt) {
+				t.Errorf("ChangeStatus() did not advance updatedAt")
+			}
+
+			want := StatusTransition{
+				UserID:     u.ID(),
+				From:       tt.from,
+				To:         tt.to,
+				Actor:      tt.actor,
+				Reason:     tt.reason,
+				OccurredAt: transition.OccurredAt,
+			}
+			if transition != want {
+				t.Errorf("ChangeStatus() transition = %+v, want %+v", transition, want)
+			}
+			if transition.OccurredAt.IsZero() {
+				t.Error("ChangeStatus() transition has no timestamp")
+			}
+
+			events := u.DrainEvents()
+			if len(events) != 1 {
+				t.Fatalf("ChangeStatus() recorded %d events, want 1", len(events))
+			}
+			changed, ok := events[0].(UserStatusChanged)
+			if !ok || changed.From != tt.from || changed.To != tt.to || changed.Actor != tt.actor || changed.Reason != tt.reason {
+				t.Errorf("ChangeStatus() recorded %#v, want UserStatusChanged matching the transition", events[0])
+			}
+		})
+	}
+}
+
+func TestNewUser_StartsActive(t *testing.T) {
+	u, err := NewUser("test@example.com", "Test User")
+	if err != nil {
+		t.Fatalf("NewUser() unexpected error = %v", err)
+	}
+	if u.Status() != StatusActive {
+		t.Errorf("NewUser() status = %s, want %s", u.Status(), StatusActive)
+	}
+}
+
+func TestNewInvalidStatusTransitionError(t *testing.T) {
+	err := NewInvalidStatusTransitionError(StatusDeactivated, StatusSuspended)
+
+	if err.Code != errors.ErrInvalidStatusTransition.Code {
+		t.Errorf("Code = %s, want %s", err.Code, errors.ErrInvalidStatusTransition.Code)
+	}
+	if err.Message != "Cannot change status from DEACTIVATED to SUSPENDED" {
+		t.Errorf("Message = %q", err.Message)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"time"
 
 	"github.com/captain-corgi/go-graphql-example/internal/domain/errors"
+	"github.com/captain-corgi/go-graphql-example/internal/domain/events"
 )
 
 // User represents the user domain entity
@@ -13,9 +14,27 @@ type User struct {
 	name      Name
 	createdAt time.Time
 	updatedAt time.Time
+	deletedAt *time.Time
+	status    Status
+	version   int64
+
+	// pending holds domain events recorded since the last DrainEvents
+	pending []events.Event
+}
+
+// Snapshot holds the persisted state of a user, used to reconstruct the entity
+type Snapshot struct {
+	ID        string
+	Email     string
+	Name      string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+	DeletedAt *time.Time
+	Status    Status
+	Version   int64
 }
 
-// NewUser creates a new User entity with validation
+// NewUser creates a new active User entity with validation
 func NewUser(email, name string) (*User, error) {
 	emailVO, err := NewEmail(email)
 	if err != nil {
@@ -29,41 +48,91 @@ func NewUser(email, name string) (*User, error) {
 
 	now := time.Now()
 
-	return &User{
+	u := &User{
 		id:        GenerateUserID(),
 		email:     emailVO,
 		name:      nameVO,
 		createdAt: now,
 		updatedAt: now,
-	}, nil
+		status:    StatusActive,
+		version:   1,
+	}
+	u.record(UserRegistered{
+		Base:   u.base(EventUserRegistered, now),
+		UserID: u.id,
+		Email:  u.email,
+		Name:   u.name,
+	})
+
+	return u, nil
 }
 
-// NewUserWithID creates a User entity with a specific ID (for reconstruction from persistence)
+// NewUserWithID creates an active User entity with a specific ID (for reconstruction from persistence)
 func NewUserWithID(id, email, name string, createdAt, updatedAt time.Time) (*User, error) {
-	userID, err := NewUserID(id)
+	return NewUserFromSnapshot(Snapshot{
+		ID:        id,
+		Email:     email,
+		Name:      name,
+		CreatedAt: createdAt,
+		UpdatedAt: updatedAt,
+		Status:    StatusActive,
+		Version:   1,
+	})
+}
+
+// NewUserFromSnapshot reconstructs a User entity from its persisted state
+// Reconstruction records no events
+func NewUserFromSnapshot(s Snapshot) (*User, error) {
+	userID, err := NewUserID(s.ID)
 	if err != nil {
 		return nil, err
 	}
 
-	emailVO, err := NewEmail(email)
+	emailVO, err := NewEmail(s.Email)
 	if err != nil {
 		return nil, err
 	}
 
-	nameVO, err := NewName(name)
+	nameVO, err := NewName(s.Name)
 	if err != nil {
 		return nil, err
 	}
 
+	if !s.Status.IsValid() {
+		return nil, errors.ErrInvalidStatus
+	}
+
 	return &User{
 		id:        userID,
 		email:     emailVO,
 		name:      nameVO,
-		createdAt: createdAt,
-		updatedAt: updatedAt,
+		createdAt: s.CreatedAt,
+		updatedAt: s.UpdatedAt,
+		deletedAt: s.DeletedAt,
+		status:    s.Status,
+		version:   s.Version,
 	}, nil
 }
 
+// Snapshot captures the persisted state of the user, the inverse of NewUserFromSnapshot
+// The deletion time is copied so the snapshot never aliases the aggregate
+func (u *User) Snapshot() Snapshot {
+	s := Snapshot{
+		ID:        u.id.String(),
+		Email:     u.email.String(),
+		Name:      u.name.String(),
+		CreatedAt: u.createdAt,
+		UpdatedAt: u.updatedAt,
+		Status:    u.status,
+		Version:   u.version,
+	}
+	if u.deletedAt != nil {
+		t := *u.deletedAt
+		s.DeletedAt = &t
+	}
+	return s
+}
+
 // ID returns the user's ID
 func (u *User) ID() UserID {
 	return u.id
@@ -89,30 +158,127 @@ func (u *User) UpdatedAt() time.Time {
 	return u.updatedAt
 }
 
+// DeletedAt returns when the user was soft-deleted, or nil for a live user
+func (u *User) DeletedAt() *time.Time {
+	return u.deletedAt
+}
+
+// IsDeleted reports whether the user has been soft-deleted
+func (u *User) IsDeleted() bool {
+	return u.deletedAt != nil
+}
+
+// Status returns the user's account status
+func (u *User) Status() Status {
+	return u.status
+}
+
+// Version returns the stored version the user was read at
+// Every saved change increments it, so writes based on a stale read can be detected
+func (u *User) Version() int64 {
+	return u.version
+}
+
+// IncrementVersion records that a change to the user has been saved
+// Repositories call it after a successful versioned write
+func (u *User) IncrementVersion() {
+	u.version++
+}
+
 // UpdateEmail updates the user's email with validation
+// UserEmailChanged is recorded only when the email actually changes
 func (u *User) UpdateEmail(email string) error {
 	emailVO, err := NewEmail(email)
 	if err != nil {
 		return err
 	}
 
+	previous := u.email
 	u.email = emailVO
 	u.updatedAt = time.Now()
+
+	if !previous.Equals(emailVO) {
+		u.record(UserEmailChanged{
+			Base:   u.base(EventUserEmailChanged, u.updatedAt),
+			UserID: u.id,
+			From:   previous,
+			To:     emailVO,
+		})
+	}
 	return nil
 }
 
 // UpdateName updates the user's name with validation
+// UserRenamed is recorded only when the name actually changes
 func (u *User) UpdateName(name string) error {
 	nameVO, err := NewName(name)
 	if err != nil {
 		return err
 	}
 
+	previous := u.name
 	u.name = nameVO
 	u.updatedAt = time.Now()
+
+	if !previous.Equals(nameVO) {
+		u.record(UserRenamed{
+			Base:   u.base(EventUserRenamed, u.updatedAt),
+			UserID: u.id,
+			From:   previous,
+			To:     nameVO,
+		})
+	}
+	return nil
+}
+
+// Delete soft-deletes the user and records UserDeleted
+// Deleting a user that is already deleted returns ErrUserNotFound
+func (u *User) Delete() error {
+	if u.IsDeleted() {
+		return errors.ErrUserNotFound
+	}
+
+	now := time.Now()
+	u.deletedAt = &now
+
+	u.record(UserDeleted{
+		Base:   u.base(EventUserDeleted, now),
+		UserID: u.id,
+		Email:  u.email,
+		Name:   u.name,
+	})
+	return nil
+}
+
+// Restore brings a soft-deleted user back and records UserRestored
+// Restoring a live user returns ErrUserNotDeleted
+func (u *User) Restore() error {
+	if !u.IsDeleted() {
+		return errors.ErrUserNotDeleted
+	}
+
+	now := time.Now()
+	u.deletedAt = nil
+
+	u.record(UserRestored{
+		Base:   u.base(EventUserRestored, now),
+		UserID: u.id,
+		Email:  u.email,
+		Name:   u.name,
+	})
 	return nil
 }
 
+// Purge records UserPurged for a user about to be permanently removed, deleted or not
+func (u *User) Purge() {
+	u.record(UserPurged{
+		Base:   u.base(EventUserPurged, time.Now()),
+		UserID: u.id,
+		Email:  u.email,
+		Name:   u.name,
+	})
+}
+
 // Validate performs comprehensive validation of the user entity
 func (u *User) Validate() error {
 	if u.id.String() == "" {
@@ -151,6 +317,10 @@ func (u *User) Validate() error {
 		}
 	}
 
+	if !u.status.IsValid() {
+		return errors.ErrInvalidStatus
+	}
+
 	return nil
 }
 
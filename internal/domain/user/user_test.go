@@ -244,6 +244,85 @@ func TestNewUserWithID(t *testing.T) {
 	}
 }
 
+func TestNewUserFromSnapshot(t *testing.T) {
+	createdAt := time.Now().Add(-time.Hour)
+	deletedAt := time.Now()
+
+	live, err := NewUserFromSnapshot(Snapshot{
+		ID:        uuid.New().String(),
+		Email:     "live@example.com",
+		Name:      "Live User",
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+		Status:    StatusActive,
+	})
+	if err != nil {
+		t.Fatalf("NewUserFromSnapshot() unexpected error = %v", err)
+	}
+	if live.IsDeleted() || live.DeletedAt() != nil {
+		t.Errorf("NewUserFromSnapshot() live user reported deleted at %v", live.DeletedAt())
+	}
+
+	deleted, err := NewUserFromSnapshot(Snapshot{
+		ID:        uuid.New().String(),
+		Email:     "deleted@example.com",
+		Name:      "Deleted User",
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+		DeletedAt: &deletedAt,
+		Status:    StatusDeactivated,
+	})
+	if err != nil {
+		t.Fatalf("NewUserFromSnapshot() unexpected error = %v", err)
+	}
+	if !deleted.IsDeleted() {
+		t.Error("NewUserFromSnapshot() deleted user reported live")
+	}
+	if deleted.DeletedAt() == nil || !deleted.DeletedAt().Equal(deletedAt) {
+		t.Errorf("NewUserFromSnapshot() DeletedAt = %v, want %v", deleted.DeletedAt(), deletedAt)
+	}
+
+	if _, err := NewUserFromSnapshot(Snapshot{ID: "invalid-id", Email: "a@example.com", Name: "A", Status: StatusActive}); err == nil {
+		t.Error("NewUserFromSnapshot() expected error for invalid ID, got nil")
+	}
+
+	if _, err := NewUserFromSnapshot(Snapshot{ID: uuid.New().String(), Email: "a@example.com", Name: "A"}); err != errors.ErrInvalidStatus {
+		t.Errorf("NewUserFromSnapshot() error = %v, want %v", err, errors.ErrInvalidStatus)
+	}
+}
+
+func TestUser_Snapshot(t *testing.T) {
+	deletedAt := time.Now()
+	want := Snapshot{
+		ID:        uuid.New().String(),
+		Email:     "deleted@example.com",
+		Name:      "Deleted User",
+		CreatedAt: deletedAt.Add(-time.Hour),
+		UpdatedAt: deletedAt.Add(-time.Minute),
+		DeletedAt: &deletedAt,
+		Status:    StatusDeactivated,
+		Version:   3,
+	}
+
+	u, err := NewUserFromSnapshot(want)
+	if err != nil {
+		t.Fatalf("NewUserFromSnapshot() unexpected error = %v", err)
+	}
+
+	got := u.Snapshot()
+	if got.ID != want.ID || got.Email != want.Email || got.Name != want.Name ||
+		!got.CreatedAt.Equal(want.CreatedAt) || !got.UpdatedAt.Equal(want.UpdatedAt) ||
+		got.Status != want.Status || got.Version != want.Version {
+		t.Errorf("Snapshot() = %+v, want %+v", got, want)
+	}
+	if got.DeletedAt == nil || !got.DeletedAt.Equal(deletedAt) {
+		t.Fatalf("Snapshot() DeletedAt = %v, want %v", got.DeletedAt, deletedAt)
+	}
+	if got.DeletedAt == u.DeletedAt() {
+		t.Error("Snapshot() DeletedAt aliases the user's deletion time")
+	}
+}
+
 func TestUser_UpdateEmail(t *testing.T) {
 	tests := []struct {
 		name     string
@@ -641,3 +720,32 @@ func TestUser_Getters(t *testing.T) {
 		t.Error("ID() should return consistent values")
 	}
 }
+
+func TestUser_Version(t *testing.T) {
+	u, err := NewUser("version@example.com", "Version User")
+	if err != nil {
+		t.Fatalf("NewUser() unexpected error = %v", err)
+	}
+	if u.Version() != 1 {
+		t.Errorf("NewUser() Version = %d, want 1", u.Version())
+	}
+
+	u.IncrementVersion()
+	if u.Version() != 2 {
+		t.Errorf("IncrementVersion() Version = %d, want 2", u.Version())
+	}
+
+	restored, err := NewUserFromSnapshot(Snapshot{
+		ID:      uuid.New().String(),
+		Email:   "restored@example.com",
+		Name:    "Restored User",
+		Status:  StatusActive,
+		Version: 7,
+	})
+	if err != nil {
+		t.Fatalf("NewUserFromSnapshot() unexpected error = %v", err)
+	}
+	if restored.Version() != 7 {
+		t.Errorf("NewUserFromSnapshot() Version = %d, want 7", restored.Version())
+	}
+}
@@ -2,31 +2,103 @@ package config
 
 import (
 	"fmt"
+	"strings"
 	"time"
 )
 
 // Config represents the application configuration
 type Config struct {
-	Server   ServerConfig   `mapstructure:"server"`
-	Database DatabaseConfig `mapstructure:"database"`
-	Logging  LoggingConfig  `mapstructure:"logging"`
+	// Environment names the deployment the configuration is for; it decides which seed profiles may run
+	Environment   string              `mapstructure:"environment"`
+	Server        ServerConfig        `mapstructure:"server"`
+	Database      DatabaseConfig      `mapstructure:"database"`
+	Logging       LoggingConfig       `mapstructure:"logging"`
+	Pagination    PaginationConfig    `mapstructure:"pagination"`
+	Subscriptions SubscriptionsConfig `mapstructure:"subscriptions"`
+	Outbox        OutboxConfig        `mapstructure:"outbox"`
+	Cache         CacheConfig         `mapstructure:"cache"`
+	Imports       ImportsConfig       `mapstructure:"imports"`
 }
 
+// Environments selectable with environment
+const (
+	EnvironmentDevelopment = "development"
+	EnvironmentTest        = "test"
+	EnvironmentStaging     = "staging"
+	EnvironmentProduction  = "production"
+)
+
 // ServerConfig holds HTTP server configuration
 type ServerConfig struct {
-	Port         string        `mapstructure:"port"`
-	ReadTimeout  time.Duration `mapstructure:"read_timeout"`
-	WriteTimeout time.Duration `mapstructure:"write_timeout"`
-	IdleTimeout  time.Duration `mapstructure:"idle_timeout"`
+	Port         string          `mapstructure:"port"`
+	ReadTimeout  time.Duration   `mapstructure:"read_timeout"`
+	WriteTimeout time.Duration   `mapstructure:"write_timeout"`
+	IdleTimeout  time.Duration   `mapstructure:"idle_timeout"`
+	WebSocket    WebSocketConfig `mapstructure:"websocket"`
+	// AdminToken, when set, grants the admin role to requests sending it as a bearer token
+	AdminToken string `mapstructure:"admin_token"`
+	// Instances is how many server instances serve the API behind the load balancer; zero means one
+	Instances int `mapstructure:"instances"`
+}
+
+// WebSocketConfig holds configuration for GraphQL subscriptions over WebSocket
+type WebSocketConfig struct {
+	// KeepAliveInterval is how often the server pings idle connections; zero disables keepalives
+	KeepAliveInterval time.Duration `mapstructure:"keepalive_interval"`
+	// InitTimeout bounds how long a client may take to send connection_init; zero waits forever
+	InitTimeout time.Duration `mapstructure:"init_timeout"`
+	// AuthToken, when set, must be sent as a bearer token in the connection_init payload
+	AuthToken string `mapstructure:"auth_token"`
 }
 
+// Database drivers selectable with database.driver
+const (
+	DatabaseDriverPostgres = "postgres"
+	DatabaseDriverMemory   = "memory"
+	DatabaseDriverSQLite   = "sqlite"
+)
+
+// Schema drift modes selectable with database.schema_drift
+const (
+	SchemaDriftFail = "fail"
+	SchemaDriftWarn = "warn"
+	SchemaDriftOff  = "off"
+)
+
 // DatabaseConfig holds database connection configuration
 type DatabaseConfig struct {
+	// Driver selects the user store; "memory" keeps users in process and ignores the other settings,
+	// "sqlite" takes a file URL such as file:data/users.db and needs a binary built with -tags sqlite
+	Driver          string        `mapstructure:"driver"`
 	URL             string        `mapstructure:"url"`
 	MaxOpenConns    int           `mapstructure:"max_open_conns"`
 	MaxIdleConns    int           `mapstructure:"max_idle_conns"`
 	ConnMaxLifetime time.Duration `mapstructure:"conn_max_lifetime"`
 	ConnMaxIdleTime time.Duration `mapstructure:"conn_max_idle_time"`
+	// AutoMigrate applies pending migrations on startup; turn it off to migrate with cmd/migrate instead
+	AutoMigrate bool `mapstructure:"auto_migrate"`
+	// MigrationLockTimeout is how long an instance waits for another one to finish migrating on startup
+	MigrationLockTimeout time.Duration `mapstructure:"migration_lock_timeout"`
+	// SchemaDrift decides what happens on startup when live tables differ from what the code expects:
+	// "fail" refuses to start, "warn" (the default) logs the differences and "off" skips the check
+	SchemaDrift string `mapstructure:"schema_drift"`
+	// ReplicaURLs are optional Postgres read replicas; each gets its own pool with the settings above
+	ReplicaURLs []string `mapstructure:"replica_urls"`
+	// ReplicaHealthCheckInterval is how often replicas are pinged to eject or readmit them
+	ReplicaHealthCheckInterval time.Duration `mapstructure:"replica_health_check_interval"`
+	// Retry controls how transactions that hit a transient failure are run again
+	Retry RetryConfig `mapstructure:"retry"`
+}
+
+// RetryConfig holds the retry policy for transactions that fail transiently,
+// such as on a serialization failure, a deadlock or a lost connection
+type RetryConfig struct {
+	// MaxAttempts is how many times a transaction is run in total; 1 disables retries
+	MaxAttempts int `mapstructure:"max_attempts"`
+	// BaseBackoff is the delay before the first retry; it doubles with each further retry
+	BaseBackoff time.Duration `mapstructure:"base_backoff"`
+	// MaxBackoff caps the delay between attempts
+	MaxBackoff time.Duration `mapstructure:"max_backoff"`
 }
 
 // LoggingConfig holds logging configuration
@@ -35,6 +107,56 @@ type LoggingConfig struct {
 	Format string `mapstructure:"format"`
 }
 
+// PaginationConfig holds cursor pagination configuration
+type PaginationConfig struct {
+	CursorSecret string `mapstructure:"cursor_secret"`
+}
+
+// SubscriptionsConfig holds configuration for the in-process event broker
+type SubscriptionsConfig struct {
+	// BufferSize is how many events a subscriber may fall behind before it is dropped
+	BufferSize int `mapstructure:"buffer_size"`
+}
+
+// OutboxConfig holds configuration for relaying outbox events to the event publisher
+type OutboxConfig struct {
+	// Publisher selects where events are published; "log" is the only built-in publisher
+	Publisher string `mapstructure:"publisher"`
+	// PollInterval is how often the relay looks for due messages
+	PollInterval time.Duration `mapstructure:"poll_interval"`
+	// BatchSize is how many messages the relay claims per transaction
+	BatchSize int `mapstructure:"batch_size"`
+	// MaxAttempts is how many failed deliveries a message gets before it is dead-lettered
+	MaxAttempts int `mapstructure:"max_attempts"`
+	// BaseBackoff is the delay after the first failed delivery; it doubles with every further failure
+	BaseBackoff time.Duration `mapstructure:"base_backoff"`
+	// MaxBackoff caps the delay between deliveries
+	MaxBackoff time.Duration `mapstructure:"max_backoff"`
+}
+
+// CacheConfig holds configuration for the read-through cache of user lookups by ID, IDs and email
+type CacheConfig struct {
+	// Enabled wraps the user store in the cache
+	Enabled bool `mapstructure:"enabled"`
+	// Size is how many lookups the cache holds before evicting the least recently used
+	Size int `mapstructure:"size"`
+	// TTL is how long a found user is served from the cache
+	TTL time.Duration `mapstructure:"ttl"`
+	// NegativeTTL is how long a lookup that found no user is remembered; zero disables negative caching
+	NegativeTTL time.Duration `mapstructure:"negative_ttl"`
+	// StaleIfError serves an expired entry when the store fails, up to MaxStale past its expiry
+	StaleIfError bool          `mapstructure:"stale_if_error"`
+	MaxStale     time.Duration `mapstructure:"max_stale"`
+}
+
+// ImportsConfig holds configuration for the user imports started with importUsers
+// Jobs and their row reports live in the memory of the instance running them, so importJob
+// finds a job only on that instance and a restart forgets it
+type ImportsConfig struct {
+	// Enabled accepts importUsers uploads; it needs a single server instance
+	Enabled bool `mapstructure:"enabled"`
+}
+
 // Validate validates the configuration and returns an error if invalid
 func (c *Config) Validate() error {
 	if err := c.Server.Validate(); err != nil {
@@ -49,6 +171,32 @@ func (c *Config) Validate() error {
 		return fmt.Errorf("logging config validation failed: %w", err)
 	}
 
+	if err := c.Pagination.Validate(); err != nil {
+		return fmt.Errorf("pagination config validation failed: %w", err)
+	}
+
+	if err := c.Subscriptions.Validate(); err != nil {
+		return fmt.Errorf("subscriptions config validation failed: %w", err)
+	}
+
+	if err := c.Outbox.Validate(); err != nil {
+		return fmt.Errorf("outbox config validation failed: %w", err)
+	}
+
+	if err := c.Cache.Validate(); err != nil {
+		return fmt.Errorf("cache config validation failed: %w", err)
+	}
+
+	if c.Imports.Enabled && c.Server.Instances > 1 {
+		return fmt.Errorf("imports need a single server instance, as import jobs live in the memory of the instance running them; set imports.enabled to false or server.instances to 1")
+	}
+
+	switch c.Environment {
+	case EnvironmentDevelopment, EnvironmentTest, EnvironmentStaging, EnvironmentProduction:
+	default:
+		return fmt.Errorf("environment must be one of development, test, staging, production; got %q", c.Environment)
+	}
+
 	return nil
 }
 
@@ -70,11 +218,46 @@ func (s *ServerConfig) Validate() error {
 		return fmt.Errorf("server idle timeout must be positive")
 	}
 
+	if s.WebSocket.KeepAliveInterval < 0 {
+		return fmt.Errorf("websocket keepalive interval cannot be negative")
+	}
+
+	if s.WebSocket.InitTimeout < 0 {
+		return fmt.Errorf("websocket init timeout cannot be negative")
+	}
+
+	if s.Instances < 0 {
+		return fmt.Errorf("server instances cannot be negative")
+	}
+
+	if isPlaceholder(s.WebSocket.AuthToken) {
+		return fmt.Errorf("websocket auth token is an unexpanded placeholder; set GRAPHQL_SERVICE_SERVER_WEBSOCKET_AUTH_TOKEN instead")
+	}
+
+	if isPlaceholder(s.AdminToken) {
+		return fmt.Errorf("admin token is an unexpanded placeholder; set GRAPHQL_SERVICE_SERVER_ADMIN_TOKEN instead")
+	}
+
 	return nil
 }
 
+// isPlaceholder reports whether a value still holds a ${VAR} reference
+// Config files are not expanded, so such a value would be used literally
+func isPlaceholder(value string) bool {
+	return strings.Contains(value, "${")
+}
+
 // Validate validates database configuration
+// An empty driver means postgres
 func (d *DatabaseConfig) Validate() error {
+	switch d.Driver {
+	case DatabaseDriverMemory:
+		return nil
+	case "", DatabaseDriverPostgres, DatabaseDriverSQLite:
+	default:
+		return fmt.Errorf("invalid database driver: %s (must be one of: memory, postgres, sqlite)", d.Driver)
+	}
+
 	if d.URL == "" {
 		return fmt.Errorf("database URL is required")
 	}
@@ -99,6 +282,44 @@ func (d *DatabaseConfig) Validate() error {
 		return fmt.Errorf("database connection max idle time must be positive")
 	}
 
+	if d.AutoMigrate && d.MigrationLockTimeout <= 0 {
+		return fmt.Errorf("database migration lock timeout must be positive when auto-migrate is enabled")
+	}
+
+	switch d.SchemaDrift {
+	case "", SchemaDriftFail, SchemaDriftWarn, SchemaDriftOff:
+	default:
+		return fmt.Errorf("database schema drift mode must be fail, warn or off; got %q", d.SchemaDrift)
+	}
+
+	if len(d.ReplicaURLs) > 0 {
+		if d.Driver == DatabaseDriverSQLite {
+			return fmt.Errorf("database replicas require the postgres driver")
+		}
+
+		for i, url := range d.ReplicaURLs {
+			if url == "" {
+				return fmt.Errorf("database replica URL %d is empty", i)
+			}
+		}
+
+		if d.ReplicaHealthCheckInterval <= 0 {
+			return fmt.Errorf("database replica health check interval must be positive")
+		}
+	}
+
+	if d.Retry.MaxAttempts <= 0 {
+		return fmt.Errorf("database retry max attempts must be positive")
+	}
+
+	if d.Retry.BaseBackoff <= 0 {
+		return fmt.Errorf("database retry base backoff must be positive")
+	}
+
+	if d.Retry.MaxBackoff < d.Retry.BaseBackoff {
+		return fmt.Errorf("database retry max backoff cannot be less than base backoff")
+	}
+
 	return nil
 }
 
@@ -126,3 +347,79 @@ func (l *LoggingConfig) Validate() error {
 
 	return nil
 }
+
+// Validate validates pagination configuration
+func (p *PaginationConfig) Validate() error {
+	if p.CursorSecret == "" {
+		return fmt.Errorf("pagination cursor secret is required")
+	}
+
+	if len(p.CursorSecret) < 32 {
+		return fmt.Errorf("pagination cursor secret must be at least 32 characters")
+	}
+
+	return nil
+}
+
+// Validate validates subscriptions configuration
+func (s *SubscriptionsConfig) Validate() error {
+	if s.BufferSize <= 0 {
+		return fmt.Errorf("subscriptions buffer size must be positive")
+	}
+
+	return nil
+}
+
+// Validate validates outbox configuration
+func (o *OutboxConfig) Validate() error {
+	if o.Publisher != "log" {
+		return fmt.Errorf("invalid outbox publisher: %s (must be: log)", o.Publisher)
+	}
+
+	if o.PollInterval <= 0 {
+		return fmt.Errorf("outbox poll interval must be positive")
+	}
+
+	if o.BatchSize <= 0 {
+		return fmt.Errorf("outbox batch size must be positive")
+	}
+
+	if o.MaxAttempts <= 0 {
+		return fmt.Errorf("outbox max attempts must be positive")
+	}
+
+	if o.BaseBackoff <= 0 {
+		return fmt.Errorf("outbox base backoff must be positive")
+	}
+
+	if o.MaxBackoff < o.BaseBackoff {
+		return fmt.Errorf("outbox max backoff cannot be less than base backoff")
+	}
+
+	return nil
+}
+
+// Validate validates cache configuration; a disabled cache is not checked
+func (c *CacheConfig) Validate() error {
+	if !c.Enabled {
+		return nil
+	}
+
+	if c.Size <= 0 {
+		return fmt.Errorf("cache size must be positive")
+	}
+
+	if c.TTL <= 0 {
+		return fmt.Errorf("cache TTL must be positive")
+	}
+
+	if c.NegativeTTL < 0 {
+		return fmt.Errorf("cache negative TTL cannot be negative")
+	}
+
+	if c.StaleIfError && c.MaxStale <= 0 {
+		return fmt.Errorf("cache max stale must be positive when stale-if-error is enabled")
+	}
+
+	return nil
+}
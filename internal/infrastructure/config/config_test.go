@@ -18,6 +18,7 @@ func TestConfig_Validate(t *testing.T) {
 		{
 			name: "valid config",
 			config: Config{
+				Environment: EnvironmentDevelopment,
 				Server: ServerConfig{
 					Port:         "8080",
 					ReadTimeout:  30 * time.Second,
@@ -30,11 +31,19 @@ func TestConfig_Validate(t *testing.T) {
 					MaxIdleConns:    5,
 					ConnMaxLifetime: 5 * time.Minute,
 					ConnMaxIdleTime: 5 * time.Minute,
+					Retry:           validRetryConfig(),
 				},
 				Logging: LoggingConfig{
 					Level:  "info",
 					Format: "json",
 				},
+				Pagination: PaginationConfig{
+					CursorSecret: "0123456789abcdef0123456789abcdef",
+				},
+				Subscriptions: SubscriptionsConfig{
+					BufferSize: 64,
+				},
+				Outbox: validOutboxConfig(),
 			},
 			wantErr: false,
 		},
@@ -53,6 +62,7 @@ func TestConfig_Validate(t *testing.T) {
 					MaxIdleConns:    5,
 					ConnMaxLifetime: 5 * time.Minute,
 					ConnMaxIdleTime: 5 * time.Minute,
+					Retry:           validRetryConfig(),
 				},
 				Logging: LoggingConfig{
 					Level:  "info",
@@ -77,6 +87,7 @@ func TestConfig_Validate(t *testing.T) {
 					MaxIdleConns:    5,
 					ConnMaxLifetime: 5 * time.Minute,
 					ConnMaxIdleTime: 5 * time.Minute,
+					Retry:           validRetryConfig(),
 				},
 				Logging: LoggingConfig{
 					Level:  "info",
@@ -101,6 +112,7 @@ func TestConfig_Validate(t *testing.T) {
 					MaxIdleConns:    5,
 					ConnMaxLifetime: 5 * time.Minute,
 					ConnMaxIdleTime: 5 * time.Minute,
+					Retry:           validRetryConfig(),
 				},
 				Logging: LoggingConfig{
 					Level:  "invalid",
@@ -110,6 +122,133 @@ func TestConfig_Validate(t *testing.T) {
 			wantErr: true,
 			errMsg:  "logging config validation failed",
 		},
+		{
+			name: "invalid pagination config - missing cursor secret",
+			config: Config{
+				Server: ServerConfig{
+					Port:         "8080",
+					ReadTimeout:  30 * time.Second,
+					WriteTimeout: 30 * time.Second,
+					IdleTimeout:  120 * time.Second,
+				},
+				Database: DatabaseConfig{
+					URL:             "postgres://localhost/test",
+					MaxOpenConns:    25,
+					MaxIdleConns:    5,
+					ConnMaxLifetime: 5 * time.Minute,
+					ConnMaxIdleTime: 5 * time.Minute,
+					Retry:           validRetryConfig(),
+				},
+				Logging: LoggingConfig{
+					Level:  "info",
+					Format: "json",
+				},
+			},
+			wantErr: true,
+			errMsg:  "pagination config validation failed",
+		},
+		{
+			name: "unknown environment",
+			config: Config{
+				Environment: "prod",
+				Server: ServerConfig{
+					Port:         "8080",
+					ReadTimeout:  30 * time.Second,
+					WriteTimeout: 30 * time.Second,
+					IdleTimeout:  120 * time.Second,
+				},
+				Database: DatabaseConfig{
+					URL:             "postgres://localhost/test",
+					MaxOpenConns:    25,
+					MaxIdleConns:    5,
+					ConnMaxLifetime: 5 * time.Minute,
+					ConnMaxIdleTime: 5 * time.Minute,
+					Retry:           validRetryConfig(),
+				},
+				Logging: LoggingConfig{
+					Level:  "info",
+					Format: "json",
+				},
+				Pagination: PaginationConfig{
+					CursorSecret: "0123456789abcdef0123456789abcdef",
+				},
+				Subscriptions: SubscriptionsConfig{
+					BufferSize: 64,
+				},
+				Outbox: validOutboxConfig(),
+			},
+			wantErr: true,
+			errMsg:  "environment must be one of",
+		},
+		{
+			name: "imports on several instances",
+			config: Config{
+				Environment: EnvironmentProduction,
+				Server: ServerConfig{
+					Port:         "8080",
+					ReadTimeout:  30 * time.Second,
+					WriteTimeout: 30 * time.Second,
+					IdleTimeout:  120 * time.Second,
+					Instances:    3,
+				},
+				Database: DatabaseConfig{
+					URL:             "postgres://localhost/test",
+					MaxOpenConns:    25,
+					MaxIdleConns:    5,
+					ConnMaxLifetime: 5 * time.Minute,
+					ConnMaxIdleTime: 5 * time.Minute,
+					Retry:           validRetryConfig(),
+				},
+				Logging: LoggingConfig{
+					Level:  "info",
+					Format: "json",
+				},
+				Pagination: PaginationConfig{
+					CursorSecret: "0123456789abcdef0123456789abcdef",
+				},
+				Subscriptions: SubscriptionsConfig{
+					BufferSize: 64,
+				},
+				Outbox:  validOutboxConfig(),
+				Imports: ImportsConfig{Enabled: true},
+			},
+			wantErr: true,
+			errMsg:  "imports need a single server instance",
+		},
+		{
+			name: "several instances without imports",
+			config: Config{
+				Environment: EnvironmentProduction,
+				Server: ServerConfig{
+					Port:         "8080",
+					ReadTimeout:  30 * time.Second,
+					WriteTimeout: 30 * time.Second,
+					IdleTimeout:  120 * time.Second,
+					Instances:    3,
+				},
+				Database: DatabaseConfig{
+					URL:             "postgres://localhost/test",
+					MaxOpenConns:    25,
+					MaxIdleConns:    5,
+					ConnMaxLifetime: 5 * time.Minute,
+					ConnMaxIdleTime: 5 * time.Minute,
+					Retry:           validRetryConfig(),
+				},
+				Logging: LoggingConfig{
+					Level:  "info",
+					Format: "json",
+				},
+				Pagination: PaginationConfig{
+					CursorSecret: "0123456789abcdef0123456789abcdef",
+				},
+				Subscriptions: SubscriptionsConfig{
+					BufferSize: 64,
+				},
+				Outbox:  validOutboxConfig(),
+				Imports: ImportsConfig{Enabled: false},
+			},
+			wantErr: false,
+		},
 	}
 
 	for _, tt := range tests {
@@ -142,6 +281,58 @@ func TestServerConfig_Validate(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "negative websocket keepalive interval",
+			config: ServerConfig{
+				Port:         "8080",
+				ReadTimeout:  30 * time.Second,
+				WriteTimeout: 30 * time.Second,
+				IdleTimeout:  120 * time.Second,
+				WebSocket: WebSocketConfig{
+					KeepAliveInterval: -time.Second,
+				},
+			},
+			wantErr: true,
+			errMsg:  "websocket keepalive interval cannot be negative",
+		},
+		{
+			name: "websocket auth token placeholder",
+			config: ServerConfig{
+				Port:         "8080",
+				ReadTimeout:  30 * time.Second,
+				WriteTimeout: 30 * time.Second,
+				IdleTimeout:  120 * time.Second,
+				WebSocket: WebSocketConfig{
+					AuthToken: "${WEBSOCKET_AUTH_TOKEN}",
+				},
+			},
+			wantErr: true,
+			errMsg:  "websocket auth token is an unexpanded placeholder",
+		},
+		{
+			name: "admin token placeholder",
+			config: ServerConfig{
+				Port:         "8080",
+				ReadTimeout:  30 * time.Second,
+				WriteTimeout: 30 * time.Second,
+				IdleTimeout:  120 * time.Second,
+				AdminToken:   "${ADMIN_TOKEN}",
+			},
+			wantErr: true,
+			errMsg:  "admin token is an unexpanded placeholder",
+		},
+		{
+			name: "negative instances",
+			config: ServerConfig{
+				Port:         "8080",
+				ReadTimeout:  30 * time.Second,
+				WriteTimeout: 30 * time.Second,
+				IdleTimeout:  120 * time.Second,
+				Instances:    -1,
+			},
+			wantErr: true,
+			errMsg:  "server instances cannot be negative",
+		},
 		{
 			name: "empty port",
 			config: ServerConfig{
@@ -216,6 +407,7 @@ func TestDatabaseConfig_Validate(t *testing.T) {
 				MaxIdleConns:    5,
 				ConnMaxLifetime: 5 * time.Minute,
 				ConnMaxIdleTime: 5 * time.Minute,
+				Retry:           validRetryConfig(),
 			},
 			wantErr: false,
 		},
@@ -227,6 +419,7 @@ func TestDatabaseConfig_Validate(t *testing.T) {
 				MaxIdleConns:    5,
 				ConnMaxLifetime: 5 * time.Minute,
 				ConnMaxIdleTime: 5 * time.Minute,
+				Retry:           validRetryConfig(),
 			},
 			wantErr: true,
 			errMsg:  "database URL is required",
@@ -239,6 +432,7 @@ func TestDatabaseConfig_Validate(t *testing.T) {
 				MaxIdleConns:    5,
 				ConnMaxLifetime: 5 * time.Minute,
 				ConnMaxIdleTime: 5 * time.Minute,
+				Retry:           validRetryConfig(),
 			},
 			wantErr: true,
 			errMsg:  "database max open connections must be positive",
@@ -251,6 +445,7 @@ func TestDatabaseConfig_Validate(t *testing.T) {
 				MaxIdleConns:    0,
 				ConnMaxLifetime: 5 * time.Minute,
 				ConnMaxIdleTime: 5 * time.Minute,
+				Retry:           validRetryConfig(),
 			},
 			wantErr: true,
 			errMsg:  "database max idle connections must be positive",
@@ -263,6 +458,7 @@ func TestDatabaseConfig_Validate(t *testing.T) {
 				MaxIdleConns:    10,
 				ConnMaxLifetime: 5 * time.Minute,
 				ConnMaxIdleTime: 5 * time.Minute,
+				Retry:           validRetryConfig(),
 			},
 			wantErr: true,
 			errMsg:  "database max idle connections cannot exceed max open connections",
@@ -275,6 +471,7 @@ func TestDatabaseConfig_Validate(t *testing.T) {
 				MaxIdleConns:    5,
 				ConnMaxLifetime: 0,
 				ConnMaxIdleTime: 5 * time.Minute,
+				Retry:           validRetryConfig(),
 			},
 			wantErr: true,
 			errMsg:  "database connection max lifetime must be positive",
@@ -287,10 +484,223 @@ func TestDatabaseConfig_Validate(t *testing.T) {
 				MaxIdleConns:    5,
 				ConnMaxLifetime: 5 * time.Minute,
 				ConnMaxIdleTime: 0,
+				Retry:           validRetryConfig(),
 			},
 			wantErr: true,
 			errMsg:  "database connection max idle time must be positive",
 		},
+		{
+			name: "memory driver ignores connection settings",
+			config: DatabaseConfig{
+				Driver: "memory",
+			},
+			wantErr: false,
+		},
+		{
+			name: "explicit postgres driver",
+			config: DatabaseConfig{
+				Driver:          "postgres",
+				URL:             "postgres://localhost/test",
+				MaxOpenConns:    25,
+				MaxIdleConns:    5,
+				ConnMaxLifetime: 5 * time.Minute,
+				ConnMaxIdleTime: 5 * time.Minute,
+				Retry:           validRetryConfig(),
+			},
+			wantErr: false,
+		},
+		{
+			name: "sqlite driver",
+			config: DatabaseConfig{
+				Driver:          "sqlite",
+				URL:             "file:data/users.db",
+				MaxOpenConns:    1,
+				MaxIdleConns:    1,
+				ConnMaxLifetime: 5 * time.Minute,
+				ConnMaxIdleTime: 5 * time.Minute,
+				Retry:           validRetryConfig(),
+			},
+			wantErr: false,
+		},
+		{
+			name: "sqlite driver requires URL",
+			config: DatabaseConfig{
+				Driver:          "sqlite",
+				MaxOpenConns:    1,
+				MaxIdleConns:    1,
+				ConnMaxLifetime: 5 * time.Minute,
+				ConnMaxIdleTime: 5 * time.Minute,
+				Retry:           validRetryConfig(),
+			},
+			wantErr: true,
+			errMsg:  "database URL is required",
+		},
+		{
+			name: "replicas",
+			config: DatabaseConfig{
+				URL:                        "postgres://primary/test",
+				MaxOpenConns:               25,
+				MaxIdleConns:               5,
+				ConnMaxLifetime:            5 * time.Minute,
+				ConnMaxIdleTime:            5 * time.Minute,
+				Retry:                      validRetryConfig(),
+				ReplicaURLs:                []string{"postgres://replica-1/test", "postgres://replica-2/test"},
+				ReplicaHealthCheckInterval: 10 * time.Second,
+			},
+			wantErr: false,
+		},
+		{
+			name: "empty replica URL",
+			config: DatabaseConfig{
+				URL:                        "postgres://primary/test",
+				MaxOpenConns:               25,
+				MaxIdleConns:               5,
+				ConnMaxLifetime:            5 * time.Minute,
+				ConnMaxIdleTime:            5 * time.Minute,
+				Retry:                      validRetryConfig(),
+				ReplicaURLs:                []string{"postgres://replica-1/test", ""},
+				ReplicaHealthCheckInterval: 10 * time.Second,
+			},
+			wantErr: true,
+			errMsg:  "database replica URL 1 is empty",
+		},
+		{
+			name: "replicas need a health check interval",
+			config: DatabaseConfig{
+				URL:             "postgres://primary/test",
+				MaxOpenConns:    25,
+				MaxIdleConns:    5,
+				ConnMaxLifetime: 5 * time.Minute,
+				ConnMaxIdleTime: 5 * time.Minute,
+				Retry:           validRetryConfig(),
+				ReplicaURLs:     []string{"postgres://replica-1/test"},
+			},
+			wantErr: true,
+			errMsg:  "database replica health check interval must be positive",
+		},
+		{
+			name: "sqlite has no replicas",
+			config: DatabaseConfig{
+				Driver:                     "sqlite",
+				URL:                        "file:data/users.db",
+				MaxOpenConns:               1,
+				MaxIdleConns:               1,
+				ConnMaxLifetime:            5 * time.Minute,
+				ConnMaxIdleTime:            5 * time.Minute,
+				Retry:                      validRetryConfig(),
+				ReplicaURLs:                []string{"file:data/replica.db"},
+				ReplicaHealthCheckInterval: 10 * time.Second,
+			},
+			wantErr: true,
+			errMsg:  "database replicas require the postgres driver",
+		},
+		{
+			name: "auto-migrate needs a lock timeout",
+			config: DatabaseConfig{
+				URL:             "postgres://localhost/test",
+				MaxOpenConns:    25,
+				MaxIdleConns:    5,
+				ConnMaxLifetime: 5 * time.Minute,
+				ConnMaxIdleTime: 5 * time.Minute,
+				AutoMigrate:     true,
+				Retry:           validRetryConfig(),
+			},
+			wantErr: true,
+			errMsg:  "database migration lock timeout must be positive when auto-migrate is enabled",
+		},
+		{
+			name: "unknown schema drift mode",
+			config: DatabaseConfig{
+				URL:             "postgres://localhost/test",
+				MaxOpenConns:    25,
+				MaxIdleConns:    5,
+				ConnMaxLifetime: 5 * time.Minute,
+				ConnMaxIdleTime: 5 * time.Minute,
+				SchemaDrift:     "strict",
+				Retry:           validRetryConfig(),
+			},
+			wantErr: true,
+			errMsg:  "database schema drift mode must be fail, warn or off",
+		},
+		{
+			name: "auto-migrate with a lock timeout",
+			config: DatabaseConfig{
+				URL:                  "postgres://localhost/test",
+				MaxOpenConns:         25,
+				MaxIdleConns:         5,
+				ConnMaxLifetime:      5 * time.Minute,
+				ConnMaxIdleTime:      5 * time.Minute,
+				AutoMigrate:          true,
+				MigrationLockTimeout: 2 * time.Minute,
+				Retry:                validRetryConfig(),
+			},
+			wantErr: false,
+		},
+		{
+			name: "retries disabled",
+			config: DatabaseConfig{
+				URL:             "postgres://localhost/test",
+				MaxOpenConns:    25,
+				MaxIdleConns:    5,
+				ConnMaxLifetime: 5 * time.Minute,
+				ConnMaxIdleTime: 5 * time.Minute,
+				Retry:           RetryConfig{MaxAttempts: 1, BaseBackoff: 50 * time.Millisecond, MaxBackoff: 50 * time.Millisecond},
+			},
+			wantErr: false,
+		},
+		{
+			name: "zero retry max attempts",
+			config: DatabaseConfig{
+				URL:             "postgres://localhost/test",
+				MaxOpenConns:    25,
+				MaxIdleConns:    5,
+				ConnMaxLifetime: 5 * time.Minute,
+				ConnMaxIdleTime: 5 * time.Minute,
+				Retry:           RetryConfig{BaseBackoff: 50 * time.Millisecond, MaxBackoff: time.Second},
+			},
+			wantErr: true,
+			errMsg:  "database retry max attempts must be positive",
+		},
+		{
+			name: "zero retry base backoff",
+			config: DatabaseConfig{
+				URL:             "postgres://localhost/test",
+				MaxOpenConns:    25,
+				MaxIdleConns:    5,
+				ConnMaxLifetime: 5 * time.Minute,
+				ConnMaxIdleTime: 5 * time.Minute,
+				Retry:           RetryConfig{MaxAttempts: 3, MaxBackoff: time.Second},
+			},
+			wantErr: true,
+			errMsg:  "database retry base backoff must be positive",
+		},
+		{
+			name: "retry max backoff below base backoff",
+			config: DatabaseConfig{
+				URL:             "postgres://localhost/test",
+				MaxOpenConns:    25,
+				MaxIdleConns:    5,
+				ConnMaxLifetime: 5 * time.Minute,
+				ConnMaxIdleTime: 5 * time.Minute,
+				Retry:           RetryConfig{MaxAttempts: 3, BaseBackoff: time.Second, MaxBackoff: 50 * time.Millisecond},
+			},
+			wantErr: true,
+			errMsg:  "database retry max backoff cannot be less than base backoff",
+		},
+		{
+			name: "invalid driver",
+			config: DatabaseConfig{
+				Driver:          "mysql",
+				URL:             "postgres://localhost/test",
+				MaxOpenConns:    25,
+				MaxIdleConns:    5,
+				ConnMaxLifetime: 5 * time.Minute,
+				ConnMaxIdleTime: 5 * time.Minute,
+				Retry:           validRetryConfig(),
+			},
+			wantErr: true,
+			errMsg:  "invalid database driver: mysql",
+		},
 	}
 
 	for _, tt := range tests {
@@ -385,3 +795,238 @@ func TestLoggingConfig_Validate(t *testing.T) {
 		})
 	}
 }
+
+func TestPaginationConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  PaginationConfig
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name: "valid pagination config",
+			config: PaginationConfig{
+				CursorSecret: "0123456789abcdef0123456789abcdef",
+			},
+			wantErr: false,
+		},
+		{
+			name:    "empty cursor secret",
+			config:  PaginationConfig{},
+			wantErr: true,
+			errMsg:  "pagination cursor secret is required",
+		},
+		{
+			name: "short cursor secret",
+			config: PaginationConfig{
+				CursorSecret: "too-short",
+			},
+			wantErr: true,
+			errMsg:  "pagination cursor secret must be at least 32 characters",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestSubscriptionsConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  SubscriptionsConfig
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:    "valid subscriptions config",
+			config:  SubscriptionsConfig{BufferSize: 64},
+			wantErr: false,
+		},
+		{
+			name:    "zero buffer size",
+			config:  SubscriptionsConfig{},
+			wantErr: true,
+			errMsg:  "subscriptions buffer size must be positive",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.config.Validate()
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func validOutboxConfig() OutboxConfig {
+	return OutboxConfig{
+		Publisher:    "log",
+		PollInterval: time.Second,
+		BatchSize:    100,
+		MaxAttempts:  10,
+		BaseBackoff:  time.Second,
+		MaxBackoff:   5 * time.Minute,
+	}
+}
+
+func TestOutboxConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*OutboxConfig)
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:    "valid outbox config",
+			modify:  func(*OutboxConfig) {},
+			wantErr: false,
+		},
+		{
+			name:    "unknown publisher",
+			modify:  func(c *OutboxConfig) { c.Publisher = "kafka" },
+			wantErr: true,
+			errMsg:  "invalid outbox publisher: kafka",
+		},
+		{
+			name:    "zero poll interval",
+			modify:  func(c *OutboxConfig) { c.PollInterval = 0 },
+			wantErr: true,
+			errMsg:  "outbox poll interval must be positive",
+		},
+		{
+			name:    "zero batch size",
+			modify:  func(c *OutboxConfig) { c.BatchSize = 0 },
+			wantErr: true,
+			errMsg:  "outbox batch size must be positive",
+		},
+		{
+			name:    "zero max attempts",
+			modify:  func(c *OutboxConfig) { c.MaxAttempts = 0 },
+			wantErr: true,
+			errMsg:  "outbox max attempts must be positive",
+		},
+		{
+			name:    "max backoff below base backoff",
+			modify:  func(c *OutboxConfig) { c.MaxBackoff = 500 * time.Millisecond },
+			wantErr: true,
+			errMsg:  "outbox max backoff cannot be less than base backoff",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := validOutboxConfig()
+			tt.modify(&config)
+
+			err := config.Validate()
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
+
+func validRetryConfig() RetryConfig {
+	return RetryConfig{
+		MaxAttempts: 3,
+		BaseBackoff: 50 * time.Millisecond,
+		MaxBackoff:  time.Second,
+	}
+}
+
+func validCacheConfig() CacheConfig {
+	return CacheConfig{
+		Enabled:      true,
+		Size:         10000,
+		TTL:          30 * time.Second,
+		NegativeTTL:  5 * time.Second,
+		StaleIfError: true,
+		MaxStale:     5 * time.Minute,
+	}
+}
+
+func TestCacheConfig_Validate(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(*CacheConfig)
+		wantErr bool
+		errMsg  string
+	}{
+		{
+			name:    "valid cache config",
+			modify:  func(*CacheConfig) {},
+			wantErr: false,
+		},
+		{
+			name:    "disabled cache is not checked",
+			modify:  func(c *CacheConfig) { *c = CacheConfig{} },
+			wantErr: false,
+		},
+		{
+			name:    "zero size",
+			modify:  func(c *CacheConfig) { c.Size = 0 },
+			wantErr: true,
+			errMsg:  "cache size must be positive",
+		},
+		{
+			name:    "zero TTL",
+			modify:  func(c *CacheConfig) { c.TTL = 0 },
+			wantErr: true,
+			errMsg:  "cache TTL must be positive",
+		},
+		{
+			name:    "negative caching disabled",
+			modify:  func(c *CacheConfig) { c.NegativeTTL = 0 },
+			wantErr: false,
+		},
+		{
+			name:    "negative negative TTL",
+			modify:  func(c *CacheConfig) { c.NegativeTTL = -time.Second },
+			wantErr: true,
+			errMsg:  "cache negative TTL cannot be negative",
+		},
+		{
+			name:    "stale-if-error without max stale",
+			modify:  func(c *CacheConfig) { c.MaxStale = 0 },
+			wantErr: true,
+			errMsg:  "cache max stale must be positive when stale-if-error is enabled",
+		},
+		{
+			name:    "max stale unused without stale-if-error",
+			modify:  func(c *CacheConfig) { c.StaleIfError, c.MaxStale = false, 0 },
+			wantErr: false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := validCacheConfig()
+			tt.modify(&config)
+
+			err := config.Validate()
+			if tt.wantErr {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.errMsg)
+			} else {
+				require.NoError(t, err)
+			}
+		})
+	}
+}
@@ -5,6 +5,8 @@ import (
 	"database/sql"
 	"fmt"
 	"log/slog"
+	"slices"
+	"sync/atomic"
 	"time"
 
 	"github.com/captain-corgi/go-graphql-example/internal/infrastructure/config"
@@ -12,17 +14,34 @@ import (
 )
 
 // DB wraps the database connection with additional functionality
+// The embedded pool is the primary; reads made through Reader may go to replicas
 type DB struct {
 	*sql.DB
-	logger *slog.Logger
+	driver   string
+	retry    config.RetryConfig
+	replicas []*replica
+	next     atomic.Uint64
+	stop     chan struct{}
+	done     chan struct{}
+	logger   *slog.Logger
 }
 
 // NewConnection creates a new database connection with proper configuration
 func NewConnection(cfg config.DatabaseConfig, logger *slog.Logger) (*DB, error) {
-	logger.Info("Establishing database connection", "url", maskDatabaseURL(cfg.URL))
+	driver := cfg.Driver
+	if driver == "" {
+		driver = config.DatabaseDriverPostgres
+	}
+
+	logger.Info("Establishing database connection", "driver", driver, "url", maskDatabaseURL(cfg.URL))
+
+	// The SQLite driver is only linked into binaries built with -tags sqlite
+	if !slices.Contains(sql.Drivers(), driver) {
+		return nil, fmt.Errorf("database driver %s is not compiled in; rebuild with -tags %s", driver, driver)
+	}
 
 	// Open database connection
-	db, err := sql.Open("postgres", cfg.URL)
+	db, err := sql.Open(driver, cfg.URL)
 	if err != nil {
 		return nil, fmt.Errorf("failed to open database connection: %w", err)
 	}
@@ -49,15 +68,52 @@ func NewConnection(cfg config.DatabaseConfig, logger *slog.Logger) (*DB, error)
 		"conn_max_idle_time", cfg.ConnMaxIdleTime,
 	)
 
-	return &DB{
+	result := &DB{
 		DB:     db,
+		driver: driver,
+		retry:  cfg.Retry,
 		logger: logger,
-	}, nil
+	}
+
+	if len(cfg.ReplicaURLs) > 0 {
+		if err := openReplicas(ctx, cfg, driver, result); err != nil {
+			result.Close()
+			return nil, err
+		}
+
+		result.stop = make(chan struct{})
+		result.done = make(chan struct{})
+		go result.monitorReplicas(cfg.ReplicaHealthCheckInterval, result.stop, result.done)
+
+		logger.Info("Database replicas configured", "replicas", len(result.replicas))
+	}
+
+	return result, nil
 }
 
-// Close closes the database connection
+// Driver returns the configured database driver; connections built without one are postgres
+func (db *DB) Driver() string {
+	if db.driver == "" {
+		return config.DatabaseDriverPostgres
+	}
+	return db.driver
+}
+
+// Close stops the replica health checks and closes the replica and primary connections
 func (db *DB) Close() error {
 	db.logger.Info("Closing database connection")
+
+	if db.stop != nil {
+		close(db.stop)
+		<-db.done
+	}
+
+	for _, r := range db.replicas {
+		if err := r.Close(); err != nil {
+			db.logger.Warn("Failed to close database replica", "url", r.url, "error", err)
+		}
+	}
+
 	return db.DB.Close()
 }
 
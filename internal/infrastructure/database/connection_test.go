@@ -51,3 +51,20 @@ func TestNewConnection_InvalidURL(t *testing.T) {
 	assert.Error(t, err)
 	assert.Contains(t, err.Error(), "failed to ping database")
 }
+
+func TestNewConnection_DriverNotCompiledIn(t *testing.T) {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	cfg := config.DatabaseConfig{
+		Driver:          "mysql",
+		URL:             "mysql://localhost/test",
+		MaxOpenConns:    5,
+		MaxIdleConns:    2,
+		ConnMaxLifetime: 5 * time.Minute,
+		ConnMaxIdleTime: 1 * time.Minute,
+	}
+
+	_, err := NewConnection(cfg, logger)
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "database driver mysql is not compiled in")
+}
@@ -3,7 +3,9 @@ package database
 import (
 	"context"
 	"fmt"
+	"io/fs"
 	"log/slog"
+	"time"
 
 	"github.com/captain-corgi/go-graphql-example/internal/infrastructure/config"
 )
@@ -14,6 +16,8 @@ type Manager struct {
 	TxManager        *TxManager
 	MigrationManager *MigrationManager
 	logger           *slog.Logger
+
+	migrationLockTimeout time.Duration
 }
 
 // NewManager creates a new database manager with all components
@@ -35,15 +39,18 @@ func NewManager(cfg config.DatabaseConfig, logger *slog.Logger) (*Manager, error
 		TxManager:        txManager,
 		MigrationManager: migrationManager,
 		logger:           logger,
+
+		migrationLockTimeout: cfg.MigrationLockTimeout,
 	}, nil
 }
 
-// Initialize sets up the database by running migrations
-func (m *Manager) Initialize(ctx context.Context, migrationsPath string) error {
-	m.logger.InfoContext(ctx, "Initializing database", "migrations_path", migrationsPath)
+// Initialize sets up the database by running the given migrations
+// Of several instances starting at once, only one runs them; see MigrateOnStartup
+func (m *Manager) Initialize(ctx context.Context, migrations fs.FS) error {
+	m.logger.InfoContext(ctx, "Initializing database")
 
 	// Run migrations
-	if err := m.MigrationManager.RunMigrations(ctx, migrationsPath); err != nil {
+	if err := m.MigrationManager.MigrateOnStartup(ctx, migrations, m.migrationLockTimeout); err != nil {
 		return fmt.Errorf("failed to run migrations: %w", err)
 	}
 
@@ -51,6 +58,41 @@ func (m *Manager) Initialize(ctx context.Context, migrationsPath string) error {
 	return nil
 }
 
+// CheckSchemaDrift compares the live tables with the expected ones and, depending on mode (see
+// config.DatabaseConfig.SchemaDrift), returns a *SchemaDrift error or only logs the differences
+// Harmless additions are always only logged. Only Postgres databases are checked
+func (m *Manager) CheckSchemaDrift(ctx context.Context, mode string, expected []TableSchema) error {
+	if mode == config.SchemaDriftOff {
+		return nil
+	}
+	if m.DB.Driver() != config.DatabaseDriverPostgres {
+		m.logger.InfoContext(ctx, "Skipping schema drift check", "driver", m.DB.Driver())
+		return nil
+	}
+
+	drift, err := m.DB.DetectSchemaDrift(ctx, expected)
+	if err != nil {
+		return fmt.Errorf("failed to check schema drift: %w", err)
+	}
+
+	for _, addition := range drift.Additions {
+		m.logger.WarnContext(ctx, "Database schema has an addition the code does not know about", "difference", addition)
+	}
+
+	if !drift.HasDifferences() {
+		m.logger.InfoContext(ctx, "Database schema matches what the code expects")
+		return nil
+	}
+	if mode == config.SchemaDriftFail {
+		return drift
+	}
+
+	for _, difference := range drift.Differences {
+		m.logger.WarnContext(ctx, "Database schema differs from what the code expects", "difference", difference)
+	}
+	return nil
+}
+
 // Close closes all database connections
 func (m *Manager) Close() error {
 	m.logger.Info("Closing database manager")
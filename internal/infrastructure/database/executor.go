@@ -0,0 +1,75 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"sync"
+)
+
+// Executor runs statements; both *sql.DB and *sql.Tx satisfy it
+type Executor interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+// txKey is the context key of the ambient transaction
+type txKey struct{}
+
+// WithTx returns a copy of ctx carrying tx as the ambient transaction
+func WithTx(ctx context.Context, tx *sql.Tx) context.Context {
+	return context.WithValue(ctx, txKey{}, tx)
+}
+
+// TxFromContext returns the ambient transaction carried by ctx, if any
+func TxFromContext(ctx context.Context) (*sql.Tx, bool) {
+	tx, ok := ctx.Value(txKey{}).(*sql.Tx)
+	return tx, ok && tx != nil
+}
+
+// afterCommitKey is the context key of the callbacks waiting for the ambient transaction to commit
+type afterCommitKey struct{}
+
+// afterCommit collects the callbacks registered during one transaction attempt
+type afterCommit struct {
+	mu  sync.Mutex
+	fns []func()
+}
+
+// WithAfterCommit returns a copy of ctx that collects AfterCommit callbacks, and a function that runs them
+// TxManager.Do uses it for the transactions it starts; call run only once the transaction has committed
+func WithAfterCommit(ctx context.Context) (_ context.Context, run func()) {
+	hooks := &afterCommit{}
+	return context.WithValue(ctx, afterCommitKey{}, hooks), func() {
+		hooks.mu.Lock()
+		fns := hooks.fns
+		hooks.fns = nil
+		hooks.mu.Unlock()
+
+		for _, fn := range fns {
+			fn()
+		}
+	}
+}
+
+// AfterCommit runs fn once the transaction ctx belongs to has committed, or right away when ctx
+// collects no callbacks (see WithAfterCommit). Callbacks of a transaction that rolls back never run
+func AfterCommit(ctx context.Context, fn func()) {
+	hooks, ok := ctx.Value(afterCommitKey{}).(*afterCommit)
+	if !ok {
+		fn()
+		return
+	}
+
+	hooks.mu.Lock()
+	defer hooks.mu.Unlock()
+	hooks.fns = append(hooks.fns, fn)
+}
+
+// Executor returns the ambient transaction carried by ctx, or the connection pool when there is none
+func (db *DB) Executor(ctx context.Context) Executor {
+	if tx, ok := TxFromContext(ctx); ok {
+		return tx
+	}
+	return db.DB
+}
@@ -0,0 +1,76 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTxFromContext(t *testing.T) {
+	tx := &sql.Tx{}
+
+	tests := []struct {
+		name   string
+		ctx    context.Context
+		wantTx *sql.Tx
+		wantOK bool
+	}{
+		{
+			name:   "no transaction",
+			ctx:    context.Background(),
+			wantOK: false,
+		},
+		{
+			name:   "nil transaction",
+			ctx:    WithTx(context.Background(), nil),
+			wantOK: false,
+		},
+		{
+			name:   "ambient transaction",
+			ctx:    WithTx(context.Background(), tx),
+			wantTx: tx,
+			wantOK: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := TxFromContext(tt.ctx)
+			assert.Equal(t, tt.wantOK, ok)
+			if tt.wantOK {
+				assert.Same(t, tt.wantTx, got)
+			}
+		})
+	}
+}
+
+func TestAfterCommit(t *testing.T) {
+	ran := 0
+	AfterCommit(context.Background(), func() { ran++ })
+	assert.Equal(t, 1, ran, "without a collecting context callbacks run right away")
+
+	ctx, run := WithAfterCommit(context.Background())
+	AfterCommit(ctx, func() { ran++ })
+	AfterCommit(ctx, func() { ran++ })
+	assert.Equal(t, 1, ran)
+
+	run()
+	assert.Equal(t, 3, ran)
+	run()
+	assert.Equal(t, 3, ran, "callbacks run once")
+}
+
+func TestDB_Executor(t *testing.T) {
+	// Opening does not connect, so no database is needed
+	pool, err := sql.Open("postgres", "postgres://localhost/unused")
+	assert.NoError(t, err)
+	defer pool.Close()
+
+	db := &DB{DB: pool}
+	tx := &sql.Tx{}
+
+	assert.Same(t, pool, db.Executor(context.Background()))
+	assert.Same(t, tx, db.Executor(WithTx(context.Background(), tx)))
+}
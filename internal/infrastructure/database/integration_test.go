@@ -2,9 +2,12 @@ package database
 
 import (
 	"context"
+	"database/sql"
+	"io/fs"
 	"log/slog"
 	"os"
 	"testing"
+	"testing/fstest"
 	"time"
 
 	"github.com/captain-corgi/go-graphql-example/internal/infrastructure/config"
@@ -185,6 +188,64 @@ func (suite *DatabaseIntegrationTestSuite) TestDatabaseTransactionHandling() {
 	assert.Equal(suite.T(), 1, count) // Still only the first record
 }
 
+// TestTxManagerDo tests that statements run through the context join the unit of work
+func (suite *DatabaseIntegrationTestSuite) TestTxManagerDo() {
+	_, err := suite.db.ExecContext(suite.ctx, `CREATE TABLE IF NOT EXISTS test_unit_of_work (value TEXT)`)
+	require.NoError(suite.T(), err)
+	defer suite.db.ExecContext(suite.ctx, `DROP TABLE IF EXISTS test_unit_of_work`)
+
+	tm := NewTxManager(suite.db, suite.logger)
+	insert := func(ctx context.Context, value string) error {
+		_, err := suite.db.Executor(ctx).ExecContext(ctx, "INSERT INTO test_unit_of_work (value) VALUES ($1)", value)
+		return err
+	}
+	count := func() int {
+		var n int
+		require.NoError(suite.T(), suite.db.QueryRowContext(suite.ctx, "SELECT COUNT(*) FROM test_unit_of_work").Scan(&n))
+		return n
+	}
+
+	// A failing unit of work discards every write, including those of a nested Do
+	err = tm.Do(suite.ctx, func(ctx context.Context) error {
+		require.NoError(suite.T(), insert(ctx, "outer"))
+		require.NoError(suite.T(), tm.Do(ctx, func(ctx context.Context) error {
+			return insert(ctx, "inner")
+		}))
+		return assert.AnError
+	})
+	assert.ErrorIs(suite.T(), err, assert.AnError)
+	assert.Equal(suite.T(), 0, count())
+
+	// A successful one keeps them
+	err = tm.Do(suite.ctx, func(ctx context.Context) error {
+		require.NoError(suite.T(), insert(ctx, "outer"))
+		return tm.Execute(ctx, "nested", func(tx *sql.Tx) error {
+			_, err := tx.ExecContext(ctx, "INSERT INTO test_unit_of_work (value) VALUES ($1)", "inner")
+			return err
+		})
+	})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 2, count())
+}
+
+// TestTransactionCommitPinsPrimary tests that committed writes pin the request to the primary
+func (suite *DatabaseIntegrationTestSuite) TestTransactionCommitPinsPrimary() {
+	noop := func(tx *sql.Tx) error { return nil }
+
+	// A read-only transaction leaves reads on the replicas
+	ctx := WithReadYourWrites(suite.ctx)
+	require.NoError(suite.T(), suite.db.WithTransactionOptions(ctx, &sql.TxOptions{ReadOnly: true}, noop))
+	assert.False(suite.T(), PinnedToPrimary(ctx))
+
+	// A failed one writes nothing
+	err := suite.db.WithTransaction(ctx, func(tx *sql.Tx) error { return assert.AnError })
+	assert.ErrorIs(suite.T(), err, assert.AnError)
+	assert.False(suite.T(), PinnedToPrimary(ctx))
+
+	require.NoError(suite.T(), suite.db.WithTransaction(ctx, noop))
+	assert.True(suite.T(), PinnedToPrimary(ctx))
+}
+
 // MigrationIntegrationTestSuite defines the integration test suite for migration operations
 type MigrationIntegrationTestSuite struct {
 	suite.Suite
@@ -193,6 +254,7 @@ type MigrationIntegrationTestSuite struct {
 	ctx               context.Context
 	logger            *slog.Logger
 	tempMigrationsDir string
+	migrations        fs.FS
 }
 
 // SetupSuite sets up the migration test suite
@@ -225,6 +287,7 @@ func (suite *MigrationIntegrationTestSuite) SetupSuite() {
 	tempDir, err := os.MkdirTemp("", "migration_test")
 	require.NoError(suite.T(), err)
 	suite.tempMigrationsDir = tempDir
+	suite.migrations = os.DirFS(tempDir)
 
 	// Create test migration files
 	suite.createTestMigrations()
@@ -238,6 +301,7 @@ func (suite *MigrationIntegrationTestSuite) TearDownSuite() {
 	if suite.db != nil {
 		// Clean up migration tables
 		_, _ = suite.db.ExecContext(suite.ctx, "DROP TABLE IF EXISTS schema_migrations")
+		_, _ = suite.db.ExecContext(suite.ctx, "DROP TABLE IF EXISTS schema_migration_failures")
 		_, _ = suite.db.ExecContext(suite.ctx, "DROP TABLE IF EXISTS test_migration_table")
 		suite.db.Close()
 	}
@@ -247,6 +311,7 @@ func (suite *MigrationIntegrationTestSuite) TearDownSuite() {
 func (suite *MigrationIntegrationTestSuite) SetupTest() {
 	// Clean up any existing migration state
 	_, _ = suite.db.ExecContext(suite.ctx, "DROP TABLE IF EXISTS schema_migrations")
+	_, _ = suite.db.ExecContext(suite.ctx, "DROP TABLE IF EXISTS schema_migration_failures")
 	_, _ = suite.db.ExecContext(suite.ctx, "DROP TABLE IF EXISTS test_migration_table")
 }
 
@@ -282,7 +347,7 @@ CREATE TABLE test_migration_table (
 // TestRunMigrations tests running migrations
 func (suite *MigrationIntegrationTestSuite) TestRunMigrations() {
 	// Run migrations
-	err := suite.migrationManager.RunMigrations(suite.ctx, suite.tempMigrationsDir)
+	err := suite.migrationManager.RunMigrations(suite.ctx, suite.migrations)
 	assert.NoError(suite.T(), err)
 
 	// Verify table was created
@@ -308,7 +373,7 @@ func (suite *MigrationIntegrationTestSuite) TestRunMigrations() {
 	assert.Equal(suite.T(), 2, columnCount)
 
 	// Check migration version
-	version, dirty, err := suite.migrationManager.GetMigrationVersion(suite.ctx, suite.tempMigrationsDir)
+	version, dirty, err := suite.migrationManager.GetMigrationVersion(suite.ctx, suite.migrations)
 	assert.NoError(suite.T(), err)
 	assert.False(suite.T(), dirty)
 	assert.Equal(suite.T(), uint(2), version)
@@ -317,32 +382,32 @@ func (suite *MigrationIntegrationTestSuite) TestRunMigrations() {
 // TestRunMigrationsNoChange tests running migrations when no new migrations exist
 func (suite *MigrationIntegrationTestSuite) TestRunMigrationsNoChange() {
 	// Run migrations first time
-	err := suite.migrationManager.RunMigrations(suite.ctx, suite.tempMigrationsDir)
+	err := suite.migrationManager.RunMigrations(suite.ctx, suite.migrations)
 	require.NoError(suite.T(), err)
 
 	// Run migrations again - should be no change
-	err = suite.migrationManager.RunMigrations(suite.ctx, suite.tempMigrationsDir)
+	err = suite.migrationManager.RunMigrations(suite.ctx, suite.migrations)
 	assert.NoError(suite.T(), err)
 }
 
 // TestRollbackMigration tests rolling back migrations
 func (suite *MigrationIntegrationTestSuite) TestRollbackMigration() {
 	// Run migrations first
-	err := suite.migrationManager.RunMigrations(suite.ctx, suite.tempMigrationsDir)
+	err := suite.migrationManager.RunMigrations(suite.ctx, suite.migrations)
 	require.NoError(suite.T(), err)
 
 	// Verify we're at version 2
-	version, dirty, err := suite.migrationManager.GetMigrationVersion(suite.ctx, suite.tempMigrationsDir)
+	version, dirty, err := suite.migrationManager.GetMigrationVersion(suite.ctx, suite.migrations)
 	require.NoError(suite.T(), err)
 	require.False(suite.T(), dirty)
 	require.Equal(suite.T(), uint(2), version)
 
 	// Rollback one migration
-	err = suite.migrationManager.RollbackMigration(suite.ctx, suite.tempMigrationsDir)
+	err = suite.migrationManager.RollbackMigration(suite.ctx, suite.migrations)
 	assert.NoError(suite.T(), err)
 
 	// Verify we're now at version 1
-	version, dirty, err = suite.migrationManager.GetMigrationVersion(suite.ctx, suite.tempMigrationsDir)
+	version, dirty, err = suite.migrationManager.GetMigrationVersion(suite.ctx, suite.migrations)
 	assert.NoError(suite.T(), err)
 	assert.False(suite.T(), dirty)
 	assert.Equal(suite.T(), uint(1), version)
@@ -363,25 +428,151 @@ func (suite *MigrationIntegrationTestSuite) TestRollbackMigration() {
 // TestGetMigrationVersion tests getting migration version
 func (suite *MigrationIntegrationTestSuite) TestGetMigrationVersion() {
 	// Initially no migrations
-	version, dirty, err := suite.migrationManager.GetMigrationVersion(suite.ctx, suite.tempMigrationsDir)
+	version, dirty, err := suite.migrationManager.GetMigrationVersion(suite.ctx, suite.migrations)
 	assert.NoError(suite.T(), err)
 	assert.False(suite.T(), dirty)
 	assert.Equal(suite.T(), uint(0), version)
 
 	// Run migrations
-	err = suite.migrationManager.RunMigrations(suite.ctx, suite.tempMigrationsDir)
+	err = suite.migrationManager.RunMigrations(suite.ctx, suite.migrations)
 	require.NoError(suite.T(), err)
 
 	// Check version after migrations
-	version, dirty, err = suite.migrationManager.GetMigrationVersion(suite.ctx, suite.tempMigrationsDir)
+	version, dirty, err = suite.migrationManager.GetMigrationVersion(suite.ctx, suite.migrations)
 	assert.NoError(suite.T(), err)
 	assert.False(suite.T(), dirty)
 	assert.Equal(suite.T(), uint(2), version)
 }
 
+// TestMigrationStatus tests listing applied and pending migrations
+func (suite *MigrationIntegrationTestSuite) TestMigrationStatus() {
+	err := suite.migrationManager.MigrateTo(suite.ctx, suite.migrations, 1)
+	require.NoError(suite.T(), err)
+
+	status, err := suite.migrationManager.Status(suite.ctx, suite.migrations)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), uint(1), status.Version)
+	assert.False(suite.T(), status.Dirty)
+	assert.Equal(suite.T(), []MigrationInfo{
+		{Version: 1, Name: "create_test_table", Applied: true},
+		{Version: 2, Name: "add_email_column", Applied: false},
+	}, status.Migrations)
+}
+
+// TestMigrateDownAndForce tests rolling back several migrations and forcing a version
+func (suite *MigrationIntegrationTestSuite) TestMigrateDownAndForce() {
+	err := suite.migrationManager.RunMigrations(suite.ctx, suite.migrations)
+	require.NoError(suite.T(), err)
+
+	err = suite.migrationManager.MigrateDown(suite.ctx, suite.migrations, 2)
+	require.NoError(suite.T(), err)
+
+	version, _, err := suite.migrationManager.GetMigrationVersion(suite.ctx, suite.migrations)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), uint(0), version)
+
+	// Forcing records the version without running migration 1
+	err = suite.migrationManager.ForceVersion(suite.ctx, suite.migrations, 1)
+	require.NoError(suite.T(), err)
+
+	version, dirty, err := suite.migrationManager.GetMigrationVersion(suite.ctx, suite.migrations)
+	require.NoError(suite.T(), err)
+	assert.False(suite.T(), dirty)
+	assert.Equal(suite.T(), uint(1), version)
+}
+
+// TestFailedMigrationIsRecordedAndRecovered tests reporting and recovering a migration that left the database dirty
+func (suite *MigrationIntegrationTestSuite) TestFailedMigrationIsRecordedAndRecovered() {
+	broken := fstest.MapFS{
+		"001_create_test_table.up.sql":   {Data: []byte(`CREATE TABLE test_migration_table (id SERIAL PRIMARY KEY);`)},
+		"001_create_test_table.down.sql": {Data: []byte(`DROP TABLE test_migration_table;`)},
+		"002_add_email_column.up.sql":    {Data: []byte(`ALTER TABLE missing_table ADD COLUMN email VARCHAR(255);`)},
+		"002_add_email_column.down.sql":  {Data: []byte(`SELECT 1;`)},
+	}
+
+	err := suite.migrationManager.RunMigrations(suite.ctx, broken)
+	require.Error(suite.T(), err)
+
+	// Every later run reports which migration failed and why
+	_, _, err = suite.migrationManager.GetMigrationVersion(suite.ctx, broken)
+	var dirtyErr *DirtySchemaError
+	require.ErrorAs(suite.T(), err, &dirtyErr)
+	assert.Equal(suite.T(), uint(2), dirtyErr.Version)
+	require.NotNil(suite.T(), dirtyErr.Failure)
+	assert.Equal(suite.T(), "add_email_column", dirtyErr.Failure.Name)
+	assert.Contains(suite.T(), dirtyErr.Failure.Error, "missing_table")
+
+	err = suite.migrationManager.CheckSchemaVersion(suite.ctx, broken)
+	assert.ErrorAs(suite.T(), err, &dirtyErr)
+
+	version, err := suite.migrationManager.Recover(suite.ctx, broken)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 1, version)
+
+	failure, err := suite.migrationManager.LastFailure(suite.ctx, 2)
+	require.NoError(suite.T(), err)
+	assert.Nil(suite.T(), failure)
+
+	// With the migration fixed, the next run applies it
+	err = suite.migrationManager.RunMigrations(suite.ctx, suite.migrations)
+	require.NoError(suite.T(), err)
+
+	_, err = suite.migrationManager.Recover(suite.ctx, suite.migrations)
+	assert.Error(suite.T(), err)
+}
+
+// TestCheckSchemaVersion tests the read-only schema version check of instances that do not migrate
+func (suite *MigrationIntegrationTestSuite) TestCheckSchemaVersion() {
+	err := suite.migrationManager.CheckSchemaVersion(suite.ctx, suite.migrations)
+	assert.ErrorContains(suite.T(), err, "expects version 2")
+
+	err = suite.migrationManager.MigrateTo(suite.ctx, suite.migrations, 1)
+	require.NoError(suite.T(), err)
+
+	err = suite.migrationManager.CheckSchemaVersion(suite.ctx, suite.migrations)
+	assert.ErrorContains(suite.T(), err, "at version 1")
+
+	err = suite.migrationManager.RunMigrations(suite.ctx, suite.migrations)
+	require.NoError(suite.T(), err)
+
+	assert.NoError(suite.T(), suite.migrationManager.CheckSchemaVersion(suite.ctx, suite.migrations))
+
+	// A schema newer than the build is allowed during rolling deploys
+	older := fstest.MapFS{
+		"001_create_test_table.up.sql":   {Data: []byte(`SELECT 1;`)},
+		"001_create_test_table.down.sql": {Data: []byte(`SELECT 1;`)},
+	}
+	assert.NoError(suite.T(), suite.migrationManager.CheckSchemaVersion(suite.ctx, older))
+}
+
+// TestMigrateOnStartup tests that only the instance holding the migration lock migrates
+func (suite *MigrationIntegrationTestSuite) TestMigrateOnStartup() {
+	// Another instance holds the lock and never finishes
+	conn, err := suite.db.Conn(suite.ctx)
+	require.NoError(suite.T(), err)
+	defer conn.Close()
+
+	acquired, err := tryMigrationLock(suite.ctx, conn)
+	require.NoError(suite.T(), err)
+	require.True(suite.T(), acquired)
+
+	err = suite.migrationManager.MigrateOnStartup(suite.ctx, suite.migrations, 1500*time.Millisecond)
+	assert.ErrorContains(suite.T(), err, "timed out")
+
+	// Once it is done, waiting instances only check the schema version
+	suite.migrationManager.releaseMigrationLock(conn)
+	err = suite.migrationManager.MigrateOnStartup(suite.ctx, suite.migrations, time.Minute)
+	require.NoError(suite.T(), err)
+
+	version, dirty, err := suite.migrationManager.GetMigrationVersion(suite.ctx, suite.migrations)
+	require.NoError(suite.T(), err)
+	assert.False(suite.T(), dirty)
+	assert.Equal(suite.T(), uint(2), version)
+}
+
 // TestMigrationWithInvalidPath tests migration with invalid path
 func (suite *MigrationIntegrationTestSuite) TestMigrationWithInvalidPath() {
-	err := suite.migrationManager.RunMigrations(suite.ctx, "/nonexistent/path")
+	err := suite.migrationManager.RunMigrations(suite.ctx, os.DirFS("/nonexistent/path"))
 	assert.Error(suite.T(), err)
 	assert.Contains(suite.T(), err.Error(), "failed to create migrate instance")
 }
@@ -2,15 +2,77 @@ package database
 
 import (
 	"context"
+	"database/sql"
 	"fmt"
+	"io/fs"
 	"log/slog"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
 
+	"github.com/captain-corgi/go-graphql-example/internal/infrastructure/config"
 	"github.com/golang-migrate/migrate/v4"
+	migratedb "github.com/golang-migrate/migrate/v4/database"
 	"github.com/golang-migrate/migrate/v4/database/postgres"
-	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/golang-migrate/migrate/v4/source"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
 )
 
+// migrationDriver creates a golang-migrate driver on the shared connection pool
+// migrate.Close closes its driver, so a driver must release only what it opened itself
+type migrationDriver func(ctx context.Context, db *sql.DB) (migratedb.Driver, error)
+
+// migrationDrivers holds the migration driver of every database driver compiled in
+var migrationDrivers = map[string]migrationDriver{
+	config.DatabaseDriverPostgres: newPostgresMigrationDriver,
+}
+
+// newPostgresMigrationDriver runs migrations on a dedicated connection taken from the pool
+func newPostgresMigrationDriver(ctx context.Context, db *sql.DB) (migratedb.Driver, error) {
+	conn, err := db.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	driver, err := postgres.WithConnection(ctx, conn, &postgres.Config{})
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return driver, nil
+}
+
+// MigrationInfo describes one migration of a migration set
+type MigrationInfo struct {
+	Version uint
+	Name    string
+	Applied bool
+}
+
+// MigrationStatus is the schema version of a database and the migrations applied to it or pending
+type MigrationStatus struct {
+	// Version is the last migration applied, 0 when none has been
+	Version uint
+	// Dirty reports that migration Version failed part way and the schema needs fixing by hand
+	Dirty      bool
+	Migrations []MigrationInfo
+}
+
+// Pending returns the migrations that have not been applied yet
+func (s MigrationStatus) Pending() []MigrationInfo {
+	var pending []MigrationInfo
+	for _, m := range s.Migrations {
+		if !m.Applied {
+			pending = append(pending, m)
+		}
+	}
+	return pending
+}
+
 // MigrationManager handles database migrations
+// Every method takes the migration set to work with, such as migrations.Postgres or os.DirFS(dir)
 type MigrationManager struct {
 	db     *DB
 	logger *slog.Logger
@@ -25,35 +87,19 @@ func NewMigrationManager(db *DB, logger *slog.Logger) *MigrationManager {
 }
 
 // RunMigrations executes all pending database migrations
-func (mm *MigrationManager) RunMigrations(ctx context.Context, migrationsPath string) error {
-	mm.logger.InfoContext(ctx, "Starting database migrations", "path", migrationsPath)
+func (mm *MigrationManager) RunMigrations(ctx context.Context, migrations fs.FS) error {
+	mm.logger.InfoContext(ctx, "Starting database migrations")
 
-	// Create postgres driver instance
-	driver, err := postgres.WithInstance(mm.db.DB, &postgres.Config{})
+	m, err := mm.newMigrate(ctx, migrations)
 	if err != nil {
-		return fmt.Errorf("failed to create postgres driver: %w", err)
-	}
-
-	// Create migrate instance
-	m, err := migrate.NewWithDatabaseInstance(
-		fmt.Sprintf("file://%s", migrationsPath),
-		"postgres",
-		driver,
-	)
-	if err != nil {
-		return fmt.Errorf("failed to create migrate instance: %w", err)
+		return err
 	}
 	defer m.Close()
 
 	// Get current version
-	currentVersion, dirty, err := m.Version()
-	if err != nil && err != migrate.ErrNilVersion {
-		return fmt.Errorf("failed to get current migration version: %w", err)
-	}
-
-	if dirty {
-		mm.logger.WarnContext(ctx, "Database is in dirty state", "version", currentVersion)
-		return fmt.Errorf("database is in dirty state at version %d", currentVersion)
+	currentVersion, err := mm.cleanVersion(ctx, m)
+	if err != nil {
+		return err
 	}
 
 	mm.logger.InfoContext(ctx, "Current migration version", "version", currentVersion)
@@ -64,6 +110,9 @@ func (mm *MigrationManager) RunMigrations(ctx context.Context, migrationsPath st
 			mm.logger.InfoContext(ctx, "No new migrations to apply")
 			return nil
 		}
+		if failed, dirty, versionErr := migrationVersion(m); versionErr == nil && dirty {
+			mm.recordFailure(ctx, migrations, failed, err)
+		}
 		return fmt.Errorf("failed to run migrations: %w", err)
 	}
 
@@ -78,43 +127,35 @@ func (mm *MigrationManager) RunMigrations(ctx context.Context, migrationsPath st
 }
 
 // RollbackMigration rolls back the last migration
-func (mm *MigrationManager) RollbackMigration(ctx context.Context, migrationsPath string) error {
-	mm.logger.InfoContext(ctx, "Rolling back last migration", "path", migrationsPath)
+func (mm *MigrationManager) RollbackMigration(ctx context.Context, migrations fs.FS) error {
+	return mm.MigrateDown(ctx, migrations, 1)
+}
 
-	// Create postgres driver instance
-	driver, err := postgres.WithInstance(mm.db.DB, &postgres.Config{})
-	if err != nil {
-		return fmt.Errorf("failed to create postgres driver: %w", err)
+// MigrateDown rolls back the last steps migrations
+func (mm *MigrationManager) MigrateDown(ctx context.Context, migrations fs.FS, steps int) error {
+	if steps <= 0 {
+		return fmt.Errorf("migration steps must be positive")
 	}
 
-	// Create migrate instance
-	m, err := migrate.NewWithDatabaseInstance(
-		fmt.Sprintf("file://%s", migrationsPath),
-		"postgres",
-		driver,
-	)
+	mm.logger.InfoContext(ctx, "Rolling back migrations", "steps", steps)
+
+	m, err := mm.newMigrate(ctx, migrations)
 	if err != nil {
-		return fmt.Errorf("failed to create migrate instance: %w", err)
+		return err
 	}
 	defer m.Close()
 
-	// Get current version
-	currentVersion, dirty, err := m.Version()
+	currentVersion, err := mm.cleanVersion(ctx, m)
 	if err != nil {
-		if err == migrate.ErrNilVersion {
-			mm.logger.InfoContext(ctx, "No migrations to rollback")
-			return nil
-		}
-		return fmt.Errorf("failed to get current migration version: %w", err)
+		return err
 	}
 
-	if dirty {
-		mm.logger.WarnContext(ctx, "Database is in dirty state", "version", currentVersion)
-		return fmt.Errorf("database is in dirty state at version %d", currentVersion)
+	if currentVersion == 0 {
+		mm.logger.InfoContext(ctx, "No migrations to rollback")
+		return nil
 	}
 
-	// Rollback one step
-	if err := m.Steps(-1); err != nil {
+	if err := m.Steps(-steps); err != nil {
 		if err == migrate.ErrNoChange {
 			mm.logger.InfoContext(ctx, "No migrations to rollback")
 			return nil
@@ -132,25 +173,121 @@ func (mm *MigrationManager) RollbackMigration(ctx context.Context, migrationsPat
 	return nil
 }
 
-// GetMigrationVersion returns the current migration version
-func (mm *MigrationManager) GetMigrationVersion(ctx context.Context, migrationsPath string) (uint, bool, error) {
-	// Create postgres driver instance
-	driver, err := postgres.WithInstance(mm.db.DB, &postgres.Config{})
+// MigrateTo applies or rolls back migrations until the schema is at version
+func (mm *MigrationManager) MigrateTo(ctx context.Context, migrations fs.FS, version uint) error {
+	mm.logger.InfoContext(ctx, "Migrating to version", "version", version)
+
+	m, err := mm.newMigrate(ctx, migrations)
 	if err != nil {
-		return 0, false, fmt.Errorf("failed to create postgres driver: %w", err)
+		return err
+	}
+	defer m.Close()
+
+	if _, err := mm.cleanVersion(ctx, m); err != nil {
+		return err
 	}
 
-	// Create migrate instance
-	m, err := migrate.NewWithDatabaseInstance(
-		fmt.Sprintf("file://%s", migrationsPath),
-		"postgres",
-		driver,
-	)
+	if err := m.Migrate(version); err != nil {
+		if err == migrate.ErrNoChange {
+			mm.logger.InfoContext(ctx, "Already at requested version", "version", version)
+			return nil
+		}
+		return fmt.Errorf("failed to migrate to version %d: %w", version, err)
+	}
+
+	mm.logger.InfoContext(ctx, "Migration completed successfully", "new_version", version)
+	return nil
+}
+
+// ForceVersion records version as applied and clears the dirty flag without running any migration
+// It is how a dirty database is recovered once its schema has been fixed by hand; -1 records no version
+func (mm *MigrationManager) ForceVersion(ctx context.Context, migrations fs.FS, version int) error {
+	mm.logger.WarnContext(ctx, "Forcing migration version", "version", version)
+
+	m, err := mm.newMigrate(ctx, migrations)
 	if err != nil {
-		return 0, false, fmt.Errorf("failed to create migrate instance: %w", err)
+		return err
 	}
 	defer m.Close()
 
+	if err := m.Force(version); err != nil {
+		return fmt.Errorf("failed to force migration version %d: %w", version, err)
+	}
+
+	return mm.clearFailures(ctx)
+}
+
+// GetMigrationVersion returns the current migration version
+func (mm *MigrationManager) GetMigrationVersion(ctx context.Context, migrations fs.FS) (uint, bool, error) {
+	m, err := mm.newMigrate(ctx, migrations)
+	if err != nil {
+		return 0, false, err
+	}
+	defer m.Close()
+
+	return migrationVersion(m)
+}
+
+// Status returns the current migration version and which migrations are applied or pending
+// Migrations apply in version order, so every migration up to the current version counts as applied
+func (mm *MigrationManager) Status(ctx context.Context, migrations fs.FS) (*MigrationStatus, error) {
+	list, err := ListMigrations(migrations)
+	if err != nil {
+		return nil, err
+	}
+
+	version, dirty, err := mm.GetMigrationVersion(ctx, migrations)
+	if err != nil {
+		return nil, err
+	}
+
+	for i := range list {
+		list[i].Applied = list[i].Version <= version
+		if dirty && list[i].Version == version {
+			list[i].Applied = false
+		}
+	}
+
+	return &MigrationStatus{Version: version, Dirty: dirty, Migrations: list}, nil
+}
+
+// ListMigrations returns the migrations in a migration set in version order
+func ListMigrations(migrations fs.FS) ([]MigrationInfo, error) {
+	entries, err := fs.ReadDir(migrations, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migrations: %w", err)
+	}
+
+	var list []MigrationInfo
+	for _, entry := range entries {
+		parsed, err := source.Parse(entry.Name())
+		if err != nil || parsed.Direction != source.Up {
+			continue
+		}
+		list = append(list, MigrationInfo{Version: parsed.Version, Name: parsed.Identifier})
+	}
+
+	sort.Slice(list, func(i, j int) bool { return list[i].Version < list[j].Version })
+	return list, nil
+}
+
+// cleanVersion returns the current version, refusing to go on from a dirty database
+func (mm *MigrationManager) cleanVersion(ctx context.Context, m *migrate.Migrate) (uint, error) {
+	version, dirty, err := migrationVersion(m)
+	if err != nil {
+		return 0, err
+	}
+
+	if dirty {
+		mm.logger.WarnContext(ctx, "Database is in dirty state", "version", version)
+		return 0, mm.dirtyError(ctx, version)
+	}
+
+	return version, nil
+}
+
+// migrationVersion returns the current version, 0 when no migration has been applied
+func migrationVersion(m *migrate.Migrate) (uint, bool, error) {
 	version, dirty, err := m.Version()
 	if err != nil {
 		if err == migrate.ErrNilVersion {
@@ -161,3 +298,68 @@ func (mm *MigrationManager) GetMigrationVersion(ctx context.Context, migrationsP
 
 	return version, dirty, nil
 }
+
+// newMigrate creates a migrate instance for a migration set on the managed database
+func (mm *MigrationManager) newMigrate(ctx context.Context, migrations fs.FS) (*migrate.Migrate, error) {
+	driverName := mm.db.Driver()
+	newDriver, ok := migrationDrivers[driverName]
+	if !ok {
+		return nil, fmt.Errorf("no migration driver for database driver %s", driverName)
+	}
+
+	src, err := iofs.New(migrations, ".")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create migrate instance: %w", err)
+	}
+
+	driver, err := newDriver(ctx, mm.db.DB)
+	if err != nil {
+		src.Close()
+		return nil, fmt.Errorf("failed to create %s driver: %w", driverName, err)
+	}
+
+	m, err := migrate.NewWithInstance("iofs", src, driverName, driver)
+	if err != nil {
+		src.Close()
+		driver.Close()
+		return nil, fmt.Errorf("failed to create migrate instance: %w", err)
+	}
+
+	return m, nil
+}
+
+// migrationName matches the names CreateMigration accepts
+var migrationName = regexp.MustCompile(`^[a-z0-9]+(_[a-z0-9]+)*$`)
+
+// CreateMigration writes an empty up and down migration named name to dir, numbered after the
+// highest version there, and returns their paths
+func CreateMigration(dir, name string) (up, down string, err error) {
+	if !migrationName.MatchString(name) {
+		return "", "", fmt.Errorf("invalid migration name %q: use lowercase letters, digits and underscores", name)
+	}
+
+	existing, err := ListMigrations(os.DirFS(dir))
+	if err != nil {
+		return "", "", err
+	}
+
+	var version uint = 1
+	if len(existing) > 0 {
+		version = existing[len(existing)-1].Version + 1
+	}
+
+	base := filepath.Join(dir, fmt.Sprintf("%03d_%s", version, name))
+	up, down = base+".up.sql", base+".down.sql"
+
+	for _, path := range []string{up, down} {
+		file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0644)
+		if err != nil {
+			return "", "", fmt.Errorf("failed to create migration: %w", err)
+		}
+		if err := file.Close(); err != nil {
+			return "", "", fmt.Errorf("failed to create migration: %w", err)
+		}
+	}
+
+	return up, down, nil
+}
@@ -0,0 +1,141 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io/fs"
+	"time"
+
+	"github.com/captain-corgi/go-graphql-example/internal/infrastructure/config"
+)
+
+// migrationLockKey is the advisory lock instances contend for to decide which one migrates
+// It must differ from the lock golang-migrate takes while applying migrations, which the winner acquires next
+const migrationLockKey int64 = 0x6d6967726174696f // "migratio"
+
+// migrationLockPoll is how often instances that lost the election check whether the migration finished
+const migrationLockPoll = time.Second
+
+// MigrateOnStartup applies pending migrations from exactly one of the instances starting at once
+// On Postgres, instances race for an advisory lock: the winner migrates while the others wait up to
+// lockTimeout for it to finish, then check that the schema is at the version they were built for
+func (mm *MigrationManager) MigrateOnStartup(ctx context.Context, migrations fs.FS, lockTimeout time.Duration) error {
+	// SQLite databases are local to one process, so there is no one to coordinate with
+	if mm.db.Driver() != config.DatabaseDriverPostgres {
+		return mm.RunMigrations(ctx, migrations)
+	}
+
+	// Session-level advisory locks belong to a connection, so the lock is held on a dedicated one
+	conn, err := mm.db.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to get connection for migration lock: %w", err)
+	}
+	defer conn.Close()
+
+	acquired, err := tryMigrationLock(ctx, conn)
+	if err != nil {
+		return err
+	}
+
+	if acquired {
+		mm.logger.InfoContext(ctx, "Acquired migration lock; applying migrations")
+		defer mm.releaseMigrationLock(conn)
+		return mm.RunMigrations(ctx, migrations)
+	}
+
+	mm.logger.InfoContext(ctx, "Another instance is applying migrations; waiting for it", "timeout", lockTimeout)
+	if err := mm.waitForMigrationLock(ctx, conn, lockTimeout); err != nil {
+		return err
+	}
+
+	// The lock is only needed to know the other instance finished, so it is released straight away
+	mm.releaseMigrationLock(conn)
+	return mm.CheckSchemaVersion(ctx, migrations)
+}
+
+// waitForMigrationLock polls the migration lock until it is acquired, lockTimeout passes or ctx is done
+func (mm *MigrationManager) waitForMigrationLock(ctx context.Context, conn *sql.Conn, lockTimeout time.Duration) error {
+	waitCtx, cancel := context.WithTimeout(ctx, lockTimeout)
+	defer cancel()
+
+	for {
+		if !sleep(waitCtx, migrationLockPoll) {
+			if ctx.Err() != nil {
+				return ctx.Err()
+			}
+			return fmt.Errorf("timed out after %s waiting for another instance to finish migrating", lockTimeout)
+		}
+
+		acquired, err := tryMigrationLock(ctx, conn)
+		if err != nil {
+			return err
+		}
+		if acquired {
+			return nil
+		}
+	}
+}
+
+// tryMigrationLock takes the migration lock on conn without blocking
+func tryMigrationLock(ctx context.Context, conn *sql.Conn) (bool, error) {
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, `SELECT pg_try_advisory_lock($1)`, migrationLockKey).Scan(&acquired); err != nil {
+		return false, fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+	return acquired, nil
+}
+
+// releaseMigrationLock releases the migration lock held on conn
+// It uses its own context so that the lock is released even when startup was canceled
+func (mm *MigrationManager) releaseMigrationLock(conn *sql.Conn) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_unlock($1)`, migrationLockKey); err != nil {
+		mm.logger.WarnContext(ctx, "Failed to release migration lock", "error", err)
+	}
+}
+
+// CheckSchemaVersion verifies, without changing anything, that the database schema is at least at the
+// latest version in migrations. It is how instances that do not migrate refuse to serve an old schema
+// A newer schema is allowed, since that is what the old instances of a rolling deploy run against
+func (mm *MigrationManager) CheckSchemaVersion(ctx context.Context, migrations fs.FS) error {
+	list, err := ListMigrations(migrations)
+	if err != nil {
+		return err
+	}
+
+	var expected uint
+	if len(list) > 0 {
+		expected = list[len(list)-1].Version
+	}
+
+	var (
+		version int64
+		dirty   bool
+	)
+	err = mm.db.QueryRowContext(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+	switch {
+	case err == sql.ErrNoRows || isUndefinedTable(err):
+		version = 0
+	case err != nil:
+		return fmt.Errorf("failed to read schema version: %w", err)
+	case version < 0:
+		version = 0
+	}
+
+	if dirty {
+		return mm.dirtyError(ctx, uint(version))
+	}
+
+	if uint(version) < expected {
+		return fmt.Errorf("database schema is at version %d but this build expects version %d; run `migrate up`", version, expected)
+	}
+	if uint(version) > expected {
+		mm.logger.WarnContext(ctx, "Database schema is newer than this build expects", "version", version, "expected", expected)
+	}
+
+	mm.logger.InfoContext(ctx, "Database schema version verified", "version", version)
+	return nil
+}
@@ -0,0 +1,178 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// MigrationFailure records a migration that failed part way, leaving the schema dirty
+type MigrationFailure struct {
+	Version  uint
+	Name     string
+	Error    string
+	Instance string
+	FailedAt time.Time
+}
+
+// DirtySchemaError reports a database left dirty by a failed migration and how to recover it
+type DirtySchemaError struct {
+	Version uint
+	// Failure is nil when the failure was not recorded, e.g. because the migrating process crashed
+	Failure *MigrationFailure
+}
+
+// Error describes the failed migration and the operator's options
+func (e *DirtySchemaError) Error() string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "database is in dirty state at version %d", e.Version)
+
+	if e.Failure != nil {
+		fmt.Fprintf(&b, ": migration %03d_%s failed on %s at %s: %s",
+			e.Failure.Version, e.Failure.Name, e.Failure.Instance, e.Failure.FailedAt.Format(time.RFC3339), e.Failure.Error)
+	} else {
+		b.WriteString(": the migration was interrupted before its failure could be recorded")
+	}
+
+	fmt.Fprintf(&b, "; undo its partial changes and run `migrate recover` to retry it, or finish it by hand and run `migrate force %d`", e.Version)
+	return b.String()
+}
+
+// createFailuresTable holds the last failure of each migration version
+// It is created on first use rather than by a migration, since it must work while migrations are failing
+const createFailuresTable = `
+	CREATE TABLE IF NOT EXISTS schema_migration_failures (
+		version   BIGINT NOT NULL PRIMARY KEY,
+		name      TEXT NOT NULL,
+		error     TEXT NOT NULL,
+		instance  TEXT NOT NULL,
+		failed_at TEXT NOT NULL
+	)`
+
+// recordFailure stores why a migration failed so that every instance, and the migrate command, can report it
+func (mm *MigrationManager) recordFailure(ctx context.Context, migrations fs.FS, version uint, cause error) {
+	name := ""
+	if list, err := ListMigrations(migrations); err == nil {
+		for _, m := range list {
+			if m.Version == version {
+				name = m.Name
+			}
+		}
+	}
+
+	instance, err := os.Hostname()
+	if err != nil {
+		instance = "unknown"
+	}
+
+	// SQLite numbers $N parameters in order of appearance, so they are kept in order
+	err = mm.db.WithTransaction(ctx, func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, createFailuresTable); err != nil {
+			return err
+		}
+		if _, err := tx.ExecContext(ctx, `DELETE FROM schema_migration_failures WHERE version = $1`, int64(version)); err != nil {
+			return err
+		}
+		_, err := tx.ExecContext(ctx,
+			`INSERT INTO schema_migration_failures (version, name, error, instance, failed_at) VALUES ($1, $2, $3, $4, $5)`,
+			int64(version), name, cause.Error(), instance, time.Now().UTC().Format(time.RFC3339Nano),
+		)
+		return err
+	})
+	if err != nil {
+		mm.logger.ErrorContext(ctx, "Failed to record migration failure", "version", version, "error", err)
+	}
+}
+
+// LastFailure returns the recorded failure of the migration at version, or nil if none was recorded
+func (mm *MigrationManager) LastFailure(ctx context.Context, version uint) (*MigrationFailure, error) {
+	var (
+		failure  MigrationFailure
+		failedAt string
+	)
+	err := mm.db.QueryRowContext(ctx,
+		`SELECT version, name, error, instance, failed_at FROM schema_migration_failures WHERE version = $1`,
+		int64(version),
+	).Scan(&failure.Version, &failure.Name, &failure.Error, &failure.Instance, &failedAt)
+	if err == sql.ErrNoRows || isUndefinedTable(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migration failure: %w", err)
+	}
+
+	failure.FailedAt, err = time.Parse(time.RFC3339Nano, failedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse migration failure time: %w", err)
+	}
+	return &failure, nil
+}
+
+// clearFailures forgets recorded failures once the operator has resolved the dirty state
+func (mm *MigrationManager) clearFailures(ctx context.Context) error {
+	_, err := mm.db.ExecContext(ctx, `DELETE FROM schema_migration_failures`)
+	if err != nil && !isUndefinedTable(err) {
+		return fmt.Errorf("failed to clear migration failures: %w", err)
+	}
+	return nil
+}
+
+// dirtyError builds the error returned for a dirty database, including its recorded failure
+func (mm *MigrationManager) dirtyError(ctx context.Context, version uint) error {
+	failure, err := mm.LastFailure(ctx, version)
+	if err != nil {
+		mm.logger.WarnContext(ctx, "Failed to look up why the migration failed", "version", version, "error", err)
+	}
+	return &DirtySchemaError{Version: version, Failure: failure}
+}
+
+// Recover marks the failed migration of a dirty database as not applied, so that the next run retries it,
+// and returns the version the database is now at (-1 for none). Whatever part of the failed migration
+// did apply must be undone first; migrations that run in a single transaction leave nothing behind
+func (mm *MigrationManager) Recover(ctx context.Context, migrations fs.FS) (int, error) {
+	version, dirty, err := mm.GetMigrationVersion(ctx, migrations)
+	if err != nil {
+		return 0, err
+	}
+	if !dirty {
+		return 0, fmt.Errorf("database is not dirty; nothing to recover")
+	}
+
+	list, err := ListMigrations(migrations)
+	if err != nil {
+		return 0, err
+	}
+
+	previous := -1
+	for _, m := range list {
+		if m.Version < version {
+			previous = int(m.Version)
+		}
+	}
+
+	mm.logger.WarnContext(ctx, "Recovering dirty database", "failed_version", version, "new_version", previous)
+
+	if err := mm.ForceVersion(ctx, migrations, previous); err != nil {
+		return 0, err
+	}
+	return previous, nil
+}
+
+// isUndefinedTable reports whether err is about a table that does not exist yet
+func isUndefinedTable(err error) bool {
+	if err == nil {
+		return false
+	}
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return pqErr.Code == "42P01"
+	}
+	return strings.Contains(err.Error(), "no such table")
+}
@@ -0,0 +1,99 @@
+package database
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"testing/fstest"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestListMigrations(t *testing.T) {
+	migrations := fstest.MapFS{
+		"010_add_index.up.sql":        {},
+		"010_add_index.down.sql":      {},
+		"002_create_users.up.sql":     {},
+		"002_create_users.down.sql":   {},
+		"README.md":                   {},
+		"sqlite/001_create_x.up.sql":  {},
+		"001_initial_schema.up.sql":   {},
+		"001_initial_schema.down.sql": {},
+	}
+
+	list, err := ListMigrations(migrations)
+	require.NoError(t, err)
+	assert.Equal(t, []MigrationInfo{
+		{Version: 1, Name: "initial_schema"},
+		{Version: 2, Name: "create_users"},
+		{Version: 10, Name: "add_index"},
+	}, list)
+}
+
+func TestMigrationStatus_Pending(t *testing.T) {
+	status := MigrationStatus{
+		Version: 1,
+		Migrations: []MigrationInfo{
+			{Version: 1, Name: "initial_schema", Applied: true},
+			{Version: 2, Name: "create_users"},
+		},
+	}
+
+	assert.Equal(t, []MigrationInfo{{Version: 2, Name: "create_users"}}, status.Pending())
+}
+
+func TestCreateMigration(t *testing.T) {
+	dir := t.TempDir()
+
+	up, down, err := CreateMigration(dir, "create_users")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "001_create_users.up.sql"), up)
+	assert.Equal(t, filepath.Join(dir, "001_create_users.down.sql"), down)
+
+	up, _, err = CreateMigration(dir, "add_email_index")
+	require.NoError(t, err)
+	assert.Equal(t, filepath.Join(dir, "002_add_email_index.up.sql"), up)
+
+	list, err := ListMigrations(os.DirFS(dir))
+	require.NoError(t, err)
+	assert.Len(t, list, 2)
+}
+
+func TestCreateMigration_InvalidName(t *testing.T) {
+	for _, name := range []string{"", "Add Users", "add-users", "_add", "add__users"} {
+		_, _, err := CreateMigration(t.TempDir(), name)
+		assert.Error(t, err, name)
+	}
+}
+
+func TestDirtySchemaError(t *testing.T) {
+	err := &DirtySchemaError{
+		Version: 3,
+		Failure: &MigrationFailure{
+			Version:  3,
+			Name:     "add_index",
+			Error:    "relation \"users\" does not exist",
+			Instance: "api-7f9c",
+			FailedAt: time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		},
+	}
+
+	assert.Equal(t, "database is in dirty state at version 3: migration 003_add_index failed on api-7f9c at 2024-05-01T12:00:00Z: "+
+		"relation \"users\" does not exist; undo its partial changes and run `migrate recover` to retry it, "+
+		"or finish it by hand and run `migrate force 3`", err.Error())
+
+	err.Failure = nil
+	assert.Contains(t, err.Error(), "interrupted before its failure could be recorded")
+}
+
+func TestIsUndefinedTable(t *testing.T) {
+	assert.True(t, isUndefinedTable(fmt.Errorf("query: %w", &pq.Error{Code: "42P01"})))
+	assert.True(t, isUndefinedTable(fmt.Errorf("no such table: schema_migration_failures")))
+	assert.False(t, isUndefinedTable(&pq.Error{Code: "42703"}))
+	assert.False(t, isUndefinedTable(nil))
+}
@@ -0,0 +1,136 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"github.com/captain-corgi/go-graphql-example/internal/infrastructure/config"
+)
+
+// replica is a read-only connection pool that reads are routed to while it is healthy
+type replica struct {
+	*sql.DB
+	url     string
+	healthy atomic.Bool
+}
+
+// primaryPin records that a request has written, so its later reads go to the primary
+type primaryPin struct {
+	pinned atomic.Bool
+}
+
+// primaryPinKey is the context key of the request's primary pin
+type primaryPinKey struct{}
+
+// WithReadYourWrites returns a copy of ctx whose reads, and those of every context derived
+// from it, go to the primary once PinPrimary has been called; install it once per request
+func WithReadYourWrites(ctx context.Context) context.Context {
+	return context.WithValue(ctx, primaryPinKey{}, &primaryPin{})
+}
+
+// PinPrimary sends every later read made through ctx's request to the primary
+// It does nothing when ctx was not prepared with WithReadYourWrites
+func PinPrimary(ctx context.Context) {
+	if pin, ok := ctx.Value(primaryPinKey{}).(*primaryPin); ok {
+		pin.pinned.Store(true)
+	}
+}
+
+// PinnedToPrimary reports whether reads made with ctx must go to the primary
+func PinnedToPrimary(ctx context.Context) bool {
+	pin, ok := ctx.Value(primaryPinKey{}).(*primaryPin)
+	return ok && pin.pinned.Load()
+}
+
+// openReplicas opens a pool for every configured replica
+// A replica that cannot be reached yet starts ejected; the health checks readmit it later
+func openReplicas(ctx context.Context, cfg config.DatabaseConfig, driver string, db *DB) error {
+	for _, url := range cfg.ReplicaURLs {
+		pool, err := sql.Open(driver, url)
+		if err != nil {
+			return fmt.Errorf("failed to open replica connection %s: %w", maskDatabaseURL(url), err)
+		}
+
+		pool.SetMaxOpenConns(cfg.MaxOpenConns)
+		pool.SetMaxIdleConns(cfg.MaxIdleConns)
+		pool.SetConnMaxLifetime(cfg.ConnMaxLifetime)
+		pool.SetConnMaxIdleTime(cfg.ConnMaxIdleTime)
+
+		r := &replica{DB: pool, url: maskDatabaseURL(url)}
+		db.replicas = append(db.replicas, r)
+
+		db.checkReplica(ctx, r)
+		if !r.healthy.Load() {
+			db.logger.WarnContext(ctx, "Database replica is unreachable; it stays ejected until a health check passes", "url", r.url)
+		}
+	}
+
+	return nil
+}
+
+// Reader returns where a read-only query should run: the ambient transaction if there is one,
+// the primary when the request is pinned to it, otherwise the next healthy replica in turn
+// Without replicas, or with all of them ejected, reads go to the primary
+func (db *DB) Reader(ctx context.Context) Executor {
+	if tx, ok := TxFromContext(ctx); ok {
+		return tx
+	}
+	if PinnedToPrimary(ctx) {
+		return db.DB
+	}
+	if r := db.nextReplica(); r != nil {
+		return r.DB
+	}
+	return db.DB
+}
+
+// nextReplica returns the next healthy replica in round-robin order, or nil if there is none
+func (db *DB) nextReplica() *replica {
+	n := uint64(len(db.replicas))
+	for i := uint64(0); i < n; i++ {
+		r := db.replicas[(db.next.Add(1)-1)%n]
+		if r.healthy.Load() {
+			return r
+		}
+	}
+	return nil
+}
+
+// monitorReplicas pings every replica each interval until stop is closed
+func (db *DB) monitorReplicas(interval time.Duration, stop <-chan struct{}, done chan<- struct{}) {
+	defer close(done)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			for _, r := range db.replicas {
+				db.checkReplica(context.Background(), r)
+			}
+		}
+	}
+}
+
+// checkReplica ejects a replica that fails its ping and readmits one that passes again
+func (db *DB) checkReplica(ctx context.Context, r *replica) {
+	ctx, cancel := context.WithTimeout(ctx, 5*time.Second)
+	defer cancel()
+
+	if err := r.PingContext(ctx); err != nil {
+		if r.healthy.Swap(false) {
+			db.logger.WarnContext(ctx, "Ejecting unhealthy database replica", "url", r.url, "error", err)
+		}
+		return
+	}
+
+	if !r.healthy.Swap(true) {
+		db.logger.InfoContext(ctx, "Database replica is healthy", "url", r.url)
+	}
+}
@@ -0,0 +1,102 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"log/slog"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRoutingTestDB builds a DB with a primary and healthy replicas without connecting to anything
+func newRoutingTestDB(t *testing.T, replicas int) *DB {
+	t.Helper()
+
+	open := func() *sql.DB {
+		// Opening does not connect, so no database is needed
+		pool, err := sql.Open("postgres", "postgres://localhost/unused")
+		require.NoError(t, err)
+		t.Cleanup(func() { pool.Close() })
+		return pool
+	}
+
+	db := &DB{
+		DB:     open(),
+		logger: slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError})),
+	}
+	for i := 0; i < replicas; i++ {
+		r := &replica{DB: open(), url: "replica"}
+		r.healthy.Store(true)
+		db.replicas = append(db.replicas, r)
+	}
+	return db
+}
+
+func TestDB_Reader_RoundRobin(t *testing.T) {
+	db := newRoutingTestDB(t, 2)
+	ctx := context.Background()
+
+	assert.Same(t, db.replicas[0].DB, db.Reader(ctx))
+	assert.Same(t, db.replicas[1].DB, db.Reader(ctx))
+	assert.Same(t, db.replicas[0].DB, db.Reader(ctx))
+}
+
+func TestDB_Reader_SkipsEjectedReplicas(t *testing.T) {
+	db := newRoutingTestDB(t, 2)
+	ctx := context.Background()
+
+	db.replicas[0].healthy.Store(false)
+	for i := 0; i < 3; i++ {
+		assert.Same(t, db.replicas[1].DB, db.Reader(ctx))
+	}
+
+	// With every replica ejected, reads fall back to the primary
+	db.replicas[1].healthy.Store(false)
+	assert.Same(t, db.DB, db.Reader(ctx))
+}
+
+func TestDB_Reader_WithoutReplicas(t *testing.T) {
+	db := newRoutingTestDB(t, 0)
+	assert.Same(t, db.DB, db.Reader(context.Background()))
+}
+
+func TestDB_Reader_PrimaryRoutes(t *testing.T) {
+	db := newRoutingTestDB(t, 1)
+	tx := &sql.Tx{}
+
+	// Reads inside a transaction stay on it
+	assert.Same(t, tx, db.Reader(WithTx(context.Background(), tx)))
+
+	// A request reads from replicas until it is pinned, then from the primary
+	ctx := WithReadYourWrites(context.Background())
+	assert.Same(t, db.replicas[0].DB, db.Reader(ctx))
+	PinPrimary(ctx)
+	assert.Same(t, db.DB, db.Reader(ctx))
+	assert.Same(t, db.DB, db.Reader(context.WithValue(ctx, struct{}{}, "derived")))
+}
+
+func TestPinPrimary_WithoutReadYourWrites(t *testing.T) {
+	ctx := context.Background()
+	PinPrimary(ctx)
+	assert.False(t, PinnedToPrimary(ctx))
+}
+
+func TestDB_CheckReplica(t *testing.T) {
+	db := newRoutingTestDB(t, 0)
+
+	// Nothing listens on port 1, so the ping fails
+	pool, err := sql.Open("postgres", "postgres://localhost:1/unused?sslmode=disable&connect_timeout=1")
+	require.NoError(t, err)
+	defer pool.Close()
+
+	r := &replica{DB: pool, url: "replica"}
+	r.healthy.Store(true)
+	db.replicas = append(db.replicas, r)
+
+	db.checkReplica(context.Background(), r)
+	assert.False(t, r.healthy.Load())
+	assert.Same(t, db.DB, db.Reader(context.Background()))
+}
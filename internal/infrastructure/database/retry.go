@@ -0,0 +1,109 @@
+package database
+
+import (
+	"context"
+	"database/sql/driver"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"syscall"
+	"time"
+
+	"github.com/lib/pq"
+
+	"github.com/captain-corgi/go-graphql-example/internal/infrastructure/config"
+)
+
+// transientCodes are the SQLSTATEs after which running the whole transaction again may succeed
+var transientCodes = map[pq.ErrorCode]string{
+	"40001": "serialization_failure",
+	"40P01": "deadlock_detected",
+	"57P01": "admin_shutdown",
+	"57P02": "crash_shutdown",
+	"57P03": "cannot_connect_now",
+}
+
+// IsTransient reports whether err is a failure that a fresh attempt may not hit again:
+// a serialization failure, a deadlock, a server shutting down or a lost connection
+func IsTransient(err error) bool {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		// Class 08 covers connection failures the server itself reports
+		_, ok := transientCodes[pqErr.Code]
+		return ok || pqErr.Code.Class() == "08"
+	}
+
+	return errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET)
+}
+
+// isRolledBack reports whether a failed commit is known to have rolled back
+// A connection lost during commit leaves the outcome unknown, so only class 40
+// (transaction rollback) errors reported by the server count
+func isRolledBack(err error) bool {
+	var pqErr *pq.Error
+	return errors.As(err, &pqErr) && pqErr.Code.Class() == "40" && IsTransient(err)
+}
+
+// sqlState returns the SQLSTATE of err, or "" when the server did not report one
+func sqlState(err error) string {
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) {
+		return string(pqErr.Code)
+	}
+	return ""
+}
+
+// noRetryKey marks contexts whose transactions run only once
+type noRetryKey struct{}
+
+// WithoutRetry returns a context whose transactions are not run again after a transient failure
+// It suits transactions with effects outside the database, such as publishing, that must not repeat
+func WithoutRetry(ctx context.Context) context.Context {
+	return context.WithValue(ctx, noRetryKey{}, true)
+}
+
+// retryDisabled reports whether ctx was returned by WithoutRetry
+func retryDisabled(ctx context.Context) bool {
+	disabled, _ := ctx.Value(noRetryKey{}).(bool)
+	return disabled
+}
+
+// retryDelay returns how long to wait before running a transaction again after the given
+// number of failed attempts, or false when no attempts are left or the wait would outlast ctx
+// The delay is the base backoff doubled per retry and capped, then jittered down by up to half
+// so that transactions that collided do not retry in lockstep
+func retryDelay(ctx context.Context, cfg config.RetryConfig, attempts int) (time.Duration, bool) {
+	if attempts >= cfg.MaxAttempts {
+		return 0, false
+	}
+
+	delay := cfg.BaseBackoff
+	for i := 1; i < attempts && delay < cfg.MaxBackoff; i++ {
+		delay *= 2
+	}
+	if delay > cfg.MaxBackoff {
+		delay = cfg.MaxBackoff
+	}
+	delay -= rand.N(delay/2 + 1)
+
+	if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) <= delay {
+		return 0, false
+	}
+
+	return delay, true
+}
+
+// sleep waits for d, returning false if ctx is done first
+func sleep(ctx context.Context, d time.Duration) bool {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+
+	select {
+	case <-ctx.Done():
+		return false
+	case <-timer.C:
+		return true
+	}
+}
@@ -0,0 +1,262 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"syscall"
+	"testing"
+	"time"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/captain-corgi/go-graphql-example/internal/infrastructure/config"
+)
+
+// scriptedConnector hands out connections whose commits fail with the queued errors in turn
+type scriptedConnector struct {
+	commitErrs []error
+	commits    int
+}
+
+func (c *scriptedConnector) Connect(context.Context) (driver.Conn, error) {
+	return scriptedConn{c}, nil
+}
+
+func (c *scriptedConnector) Driver() driver.Driver {
+	return nil
+}
+
+type scriptedConn struct{ c *scriptedConnector }
+
+func (conn scriptedConn) Prepare(string) (driver.Stmt, error) {
+	return nil, fmt.Errorf("not supported")
+}
+
+func (conn scriptedConn) Close() error {
+	return nil
+}
+
+func (conn scriptedConn) Begin() (driver.Tx, error) {
+	return scriptedTx(conn), nil
+}
+
+type scriptedTx struct{ c *scriptedConnector }
+
+func (tx scriptedTx) Rollback() error {
+	return nil
+}
+
+func (tx scriptedTx) Commit() error {
+	tx.c.commits++
+	if len(tx.c.commitErrs) == 0 {
+		return nil
+	}
+	err := tx.c.commitErrs[0]
+	tx.c.commitErrs = tx.c.commitErrs[1:]
+	return err
+}
+
+// newRetryTestDB builds a DB on scripted connections with a fast retry policy
+func newRetryTestDB(t *testing.T, connector *scriptedConnector) *DB {
+	t.Helper()
+
+	pool := sql.OpenDB(connector)
+	t.Cleanup(func() { pool.Close() })
+
+	return &DB{
+		DB:     pool,
+		retry:  config.RetryConfig{MaxAttempts: 3, BaseBackoff: time.Millisecond, MaxBackoff: 4 * time.Millisecond},
+		logger: slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError})),
+	}
+}
+
+func TestIsTransient(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"serialization failure", &pq.Error{Code: "40001"}, true},
+		{"deadlock", fmt.Errorf("failed to update user: %w", &pq.Error{Code: "40P01"}), true},
+		{"admin shutdown", &pq.Error{Code: "57P01"}, true},
+		{"connection failure", &pq.Error{Code: "08006"}, true},
+		{"unique violation", &pq.Error{Code: "23505"}, false},
+		{"query canceled", &pq.Error{Code: "57014"}, false},
+		{"bad connection", driver.ErrBadConn, true},
+		{"unexpected EOF", fmt.Errorf("read: %w", io.ErrUnexpectedEOF), true},
+		{"connection reset", fmt.Errorf("read tcp: %w", syscall.ECONNRESET), true},
+		{"other error", fmt.Errorf("boom"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, IsTransient(tt.err))
+		})
+	}
+}
+
+func TestRetryDelay(t *testing.T) {
+	cfg := config.RetryConfig{MaxAttempts: 5, BaseBackoff: 100 * time.Millisecond, MaxBackoff: 300 * time.Millisecond}
+	ctx := context.Background()
+
+	for attempts, ceiling := range map[int]time.Duration{1: 100 * time.Millisecond, 2: 200 * time.Millisecond, 3: 300 * time.Millisecond, 4: 300 * time.Millisecond} {
+		for i := 0; i < 20; i++ {
+			delay, ok := retryDelay(ctx, cfg, attempts)
+			require.True(t, ok)
+			assert.GreaterOrEqual(t, delay, ceiling/2)
+			assert.LessOrEqual(t, delay, ceiling)
+		}
+	}
+
+	_, ok := retryDelay(ctx, cfg, 5)
+	assert.False(t, ok, "no attempts left")
+
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Millisecond)
+	defer cancel()
+	_, ok = retryDelay(ctx, cfg, 1)
+	assert.False(t, ok, "delay outlasts the context deadline")
+}
+
+func TestWithTransaction_RetriesTransientFailures(t *testing.T) {
+	db := newRetryTestDB(t, &scriptedConnector{})
+
+	calls := 0
+	err := db.WithTransaction(context.Background(), func(tx *sql.Tx) error {
+		calls++
+		if calls < 3 {
+			return fmt.Errorf("failed to update user: %w", &pq.Error{Code: "40001"})
+		}
+		return nil
+	})
+
+	require.NoError(t, err)
+	assert.Equal(t, 3, calls)
+}
+
+func TestWithTransaction_GivesUpAfterMaxAttempts(t *testing.T) {
+	db := newRetryTestDB(t, &scriptedConnector{})
+
+	calls := 0
+	err := db.WithTransaction(context.Background(), func(tx *sql.Tx) error {
+		calls++
+		return &pq.Error{Code: "40P01"}
+	})
+
+	assert.Equal(t, "40P01", sqlState(err))
+	assert.Equal(t, 3, calls)
+}
+
+func TestWithTransaction_DoesNotRetryOtherFailures(t *testing.T) {
+	db := newRetryTestDB(t, &scriptedConnector{})
+
+	calls := 0
+	err := db.WithTransaction(context.Background(), func(tx *sql.Tx) error {
+		calls++
+		return &pq.Error{Code: "23505"}
+	})
+
+	assert.Error(t, err)
+	assert.Equal(t, 1, calls)
+}
+
+func TestWithTransaction_WithoutRetryRunsOnce(t *testing.T) {
+	db := newRetryTestDB(t, &scriptedConnector{})
+
+	calls := 0
+	err := db.WithTransaction(WithoutRetry(context.Background()), func(tx *sql.Tx) error {
+		calls++
+		return &pq.Error{Code: "40001"}
+	})
+
+	assert.Equal(t, "40001", sqlState(err))
+	assert.Equal(t, 1, calls)
+}
+
+func TestWithTransaction_StopsWhenContextIsDone(t *testing.T) {
+	db := newRetryTestDB(t, &scriptedConnector{})
+	db.retry.BaseBackoff = time.Hour
+	db.retry.MaxBackoff = time.Hour
+
+	ctx, cancel := context.WithTimeout(context.Background(), time.Second)
+	defer cancel()
+
+	calls := 0
+	err := db.WithTransaction(ctx, func(tx *sql.Tx) error {
+		calls++
+		return &pq.Error{Code: "40001"}
+	})
+
+	assert.Equal(t, "40001", sqlState(err))
+	assert.Equal(t, 1, calls)
+}
+
+func TestWithTransaction_RetriesCommitOnlyWhenRolledBack(t *testing.T) {
+	t.Run("serialization failure", func(t *testing.T) {
+		connector := &scriptedConnector{commitErrs: []error{&pq.Error{Code: "40001"}}}
+		db := newRetryTestDB(t, connector)
+
+		require.NoError(t, db.WithTransaction(context.Background(), func(tx *sql.Tx) error { return nil }))
+		assert.Equal(t, 2, connector.commits)
+	})
+
+	t.Run("lost connection", func(t *testing.T) {
+		connector := &scriptedConnector{commitErrs: []error{io.ErrUnexpectedEOF}}
+		db := newRetryTestDB(t, connector)
+
+		err := db.WithTransaction(context.Background(), func(tx *sql.Tx) error { return nil })
+		assert.ErrorIs(t, err, io.ErrUnexpectedEOF)
+		assert.Equal(t, 1, connector.commits)
+	})
+}
+
+func TestTxManager_DoRunsAfterCommitCallbacks(t *testing.T) {
+	t.Run("after the commit", func(t *testing.T) {
+		connector := &scriptedConnector{}
+		tm := NewTxManager(newRetryTestDB(t, connector), slog.Default())
+
+		var commitsSeen []int
+		err := tm.Do(context.Background(), func(ctx context.Context) error {
+			AfterCommit(ctx, func() { commitsSeen = append(commitsSeen, connector.commits) })
+			assert.Empty(t, commitsSeen, "callbacks wait for the commit")
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, []int{1}, commitsSeen)
+	})
+
+	t.Run("not after a rollback", func(t *testing.T) {
+		tm := NewTxManager(newRetryTestDB(t, &scriptedConnector{}), slog.Default())
+
+		ran := false
+		err := tm.Do(context.Background(), func(ctx context.Context) error {
+			AfterCommit(ctx, func() { ran = true })
+			return fmt.Errorf("write failed")
+		})
+
+		assert.Error(t, err)
+		assert.False(t, ran)
+	})
+
+	t.Run("once for a retried transaction", func(t *testing.T) {
+		connector := &scriptedConnector{commitErrs: []error{&pq.Error{Code: "40001"}}}
+		tm := NewTxManager(newRetryTestDB(t, connector), slog.Default())
+
+		runs := 0
+		err := tm.Do(context.Background(), func(ctx context.Context) error {
+			AfterCommit(ctx, func() { runs++ })
+			return nil
+		})
+
+		require.NoError(t, err)
+		assert.Equal(t, 2, connector.commits)
+		assert.Equal(t, 1, runs)
+	})
+}
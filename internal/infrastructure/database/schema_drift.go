@@ -0,0 +1,453 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// TableSchema is the shape of a table that code depends on, or that a database actually has
+type TableSchema struct {
+	Name        string
+	Columns     []ColumnSchema
+	Indexes     []IndexSchema
+	Constraints []ConstraintSchema
+	Triggers    []TriggerSchema
+}
+
+// ColumnSchema describes a column; Type and Default are spelled the way Postgres prints them,
+// e.g. "character varying(255)" and "'ACTIVE'::character varying"
+type ColumnSchema struct {
+	Name     string
+	Type     string
+	Nullable bool
+	Default  string
+}
+
+// IndexSchema describes an index that does not back a constraint
+// Predicate is the WHERE clause of a partial index, without parentheses
+type IndexSchema struct {
+	Name      string
+	Columns   []string
+	Unique    bool
+	Predicate string
+}
+
+// ConstraintKind is the kind of a table constraint
+type ConstraintKind string
+
+const (
+	ConstraintPrimaryKey ConstraintKind = "PRIMARY KEY"
+	ConstraintUnique     ConstraintKind = "UNIQUE"
+	ConstraintCheck      ConstraintKind = "CHECK"
+	ConstraintForeignKey ConstraintKind = "FOREIGN KEY"
+	ConstraintExclusion  ConstraintKind = "EXCLUDE"
+)
+
+// ConstraintSchema describes a table constraint
+// Check constraints are compared by the columns they involve, not by their expression
+type ConstraintSchema struct {
+	Name    string
+	Kind    ConstraintKind
+	Columns []string
+}
+
+// TriggerSchema describes a trigger; Events lists INSERT, UPDATE, DELETE and TRUNCATE in that order
+type TriggerSchema struct {
+	Name       string
+	Timing     string
+	Events     []string
+	ForEachRow bool
+	Function   string
+}
+
+// String renders the trigger roughly as CREATE TRIGGER would declare it
+func (t TriggerSchema) String() string {
+	level := "STATEMENT"
+	if t.ForEachRow {
+		level = "ROW"
+	}
+	return fmt.Sprintf("%s %s FOR EACH %s EXECUTE FUNCTION %s()", t.Timing, strings.Join(t.Events, " OR "), level, t.Function)
+}
+
+// SchemaDrift lists how live tables differ from the expected ones
+type SchemaDrift struct {
+	// Differences are missing or changed objects, and unexpected objects that can change how writes behave
+	Differences []string
+	// Additions are unexpected objects that code unaware of them still works with: nullable or
+	// defaulted columns and non-unique indexes, such as those a newer release's migrations add
+	Additions []string
+}
+
+// HasDifferences reports whether any difference other than a harmless addition was found
+func (d *SchemaDrift) HasDifferences() bool {
+	return len(d.Differences) > 0
+}
+
+// Error lists the differences one per line
+func (d *SchemaDrift) Error() string {
+	var b strings.Builder
+	b.WriteString("database schema differs from what the code expects:")
+	for _, difference := range d.Differences {
+		b.WriteString("\n  - ")
+		b.WriteString(difference)
+	}
+	return b.String()
+}
+
+// merge appends the findings of other
+func (d *SchemaDrift) merge(other SchemaDrift) {
+	d.Differences = append(d.Differences, other.Differences...)
+	d.Additions = append(d.Additions, other.Additions...)
+}
+
+// DetectSchemaDrift introspects each expected table in the current schema and compares it with
+// what is expected. It only reads the Postgres catalogs
+func (db *DB) DetectSchemaDrift(ctx context.Context, expected []TableSchema) (*SchemaDrift, error) {
+	drift := &SchemaDrift{}
+	for _, table := range expected {
+		actual, err := db.InspectTable(ctx, table.Name)
+		if err != nil {
+			return nil, err
+		}
+		if actual == nil {
+			drift.Differences = append(drift.Differences, fmt.Sprintf("%s: table is missing", table.Name))
+			continue
+		}
+		drift.merge(DiffTable(table, *actual))
+	}
+	return drift, nil
+}
+
+// InspectTable reads a table's columns, indexes, constraints and triggers from pg_catalog,
+// or returns nil when the table does not exist
+func (db *DB) InspectTable(ctx context.Context, name string) (*TableSchema, error) {
+	var exists bool
+	if err := db.QueryRowContext(ctx, `SELECT to_regclass($1::text) IS NOT NULL`, name).Scan(&exists); err != nil {
+		return nil, fmt.Errorf("failed to look up table %s: %w", name, err)
+	}
+	if !exists {
+		return nil, nil
+	}
+
+	table := &TableSchema{Name: name}
+	var err error
+	if table.Columns, err = db.inspectColumns(ctx, name); err != nil {
+		return nil, err
+	}
+	if table.Indexes, err = db.inspectIndexes(ctx, name); err != nil {
+		return nil, err
+	}
+	if table.Constraints, err = db.inspectConstraints(ctx, name); err != nil {
+		return nil, err
+	}
+	if table.Triggers, err = db.inspectTriggers(ctx, name); err != nil {
+		return nil, err
+	}
+	return table, nil
+}
+
+// inspectColumns reads the live columns of a table
+func (db *DB) inspectColumns(ctx context.Context, table string) ([]ColumnSchema, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT a.attname, format_type(a.atttypid, a.atttypmod), NOT a.attnotnull,
+		       COALESCE(pg_get_expr(d.adbin, d.adrelid), '')
+		FROM pg_attribute a
+		LEFT JOIN pg_attrdef d ON d.adrelid = a.attrelid AND d.adnum = a.attnum
+		WHERE a.attrelid = to_regclass($1::text) AND a.attnum > 0 AND NOT a.attisdropped
+		ORDER BY a.attnum`, table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect columns of %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	var columns []ColumnSchema
+	for rows.Next() {
+		var c ColumnSchema
+		if err := rows.Scan(&c.Name, &c.Type, &c.Nullable, &c.Default); err != nil {
+			return nil, fmt.Errorf("failed to scan column of %s: %w", table, err)
+		}
+		columns = append(columns, c)
+	}
+	return columns, rows.Err()
+}
+
+// inspectIndexes reads the indexes of a table, leaving out those backing a constraint
+func (db *DB) inspectIndexes(ctx context.Context, table string) ([]IndexSchema, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT i.relname, ix.indisunique,
+		       ARRAY(SELECT pg_get_indexdef(ix.indexrelid, k, true) FROM generate_series(1, ix.indnatts) AS k ORDER BY k),
+		       COALESCE(pg_get_expr(ix.indpred, ix.indrelid, true), '')
+		FROM pg_index ix
+		JOIN pg_class i ON i.oid = ix.indexrelid
+		WHERE ix.indrelid = to_regclass($1::text)
+		  AND NOT EXISTS (SELECT 1 FROM pg_constraint c WHERE c.conindid = ix.indexrelid)
+		ORDER BY i.relname`, table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect indexes of %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	var indexes []IndexSchema
+	for rows.Next() {
+		var ix IndexSchema
+		if err := rows.Scan(&ix.Name, &ix.Unique, pq.Array(&ix.Columns), &ix.Predicate); err != nil {
+			return nil, fmt.Errorf("failed to scan index of %s: %w", table, err)
+		}
+		indexes = append(indexes, ix)
+	}
+	return indexes, rows.Err()
+}
+
+// constraintKinds maps pg_constraint.contype to constraint kinds
+// Not-null constraints, catalogued since Postgres 18, are compared as part of their column
+var constraintKinds = map[string]ConstraintKind{
+	"p": ConstraintPrimaryKey,
+	"u": ConstraintUnique,
+	"c": ConstraintCheck,
+	"f": ConstraintForeignKey,
+	"x": ConstraintExclusion,
+}
+
+// inspectConstraints reads the constraints of a table
+func (db *DB) inspectConstraints(ctx context.Context, table string) ([]ConstraintSchema, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT c.conname, c.contype::text,
+		       ARRAY(SELECT a.attname::text
+		             FROM unnest(c.conkey) WITH ORDINALITY AS k(attnum, n)
+		             JOIN pg_attribute a ON a.attrelid = c.conrelid AND a.attnum = k.attnum
+		             ORDER BY k.n)
+		FROM pg_constraint c
+		WHERE c.conrelid = to_regclass($1::text) AND c.contype <> 'n'
+		ORDER BY c.conname`, table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect constraints of %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	var constraints []ConstraintSchema
+	for rows.Next() {
+		var (
+			c       ConstraintSchema
+			contype string
+		)
+		if err := rows.Scan(&c.Name, &contype, pq.Array(&c.Columns)); err != nil {
+			return nil, fmt.Errorf("failed to scan constraint of %s: %w", table, err)
+		}
+		c.Kind = constraintKinds[contype]
+		if c.Kind == "" {
+			c.Kind = ConstraintKind(contype)
+		}
+		constraints = append(constraints, c)
+	}
+	return constraints, rows.Err()
+}
+
+// Bits of pg_trigger.tgtype
+const (
+	triggerTypeRow      = 1 << 0
+	triggerTypeBefore   = 1 << 1
+	triggerTypeInsert   = 1 << 2
+	triggerTypeDelete   = 1 << 3
+	triggerTypeUpdate   = 1 << 4
+	triggerTypeTruncate = 1 << 5
+	triggerTypeInstead  = 1 << 6
+)
+
+// inspectTriggers reads the user-defined triggers of a table
+func (db *DB) inspectTriggers(ctx context.Context, table string) ([]TriggerSchema, error) {
+	rows, err := db.QueryContext(ctx, `
+		SELECT t.tgname, p.proname, t.tgtype
+		FROM pg_trigger t
+		JOIN pg_proc p ON p.oid = t.tgfoid
+		WHERE t.tgrelid = to_regclass($1::text) AND NOT t.tgisinternal
+		ORDER BY t.tgname`, table)
+	if err != nil {
+		return nil, fmt.Errorf("failed to inspect triggers of %s: %w", table, err)
+	}
+	defer rows.Close()
+
+	var triggers []TriggerSchema
+	for rows.Next() {
+		var (
+			name, function string
+			tgtype         int
+		)
+		if err := rows.Scan(&name, &function, &tgtype); err != nil {
+			return nil, fmt.Errorf("failed to scan trigger of %s: %w", table, err)
+		}
+		triggers = append(triggers, decodeTrigger(name, function, tgtype))
+	}
+	return triggers, rows.Err()
+}
+
+// decodeTrigger turns pg_trigger.tgtype into a TriggerSchema
+func decodeTrigger(name, function string, tgtype int) TriggerSchema {
+	t := TriggerSchema{
+		Name:       name,
+		Timing:     "AFTER",
+		ForEachRow: tgtype&triggerTypeRow != 0,
+		Function:   function,
+	}
+	switch {
+	case tgtype&triggerTypeBefore != 0:
+		t.Timing = "BEFORE"
+	case tgtype&triggerTypeInstead != 0:
+		t.Timing = "INSTEAD OF"
+	}
+
+	for _, event := range []struct {
+		bit  int
+		name string
+	}{
+		{triggerTypeInsert, "INSERT"},
+		{triggerTypeUpdate, "UPDATE"},
+		{triggerTypeDelete, "DELETE"},
+		{triggerTypeTruncate, "TRUNCATE"},
+	} {
+		if tgtype&event.bit != 0 {
+			t.Events = append(t.Events, event.name)
+		}
+	}
+	return t
+}
+
+// DiffTable compares a live table with the expected one
+func DiffTable(expected, actual TableSchema) SchemaDrift {
+	var drift SchemaDrift
+	differ := func(format string, args ...interface{}) {
+		drift.Differences = append(drift.Differences, expected.Name+": "+fmt.Sprintf(format, args...))
+	}
+	added := func(format string, args ...interface{}) {
+		drift.Additions = append(drift.Additions, expected.Name+": "+fmt.Sprintf(format, args...))
+	}
+
+	// Columns
+	for _, want := range expected.Columns {
+		got, ok := findByName(actual.Columns, want.Name, func(c ColumnSchema) string { return c.Name })
+		if !ok {
+			differ("column %s is missing", want.Name)
+			continue
+		}
+		if got.Type != want.Type {
+			differ("column %s has type %s, expected %s", want.Name, got.Type, want.Type)
+		}
+		if got.Nullable != want.Nullable {
+			differ("column %s is %s, expected %s", want.Name, nullability(got.Nullable), nullability(want.Nullable))
+		}
+		if got.Default != want.Default {
+			differ("column %s has default %s, expected %s", want.Name, orNone(got.Default), orNone(want.Default))
+		}
+	}
+	for _, got := range actual.Columns {
+		if _, ok := findByName(expected.Columns, got.Name, func(c ColumnSchema) string { return c.Name }); ok {
+			continue
+		}
+		if got.Nullable || got.Default != "" {
+			added("unexpected column %s %s", got.Name, got.Type)
+		} else {
+			differ("unexpected column %s %s is NOT NULL without a default, so inserts that omit it fail", got.Name, got.Type)
+		}
+	}
+
+	// Indexes
+	for _, want := range expected.Indexes {
+		got, ok := findByName(actual.Indexes, want.Name, func(ix IndexSchema) string { return ix.Name })
+		if !ok {
+			differ("index %s on %s is missing", want.Name, describeIndex(want))
+			continue
+		}
+		if got.Unique != want.Unique || !slices.Equal(got.Columns, want.Columns) || got.Predicate != want.Predicate {
+			differ("index %s is on %s, expected %s", want.Name, describeIndex(got), describeIndex(want))
+		}
+	}
+	for _, got := range actual.Indexes {
+		if _, ok := findByName(expected.Indexes, got.Name, func(ix IndexSchema) string { return ix.Name }); ok {
+			continue
+		}
+		if got.Unique {
+			differ("unexpected unique index %s on %s", got.Name, describeIndex(got))
+		} else {
+			added("unexpected index %s on %s", got.Name, describeIndex(got))
+		}
+	}
+
+	// Constraints
+	for _, want := range expected.Constraints {
+		got, ok := findByName(actual.Constraints, want.Name, func(c ConstraintSchema) string { return c.Name })
+		if !ok {
+			differ("constraint %s %s (%s) is missing", want.Name, want.Kind, strings.Join(want.Columns, ", "))
+			continue
+		}
+		if got.Kind != want.Kind || !slices.Equal(got.Columns, want.Columns) {
+			differ("constraint %s is %s (%s), expected %s (%s)",
+				want.Name, got.Kind, strings.Join(got.Columns, ", "), want.Kind, strings.Join(want.Columns, ", "))
+		}
+	}
+	for _, got := range actual.Constraints {
+		if _, ok := findByName(expected.Constraints, got.Name, func(c ConstraintSchema) string { return c.Name }); !ok {
+			differ("unexpected constraint %s %s (%s)", got.Name, got.Kind, strings.Join(got.Columns, ", "))
+		}
+	}
+
+	// Triggers
+	for _, want := range expected.Triggers {
+		got, ok := findByName(actual.Triggers, want.Name, func(t TriggerSchema) string { return t.Name })
+		if !ok {
+			differ("trigger %s (%s) is missing", want.Name, want)
+			continue
+		}
+		if got.String() != want.String() {
+			differ("trigger %s is %s, expected %s", want.Name, got, want)
+		}
+	}
+	for _, got := range actual.Triggers {
+		if _, ok := findByName(expected.Triggers, got.Name, func(t TriggerSchema) string { return t.Name }); !ok {
+			differ("unexpected trigger %s (%s)", got.Name, got)
+		}
+	}
+
+	return drift
+}
+
+// findByName returns the item with the given name
+func findByName[T any](items []T, name string, nameOf func(T) string) (T, bool) {
+	for _, item := range items {
+		if nameOf(item) == name {
+			return item, true
+		}
+	}
+	var zero T
+	return zero, false
+}
+
+// describeIndex renders an index's uniqueness, columns and predicate
+func describeIndex(ix IndexSchema) string {
+	s := "(" + strings.Join(ix.Columns, ", ") + ")"
+	if ix.Unique {
+		s = "UNIQUE " + s
+	}
+	if ix.Predicate != "" {
+		s += " WHERE " + ix.Predicate
+	}
+	return s
+}
+
+// nullability names whether a column accepts NULL
+func nullability(nullable bool) string {
+	if nullable {
+		return "NULL"
+	}
+	return "NOT NULL"
+}
+
+// orNone shows an empty default as none
+func orNone(s string) string {
+	if s == "" {
+		return "none"
+	}
+	return s
+}
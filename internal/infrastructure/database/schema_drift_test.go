@@ -0,0 +1,147 @@
+package database
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// testTable is a small table with one of everything
+func testTable() TableSchema {
+	return TableSchema{
+		Name: "accounts",
+		Columns: []ColumnSchema{
+			{Name: "id", Type: "uuid"},
+			{Name: "email", Type: "character varying(255)"},
+			{Name: "created_at", Type: "timestamp with time zone", Nullable: true, Default: "now()"},
+		},
+		Indexes: []IndexSchema{
+			{Name: "accounts_email_key", Columns: []string{"email"}, Unique: true, Predicate: "deleted_at IS NULL"},
+		},
+		Constraints: []ConstraintSchema{
+			{Name: "accounts_pkey", Kind: ConstraintPrimaryKey, Columns: []string{"id"}},
+		},
+		Triggers: []TriggerSchema{
+			{Name: "touch", Timing: "BEFORE", Events: []string{"UPDATE"}, ForEachRow: true, Function: "touch_updated_at"},
+		},
+	}
+}
+
+func TestDiffTable_NoDrift(t *testing.T) {
+	drift := DiffTable(testTable(), testTable())
+	assert.False(t, drift.HasDifferences())
+	assert.Empty(t, drift.Additions)
+}
+
+func TestDiffTable_Differences(t *testing.T) {
+	tests := []struct {
+		name   string
+		change func(*TableSchema)
+		want   string
+	}{
+		{
+			name:   "missing column",
+			change: func(s *TableSchema) { s.Columns = s.Columns[:2] },
+			want:   "accounts: column created_at is missing",
+		},
+		{
+			name:   "widened column",
+			change: func(s *TableSchema) { s.Columns[1].Type = "character varying(320)" },
+			want:   "accounts: column email has type character varying(320), expected character varying(255)",
+		},
+		{
+			name:   "dropped NOT NULL",
+			change: func(s *TableSchema) { s.Columns[1].Nullable = true },
+			want:   "accounts: column email is NULL, expected NOT NULL",
+		},
+		{
+			name:   "dropped default",
+			change: func(s *TableSchema) { s.Columns[2].Default = "" },
+			want:   "accounts: column created_at has default none, expected now()",
+		},
+		{
+			name: "required column added",
+			change: func(s *TableSchema) {
+				s.Columns = append(s.Columns, ColumnSchema{Name: "tenant", Type: "text"})
+			},
+			want: "accounts: unexpected column tenant text is NOT NULL without a default, so inserts that omit it fail",
+		},
+		{
+			name:   "index lost its predicate",
+			change: func(s *TableSchema) { s.Indexes[0].Predicate = "" },
+			want:   "accounts: index accounts_email_key is on UNIQUE (email), expected UNIQUE (email) WHERE deleted_at IS NULL",
+		},
+		{
+			name:   "missing index",
+			change: func(s *TableSchema) { s.Indexes = nil },
+			want:   "accounts: index accounts_email_key on UNIQUE (email) WHERE deleted_at IS NULL is missing",
+		},
+		{
+			name: "unique index added",
+			change: func(s *TableSchema) {
+				s.Indexes = append(s.Indexes, IndexSchema{Name: "hotfix", Columns: []string{"created_at"}, Unique: true})
+			},
+			want: "accounts: unexpected unique index hotfix on UNIQUE (created_at)",
+		},
+		{
+			name: "constraint added",
+			change: func(s *TableSchema) {
+				s.Constraints = append(s.Constraints, ConstraintSchema{Name: "email_check", Kind: ConstraintCheck, Columns: []string{"email"}})
+			},
+			want: "accounts: unexpected constraint email_check CHECK (email)",
+		},
+		{
+			name:   "trigger changed",
+			change: func(s *TableSchema) { s.Triggers[0].Events = []string{"INSERT", "UPDATE"} },
+			want:   "accounts: trigger touch is BEFORE INSERT OR UPDATE FOR EACH ROW EXECUTE FUNCTION touch_updated_at(), expected BEFORE UPDATE FOR EACH ROW EXECUTE FUNCTION touch_updated_at()",
+		},
+		{
+			name:   "trigger dropped",
+			change: func(s *TableSchema) { s.Triggers = nil },
+			want:   "accounts: trigger touch (BEFORE UPDATE FOR EACH ROW EXECUTE FUNCTION touch_updated_at()) is missing",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			actual := testTable()
+			tt.change(&actual)
+
+			drift := DiffTable(testTable(), actual)
+			assert.Equal(t, []string{tt.want}, drift.Differences)
+			assert.Empty(t, drift.Additions)
+		})
+	}
+}
+
+func TestDiffTable_Additions(t *testing.T) {
+	actual := testTable()
+	actual.Columns = append(actual.Columns,
+		ColumnSchema{Name: "nickname", Type: "text", Nullable: true},
+		ColumnSchema{Name: "plan", Type: "text", Default: "'free'::text"},
+	)
+	actual.Indexes = append(actual.Indexes, IndexSchema{Name: "idx_accounts_created_at", Columns: []string{"created_at"}})
+
+	drift := DiffTable(testTable(), actual)
+	assert.False(t, drift.HasDifferences())
+	assert.Equal(t, []string{
+		"accounts: unexpected column nickname text",
+		"accounts: unexpected column plan text",
+		"accounts: unexpected index idx_accounts_created_at on (created_at)",
+	}, drift.Additions)
+}
+
+func TestSchemaDrift_Error(t *testing.T) {
+	drift := &SchemaDrift{Differences: []string{"users: column email is missing", "users: table is missing"}}
+	assert.Equal(t, "database schema differs from what the code expects:\n"+
+		"  - users: column email is missing\n"+
+		"  - users: table is missing", drift.Error())
+}
+
+func TestDecodeTrigger(t *testing.T) {
+	trigger := decodeTrigger("update_users_updated_at", "update_updated_at_column", triggerTypeRow|triggerTypeBefore|triggerTypeUpdate)
+	assert.Equal(t, "BEFORE UPDATE FOR EACH ROW EXECUTE FUNCTION update_updated_at_column()", trigger.String())
+
+	trigger = decodeTrigger("audit", "audit_users", triggerTypeInsert|triggerTypeDelete)
+	assert.Equal(t, "AFTER INSERT OR DELETE FOR EACH STATEMENT EXECUTE FUNCTION audit_users()", trigger.String())
+}
@@ -0,0 +1,36 @@
+//go:build sqlite
+
+package database
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/captain-corgi/go-graphql-example/internal/infrastructure/config"
+	migratedb "github.com/golang-migrate/migrate/v4/database"
+	"github.com/golang-migrate/migrate/v4/database/sqlite"
+	_ "modernc.org/sqlite" // Pure-Go SQLite driver, registered as "sqlite"
+)
+
+func init() {
+	migrationDrivers[config.DatabaseDriverSQLite] = newSQLiteMigrationDriver
+}
+
+// newSQLiteMigrationDriver runs migrations on the shared pool and leaves it open when done
+func newSQLiteMigrationDriver(_ context.Context, db *sql.DB) (migratedb.Driver, error) {
+	driver, err := sqlite.WithInstance(db, &sqlite.Config{})
+	if err != nil {
+		return nil, err
+	}
+	return sharedDriver{driver}, nil
+}
+
+// sharedDriver is a migration driver whose Close leaves the underlying pool to its owner
+type sharedDriver struct {
+	migratedb.Driver
+}
+
+// Close does nothing; DB.Close closes the pool
+func (sharedDriver) Close() error {
+	return nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"database/sql"
 	"fmt"
+	"io/fs"
 	"log/slog"
 	"os"
 	"strings"
@@ -111,14 +112,14 @@ func (tm *TestDBManager) GetTestDB(t *testing.T) *DB {
 }
 
 // RunMigrations runs migrations on the test database
-func (tm *TestDBManager) RunMigrations(t *testing.T, migrationsPath string) {
+func (tm *TestDBManager) RunMigrations(t *testing.T, migrations fs.FS) {
 	testDB := tm.GetTestDB(t)
 
 	migrationManager := NewMigrationManager(testDB, tm.logger)
 	ctx, cancel := context.WithTimeout(context.Background(), 60*time.Second)
 	defer cancel()
 
-	err := migrationManager.RunMigrations(ctx, migrationsPath)
+	err := migrationManager.RunMigrations(ctx, migrations)
 	require.NoError(t, err)
 }
 
@@ -235,7 +236,8 @@ func (tm *TestDBManager) QueryRow(t *testing.T, sql string, args ...interface{})
 }
 
 // TestDBSetup is a helper function for setting up test database in test suites
-func TestDBSetup(t *testing.T, migrationsPath string) (*DB, func()) {
+// migrations, such as migrations.Postgres, are run when not nil
+func TestDBSetup(t *testing.T, migrations fs.FS) (*DB, func()) {
 	// Skip if no test database URL is provided
 	if os.Getenv("TEST_DATABASE_URL") == "" {
 		t.Skip("TEST_DATABASE_URL not set, skipping database integration tests")
@@ -246,8 +248,8 @@ func TestDBSetup(t *testing.T, migrationsPath string) (*DB, func()) {
 
 	// Create test database and run migrations
 	db := manager.CreateTestDB(t)
-	if migrationsPath != "" {
-		manager.RunMigrations(t, migrationsPath)
+	if migrations != nil {
+		manager.RunMigrations(t, migrations)
 	}
 
 	// Return database and cleanup function
@@ -259,7 +261,7 @@ func TestDBSetup(t *testing.T, migrationsPath string) (*DB, func()) {
 }
 
 // TestDBSetupWithConfig is a helper function for setting up test database with custom config
-func TestDBSetupWithConfig(t *testing.T, cfg TestDBConfig, migrationsPath string) (*DB, func()) {
+func TestDBSetupWithConfig(t *testing.T, cfg TestDBConfig, migrations fs.FS) (*DB, func()) {
 	// Skip if no test database URL is provided
 	if os.Getenv("TEST_DATABASE_URL") == "" {
 		t.Skip("TEST_DATABASE_URL not set, skipping database integration tests")
@@ -269,8 +271,8 @@ func TestDBSetupWithConfig(t *testing.T, cfg TestDBConfig, migrationsPath string
 
 	// Create test database and run migrations
 	db := manager.CreateTestDB(t)
-	if migrationsPath != "" {
-		manager.RunMigrations(t, migrationsPath)
+	if migrations != nil {
+		manager.RunMigrations(t, migrations)
 	}
 
 	// Return database and cleanup function
@@ -19,10 +19,50 @@ func (db *DB) WithTransaction(ctx context.Context, fn TxFunc) error {
 }
 
 // WithTransactionOptions executes a function within a database transaction with custom options
+// Committing a read-write transaction pins the rest of the request to the primary (see PinPrimary)
+// A transaction that fails transiently (see IsTransient) is rolled back and run again from the start,
+// so fn must not have effects outside the transaction unless ctx comes from WithoutRetry;
+// the retry policy comes from database.retry
 func (db *DB) WithTransactionOptions(ctx context.Context, opts *sql.TxOptions, fn TxFunc) error {
+	for attempts := 1; ; attempts++ {
+		retryable, err := db.runTransaction(ctx, opts, fn)
+		if err == nil {
+			break
+		}
+		if !retryable || retryDisabled(ctx) {
+			return err
+		}
+
+		delay, ok := retryDelay(ctx, db.retry, attempts)
+		if !ok {
+			return err
+		}
+
+		db.logger.WarnContext(ctx, "Retrying transaction after transient failure",
+			"attempt", attempts,
+			"max_attempts", db.retry.MaxAttempts,
+			"delay", delay,
+			"sqlstate", sqlState(err),
+			"error", err,
+		)
+
+		if !sleep(ctx, delay) {
+			return err
+		}
+	}
+
+	if opts == nil || !opts.ReadOnly {
+		PinPrimary(ctx)
+	}
+
+	return nil
+}
+
+// runTransaction runs fn once in a transaction and reports whether a failure may be retried
+func (db *DB) runTransaction(ctx context.Context, opts *sql.TxOptions, fn TxFunc) (retryable bool, err error) {
 	tx, err := db.BeginTx(ctx, opts)
 	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+		return IsTransient(err), fmt.Errorf("failed to begin transaction: %w", err)
 	}
 
 	// Ensure transaction is always handled
@@ -47,20 +87,22 @@ func (db *DB) WithTransactionOptions(ctx context.Context, opts *sql.TxOptions, f
 				"original_error", err,
 				"rollback_error", rollbackErr,
 			)
-			return fmt.Errorf("transaction failed and rollback failed: original error: %w, rollback error: %v", err, rollbackErr)
+			return IsTransient(err), fmt.Errorf("transaction failed and rollback failed: original error: %w, rollback error: %v", err, rollbackErr)
 		}
-		return err
+		return IsTransient(err), err
 	}
 
 	// Commit the transaction
 	if err := tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+		return isRolledBack(err), fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
-	return nil
+	return false, nil
 }
 
 // TxManager provides transaction management utilities
+// When ctx already carries a transaction (see WithTx), Execute and Do join it
+// instead of starting a new one; the outermost caller commits or rolls back
 type TxManager struct {
 	db     *DB
 	logger *slog.Logger
@@ -74,8 +116,36 @@ func NewTxManager(db *DB, logger *slog.Logger) *TxManager {
 	}
 }
 
+// Do runs fn in a transaction carried by the context it is given, so every repository call made with
+// that context joins it. The transaction commits if fn returns nil and rolls back otherwise.
+// AfterCommit callbacks registered with that context run once it has committed
+func (tm *TxManager) Do(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := TxFromContext(ctx); ok {
+		return fn(ctx)
+	}
+
+	var runAfterCommit func()
+	err := tm.Execute(ctx, "unit of work", func(tx *sql.Tx) error {
+		// A retried attempt starts over, so only the callbacks of the attempt that commits run
+		var txCtx context.Context
+		txCtx, runAfterCommit = WithAfterCommit(WithTx(ctx, tx))
+		return fn(txCtx)
+	})
+	if err != nil {
+		return err
+	}
+
+	runAfterCommit()
+	return nil
+}
+
 // Execute runs a function within a transaction with logging
 func (tm *TxManager) Execute(ctx context.Context, operation string, fn TxFunc) error {
+	if tx, ok := TxFromContext(ctx); ok {
+		tm.logger.DebugContext(ctx, "Joining ambient transaction", "operation", operation)
+		return fn(tx)
+	}
+
 	tm.logger.DebugContext(ctx, "Starting transaction", "operation", operation)
 
 	start := time.Now()
@@ -100,7 +170,13 @@ func (tm *TxManager) Execute(ctx context.Context, operation string, fn TxFunc) e
 }
 
 // ExecuteWithOptions runs a function within a transaction with custom options and logging
+// An ambient transaction is joined as is; opts only apply to a transaction this call starts
 func (tm *TxManager) ExecuteWithOptions(ctx context.Context, operation string, opts *sql.TxOptions, fn TxFunc) error {
+	if tx, ok := TxFromContext(ctx); ok {
+		tm.logger.DebugContext(ctx, "Joining ambient transaction", "operation", operation)
+		return fn(tx)
+	}
+
 	tm.logger.DebugContext(ctx, "Starting transaction with options",
 		"operation", operation,
 		"isolation_level", opts.Isolation,
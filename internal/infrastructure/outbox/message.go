@@ -0,0 +1,72 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// Message is an event stored in the outbox
+type Message struct {
+	ID          int64
+	EventName   string
+	AggregateID string
+	Payload     json.RawMessage
+	OccurredAt  time.Time
+
+	// Attempts counts the delivery attempts made before this one
+	Attempts int
+}
+
+// Append stores messages in the outbox as part of tx, so they are only
+// published if the change that produced them commits
+func Append(ctx context.Context, tx *sql.Tx, messages ...Message) error {
+	for _, m := range messages {
+		_, err := tx.ExecContext(ctx, `
+			INSERT INTO outbox (event_name, aggregate_id, payload, occurred_at) 
+			VALUES ($1, $2, $3, $4)`,
+			m.EventName, m.AggregateID, []byte(m.Payload), m.OccurredAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to append %s to outbox: %w", m.EventName, err)
+		}
+	}
+
+	return nil
+}
+
+// CopyIn stores messages in the outbox as part of tx like Append, streaming
+// them in a single COPY for bulk writes
+func CopyIn(ctx context.Context, tx *sql.Tx, messages ...Message) error {
+	if len(messages) == 0 {
+		return nil
+	}
+
+	stmt, err := tx.PrepareContext(ctx, pq.CopyIn("outbox", "event_name", "aggregate_id", "payload", "occurred_at"))
+	if err != nil {
+		return fmt.Errorf("failed to start copying to outbox: %w", err)
+	}
+
+	for _, m := range messages {
+		// COPY sends text, so the payload goes as a string rather than as bytea
+		if _, err := stmt.ExecContext(ctx, m.EventName, m.AggregateID, string(m.Payload), m.OccurredAt); err != nil {
+			stmt.Close()
+			return fmt.Errorf("failed to copy %s to outbox: %w", m.EventName, err)
+		}
+	}
+
+	// An empty Exec flushes the rows; errors in them may only surface on Close
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		stmt.Close()
+		return fmt.Errorf("failed to copy messages to outbox: %w", err)
+	}
+	if err := stmt.Close(); err != nil {
+		return fmt.Errorf("failed to copy messages to outbox: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,80 @@
+package outbox
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+)
+
+//go:generate go run github.com/golang/mock/mockgen -source=$GOFILE -destination=./mocks/mock_$GOFILE -package=mocks
+
+// EventPublisher delivers outbox messages to the outside world
+// Delivery is at least once: a message whose delivery was not recorded is published again
+type EventPublisher interface {
+	// Publish delivers one message; an error schedules a retry
+	Publish(ctx context.Context, message Message) error
+}
+
+// LogPublisher publishes messages by logging them
+type LogPublisher struct {
+	logger *slog.Logger
+}
+
+// NewLogPublisher creates a publisher that writes each message to logger
+func NewLogPublisher(logger *slog.Logger) *LogPublisher {
+	return &LogPublisher{logger: logger}
+}
+
+// Publish logs the message
+func (p *LogPublisher) Publish(ctx context.Context, message Message) error {
+	p.logger.InfoContext(ctx, "Published event",
+		"outbox_id", message.ID,
+		"event", message.EventName,
+		"aggregate_id", message.AggregateID,
+		"occurred_at", message.OccurredAt,
+		"payload", string(message.Payload),
+	)
+	return nil
+}
+
+// MemoryPublisher keeps published messages in memory, for tests
+type MemoryPublisher struct {
+	mu        sync.Mutex
+	published []Message
+	failures  int
+	err       error
+}
+
+// NewMemoryPublisher creates an empty in-memory publisher
+func NewMemoryPublisher() *MemoryPublisher {
+	return &MemoryPublisher{}
+}
+
+// FailNext makes the next n calls to Publish return err
+func (p *MemoryPublisher) FailNext(n int, err error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.failures = n
+	p.err = err
+}
+
+// Publish records the message, or fails if FailNext asked it to
+func (p *MemoryPublisher) Publish(_ context.Context, message Message) error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.failures > 0 {
+		p.failures--
+		return p.err
+	}
+
+	p.published = append(p.published, message)
+	return nil
+}
+
+// Published returns the messages published so far, in order
+func (p *MemoryPublisher) Published() []Message {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return append([]Message(nil), p.published...)
+}
@@ -0,0 +1,27 @@
+package outbox
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMemoryPublisher(t *testing.T) {
+	publisher := NewMemoryPublisher()
+	errUnavailable := errors.New("broker unavailable")
+
+	publisher.FailNext(2, errUnavailable)
+
+	first := Message{ID: 1, EventName: "user.registered"}
+	assert.ErrorIs(t, publisher.Publish(context.Background(), first), errUnavailable)
+	assert.ErrorIs(t, publisher.Publish(context.Background(), first), errUnavailable)
+	require.NoError(t, publisher.Publish(context.Background(), first))
+
+	second := Message{ID: 2, EventName: "user.renamed"}
+	require.NoError(t, publisher.Publish(context.Background(), second))
+
+	assert.Equal(t, []Message{first, second}, publisher.Published())
+}
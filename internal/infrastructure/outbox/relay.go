@@ -0,0 +1,236 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+
+	"github.com/captain-corgi/go-graphql-example/internal/infrastructure/config"
+	"github.com/captain-corgi/go-graphql-example/internal/infrastructure/database"
+)
+
+// Relay moves messages from the outbox to an EventPublisher.
+//
+// Each batch is claimed with FOR UPDATE SKIP LOCKED, so several instances can
+// relay concurrently without publishing the same message twice at once. A failed
+// delivery is retried with exponential backoff; after MaxAttempts failures the
+// message is dead-lettered and left in the table for inspection.
+//
+// A batch is published inside its claiming transaction, which is never retried,
+// so a transient failure cannot publish the batch again at once. Messages whose
+// transaction fails are published again by a later batch: delivery is at least once.
+type Relay struct {
+	txManager *database.TxManager
+	publisher EventPublisher
+	cfg       config.OutboxConfig
+	logger    *slog.Logger
+
+	// ctx is the context of background batches; cancel aborts the one in progress
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	stop chan struct{}
+	done sync.WaitGroup
+}
+
+// NewRelay creates a relay publishing to publisher
+func NewRelay(txManager *database.TxManager, publisher EventPublisher, cfg config.OutboxConfig, logger *slog.Logger) *Relay {
+	ctx, cancel := context.WithCancel(context.Background())
+	return &Relay{
+		txManager: txManager,
+		publisher: publisher,
+		cfg:       cfg,
+		logger:    logger,
+		ctx:       ctx,
+		cancel:    cancel,
+		stop:      make(chan struct{}),
+	}
+}
+
+// Start polls the outbox in the background until Stop is called
+func (r *Relay) Start() {
+	r.done.Add(1)
+	go func() {
+		defer r.done.Done()
+
+		ticker := time.NewTicker(r.cfg.PollInterval)
+		defer ticker.Stop()
+
+		for {
+			// Keep going while batches come back full, so a backlog drains without waiting for ticks
+			for {
+				n, err := r.RelayBatch(r.ctx)
+				if err != nil && r.ctx.Err() == nil {
+					r.logger.Error("Failed to relay outbox batch", "error", err)
+				}
+				if err != nil || n < r.cfg.BatchSize || r.stopping() {
+					break
+				}
+			}
+
+			select {
+			case <-r.stop:
+				return
+			case <-ticker.C:
+			}
+		}
+	}()
+}
+
+// Stop ends polling and waits for the batch in progress to finish
+// If ctx ends first the batch is cancelled and rolled back; its messages are relayed on the next start
+func (r *Relay) Stop(ctx context.Context) {
+	close(r.stop)
+	defer r.cancel()
+
+	finished := make(chan struct{})
+	go func() {
+		r.done.Wait()
+		close(finished)
+	}()
+
+	select {
+	case <-finished:
+	case <-ctx.Done():
+		r.logger.Warn("Cancelling outbox batch in progress", "error", ctx.Err())
+		r.cancel()
+		<-finished
+	}
+}
+
+// stopping reports whether Stop has been called
+func (r *Relay) stopping() bool {
+	select {
+	case <-r.stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// RelayBatch publishes up to BatchSize due messages and returns how many it claimed
+func (r *Relay) RelayBatch(ctx context.Context) (int, error) {
+	var claimed int
+
+	// Publishing cannot be undone, so a failed batch is left for the next one instead of retried
+	err := r.txManager.Execute(database.WithoutRetry(ctx), "relay outbox batch", func(tx *sql.Tx) error {
+		messages, err := r.claim(ctx, tx)
+		if err != nil {
+			return err
+		}
+		claimed = len(messages)
+
+		for _, m := range messages {
+			if err := r.deliver(ctx, tx, m); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	})
+
+	return claimed, err
+}
+
+// claim locks the due messages, skipping any another relay is already handling
+func (r *Relay) claim(ctx context.Context, tx *sql.Tx) ([]Message, error) {
+	rows, err := tx.QueryContext(ctx, `
+		SELECT id, event_name, aggregate_id, payload, occurred_at, attempts 
+		FROM outbox 
+		WHERE delivered_at IS NULL AND dead_lettered_at IS NULL AND next_attempt_at <= NOW() 
+		ORDER BY id 
+		LIMIT $1 
+		FOR UPDATE SKIP LOCKED`,
+		r.cfg.BatchSize,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to claim outbox messages: %w", err)
+	}
+	defer rows.Close()
+
+	var messages []Message
+	for rows.Next() {
+		var m Message
+		if err := rows.Scan(&m.ID, &m.EventName, &m.AggregateID, &m.Payload, &m.OccurredAt, &m.Attempts); err != nil {
+			return nil, fmt.Errorf("failed to scan outbox message: %w", err)
+		}
+		messages = append(messages, m)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating over outbox messages: %w", err)
+	}
+
+	return messages, nil
+}
+
+// deliver publishes one message and records the outcome
+func (r *Relay) deliver(ctx context.Context, tx *sql.Tx, m Message) error {
+	publishErr := r.publisher.Publish(ctx, m)
+	attempts := m.Attempts + 1
+
+	if publishErr == nil {
+		_, err := tx.ExecContext(ctx,
+			`UPDATE outbox SET delivered_at = NOW(), attempts = $2, last_error = NULL WHERE id = $1`,
+			m.ID, attempts,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to mark outbox message %d delivered: %w", m.ID, err)
+		}
+		return nil
+	}
+
+	if attempts >= r.cfg.MaxAttempts {
+		r.logger.ErrorContext(ctx, "Dead-lettered outbox message",
+			"outbox_id", m.ID,
+			"event", m.EventName,
+			"attempts", attempts,
+			"error", publishErr,
+		)
+		_, err := tx.ExecContext(ctx,
+			`UPDATE outbox SET dead_lettered_at = NOW(), attempts = $2, last_error = $3 WHERE id = $1`,
+			m.ID, attempts, publishErr.Error(),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to dead-letter outbox message %d: %w", m.ID, err)
+		}
+		return nil
+	}
+
+	retryIn := Backoff(attempts, r.cfg.BaseBackoff, r.cfg.MaxBackoff)
+	r.logger.WarnContext(ctx, "Failed to publish outbox message; will retry",
+		"outbox_id", m.ID,
+		"event", m.EventName,
+		"attempts", attempts,
+		"retry_in", retryIn,
+		"error", publishErr,
+	)
+	_, err := tx.ExecContext(ctx,
+		`UPDATE outbox SET next_attempt_at = $2, attempts = $3, last_error = $4 WHERE id = $1`,
+		m.ID, time.Now().Add(retryIn), attempts, publishErr.Error(),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to reschedule outbox message %d: %w", m.ID, err)
+	}
+	return nil
+}
+
+// Backoff returns how long to wait after the given number of failed attempts:
+// base doubled for every attempt after the first, capped at max
+func Backoff(attempts int, base, max time.Duration) time.Duration {
+	delay := base
+	for i := 1; i < attempts; i++ {
+		if delay >= max/2 {
+			return max
+		}
+		delay *= 2
+	}
+
+	if delay > max {
+		return max
+	}
+	return delay
+}
@@ -0,0 +1,206 @@
+package outbox
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+
+	"github.com/captain-corgi/go-graphql-example/internal/infrastructure/config"
+	"github.com/captain-corgi/go-graphql-example/internal/infrastructure/database"
+	"github.com/captain-corgi/go-graphql-example/migrations"
+)
+
+func TestBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{attempts: 1, want: time.Second},
+		{attempts: 2, want: 2 * time.Second},
+		{attempts: 3, want: 4 * time.Second},
+		{attempts: 6, want: 32 * time.Second},
+		{attempts: 7, want: time.Minute},
+		{attempts: 100, want: time.Minute},
+	}
+
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, Backoff(tt.attempts, time.Second, time.Minute), "attempts=%d", tt.attempts)
+	}
+}
+
+// RelayTestSuite exercises the relay against a real database
+type RelayTestSuite struct {
+	suite.Suite
+	db        *database.DB
+	txManager *database.TxManager
+	ctx       context.Context
+	cleanup   func()
+}
+
+func (suite *RelayTestSuite) SetupSuite() {
+	suite.ctx = context.Background()
+
+	db, cleanup := database.TestDBSetup(suite.T(), migrations.Postgres)
+	suite.db = db
+	suite.cleanup = cleanup
+	suite.txManager = database.NewTxManager(db, slog.Default())
+}
+
+func (suite *RelayTestSuite) TearDownSuite() {
+	if suite.cleanup != nil {
+		suite.cleanup()
+	}
+}
+
+func (suite *RelayTestSuite) SetupTest() {
+	_, err := suite.db.ExecContext(suite.ctx, "DELETE FROM outbox")
+	require.NoError(suite.T(), err)
+}
+
+// append stores one message the way a repository would
+func (suite *RelayTestSuite) append(name string) {
+	err := suite.txManager.Execute(suite.ctx, "append", func(tx *sql.Tx) error {
+		return Append(suite.ctx, tx, Message{
+			EventName:   name,
+			AggregateID: "123e4567-e89b-12d3-a456-426614174000",
+			Payload:     []byte(`{"userId":"123e4567-e89b-12d3-a456-426614174000"}`),
+			OccurredAt:  time.Now(),
+		})
+	})
+	require.NoError(suite.T(), err)
+}
+
+func (suite *RelayTestSuite) relay(publisher EventPublisher, maxAttempts int) *Relay {
+	return NewRelay(suite.txManager, publisher, config.OutboxConfig{
+		Publisher:    "log",
+		PollInterval: time.Second,
+		BatchSize:    10,
+		MaxAttempts:  maxAttempts,
+		// Zero backoff keeps failed messages due so the next batch retries them
+		BaseBackoff: 0,
+		MaxBackoff:  0,
+	}, slog.Default())
+}
+
+func (suite *RelayTestSuite) TestDeliversInOrderOnce() {
+	suite.append("user.registered")
+	suite.append("user.renamed")
+
+	publisher := NewMemoryPublisher()
+	relay := suite.relay(publisher, 3)
+
+	n, err := relay.RelayBatch(suite.ctx)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 2, n)
+
+	// Delivered messages are not relayed again
+	n, err = relay.RelayBatch(suite.ctx)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 0, n)
+
+	published := publisher.Published()
+	require.Len(suite.T(), published, 2)
+	assert.Equal(suite.T(), "user.registered", published[0].EventName)
+	assert.Equal(suite.T(), "user.renamed", published[1].EventName)
+	assert.JSONEq(suite.T(), `{"userId":"123e4567-e89b-12d3-a456-426614174000"}`, string(published[0].Payload))
+}
+
+func (suite *RelayTestSuite) TestRetriesThenDeadLetters() {
+	suite.append("user.registered")
+
+	publisher := NewMemoryPublisher()
+	publisher.FailNext(3, errors.New("broker unavailable"))
+	relay := suite.relay(publisher, 3)
+
+	for i := 0; i < 3; i++ {
+		n, err := relay.RelayBatch(suite.ctx)
+		require.NoError(suite.T(), err)
+		assert.Equal(suite.T(), 1, n)
+	}
+
+	// The third failure dead-letters the message, so nothing is left to relay
+	n, err := relay.RelayBatch(suite.ctx)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 0, n)
+	assert.Empty(suite.T(), publisher.Published())
+
+	var attempts int
+	var lastError string
+	var deadLettered bool
+	err = suite.db.QueryRowContext(suite.ctx,
+		`SELECT attempts, last_error, dead_lettered_at IS NOT NULL FROM outbox`,
+	).Scan(&attempts, &lastError, &deadLettered)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 3, attempts)
+	assert.Equal(suite.T(), "broker unavailable", lastError)
+	assert.True(suite.T(), deadLettered)
+}
+
+func (suite *RelayTestSuite) TestConcurrentRelaysSkipLockedMessages() {
+	suite.append("user.registered")
+
+	blocking := &blockingPublisher{entered: make(chan struct{}), release: make(chan struct{})}
+	first := suite.relay(blocking, 3)
+	second := suite.relay(NewMemoryPublisher(), 3)
+
+	done := make(chan error, 1)
+	go func() {
+		_, err := first.RelayBatch(suite.ctx)
+		done <- err
+	}()
+	<-blocking.entered
+
+	// The message is locked by the first relay, so the second one skips it
+	n, err := second.RelayBatch(suite.ctx)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), 0, n)
+
+	close(blocking.release)
+	require.NoError(suite.T(), <-done)
+}
+
+func (suite *RelayTestSuite) TestStopCancelsBatchInProgress() {
+	suite.append("user.registered")
+
+	blocking := &blockingPublisher{entered: make(chan struct{}), release: make(chan struct{})}
+	relay := suite.relay(blocking, 3)
+	relay.Start()
+	<-blocking.entered
+
+	ctx, cancel := context.WithTimeout(suite.ctx, 50*time.Millisecond)
+	defer cancel()
+	relay.Stop(ctx)
+
+	// The cancelled batch rolled back, leaving the message for the next start
+	var delivered bool
+	err := suite.db.QueryRowContext(suite.ctx, `SELECT delivered_at IS NOT NULL FROM outbox`).Scan(&delivered)
+	require.NoError(suite.T(), err)
+	assert.False(suite.T(), delivered)
+}
+
+// blockingPublisher holds the first Publish call until released or its context ends
+type blockingPublisher struct {
+	entered chan struct{}
+	release chan struct{}
+}
+
+func (p *blockingPublisher) Publish(ctx context.Context, _ Message) error {
+	close(p.entered)
+	select {
+	case <-p.release:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func TestRelayIntegration(t *testing.T) {
+	suite.Run(t, new(RelayTestSuite))
+}
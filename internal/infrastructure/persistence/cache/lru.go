@@ -0,0 +1,140 @@
+package cache
+
+import (
+	"container/list"
+	"sync"
+	"time"
+
+	"github.com/captain-corgi/go-graphql-example/internal/domain/user"
+)
+
+// entry is a cached lookup result: a found user, or the fact that none was found
+type entry struct {
+	snapshot user.Snapshot
+	found    bool
+	expires  time.Time
+}
+
+// item is an entry stored in the LRU list under its key
+type item struct {
+	key   string
+	entry entry
+}
+
+// lru is a bounded, least-recently-used map of lookup results, safe for concurrent use
+// Entries holding a user are indexed by user ID so every lookup of that user can be dropped at once.
+// Every removal bumps a generation, letting a lookup that raced with a write skip caching its result.
+type lru struct {
+	mu         sync.Mutex
+	capacity   int
+	items      map[string]*list.Element
+	order      *list.List
+	byUser     map[string]map[string]struct{}
+	generation uint64
+	evictions  uint64
+}
+
+// newLRU creates an empty cache holding at most capacity entries
+func newLRU(capacity int) *lru {
+	return &lru{
+		capacity: capacity,
+		items:    make(map[string]*list.Element),
+		order:    list.New(),
+		byUser:   make(map[string]map[string]struct{}),
+	}
+}
+
+// get returns the entry stored under key and marks it most recently used
+func (c *lru) get(key string) (entry, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return entry{}, false
+	}
+	c.order.MoveToFront(el)
+	return el.Value.(*item).entry, true
+}
+
+// currentGeneration returns the generation to pass to add for a lookup about to start
+func (c *lru) currentGeneration() uint64 {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.generation
+}
+
+// add stores e under key unless something was removed since generation was read,
+// evicting the least recently used entry when the cache is full
+func (c *lru) add(key string, e entry, generation uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if generation != c.generation {
+		return
+	}
+
+	if el, ok := c.items[key]; ok {
+		c.unlink(el)
+	}
+
+	el := c.order.PushFront(&item{key: key, entry: e})
+	c.items[key] = el
+	if e.found {
+		keys, ok := c.byUser[e.snapshot.ID]
+		if !ok {
+			keys = make(map[string]struct{})
+			c.byUser[e.snapshot.ID] = keys
+		}
+		keys[key] = struct{}{}
+	}
+
+	for c.order.Len() > c.capacity {
+		c.unlink(c.order.Back())
+		c.evictions++
+	}
+}
+
+// remove drops the entry stored under key
+func (c *lru) remove(key string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	if el, ok := c.items[key]; ok {
+		c.unlink(el)
+	}
+}
+
+// removeUser drops every entry holding the user with the given ID
+func (c *lru) removeUser(id string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.generation++
+	for key := range c.byUser[id] {
+		c.unlink(c.items[key])
+	}
+}
+
+// len returns the number of entries and how many have been evicted to make room
+func (c *lru) len() (size int, evictions uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.order.Len(), c.evictions
+}
+
+// unlink removes an element from the list and both indexes; c.mu must be held
+func (c *lru) unlink(el *list.Element) {
+	it := el.Value.(*item)
+	c.order.Remove(el)
+	delete(c.items, it.key)
+
+	if it.entry.found {
+		keys := c.byUser[it.entry.snapshot.ID]
+		delete(keys, it.key)
+		if len(keys) == 0 {
+			delete(c.byUser, it.entry.snapshot.ID)
+		}
+	}
+}
@@ -0,0 +1,318 @@
+package cache
+
+import (
+	"context"
+	"log/slog"
+	"sync/atomic"
+	"time"
+
+	"github.com/captain-corgi/go-graphql-example/internal/domain/errors"
+	"github.com/captain-corgi/go-graphql-example/internal/domain/user"
+	"github.com/captain-corgi/go-graphql-example/internal/infrastructure/config"
+	"github.com/captain-corgi/go-graphql-example/internal/infrastructure/database"
+)
+
+// Stats counts how cached lookups were answered
+type Stats struct {
+	// Hits were answered from a fresh entry, found or not
+	Hits uint64
+	// Misses went to the backing store
+	Misses uint64
+	// StaleHits were answered from an expired entry because the backing store failed
+	StaleHits uint64
+	// Evictions dropped the least recently used entry to make room
+	Evictions uint64
+	// Size is the number of entries currently cached
+	Size int
+}
+
+// UserRepository is a read-through cache in front of another user.Repository
+// FindByID, FindByIDs and FindByEmail are served from a bounded LRU with TTL, including lookups
+// that found nothing; FindByIDs shares FindByID's entries and only loads the IDs it misses. Writes drop the entries they affect; other methods pass straight through.
+// Lookups inside a transaction bypass the cache so uncommitted state is never cached, and writes
+// inside one drop their entries once it commits, so a lookup racing the commit cannot keep the old row.
+// Each process has its own cache, so other instances' writes show up once entries expire.
+type UserRepository struct {
+	user.Repository
+	entries   *lru
+	cfg       config.CacheConfig
+	now       func() time.Time
+	hits      atomic.Uint64
+	misses    atomic.Uint64
+	staleHits atomic.Uint64
+	logger    *slog.Logger
+}
+
+// NewUserRepository wraps next in a cache configured by cfg
+func NewUserRepository(next user.Repository, cfg config.CacheConfig, logger *slog.Logger) *UserRepository {
+	return &UserRepository{
+		Repository: next,
+		entries:    newLRU(cfg.Size),
+		cfg:        cfg,
+		now:        time.Now,
+		logger:     logger,
+	}
+}
+
+// Stats returns the cache counters
+func (r *UserRepository) Stats() Stats {
+	size, evictions := r.entries.len()
+	return Stats{
+		Hits:      r.hits.Load(),
+		Misses:    r.misses.Load(),
+		StaleHits: r.staleHits.Load(),
+		Evictions: evictions,
+		Size:      size,
+	}
+}
+
+// FindByID retrieves a user by their ID, from the cache when possible
+func (r *UserRepository) FindByID(ctx context.Context, id user.UserID) (*user.User, error) {
+	return r.lookup(ctx, idKey(id.String()), func() (*user.User, error) {
+		return r.Repository.FindByID(ctx, id)
+	})
+}
+
+// FindByIDs retrieves the users with the given IDs, loading only those not cached in a single call
+// When that call fails and stale-if-error is enabled, it is answered from entries expired less than
+// MaxStale ago, provided every missed ID has one
+func (r *UserRepository) FindByIDs(ctx context.Context, ids []user.UserID) ([]*user.User, error) {
+	if _, ok := database.TxFromContext(ctx); ok {
+		return r.Repository.FindByIDs(ctx, ids)
+	}
+
+	now := r.now()
+	users := make([]*user.User, 0, len(ids))
+	var missed []user.UserID
+	var expired []entry
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		key := idKey(id.String())
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+
+		cached, ok := r.entries.get(key)
+		if ok && now.Before(cached.expires) {
+			r.hits.Add(1)
+			var err error
+			if users, err = appendFound(users, cached); err != nil {
+				return nil, err
+			}
+			continue
+		}
+
+		missed = append(missed, id)
+		if ok {
+			expired = append(expired, cached)
+		}
+	}
+	if len(missed) == 0 {
+		return users, nil
+	}
+
+	r.misses.Add(uint64(len(missed)))
+	generation := r.entries.currentGeneration()
+
+	loaded, err := r.Repository.FindByIDs(ctx, missed)
+	if err != nil {
+		if !r.cfg.StaleIfError || len(expired) < len(missed) {
+			return nil, err
+		}
+		for _, cached := range expired {
+			if !now.Before(cached.expires.Add(r.cfg.MaxStale)) {
+				return nil, err
+			}
+		}
+
+		r.staleHits.Add(uint64(len(expired)))
+		r.logger.WarnContext(ctx, "Serving stale cached users because the store failed", "count", len(expired), "error", err)
+		for _, cached := range expired {
+			if users, err = appendFound(users, cached); err != nil {
+				return nil, err
+			}
+		}
+		return users, nil
+	}
+
+	found := make(map[string]bool, len(loaded))
+	for _, u := range loaded {
+		found[u.ID().String()] = true
+		r.entries.add(idKey(u.ID().String()), entry{snapshot: u.Snapshot(), found: true, expires: now.Add(r.cfg.TTL)}, generation)
+	}
+	if r.cfg.NegativeTTL > 0 {
+		for _, id := range missed {
+			if !found[id.String()] {
+				r.entries.add(idKey(id.String()), entry{expires: now.Add(r.cfg.NegativeTTL)}, generation)
+			}
+		}
+	}
+
+	return append(users, loaded...), nil
+}
+
+// FindByEmail retrieves a user by their email address, from the cache when possible
+func (r *UserRepository) FindByEmail(ctx context.Context, email user.Email) (*user.User, error) {
+	return r.lookup(ctx, emailKey(email.String()), func() (*user.User, error) {
+		return r.Repository.FindByEmail(ctx, email)
+	})
+}
+
+// Create persists a new user and forgets that its ID and email were not found
+func (r *UserRepository) Create(ctx context.Context, u *user.User) error {
+	if err := r.Repository.Create(ctx, u); err != nil {
+		return err
+	}
+	r.forget(ctx, idKey(u.ID().String()), emailKey(u.Email().String()))
+	return nil
+}
+
+// Update modifies an existing user and drops its cached lookups, including under its new email
+func (r *UserRepository) Update(ctx context.Context, u *user.User) error {
+	if err := r.Repository.Update(ctx, u); err != nil {
+		return err
+	}
+	r.forgetUser(ctx, u.ID().String())
+	r.forget(ctx, emailKey(u.Email().String()))
+	return nil
+}
+
+// UpdateStatus stores the user's new status and drops its cached lookups
+func (r *UserRepository) UpdateStatus(ctx context.Context, u *user.User, t user.StatusTransition) error {
+	if err := r.Repository.UpdateStatus(ctx, u, t); err != nil {
+		return err
+	}
+	r.forgetUser(ctx, u.ID().String())
+	return nil
+}
+
+// Delete soft-deletes a user and drops its cached lookups
+func (r *UserRepository) Delete(ctx context.Context, u *user.User) error {
+	if err := r.Repository.Delete(ctx, u); err != nil {
+		return err
+	}
+	r.forgetUser(ctx, u.ID().String())
+	return nil
+}
+
+// Restore brings a soft-deleted user back and forgets that its ID and email were not found
+func (r *UserRepository) Restore(ctx context.Context, u *user.User) error {
+	if err := r.Repository.Restore(ctx, u); err != nil {
+		return err
+	}
+	r.forget(ctx, idKey(u.ID().String()), emailKey(u.Email().String()))
+	return nil
+}
+
+// Purge permanently removes a user and drops its cached lookups
+func (r *UserRepository) Purge(ctx context.Context, u *user.User) error {
+	if err := r.Repository.Purge(ctx, u); err != nil {
+		return err
+	}
+	r.forgetUser(ctx, u.ID().String())
+	return nil
+}
+
+// Imports wraps next, the bulk import side of the store behind the cache, so that users
+// it creates are no longer cached as not found
+func (r *UserRepository) Imports(next user.ImportRepository) user.ImportRepository {
+	return &importRepository{ImportRepository: next, cache: r}
+}
+
+// importRepository forgets the cached misses of the users a bulk import creates
+type importRepository struct {
+	user.ImportRepository
+	cache *UserRepository
+}
+
+// CreateBatch persists new users and forgets that their IDs and emails were not found
+func (r *importRepository) CreateBatch(ctx context.Context, users []*user.User) error {
+	if err := r.ImportRepository.CreateBatch(ctx, users); err != nil {
+		return err
+	}
+	for _, u := range users {
+		r.cache.forget(ctx, idKey(u.ID().String()), emailKey(u.Email().String()))
+	}
+	return nil
+}
+
+// forget drops the entries under keys once the write made with ctx has committed
+func (r *UserRepository) forget(ctx context.Context, keys ...string) {
+	database.AfterCommit(ctx, func() {
+		for _, key := range keys {
+			r.entries.remove(key)
+		}
+	})
+}
+
+// forgetUser drops every entry holding the user once the write made with ctx has committed
+func (r *UserRepository) forgetUser(ctx context.Context, id string) {
+	database.AfterCommit(ctx, func() {
+		r.entries.removeUser(id)
+	})
+}
+
+// lookup answers a lookup from a fresh entry, or loads and caches it
+// When loading fails and stale-if-error is enabled, an entry expired less than MaxStale ago is served instead
+func (r *UserRepository) lookup(ctx context.Context, key string, load func() (*user.User, error)) (*user.User, error) {
+	if _, ok := database.TxFromContext(ctx); ok {
+		return load()
+	}
+
+	now := r.now()
+	cached, ok := r.entries.get(key)
+	if ok && now.Before(cached.expires) {
+		r.hits.Add(1)
+		return cached.user()
+	}
+
+	r.misses.Add(1)
+	generation := r.entries.currentGeneration()
+
+	u, err := load()
+	switch {
+	case err == nil:
+		r.entries.add(key, entry{snapshot: u.Snapshot(), found: true, expires: now.Add(r.cfg.TTL)}, generation)
+	case err == errors.ErrUserNotFound:
+		if r.cfg.NegativeTTL > 0 {
+			r.entries.add(key, entry{expires: now.Add(r.cfg.NegativeTTL)}, generation)
+		}
+	case ok && r.cfg.StaleIfError && now.Before(cached.expires.Add(r.cfg.MaxStale)):
+		r.staleHits.Add(1)
+		r.logger.WarnContext(ctx, "Serving stale cached user because the store failed", "key", key, "error", err)
+		return cached.user()
+	}
+
+	return u, err
+}
+
+// user rebuilds the cached result, giving every caller its own aggregate
+func (e entry) user() (*user.User, error) {
+	if !e.found {
+		return nil, errors.ErrUserNotFound
+	}
+	return user.NewUserFromSnapshot(e.snapshot)
+}
+
+// appendFound appends the user an entry holds to users; entries that found nothing add none
+func appendFound(users []*user.User, e entry) ([]*user.User, error) {
+	if !e.found {
+		return users, nil
+	}
+	u, err := e.user()
+	if err != nil {
+		return nil, err
+	}
+	return append(users, u), nil
+}
+
+// idKey is the cache key of a lookup by ID
+func idKey(id string) string {
+	return "id:" + id
+}
+
+// emailKey is the cache key of a lookup by email
+func emailKey(email string) string {
+	return "email:" + email
+}
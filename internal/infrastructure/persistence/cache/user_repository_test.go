@@ -0,0 +1,557 @@
+package cache
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/captain-corgi/go-graphql-example/internal/application/unitofwork"
+	appUser "github.com/captain-corgi/go-graphql-example/internal/application/user"
+	"github.com/captain-corgi/go-graphql-example/internal/domain/errors"
+	domainEvents "github.com/captain-corgi/go-graphql-example/internal/domain/events"
+	"github.com/captain-corgi/go-graphql-example/internal/domain/user"
+	"github.com/captain-corgi/go-graphql-example/internal/domain/user/mocks"
+	"github.com/captain-corgi/go-graphql-example/internal/infrastructure/config"
+	"github.com/captain-corgi/go-graphql-example/internal/infrastructure/database"
+	"github.com/captain-corgi/go-graphql-example/internal/infrastructure/persistence/memory"
+	"github.com/captain-corgi/go-graphql-example/internal/infrastructure/persistence/repotest"
+	"github.com/captain-corgi/go-graphql-example/internal/infrastructure/pubsub"
+)
+
+// testCacheConfig returns an enabled cache configuration for tests
+func testCacheConfig() config.CacheConfig {
+	return config.CacheConfig{
+		Enabled:     true,
+		Size:        100,
+		TTL:         30 * time.Second,
+		NegativeTTL: 5 * time.Second,
+		MaxStale:    5 * time.Minute,
+	}
+}
+
+// fakeClock is a controllable time source
+type fakeClock struct {
+	now time.Time
+}
+
+func newFakeClock() *fakeClock {
+	return &fakeClock{now: time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)}
+}
+
+func (c *fakeClock) Now() time.Time          { return c.now }
+func (c *fakeClock) Advance(d time.Duration) { c.now = c.now.Add(d) }
+
+// withClock makes repo read time from clock
+func withClock(repo *UserRepository, clock *fakeClock) *UserRepository {
+	repo.now = clock.Now
+	return repo
+}
+
+// newTestUser returns a valid user for tests
+func newTestUser(t *testing.T, email string) *user.User {
+	t.Helper()
+	u, err := user.NewUser(email, "Alice")
+	require.NoError(t, err)
+	return u
+}
+
+// TestUserRepositoryContract runs the shared repository behavior suite through the cache
+func TestUserRepositoryContract(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) user.Repository {
+		return NewUserRepository(memory.NewUserRepository(slog.Default()), testCacheConfig(), slog.Default())
+	})
+}
+
+func TestUserRepository_FindByIDHitsAfterMiss(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	u := newTestUser(t, "alice@example.com")
+	inner := mocks.NewMockRepository(ctrl)
+	inner.EXPECT().FindByID(ctx, u.ID()).Return(u, nil).Times(1)
+
+	repo := NewUserRepository(inner, testCacheConfig(), slog.Default())
+
+	for i := 0; i < 3; i++ {
+		found, err := repo.FindByID(ctx, u.ID())
+		require.NoError(t, err)
+		assert.Equal(t, u.Email(), found.Email())
+	}
+
+	stats := repo.Stats()
+	assert.Equal(t, uint64(2), stats.Hits)
+	assert.Equal(t, uint64(1), stats.Misses)
+	assert.Equal(t, 1, stats.Size)
+}
+
+func TestUserRepository_FindByIDsLoadsOnlyMisses(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	alice := newTestUser(t, "alice@example.com")
+	bob := newTestUser(t, "bob@example.com")
+	missing := user.GenerateUserID()
+	inner := mocks.NewMockRepository(ctrl)
+	inner.EXPECT().FindByID(ctx, alice.ID()).Return(alice, nil).Times(1)
+	inner.EXPECT().FindByIDs(ctx, []user.UserID{bob.ID(), missing}).Return([]*user.User{bob}, nil).Times(1)
+
+	repo := NewUserRepository(inner, testCacheConfig(), slog.Default())
+
+	_, err := repo.FindByID(ctx, alice.ID())
+	require.NoError(t, err)
+
+	// Alice comes from FindByID's entry; Bob and the missing ID are loaded together, then cached
+	for i := 0; i < 2; i++ {
+		found, err := repo.FindByIDs(ctx, []user.UserID{alice.ID(), bob.ID(), missing, bob.ID()})
+		require.NoError(t, err)
+		require.Len(t, found, 2)
+		assert.ElementsMatch(t, []user.UserID{alice.ID(), bob.ID()}, []user.UserID{found[0].ID(), found[1].ID()})
+	}
+
+	// FindByID shares the entries FindByIDs filled
+	found, err := repo.FindByID(ctx, bob.ID())
+	require.NoError(t, err)
+	assert.Equal(t, bob.ID(), found.ID())
+
+	stats := repo.Stats()
+	assert.Equal(t, uint64(3), stats.Misses)
+	assert.Equal(t, uint64(5), stats.Hits)
+}
+
+func TestUserRepository_FindByIDsStaleIfError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	clock := newFakeClock()
+	alice := newTestUser(t, "alice@example.com")
+	bob := newTestUser(t, "bob@example.com")
+	storeErr := errors.ErrRepositoryConnection
+	inner := mocks.NewMockRepository(ctrl)
+	gomock.InOrder(
+		inner.EXPECT().FindByIDs(ctx, []user.UserID{alice.ID()}).Return([]*user.User{alice}, nil),
+		inner.EXPECT().FindByIDs(ctx, []user.UserID{alice.ID()}).Return(nil, storeErr),
+		inner.EXPECT().FindByIDs(ctx, []user.UserID{alice.ID(), bob.ID()}).Return(nil, storeErr),
+	)
+
+	cfg := testCacheConfig()
+	cfg.StaleIfError = true
+	repo := withClock(NewUserRepository(inner, cfg, slog.Default()), clock)
+
+	_, err := repo.FindByIDs(ctx, []user.UserID{alice.ID()})
+	require.NoError(t, err)
+	clock.Advance(31 * time.Second)
+
+	found, err := repo.FindByIDs(ctx, []user.UserID{alice.ID()})
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, alice.ID(), found[0].ID())
+	assert.Equal(t, uint64(1), repo.Stats().StaleHits)
+
+	// Bob was never cached, so the failure cannot be covered
+	_, err = repo.FindByIDs(ctx, []user.UserID{alice.ID(), bob.ID()})
+	assert.Equal(t, storeErr, err)
+}
+
+func TestUserRepository_ServiceBatchLookupsUseCache(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	alice := newTestUser(t, "alice@example.com")
+	missing := user.GenerateUserID()
+	inner := mocks.NewMockRepository(ctrl)
+	inner.EXPECT().FindByIDs(gomock.Any(), []user.UserID{alice.ID(), missing}).Return([]*user.User{alice}, nil).Times(1)
+
+	logger := slog.Default()
+	repo := NewUserRepository(inner, testCacheConfig(), logger)
+	service := appUser.NewService(repo, nil, nil, appUser.NewCursorCodec([]byte("test-only-cursor-secret-0123456789abcdef")),
+		pubsub.NewBroker[*appUser.UserEventDTO](1, logger), domainEvents.NewDomainEventDispatcher(logger), unitofwork.Passthrough{}, logger)
+
+	// The batch lookups node and loader resolution make are answered from the cache the second time
+	for i := 0; i < 2; i++ {
+		resp, err := service.GetUsersByIDs(context.Background(), appUser.GetUsersByIDsRequest{
+			IDs: []string{alice.ID().String(), missing.String()},
+		})
+		require.NoError(t, err)
+		require.Empty(t, resp.Errors)
+		require.Len(t, resp.Users, 1)
+		assert.Equal(t, alice.ID().String(), resp.Users[0].ID)
+	}
+	assert.Equal(t, uint64(2), repo.Stats().Hits)
+}
+
+func TestUserRepository_ReturnsCopies(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	u := newTestUser(t, "alice@example.com")
+	inner := mocks.NewMockRepository(ctrl)
+	inner.EXPECT().FindByID(ctx, u.ID()).Return(u, nil)
+
+	repo := NewUserRepository(inner, testCacheConfig(), slog.Default())
+
+	first, err := repo.FindByID(ctx, u.ID())
+	require.NoError(t, err)
+	require.NoError(t, first.UpdateName("Changed By Reader"))
+
+	again, err := repo.FindByID(ctx, u.ID())
+	require.NoError(t, err)
+	assert.Equal(t, "Alice", again.Name().String())
+}
+
+func TestUserRepository_CachesNotFound(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	clock := newFakeClock()
+	email, err := user.NewEmail("missing@example.com")
+	require.NoError(t, err)
+
+	inner := mocks.NewMockRepository(ctrl)
+	inner.EXPECT().FindByEmail(ctx, email).Return(nil, errors.ErrUserNotFound).Times(2)
+
+	repo := withClock(NewUserRepository(inner, testCacheConfig(), slog.Default()), clock)
+
+	_, err = repo.FindByEmail(ctx, email)
+	assert.Equal(t, errors.ErrUserNotFound, err)
+	_, err = repo.FindByEmail(ctx, email)
+	assert.Equal(t, errors.ErrUserNotFound, err)
+
+	clock.Advance(5 * time.Second)
+	_, err = repo.FindByEmail(ctx, email)
+	assert.Equal(t, errors.ErrUserNotFound, err)
+
+	stats := repo.Stats()
+	assert.Equal(t, uint64(1), stats.Hits)
+	assert.Equal(t, uint64(2), stats.Misses)
+}
+
+func TestUserRepository_NegativeCachingDisabled(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	id := user.GenerateUserID()
+	inner := mocks.NewMockRepository(ctrl)
+	inner.EXPECT().FindByID(ctx, id).Return(nil, errors.ErrUserNotFound).Times(2)
+
+	cfg := testCacheConfig()
+	cfg.NegativeTTL = 0
+	repo := NewUserRepository(inner, cfg, slog.Default())
+
+	for i := 0; i < 2; i++ {
+		_, err := repo.FindByID(ctx, id)
+		assert.Equal(t, errors.ErrUserNotFound, err)
+	}
+}
+
+func TestUserRepository_EntriesExpire(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	clock := newFakeClock()
+	u := newTestUser(t, "alice@example.com")
+	inner := mocks.NewMockRepository(ctrl)
+	inner.EXPECT().FindByID(ctx, u.ID()).Return(u, nil).Times(2)
+
+	repo := withClock(NewUserRepository(inner, testCacheConfig(), slog.Default()), clock)
+
+	_, err := repo.FindByID(ctx, u.ID())
+	require.NoError(t, err)
+	clock.Advance(29 * time.Second)
+	_, err = repo.FindByID(ctx, u.ID())
+	require.NoError(t, err)
+	clock.Advance(time.Second)
+	_, err = repo.FindByID(ctx, u.ID())
+	require.NoError(t, err)
+}
+
+func TestUserRepository_WritesInvalidate(t *testing.T) {
+	tests := []struct {
+		name  string
+		write func(t *testing.T, ctx context.Context, repo user.Repository, u *user.User) error
+	}{
+		{"update", func(t *testing.T, ctx context.Context, repo user.Repository, u *user.User) error {
+			require.NoError(t, u.UpdateName("Alicia"))
+			return repo.Update(ctx, u)
+		}},
+		{"update status", func(t *testing.T, ctx context.Context, repo user.Repository, u *user.User) error {
+			transition, err := u.ChangeStatus(user.StatusSuspended, "admin", "spam")
+			require.NoError(t, err)
+			return repo.UpdateStatus(ctx, u, transition)
+		}},
+		{"delete", func(t *testing.T, ctx context.Context, repo user.Repository, u *user.User) error {
+			require.NoError(t, u.Delete())
+			return repo.Delete(ctx, u)
+		}},
+		{"purge", func(t *testing.T, ctx context.Context, repo user.Repository, u *user.User) error {
+			return repo.Purge(ctx, u)
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			inner := memory.NewUserRepository(slog.Default())
+			repo := NewUserRepository(inner, testCacheConfig(), slog.Default())
+
+			u := newTestUser(t, "alice@example.com")
+			require.NoError(t, repo.Create(ctx, u))
+			_, err := repo.FindByID(ctx, u.ID())
+			require.NoError(t, err)
+			_, err = repo.FindByEmail(ctx, u.Email())
+			require.NoError(t, err)
+			require.Equal(t, 2, repo.Stats().Size)
+
+			require.NoError(t, tt.write(t, ctx, repo, u))
+			assert.Zero(t, repo.Stats().Size)
+
+			want, wantErr := inner.FindByID(ctx, u.ID())
+			got, err := repo.FindByID(ctx, u.ID())
+			assert.Equal(t, wantErr, err)
+			if wantErr == nil {
+				assert.Equal(t, want.Snapshot(), got.Snapshot())
+			}
+		})
+	}
+}
+
+func TestUserRepository_UpdateForgetsNewEmailNotFound(t *testing.T) {
+	ctx := context.Background()
+	repo := NewUserRepository(memory.NewUserRepository(slog.Default()), testCacheConfig(), slog.Default())
+
+	u := newTestUser(t, "alice@example.com")
+	require.NoError(t, repo.Create(ctx, u))
+
+	newEmail, err := user.NewEmail("alicia@example.com")
+	require.NoError(t, err)
+	_, err = repo.FindByEmail(ctx, newEmail)
+	require.Equal(t, errors.ErrUserNotFound, err)
+
+	require.NoError(t, u.UpdateEmail("alicia@example.com"))
+	require.NoError(t, repo.Update(ctx, u))
+
+	found, err := repo.FindByEmail(ctx, newEmail)
+	require.NoError(t, err)
+	assert.Equal(t, u.ID(), found.ID())
+
+	_, err = repo.FindByEmail(ctx, mustEmail(t, "alice@example.com"))
+	assert.Equal(t, errors.ErrUserNotFound, err)
+}
+
+func TestUserRepository_CreateAndRestoreForgetNotFound(t *testing.T) {
+	ctx := context.Background()
+	repo := NewUserRepository(memory.NewUserRepository(slog.Default()), testCacheConfig(), slog.Default())
+
+	u := newTestUser(t, "alice@example.com")
+	_, err := repo.FindByID(ctx, u.ID())
+	require.Equal(t, errors.ErrUserNotFound, err)
+	_, err = repo.FindByEmail(ctx, u.Email())
+	require.Equal(t, errors.ErrUserNotFound, err)
+
+	require.NoError(t, repo.Create(ctx, u))
+	_, err = repo.FindByID(ctx, u.ID())
+	require.NoError(t, err)
+	_, err = repo.FindByEmail(ctx, u.Email())
+	require.NoError(t, err)
+
+	require.NoError(t, u.Delete())
+	require.NoError(t, repo.Delete(ctx, u))
+	_, err = repo.FindByID(ctx, u.ID())
+	require.Equal(t, errors.ErrUserNotFound, err)
+	_, err = repo.FindByEmail(ctx, u.Email())
+	require.Equal(t, errors.ErrUserNotFound, err)
+
+	require.NoError(t, repo.Restore(ctx, u))
+	_, err = repo.FindByID(ctx, u.ID())
+	assert.NoError(t, err)
+	_, err = repo.FindByEmail(ctx, u.Email())
+	assert.NoError(t, err)
+}
+
+func TestUserRepository_ImportsForgetNotFound(t *testing.T) {
+	ctx := context.Background()
+	store := memory.NewUserRepository(slog.Default())
+	repo := NewUserRepository(store, testCacheConfig(), slog.Default())
+	imports := repo.Imports(store.(user.ImportRepository))
+
+	u := newTestUser(t, "alice@example.com")
+	_, err := repo.FindByEmail(ctx, u.Email())
+	require.Equal(t, errors.ErrUserNotFound, err)
+
+	require.NoError(t, imports.CreateBatch(ctx, []*user.User{u}))
+	_, err = repo.FindByEmail(ctx, u.Email())
+	assert.NoError(t, err)
+}
+
+func TestUserRepository_FailedWriteKeepsEntries(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := context.Background()
+	u := newTestUser(t, "alice@example.com")
+	inner := mocks.NewMockRepository(ctrl)
+	inner.EXPECT().FindByID(ctx, u.ID()).Return(u, nil)
+	inner.EXPECT().Update(ctx, u).Return(errors.ErrUserNotFound)
+
+	repo := NewUserRepository(inner, testCacheConfig(), slog.Default())
+
+	_, err := repo.FindByID(ctx, u.ID())
+	require.NoError(t, err)
+	assert.Equal(t, errors.ErrUserNotFound, repo.Update(ctx, u))
+	assert.Equal(t, 1, repo.Stats().Size)
+}
+
+func TestUserRepository_EvictsLeastRecentlyUsed(t *testing.T) {
+	ctx := context.Background()
+	inner := memory.NewUserRepository(slog.Default())
+
+	cfg := testCacheConfig()
+	cfg.Size = 2
+	repo := NewUserRepository(inner, cfg, slog.Default())
+
+	users := make([]*user.User, 3)
+	for i := range users {
+		users[i] = newTestUser(t, fmt.Sprintf("user%d@example.com", i))
+		require.NoError(t, repo.Create(ctx, users[i]))
+	}
+
+	for _, id := range []user.UserID{users[0].ID(), users[1].ID(), users[0].ID(), users[2].ID()} {
+		_, err := repo.FindByID(ctx, id)
+		require.NoError(t, err)
+	}
+
+	stats := repo.Stats()
+	assert.Equal(t, 2, stats.Size)
+	assert.Equal(t, uint64(1), stats.Evictions)
+
+	// users[1] was least recently used, so only it has to be loaded again
+	for _, id := range []user.UserID{users[0].ID(), users[2].ID(), users[1].ID()} {
+		_, err := repo.FindByID(ctx, id)
+		require.NoError(t, err)
+	}
+	stats = repo.Stats()
+	assert.Equal(t, uint64(3), stats.Hits)
+	assert.Equal(t, uint64(4), stats.Misses)
+}
+
+func TestUserRepository_StaleIfError(t *testing.T) {
+	storeErr := errors.ErrRepositoryConnection
+
+	tests := []struct {
+		name         string
+		staleIfError bool
+		age          time.Duration
+		wantStale    bool
+	}{
+		{"disabled", false, 31 * time.Second, false},
+		{"within max stale", true, 31 * time.Second, true},
+		{"beyond max stale", true, 30*time.Second + 5*time.Minute, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctrl := gomock.NewController(t)
+			defer ctrl.Finish()
+
+			ctx := context.Background()
+			clock := newFakeClock()
+			u := newTestUser(t, "alice@example.com")
+			inner := mocks.NewMockRepository(ctrl)
+			gomock.InOrder(
+				inner.EXPECT().FindByID(ctx, u.ID()).Return(u, nil),
+				inner.EXPECT().FindByID(ctx, u.ID()).Return(nil, storeErr),
+			)
+
+			cfg := testCacheConfig()
+			cfg.StaleIfError = tt.staleIfError
+			repo := withClock(NewUserRepository(inner, cfg, slog.Default()), clock)
+
+			_, err := repo.FindByID(ctx, u.ID())
+			require.NoError(t, err)
+
+			clock.Advance(tt.age)
+			found, err := repo.FindByID(ctx, u.ID())
+			if tt.wantStale {
+				require.NoError(t, err)
+				assert.Equal(t, u.ID(), found.ID())
+				assert.Equal(t, uint64(1), repo.Stats().StaleHits)
+			} else {
+				assert.Equal(t, storeErr, err)
+				assert.Zero(t, repo.Stats().StaleHits)
+			}
+		})
+	}
+}
+
+func TestUserRepository_BypassesCacheInTransaction(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	ctx := database.WithTx(context.Background(), &sql.Tx{})
+	u := newTestUser(t, "alice@example.com")
+	inner := mocks.NewMockRepository(ctrl)
+	inner.EXPECT().FindByID(ctx, u.ID()).Return(u, nil).Times(2)
+	inner.EXPECT().FindByIDs(ctx, []user.UserID{u.ID()}).Return([]*user.User{u}, nil).Times(2)
+
+	repo := NewUserRepository(inner, testCacheConfig(), slog.Default())
+
+	for i := 0; i < 2; i++ {
+		_, err := repo.FindByID(ctx, u.ID())
+		require.NoError(t, err)
+		_, err = repo.FindByIDs(ctx, []user.UserID{u.ID()})
+		require.NoError(t, err)
+	}
+	assert.Zero(t, repo.Stats().Size)
+}
+
+func TestUserRepository_WriteInTransactionInvalidatesAfterCommit(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	before := newTestUser(t, "alice@example.com")
+	after, err := user.NewUserFromSnapshot(before.Snapshot())
+	require.NoError(t, err)
+	require.NoError(t, after.UpdateName("Alicia"))
+
+	inner := mocks.NewMockRepository(ctrl)
+	repo := NewUserRepository(inner, testCacheConfig(), slog.Default())
+
+	txCtx, commit := database.WithAfterCommit(database.WithTx(context.Background(), &sql.Tx{}))
+	inner.EXPECT().Update(txCtx, after).Return(nil)
+	require.NoError(t, repo.Update(txCtx, after))
+
+	// A lookup outside the transaction before it commits still reads the old row, and caches it
+	ctx := context.Background()
+	inner.EXPECT().FindByID(ctx, before.ID()).Return(before, nil)
+	found, err := repo.FindByID(ctx, before.ID())
+	require.NoError(t, err)
+	assert.Equal(t, "Alice", found.Name().String())
+
+	commit()
+
+	inner.EXPECT().FindByID(ctx, before.ID()).Return(after, nil)
+	found, err = repo.FindByID(ctx, before.ID())
+	require.NoError(t, err)
+	assert.Equal(t, "Alicia", found.Name().String())
+}
+
+// mustEmail parses a test email address
+func mustEmail(t *testing.T, value string) user.Email {
+	t.Helper()
+	email, err := user.NewEmail(value)
+	require.NoError(t, err)
+	return email
+}
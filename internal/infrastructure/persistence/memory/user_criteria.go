@@ -0,0 +1,130 @@
+package memory
+
+import (
+	"strings"
+	"time"
+
+	"github.com/captain-corgi/go-graphql-example/internal/domain/user"
+)
+
+// matches reports whether a stored user satisfies every condition of the filter
+func matches(s user.Snapshot, filter user.Filter) bool {
+	if !filter.IncludeDeleted && s.DeletedAt != nil {
+		return false
+	}
+	if !matchesText(s.Email, filter.Email) || !matchesText(s.Name, filter.Name) {
+		return false
+	}
+	if !inRange(s.CreatedAt, filter.CreatedAt) || !inRange(s.UpdatedAt, filter.UpdatedAt) {
+		return false
+	}
+
+	// A non-nil empty list matches no users
+	if filter.IDs != nil {
+		found := false
+		for _, id := range filter.IDs {
+			if id.String() == s.ID {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	if filter.Statuses != nil {
+		found := false
+		for _, status := range filter.Statuses {
+			if status == s.Status {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+
+	return true
+}
+
+// matchesText applies a text filter; Contains is case-insensitive like ILIKE
+func matchesText(value string, filter *user.TextFilter) bool {
+	if filter == nil {
+		return true
+	}
+	if filter.Equals != nil && value != *filter.Equals {
+		return false
+	}
+	if filter.Contains != nil && !strings.Contains(strings.ToLower(value), strings.ToLower(*filter.Contains)) {
+		return false
+	}
+	return true
+}
+
+// inRange reports whether t falls within [From, To)
+func inRange(t time.Time, r *user.TimeRange) bool {
+	if r == nil {
+		return true
+	}
+	if r.From != nil && t.Before(*r.From) {
+		return false
+	}
+	if r.To != nil && !t.Before(*r.To) {
+		return false
+	}
+	return true
+}
+
+// sortValue returns the stored user's value for the sort field, as SortField.ValueOf does
+func sortValue(s user.Snapshot, field user.SortField) interface{} {
+	switch field {
+	case user.SortByName:
+		return s.Name
+	case user.SortByEmail:
+		return s.Email
+	case user.SortByUpdatedAt:
+		return s.UpdatedAt
+	default:
+		return s.CreatedAt
+	}
+}
+
+// compareKey orders a stored user against a cursor by (sort value, id) ascending,
+// the same row comparison the SQL repository uses for its keyset
+func compareKey(s user.Snapshot, field user.SortField, cursor user.Cursor) int {
+	return compare(sortValue(s, field), s.ID, cursor.Value, cursor.ID.String())
+}
+
+// compareUsers orders two stored users by (sort value, id) ascending
+func compareUsers(a, b user.Snapshot, field user.SortField) int {
+	return compare(sortValue(a, field), a.ID, sortValue(b, field), b.ID)
+}
+
+// compare orders two (sort value, id) keys; strings compare bytewise, like the C collation
+func compare(aValue interface{}, aID string, bValue interface{}, bID string) int {
+	var c int
+	switch a := aValue.(type) {
+	case time.Time:
+		if b, ok := bValue.(time.Time); ok {
+			c = a.Compare(b)
+		}
+	case string:
+		if b, ok := bValue.(string); ok {
+			c = strings.Compare(a, b)
+		}
+	}
+	if c != 0 {
+		return c
+	}
+	return strings.Compare(aID, bID)
+}
+
+// inDirection turns an ascending comparison into one for the given direction
+func inDirection(c int, direction user.SortDirection) int {
+	if direction == user.SortDesc {
+		return -c
+	}
+	return c
+}
@@ -0,0 +1,530 @@
+package memory
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"slices"
+	"sync"
+	"time"
+
+	"github.com/captain-corgi/go-graphql-example/internal/domain/errors"
+	"github.com/captain-corgi/go-graphql-example/internal/domain/user"
+)
+
+var (
+	_ user.SearchRepository = (*userRepository)(nil)
+	_ user.ImportRepository = (*userRepository)(nil)
+)
+
+// userRepository implements the user.Repository interface in process memory
+// Users are stored as snapshots, so callers never share state with the store
+// or with each other. There is no outbox: pending domain events are left on the
+// user for the application service to dispatch in process.
+type userRepository struct {
+	mu          sync.RWMutex
+	users       map[string]user.Snapshot
+	transitions []user.StatusTransition
+	logger      *slog.Logger
+}
+
+// NewUserRepository creates an empty in-memory user repository, safe for concurrent use
+// Data lives only as long as the process. The repository also implements user.SearchRepository and user.ImportRepository
+func NewUserRepository(logger *slog.Logger) user.Repository {
+	return &userRepository{
+		users:  make(map[string]user.Snapshot),
+		logger: logger,
+	}
+}
+
+// FindByID retrieves a live user by their ID
+func (r *userRepository) FindByID(ctx context.Context, id user.UserID) (*user.User, error) {
+	r.logger.DebugContext(ctx, "Finding user by ID", "user_id", id.String())
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	s, ok := r.live(id.String())
+	if !ok {
+		r.logger.DebugContext(ctx, "User not found", "user_id", id.String())
+		return nil, errors.ErrUserNotFound
+	}
+
+	return r.load(ctx, s)
+}
+
+// FindByIDs retrieves the live users with the given IDs
+func (r *userRepository) FindByIDs(ctx context.Context, ids []user.UserID) ([]*user.User, error) {
+	r.logger.DebugContext(ctx, "Finding users by IDs", "count", len(ids))
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	users := make([]*user.User, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		s, ok := r.live(id.String())
+		if !ok || seen[s.ID] {
+			continue
+		}
+		seen[s.ID] = true
+
+		domainUser, err := r.load(ctx, s)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, domainUser)
+	}
+
+	return users, nil
+}
+
+// FindByEmail retrieves a live user by their email address
+func (r *userRepository) FindByEmail(ctx context.Context, email user.Email) (*user.User, error) {
+	r.logger.DebugContext(ctx, "Finding user by email", "email", email.String())
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	s, ok := r.liveByEmail(email.String())
+	if !ok {
+		r.logger.DebugContext(ctx, "User not found by email", "email", email.String())
+		return nil, errors.ErrUserNotFound
+	}
+
+	return r.load(ctx, s)
+}
+
+// FindAll retrieves a keyset page of the users matching the criteria
+func (r *userRepository) FindAll(ctx context.Context, criteria user.Criteria, page user.PageRequest) (*user.Page, error) {
+	r.logger.DebugContext(ctx, "Finding all users",
+		"first", page.First,
+		"last", page.Last,
+		"has_after", page.After != nil,
+		"has_before", page.Before != nil,
+		"sort_field", criteria.Sort.Field,
+		"sort_direction", criteria.Sort.Direction,
+	)
+
+	if !criteria.Sort.Field.IsValid() {
+		return nil, errors.ErrInvalidSort
+	}
+
+	field, direction := criteria.Sort.Field, criteria.Sort.Direction
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	// Collect the window between the cursors in sort order, and note whether
+	// matching users lie at or beyond either cursor
+	var window []user.Snapshot
+	var atOrBeforeAfter, atOrAfterBefore bool
+	for _, s := range r.users {
+		if !matches(s, criteria.Filter) {
+			continue
+		}
+		inWindow := true
+		if page.After != nil && inDirection(compareKey(s, field, *page.After), direction) <= 0 {
+			atOrBeforeAfter = true
+			inWindow = false
+		}
+		if page.Before != nil && inDirection(compareKey(s, field, *page.Before), direction) >= 0 {
+			atOrAfterBefore = true
+			inWindow = false
+		}
+		if inWindow {
+			window = append(window, s)
+		}
+	}
+
+	slices.SortFunc(window, func(a, b user.Snapshot) int {
+		return inDirection(compareUsers(a, b, field), direction)
+	})
+
+	result := &user.Page{}
+	if page.IsBackward() {
+		// Take the window's tail; anything at or past the before cursor is on the next page
+		start := max(len(window)-page.Last, 0)
+		window = window[start:]
+		result.HasPreviousPage = start > 0
+		result.HasNextPage = atOrAfterBefore
+	} else {
+		// Take the window's head; anything at or ahead of the after cursor is on the previous page
+		end := min(max(page.First, 0), len(window))
+		result.HasNextPage = len(window) > end
+		window = window[:end]
+		result.HasPreviousPage = atOrBeforeAfter
+	}
+
+	for _, s := range window {
+		domainUser, err := r.load(ctx, s)
+		if err != nil {
+			return nil, err
+		}
+		result.Users = append(result.Users, domainUser)
+	}
+
+	r.logger.DebugContext(ctx, "Successfully found users",
+		"count", len(result.Users),
+		"has_next_page", result.HasNextPage,
+		"has_previous_page", result.HasPreviousPage,
+	)
+	return result, nil
+}
+
+// Create stores a new user
+func (r *userRepository) Create(ctx context.Context, u *user.User) error {
+	r.logger.DebugContext(ctx, "Creating user", "user_id", u.ID().String(), "email", u.Email().String())
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.users[u.ID().String()]; ok {
+		r.logger.ErrorContext(ctx, "Failed to create user", "error", "duplicate ID", "user_id", u.ID().String())
+		return fmt.Errorf("failed to create user: user %s already exists", u.ID().String())
+	}
+
+	if _, taken := r.liveByEmail(u.Email().String()); taken {
+		r.logger.WarnContext(ctx, "Duplicate email constraint violation", "email", u.Email().String())
+		return errors.ErrDuplicateEmail
+	}
+
+	r.users[u.ID().String()] = u.Snapshot()
+
+	r.logger.InfoContext(ctx, "Successfully created user", "user_id", u.ID().String(), "email", u.Email().String())
+	return nil
+}
+
+// ExistingEmails returns those of the given emails already held by live users
+func (r *userRepository) ExistingEmails(ctx context.Context, emails []user.Email) ([]user.Email, error) {
+	r.logger.DebugContext(ctx, "Finding existing emails", "count", len(emails))
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var existing []user.Email
+	for _, email := range emails {
+		if _, taken := r.liveByEmail(email.String()); taken {
+			existing = append(existing, email)
+		}
+	}
+	return existing, nil
+}
+
+// CreateBatch persists new users, all of them or none
+func (r *userRepository) CreateBatch(ctx context.Context, users []*user.User) error {
+	r.logger.DebugContext(ctx, "Creating users in bulk", "count", len(users))
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	// Check the whole batch before storing any of it
+	emails := make(map[string]bool, len(users))
+	for _, u := range users {
+		if _, ok := r.users[u.ID().String()]; ok {
+			r.logger.ErrorContext(ctx, "Failed to create users in bulk", "error", "duplicate ID", "user_id", u.ID().String())
+			return fmt.Errorf("failed to create user: user %s already exists", u.ID().String())
+		}
+
+		email := u.Email().String()
+		if _, taken := r.liveByEmail(email); taken || emails[email] {
+			r.logger.WarnContext(ctx, "Duplicate email constraint violation in bulk create", "email", email)
+			return errors.ErrDuplicateEmail
+		}
+		emails[email] = true
+	}
+
+	for _, u := range users {
+		r.users[u.ID().String()] = u.Snapshot()
+	}
+
+	r.logger.InfoContext(ctx, "Successfully created users in bulk", "count", len(users))
+	return nil
+}
+
+// Update modifies an existing user if nobody else has written it since it was read
+func (r *userRepository) Update(ctx context.Context, u *user.User) error {
+	r.logger.DebugContext(ctx, "Updating user", "user_id", u.ID().String(), "email", u.Email().String())
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, err := r.versioned(ctx, u)
+	if err != nil {
+		return err
+	}
+
+	if other, taken := r.liveByEmail(u.Email().String()); taken && other.ID != stored.ID {
+		r.logger.WarnContext(ctx, "Duplicate email constraint violation during update", "email", u.Email().String())
+		return errors.ErrDuplicateEmail
+	}
+
+	stored.Email = u.Email().String()
+	stored.Name = u.Name().String()
+	stored.UpdatedAt = u.UpdatedAt()
+	stored.Version++
+	r.users[stored.ID] = stored
+
+	u.IncrementVersion()
+
+	r.logger.InfoContext(ctx, "Successfully updated user", "user_id", u.ID().String(), "email", u.Email().String())
+	return nil
+}
+
+// UpdateStatus stores the user's new status and records the transition
+func (r *userRepository) UpdateStatus(ctx context.Context, u *user.User, t user.StatusTransition) error {
+	r.logger.DebugContext(ctx, "Updating user status",
+		"user_id", u.ID().String(),
+		"from", t.From,
+		"to", t.To,
+	)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.live(u.ID().String())
+	if !ok {
+		r.logger.WarnContext(ctx, "User status not updated", "error", errors.ErrUserNotFound, "user_id", u.ID().String())
+		return errors.ErrUserNotFound
+	}
+
+	// Guard on the previous status so concurrent transitions cannot skip the table,
+	// and on the version so they cannot overwrite other writes either
+	if stored.Status != t.From {
+		err := user.NewInvalidStatusTransitionError(stored.Status, t.To)
+		r.logger.WarnContext(ctx, "User status not updated", "error", err, "user_id", u.ID().String())
+		return err
+	}
+	if stored.Version != u.Version() {
+		err := errors.NewConflictError(stored.Version)
+		r.logger.WarnContext(ctx, "User status not updated", "error", err, "user_id", u.ID().String())
+		return err
+	}
+
+	stored.Status = t.To
+	stored.UpdatedAt = u.UpdatedAt()
+	stored.Version++
+	r.users[stored.ID] = stored
+	r.transitions = append(r.transitions, t)
+
+	u.IncrementVersion()
+
+	r.logger.InfoContext(ctx, "Successfully updated user status",
+		"user_id", u.ID().String(),
+		"from", t.From,
+		"to", t.To,
+	)
+	return nil
+}
+
+// Delete soft-deletes a live user
+func (r *userRepository) Delete(ctx context.Context, u *user.User) error {
+	r.logger.DebugContext(ctx, "Deleting user", "user_id", u.ID().String())
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.live(u.ID().String())
+	if !ok {
+		r.logger.WarnContext(ctx, "No rows affected during user delete", "user_id", u.ID().String())
+		return errors.ErrUserNotFound
+	}
+
+	deletedAt := time.Now()
+	if u.DeletedAt() != nil {
+		deletedAt = *u.DeletedAt()
+	}
+	stored.DeletedAt = &deletedAt
+	stored.Version++
+	r.users[stored.ID] = stored
+
+	u.IncrementVersion()
+
+	r.logger.InfoContext(ctx, "Successfully deleted user", "user_id", u.ID().String())
+	return nil
+}
+
+// Restore brings a soft-deleted user back
+func (r *userRepository) Restore(ctx context.Context, u *user.User) error {
+	id := u.ID()
+	r.logger.DebugContext(ctx, "Restoring user", "user_id", id.String())
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	stored, ok := r.users[id.String()]
+	if !ok {
+		r.logger.WarnContext(ctx, "No rows affected during user restore", "user_id", id.String())
+		return errors.ErrUserNotFound
+	}
+	if stored.DeletedAt == nil {
+		r.logger.WarnContext(ctx, "User to restore is not deleted", "user_id", id.String())
+		return errors.ErrUserNotDeleted
+	}
+
+	// Another live user may have taken the email while this one was deleted
+	if _, taken := r.liveByEmail(stored.Email); taken {
+		r.logger.WarnContext(ctx, "Duplicate email constraint violation during restore", "user_id", id.String())
+		return errors.ErrDuplicateEmail
+	}
+
+	stored.DeletedAt = nil
+	stored.Version++
+	r.users[stored.ID] = stored
+
+	u.IncrementVersion()
+
+	r.logger.InfoContext(ctx, "Successfully restored user", "user_id", id.String())
+	return nil
+}
+
+// Purge permanently removes a user and their status history
+func (r *userRepository) Purge(ctx context.Context, u *user.User) error {
+	id := u.ID()
+	r.logger.DebugContext(ctx, "Purging user", "user_id", id.String())
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if _, ok := r.users[id.String()]; !ok {
+		r.logger.WarnContext(ctx, "No rows affected during user purge", "user_id", id.String())
+		return errors.ErrUserNotFound
+	}
+
+	delete(r.users, id.String())
+	r.transitions = slices.DeleteFunc(r.transitions, func(t user.StatusTransition) bool {
+		return t.UserID.Equals(id)
+	})
+
+	r.logger.InfoContext(ctx, "Successfully purged user", "user_id", id.String())
+	return nil
+}
+
+// ExistsByEmail checks if a live user with the given email exists
+func (r *userRepository) ExistsByEmail(ctx context.Context, email user.Email) (bool, error) {
+	r.logger.DebugContext(ctx, "Checking if user exists by email", "email", email.String())
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	_, exists := r.liveByEmail(email.String())
+	return exists, nil
+}
+
+// Count returns the number of users matching the filter
+func (r *userRepository) Count(ctx context.Context, filter user.Filter) (int64, error) {
+	r.logger.DebugContext(ctx, "Counting users")
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	var count int64
+	for _, s := range r.users {
+		if matches(s, filter) {
+			count++
+		}
+	}
+
+	return count, nil
+}
+
+// Search ranks the live users against the query in process
+// Ranks approximate those of the Postgres store; see user.SearchQuery.Rank
+func (r *userRepository) Search(ctx context.Context, query user.SearchQuery) (*user.SearchPage, error) {
+	r.logger.DebugContext(ctx, "Searching users", "first", query.First, "has_after", query.After != nil)
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	users := make([]*user.User, 0, len(r.users))
+	for _, s := range r.users {
+		if s.DeletedAt != nil {
+			continue
+		}
+		domainUser, err := r.load(ctx, s)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, domainUser)
+	}
+
+	return query.RankUsers(users), nil
+}
+
+// CountSignups returns the number of users created in each UTC interval of the query range
+// Soft-deleted users are included: they still signed up
+func (r *userRepository) CountSignups(ctx context.Context, q user.SignupStatsQuery) ([]user.SignupBucket, error) {
+	r.logger.DebugContext(ctx, "Counting signups",
+		"from", q.From,
+		"to", q.To,
+		"interval", q.Interval,
+	)
+
+	if !q.Interval.IsValid() {
+		return nil, errors.ErrInvalidStatsInterval
+	}
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	createdAt := make([]time.Time, 0, len(r.users))
+	for _, s := range r.users {
+		createdAt = append(createdAt, s.CreatedAt)
+	}
+
+	return q.Bucket(createdAt), nil
+}
+
+// live returns the stored user with the given ID unless it is missing or soft-deleted; r.mu must be held
+func (r *userRepository) live(id string) (user.Snapshot, bool) {
+	s, ok := r.users[id]
+	if !ok || s.DeletedAt != nil {
+		return user.Snapshot{}, false
+	}
+	return s, true
+}
+
+// liveByEmail returns the live user holding the email, if any; r.mu must be held
+func (r *userRepository) liveByEmail(email string) (user.Snapshot, bool) {
+	for _, s := range r.users {
+		if s.DeletedAt == nil && s.Email == email {
+			return s, true
+		}
+	}
+	return user.Snapshot{}, false
+}
+
+// versioned returns the stored live user if its version still equals u's; r.mu must be held
+func (r *userRepository) versioned(ctx context.Context, u *user.User) (user.Snapshot, error) {
+	stored, ok := r.live(u.ID().String())
+	if !ok {
+		r.logger.WarnContext(ctx, "No rows affected during user update", "user_id", u.ID().String())
+		return user.Snapshot{}, errors.ErrUserNotFound
+	}
+
+	if stored.Version != u.Version() {
+		r.logger.WarnContext(ctx, "User update conflicted with a concurrent write",
+			"user_id", u.ID().String(),
+			"expected_version", u.Version(),
+			"current_version", stored.Version,
+		)
+		return user.Snapshot{}, errors.NewConflictError(stored.Version)
+	}
+
+	return stored, nil
+}
+
+// load reconstructs a domain user from a stored snapshot
+func (r *userRepository) load(ctx context.Context, s user.Snapshot) (*user.User, error) {
+	if s.DeletedAt != nil {
+		t := *s.DeletedAt
+		s.DeletedAt = &t
+	}
+
+	domainUser, err := user.NewUserFromSnapshot(s)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Failed to create domain user from stored record", "error", err)
+		return nil, fmt.Errorf("failed to create domain user: %w", err)
+	}
+	return domainUser, nil
+}
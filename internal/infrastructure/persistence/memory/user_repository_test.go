@@ -0,0 +1,41 @@
+package memory
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/captain-corgi/go-graphql-example/internal/domain/user"
+	"github.com/captain-corgi/go-graphql-example/internal/infrastructure/persistence/repotest"
+)
+
+// TestUserRepositoryContract runs the shared repository behavior suite
+func TestUserRepositoryContract(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) user.Repository {
+		return NewUserRepository(slog.Default())
+	})
+}
+
+func TestUserRepository_ReturnsCopies(t *testing.T) {
+	ctx := context.Background()
+	repo := NewUserRepository(slog.Default())
+
+	created, err := user.NewUser("alice@example.com", "Alice")
+	require.NoError(t, err)
+	require.NoError(t, repo.Create(ctx, created))
+
+	// Changing the created or a loaded user must not change the store until it is saved
+	require.NoError(t, created.UpdateName("Changed By Creator"))
+	found, err := repo.FindByID(ctx, created.ID())
+	require.NoError(t, err)
+	require.NoError(t, found.UpdateName("Changed By Reader"))
+	require.NoError(t, found.Delete())
+
+	again, err := repo.FindByID(ctx, created.ID())
+	require.NoError(t, err)
+	assert.Equal(t, "Alice", again.Name().String())
+	assert.False(t, again.IsDeleted())
+}
@@ -0,0 +1,721 @@
+// Package repotest provides a behavioral test suite that every user.Repository
+// implementation must pass, so that backends are interchangeable. Backends that
+// also implement user.SearchRepository or user.ImportRepository are held to their
+// behavior as well.
+//
+// A backend's tests call Run with a factory returning an empty repository:
+//
+//	func TestUserRepositoryContract(t *testing.T) {
+//		repotest.Run(t, func(t *testing.T) user.Repository {
+//			return NewUserRepository(slog.Default())
+//		})
+//	}
+package repotest
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/suite"
+
+	"github.com/captain-corgi/go-graphql-example/internal/domain/errors"
+	"github.com/captain-corgi/go-graphql-example/internal/domain/user"
+)
+
+// Factory returns an empty repository for a single test
+// Factories release what they allocate with t.Cleanup, and skip t when the backend is unavailable
+type Factory func(t *testing.T) user.Repository
+
+// Run runs the contract suite, calling newRepository once per test
+func Run(t *testing.T, newRepository Factory) {
+	suite.Run(t, &contractSuite{newRepository: newRepository})
+}
+
+// base is the creation time of the first seeded user; whole seconds survive every backend's precision
+var base = time.Date(2025, 1, 15, 10, 0, 0, 0, time.UTC)
+
+// byName orders users by name, then id
+var byName = user.Sort{Field: user.SortByName, Direction: user.SortAsc}
+
+// contractSuite holds the behavior shared by all user.Repository implementations
+type contractSuite struct {
+	suite.Suite
+	newRepository Factory
+	repository    user.Repository
+	ctx           context.Context
+}
+
+// SetupTest gives every test an empty repository
+func (s *contractSuite) SetupTest() {
+	s.ctx = context.Background()
+	s.repository = s.newRepository(s.T())
+}
+
+// seed stores an active user created at the given time
+func (s *contractSuite) seed(email, name string, createdAt time.Time) *user.User {
+	s.T().Helper()
+
+	u, err := user.NewUserFromSnapshot(user.Snapshot{
+		ID:        user.GenerateUserID().String(),
+		Email:     email,
+		Name:      name,
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+		Status:    user.StatusActive,
+		Version:   1,
+	})
+	s.Require().NoError(err)
+	s.Require().NoError(s.repository.Create(s.ctx, u))
+	return u
+}
+
+// seedMany stores n users named "User 0".."User n-1", each an hour newer than the last
+func (s *contractSuite) seedMany(n int) []*user.User {
+	users := make([]*user.User, n)
+	for i := range users {
+		users[i] = s.seed(fmt.Sprintf("user%d@example.com", i), fmt.Sprintf("User %d", i), base.Add(time.Duration(i)*time.Hour))
+	}
+	return users
+}
+
+// load reads a live user back, failing the test if it is missing
+func (s *contractSuite) load(id user.UserID) *user.User {
+	s.T().Helper()
+
+	found, err := s.repository.FindByID(s.ctx, id)
+	s.Require().NoError(err)
+	return found
+}
+
+// ids lists the users' IDs in order
+func ids(users []*user.User) []string {
+	out := make([]string, len(users))
+	for i, u := range users {
+		out[i] = u.ID().String()
+	}
+	return out
+}
+
+func (s *contractSuite) TestCreateAndFind() {
+	created := s.seed("alice@example.com", "Alice", base)
+
+	found := s.load(created.ID())
+	s.Equal(created.ID(), found.ID())
+	s.Equal("alice@example.com", found.Email().String())
+	s.Equal("Alice", found.Name().String())
+	s.Equal(user.StatusActive, found.Status())
+	s.Equal(int64(1), found.Version())
+	s.True(base.Equal(found.CreatedAt()))
+	s.False(found.IsDeleted())
+
+	byEmail, err := s.repository.FindByEmail(s.ctx, created.Email())
+	s.Require().NoError(err)
+	s.Equal(created.ID(), byEmail.ID())
+
+	exists, err := s.repository.ExistsByEmail(s.ctx, created.Email())
+	s.Require().NoError(err)
+	s.True(exists)
+}
+
+func (s *contractSuite) TestNotFound() {
+	missing, err := user.NewUser("missing@example.com", "Missing")
+	s.Require().NoError(err)
+
+	_, err = s.repository.FindByID(s.ctx, missing.ID())
+	s.Equal(errors.ErrUserNotFound, err)
+
+	_, err = s.repository.FindByEmail(s.ctx, missing.Email())
+	s.Equal(errors.ErrUserNotFound, err)
+
+	exists, err := s.repository.ExistsByEmail(s.ctx, missing.Email())
+	s.Require().NoError(err)
+	s.False(exists)
+
+	s.Equal(errors.ErrUserNotFound, s.repository.Update(s.ctx, missing))
+	s.Equal(errors.ErrUserNotFound, s.repository.Delete(s.ctx, missing))
+	s.Equal(errors.ErrUserNotFound, s.repository.Restore(s.ctx, missing))
+	s.Equal(errors.ErrUserNotFound, s.repository.Purge(s.ctx, missing))
+
+	transition, err := missing.ChangeStatus(user.StatusSuspended, "admin", "testing")
+	s.Require().NoError(err)
+	s.Equal(errors.ErrUserNotFound, s.repository.UpdateStatus(s.ctx, missing, transition))
+}
+
+func (s *contractSuite) TestFindByIDsOmitsMissingAndDeleted() {
+	users := s.seedMany(3)
+	s.Require().NoError(s.repository.Delete(s.ctx, users[2]))
+
+	found, err := s.repository.FindByIDs(s.ctx, []user.UserID{users[0].ID(), user.GenerateUserID(), users[1].ID(), users[2].ID()})
+	s.Require().NoError(err)
+	s.ElementsMatch(ids(users[:2]), ids(found))
+
+	found, err = s.repository.FindByIDs(s.ctx, nil)
+	s.Require().NoError(err)
+	s.Empty(found)
+}
+
+func (s *contractSuite) TestCreateDuplicateEmail() {
+	s.seed("alice@example.com", "Alice", base)
+
+	duplicate, err := user.NewUser("alice@example.com", "Another Alice")
+	s.Require().NoError(err)
+	s.Equal(errors.ErrDuplicateEmail, s.repository.Create(s.ctx, duplicate))
+
+	count, err := s.repository.Count(s.ctx, user.Filter{IncludeDeleted: true})
+	s.Require().NoError(err)
+	s.Equal(int64(1), count)
+}
+
+func (s *contractSuite) TestDeletedEmailCanBeReused() {
+	alice := s.seed("alice@example.com", "Alice", base)
+	s.Require().NoError(s.repository.Delete(s.ctx, alice))
+
+	exists, err := s.repository.ExistsByEmail(s.ctx, alice.Email())
+	s.Require().NoError(err)
+	s.False(exists)
+
+	s.seed("alice@example.com", "New Alice", base)
+
+	// The original cannot come back while the email is taken
+	s.Equal(errors.ErrDuplicateEmail, s.repository.Restore(s.ctx, alice))
+}
+
+func (s *contractSuite) TestUpdate() {
+	alice := s.seed("alice@example.com", "Alice", base)
+
+	loaded := s.load(alice.ID())
+	s.Require().NoError(loaded.UpdateEmail("alice.cooper@example.com"))
+	s.Require().NoError(loaded.UpdateName("Alice Cooper"))
+	s.Require().NoError(s.repository.Update(s.ctx, loaded))
+	s.Equal(int64(2), loaded.Version())
+
+	found := s.load(alice.ID())
+	s.Equal("alice.cooper@example.com", found.Email().String())
+	s.Equal("Alice Cooper", found.Name().String())
+	s.Equal(int64(2), found.Version())
+
+	// The old email is free, the new one is taken
+	exists, err := s.repository.ExistsByEmail(s.ctx, alice.Email())
+	s.Require().NoError(err)
+	s.False(exists)
+}
+
+func (s *contractSuite) TestUpdateDuplicateEmail() {
+	alice := s.seed("alice@example.com", "Alice", base)
+	s.seed("bob@example.com", "Bob", base)
+
+	loaded := s.load(alice.ID())
+	s.Require().NoError(loaded.UpdateEmail("bob@example.com"))
+	s.Equal(errors.ErrDuplicateEmail, s.repository.Update(s.ctx, loaded))
+
+	s.Equal("alice@example.com", s.load(alice.ID()).Email().String())
+}
+
+func (s *contractSuite) TestUpdateDeletedUser() {
+	alice := s.seed("alice@example.com", "Alice", base)
+	loaded := s.load(alice.ID())
+	s.Require().NoError(s.repository.Delete(s.ctx, alice))
+
+	s.Require().NoError(loaded.UpdateName("Ghost"))
+	s.Equal(errors.ErrUserNotFound, s.repository.Update(s.ctx, loaded))
+}
+
+func (s *contractSuite) TestUpdateVersionConflict() {
+	alice := s.seed("alice@example.com", "Alice", base)
+
+	first := s.load(alice.ID())
+	stale := s.load(alice.ID())
+
+	s.Require().NoError(first.UpdateName("First Writer"))
+	s.Require().NoError(s.repository.Update(s.ctx, first))
+
+	s.Require().NoError(stale.UpdateName("Second Writer"))
+	err := s.repository.Update(s.ctx, stale)
+
+	var conflict errors.ConflictError
+	s.Require().ErrorAs(err, &conflict)
+	s.Equal(int64(2), conflict.CurrentVersion)
+	s.Equal("First Writer", s.load(alice.ID()).Name().String())
+}
+
+func (s *contractSuite) TestUpdateStatus() {
+	alice := s.seed("alice@example.com", "Alice", base)
+
+	loaded := s.load(alice.ID())
+	stale := s.load(alice.ID())
+
+	transition, err := loaded.ChangeStatus(user.StatusSuspended, "admin", "spam")
+	s.Require().NoError(err)
+	s.Require().NoError(s.repository.UpdateStatus(s.ctx, loaded, transition))
+	s.Equal(int64(2), loaded.Version())
+
+	found := s.load(alice.ID())
+	s.Equal(user.StatusSuspended, found.Status())
+	s.Equal(int64(2), found.Version())
+
+	// A transition computed from the old status is rejected for the stored one
+	transition, err = stale.ChangeStatus(user.StatusSuspended, "admin", "spam again")
+	s.Require().NoError(err)
+	err = s.repository.UpdateStatus(s.ctx, stale, transition)
+	s.Equal(user.NewInvalidStatusTransitionError(user.StatusSuspended, user.StatusSuspended), err)
+}
+
+func (s *contractSuite) TestDeleteRestorePurge() {
+	alice := s.seed("alice@example.com", "Alice", base)
+
+	s.Equal(errors.ErrUserNotDeleted, s.repository.Restore(s.ctx, alice))
+
+	s.Require().NoError(s.repository.Delete(s.ctx, alice))
+	s.Equal(int64(2), alice.Version())
+	s.Equal(errors.ErrUserNotFound, s.repository.Delete(s.ctx, alice))
+
+	_, err := s.repository.FindByID(s.ctx, alice.ID())
+	s.Equal(errors.ErrUserNotFound, err)
+
+	s.Require().NoError(s.repository.Restore(s.ctx, alice))
+	restored := s.load(alice.ID())
+	s.False(restored.IsDeleted())
+	s.Equal(int64(3), restored.Version())
+	s.Equal(int64(3), alice.Version())
+
+	s.Require().NoError(s.repository.Purge(s.ctx, alice))
+	s.Equal(errors.ErrUserNotFound, s.repository.Purge(s.ctx, alice))
+
+	count, err := s.repository.Count(s.ctx, user.Filter{IncludeDeleted: true})
+	s.Require().NoError(err)
+	s.Zero(count)
+}
+
+func (s *contractSuite) TestFindAllEmpty() {
+	page, err := s.repository.FindAll(s.ctx, user.Criteria{Sort: user.DefaultSort}, user.PageRequest{First: 10})
+	s.Require().NoError(err)
+	s.Empty(page.Users)
+	s.False(page.HasNextPage)
+	s.False(page.HasPreviousPage)
+
+	page, err = s.repository.FindAll(s.ctx, user.Criteria{Sort: user.DefaultSort}, user.PageRequest{Last: 10})
+	s.Require().NoError(err)
+	s.Empty(page.Users)
+	s.False(page.HasNextPage)
+	s.False(page.HasPreviousPage)
+}
+
+func (s *contractSuite) TestFindAllPageSizeBoundaries() {
+	s.seedMany(3)
+	criteria := user.Criteria{Sort: user.DefaultSort}
+
+	for _, tc := range []struct {
+		first    int
+		count    int
+		hasNext  bool
+		backward bool
+	}{
+		{first: 2, count: 2, hasNext: true},
+		{first: 3, count: 3, hasNext: false},
+		{first: 4, count: 3, hasNext: false},
+		{first: 2, count: 2, hasNext: true, backward: true},
+		{first: 3, count: 3, hasNext: false, backward: true},
+	} {
+		req := user.PageRequest{First: tc.first}
+		if tc.backward {
+			req = user.PageRequest{Last: tc.first}
+		}
+
+		page, err := s.repository.FindAll(s.ctx, criteria, req)
+		s.Require().NoError(err)
+		s.Len(page.Users, tc.count, "page of %d (backward %t)", tc.first, tc.backward)
+
+		// Walking backward, the remainder lies on the previous page
+		if tc.backward {
+			s.Equal(tc.hasNext, page.HasPreviousPage)
+			s.False(page.HasNextPage)
+		} else {
+			s.Equal(tc.hasNext, page.HasNextPage)
+			s.False(page.HasPreviousPage)
+		}
+	}
+}
+
+func (s *contractSuite) TestFindAllWalksEveryPage() {
+	users := s.seedMany(7)
+	criteria := user.Criteria{Sort: user.DefaultSort}
+
+	// Forward, newest first, three at a time
+	var forward []*user.User
+	req := user.PageRequest{First: 3}
+	for pages := 0; ; pages++ {
+		s.Require().Less(pages, 5, "pagination did not terminate")
+
+		page, err := s.repository.FindAll(s.ctx, criteria, req)
+		s.Require().NoError(err)
+		s.Equal(req.After != nil, page.HasPreviousPage)
+		forward = append(forward, page.Users...)
+
+		if !page.HasNextPage {
+			break
+		}
+		after := user.CursorOf(page.Users[len(page.Users)-1], criteria.Sort)
+		req = user.PageRequest{First: 3, After: &after}
+	}
+
+	expected := make([]string, len(users))
+	for i, u := range users {
+		expected[len(users)-1-i] = u.ID().String()
+	}
+	s.Equal(expected, ids(forward))
+
+	// Backward from the end visits the same users in the same order
+	var backward []*user.User
+	req = user.PageRequest{Last: 3}
+	for pages := 0; ; pages++ {
+		s.Require().Less(pages, 5, "pagination did not terminate")
+
+		page, err := s.repository.FindAll(s.ctx, criteria, req)
+		s.Require().NoError(err)
+		s.Equal(req.Before != nil, page.HasNextPage)
+		backward = append(page.Users, backward...)
+
+		if !page.HasPreviousPage {
+			break
+		}
+		before := user.CursorOf(page.Users[0], criteria.Sort)
+		req = user.PageRequest{Last: 3, Before: &before}
+	}
+	s.Equal(expected, ids(backward))
+}
+
+func (s *contractSuite) TestFindAllBetweenCursors() {
+	users := s.seedMany(5)
+	criteria := user.Criteria{Sort: user.Sort{Field: user.SortByCreatedAt, Direction: user.SortAsc}}
+
+	after := user.CursorOf(users[0], criteria.Sort)
+	before := user.CursorOf(users[4], criteria.Sort)
+
+	page, err := s.repository.FindAll(s.ctx, criteria, user.PageRequest{First: 10, After: &after, Before: &before})
+	s.Require().NoError(err)
+	s.Equal(ids(users[1:4]), ids(page.Users))
+	s.True(page.HasPreviousPage)
+	s.False(page.HasNextPage)
+
+	page, err = s.repository.FindAll(s.ctx, criteria, user.PageRequest{Last: 2, After: &after, Before: &before})
+	s.Require().NoError(err)
+	s.Equal(ids(users[2:4]), ids(page.Users))
+	s.True(page.HasPreviousPage)
+	s.True(page.HasNextPage)
+}
+
+func (s *contractSuite) TestFindAllBreaksTiesByID() {
+	var twins []*user.User
+	for i := 0; i < 4; i++ {
+		twins = append(twins, s.seed(fmt.Sprintf("sam%d@example.com", i), "Sam", base))
+	}
+
+	criteria := user.Criteria{Sort: byName}
+
+	all, err := s.repository.FindAll(s.ctx, criteria, user.PageRequest{First: 10})
+	s.Require().NoError(err)
+	s.Require().Len(all.Users, 4)
+	s.ElementsMatch(ids(twins), ids(all.Users))
+	for i := 1; i < len(all.Users); i++ {
+		s.Less(all.Users[i-1].ID().String(), all.Users[i].ID().String(), "ties must be ordered by id")
+	}
+
+	// A cursor in the middle of a tie splits it without skipping or repeating anyone
+	after := user.CursorOf(all.Users[1], criteria.Sort)
+	rest, err := s.repository.FindAll(s.ctx, criteria, user.PageRequest{First: 10, After: &after})
+	s.Require().NoError(err)
+	s.Equal(ids(all.Users[2:]), ids(rest.Users))
+
+	// Descending reverses the whole key, id included
+	criteria.Sort.Direction = user.SortDesc
+	desc, err := s.repository.FindAll(s.ctx, criteria, user.PageRequest{First: 10})
+	s.Require().NoError(err)
+	s.Equal([]string{all.Users[3].ID().String(), all.Users[2].ID().String(), all.Users[1].ID().String(), all.Users[0].ID().String()}, ids(desc.Users))
+}
+
+func (s *contractSuite) TestFindAllCursorOfDeletedUser() {
+	users := s.seedMany(3)
+
+	after := user.CursorOf(users[1], user.DefaultSort)
+	s.Require().NoError(s.repository.Delete(s.ctx, users[1]))
+
+	page, err := s.repository.FindAll(s.ctx, user.Criteria{Sort: user.DefaultSort}, user.PageRequest{First: 10, After: &after})
+	s.Require().NoError(err)
+	s.Equal(ids(users[:1]), ids(page.Users))
+	s.True(page.HasPreviousPage)
+	s.False(page.HasNextPage)
+}
+
+func (s *contractSuite) TestFindAllFilters() {
+	users := s.seedMany(4)
+	s.Require().NoError(s.repository.Delete(s.ctx, users[3]))
+
+	contains := "USER 1"
+	equals := "user2@example.com"
+	from := base.Add(time.Hour)
+
+	for _, tc := range []struct {
+		name   string
+		filter user.Filter
+		want   []*user.User
+	}{
+		{name: "no filter hides deleted", filter: user.Filter{}, want: users[:3]},
+		{name: "include deleted", filter: user.Filter{IncludeDeleted: true}, want: users},
+		{name: "name contains ignores case", filter: user.Filter{Name: &user.TextFilter{Contains: &contains}}, want: users[1:2]},
+		{name: "email equals", filter: user.Filter{Email: &user.TextFilter{Equals: &equals}}, want: users[2:3]},
+		{name: "created from", filter: user.Filter{CreatedAt: &user.TimeRange{From: &from}}, want: users[1:3]},
+		{name: "created before", filter: user.Filter{CreatedAt: &user.TimeRange{To: &from}}, want: users[:1]},
+		{name: "ids", filter: user.Filter{IDs: []user.UserID{users[0].ID(), users[3].ID()}}, want: users[:1]},
+		{name: "empty ids", filter: user.Filter{IDs: []user.UserID{}}, want: nil},
+		{name: "statuses", filter: user.Filter{Statuses: []user.Status{user.StatusSuspended}}, want: nil},
+	} {
+		page, err := s.repository.FindAll(s.ctx, user.Criteria{Filter: tc.filter, Sort: byName}, user.PageRequest{First: 10})
+		s.Require().NoError(err, tc.name)
+		s.Equal(ids(tc.want), ids(page.Users), tc.name)
+
+		count, err := s.repository.Count(s.ctx, tc.filter)
+		s.Require().NoError(err, tc.name)
+		s.Equal(int64(len(tc.want)), count, tc.name)
+	}
+}
+
+func (s *contractSuite) TestFindAllInvalidSort() {
+	_, err := s.repository.FindAll(s.ctx, user.Criteria{Sort: user.Sort{Field: "AGE", Direction: user.SortAsc}}, user.PageRequest{First: 1})
+	s.Equal(errors.ErrInvalidSort, err)
+}
+
+func (s *contractSuite) TestCount() {
+	count, err := s.repository.Count(s.ctx, user.Filter{})
+	s.Require().NoError(err)
+	s.Zero(count)
+
+	users := s.seedMany(3)
+	s.Require().NoError(s.repository.Delete(s.ctx, users[0]))
+
+	count, err = s.repository.Count(s.ctx, user.Filter{})
+	s.Require().NoError(err)
+	s.Equal(int64(2), count)
+
+	count, err = s.repository.Count(s.ctx, user.Filter{IncludeDeleted: true})
+	s.Require().NoError(err)
+	s.Equal(int64(3), count)
+}
+
+func (s *contractSuite) TestCountSignups() {
+	// Wednesday 15 January 2025, twice, and Monday 20 January 2025
+	first := s.seed("a@example.com", "A", base)
+	s.seed("b@example.com", "B", base.Add(time.Hour))
+	s.seed("c@example.com", "C", base.AddDate(0, 0, 5))
+
+	// Deleted users still signed up
+	s.Require().NoError(s.repository.Delete(s.ctx, first))
+
+	buckets, err := s.repository.CountSignups(s.ctx, user.SignupStatsQuery{
+		From:     base.AddDate(0, 0, -2),
+		To:       base.AddDate(0, 0, 14),
+		Interval: user.StatsIntervalWeek,
+	})
+	s.Require().NoError(err)
+	s.Equal([]user.SignupBucket{
+		{Start: time.Date(2025, 1, 13, 0, 0, 0, 0, time.UTC), Count: 2},
+		{Start: time.Date(2025, 1, 20, 0, 0, 0, 0, time.UTC), Count: 1},
+		{Start: time.Date(2025, 1, 27, 0, 0, 0, 0, time.UTC), Count: 0},
+	}, buckets)
+
+	// The range is half-open: a signup exactly at To is left out
+	buckets, err = s.repository.CountSignups(s.ctx, user.SignupStatsQuery{
+		From:     time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC),
+		To:       base.Add(time.Hour),
+		Interval: user.StatsIntervalDay,
+	})
+	s.Require().NoError(err)
+	s.Equal([]user.SignupBucket{{Start: time.Date(2025, 1, 15, 0, 0, 0, 0, time.UTC), Count: 1}}, buckets)
+}
+
+// search returns the repository's search side, skipping the test for backends without one
+func (s *contractSuite) search() user.SearchRepository {
+	search, ok := s.repository.(user.SearchRepository)
+	if !ok {
+		s.T().Skip("repository does not implement user.SearchRepository")
+	}
+	return search
+}
+
+// hitIDs lists the IDs of the hit users in order
+func hitIDs(hits []user.SearchHit) []string {
+	out := make([]string, len(hits))
+	for i, hit := range hits {
+		out[i] = hit.User.ID().String()
+	}
+	return out
+}
+
+func (s *contractSuite) TestSearchRanksNameMatchesFirst() {
+	search := s.search()
+	john := s.seed("john.doe@example.com", "John Doe", base)
+	fan := s.seed("doe.fan@example.com", "Bob Wilson", base)
+	s.seed("jane.smith@example.com", "Jane Smith", base)
+
+	page, err := search.Search(s.ctx, user.SearchQuery{Text: "doe", First: 10})
+	s.Require().NoError(err)
+	s.Equal(ids([]*user.User{john, fan}), hitIDs(page.Hits))
+	s.Greater(page.Hits[0].Rank, page.Hits[1].Rank)
+	s.False(page.HasNextPage)
+}
+
+func (s *contractSuite) TestSearchToleratesTypos() {
+	search := s.search()
+	alexander := s.seed("alexander@example.com", "Alexander Hamilton", base)
+	s.seed("aaron@example.com", "Aaron Burr", base)
+
+	page, err := search.Search(s.ctx, user.SearchQuery{Text: "hamiltn", First: 10})
+	s.Require().NoError(err)
+	s.Equal(ids([]*user.User{alexander}), hitIDs(page.Hits))
+}
+
+func (s *contractSuite) TestSearchSkipsDeletedUsers() {
+	search := s.search()
+	alice := s.seed("alice@example.com", "Alice", base)
+	s.Require().NoError(s.repository.Delete(s.ctx, alice))
+
+	page, err := search.Search(s.ctx, user.SearchQuery{Text: "alice", First: 10})
+	s.Require().NoError(err)
+	s.Empty(page.Hits)
+}
+
+func (s *contractSuite) TestSearchWalksEveryPage() {
+	search := s.search()
+	users := s.seedMany(5)
+	s.seed("someone@example.com", "Someone Else", base)
+
+	// Every user ranks the same, so the order within the ranking comes down to ids
+	query := user.SearchQuery{Text: "user", First: 2}
+	var walked []string
+	for pages := 0; ; pages++ {
+		s.Require().Less(pages, 5, "search did not end")
+
+		page, err := search.Search(s.ctx, query)
+		s.Require().NoError(err)
+		for i := 1; i < len(page.Hits); i++ {
+			s.GreaterOrEqual(page.Hits[i-1].Rank, page.Hits[i].Rank)
+		}
+		walked = append(walked, hitIDs(page.Hits)...)
+
+		if !page.HasNextPage {
+			break
+		}
+		after := page.Hits[len(page.Hits)-1].Cursor()
+		query.After = &after
+	}
+
+	s.ElementsMatch(ids(users), walked)
+}
+
+// imports returns the repository's bulk import side, skipping the test for backends without one
+func (s *contractSuite) imports() user.ImportRepository {
+	imports, ok := s.repository.(user.ImportRepository)
+	if !ok {
+		s.T().Skip("repository does not implement user.ImportRepository")
+	}
+	return imports
+}
+
+func (s *contractSuite) TestExistingEmails() {
+	imports := s.imports()
+	alice := s.seed("alice@example.com", "Alice", base)
+	bob := s.seed("bob@example.com", "Bob", base)
+	s.Require().NoError(s.repository.Delete(s.ctx, bob))
+
+	carol, err := user.NewEmail("carol@example.com")
+	s.Require().NoError(err)
+
+	existing, err := imports.ExistingEmails(s.ctx, []user.Email{alice.Email(), bob.Email(), carol})
+	s.Require().NoError(err)
+	s.Equal([]user.Email{alice.Email()}, existing)
+}
+
+func (s *contractSuite) TestCreateBatch() {
+	imports := s.imports()
+	users := make([]*user.User, 3)
+	for i := range users {
+		u, err := user.NewUser(fmt.Sprintf("batch%d@example.com", i), fmt.Sprintf("Batch %d", i))
+		s.Require().NoError(err)
+		users[i] = u
+	}
+
+	s.Require().NoError(imports.CreateBatch(s.ctx, users))
+
+	found, err := s.repository.FindByIDs(s.ctx, []user.UserID{users[0].ID(), users[1].ID(), users[2].ID()})
+	s.Require().NoError(err)
+	s.ElementsMatch(ids(users), ids(found))
+}
+
+func (s *contractSuite) TestCreateBatchIsAllOrNothing() {
+	imports := s.imports()
+	taken := s.seed("taken@example.com", "Taken", base)
+
+	fresh, err := user.NewUser("fresh@example.com", "Fresh")
+	s.Require().NoError(err)
+	clash, err := user.NewUser(taken.Email().String(), "Clash")
+	s.Require().NoError(err)
+
+	s.Equal(errors.ErrDuplicateEmail, imports.CreateBatch(s.ctx, []*user.User{fresh, clash}))
+
+	_, err = s.repository.FindByID(s.ctx, fresh.ID())
+	s.Equal(errors.ErrUserNotFound, err)
+
+	// Two users of the same batch cannot share an email either
+	twin, err := user.NewUser(fresh.Email().String(), "Twin")
+	s.Require().NoError(err)
+	s.Equal(errors.ErrDuplicateEmail, imports.CreateBatch(s.ctx, []*user.User{fresh, twin}))
+}
+
+func (s *contractSuite) TestConcurrentCreates() {
+	const writers = 10
+
+	var wg sync.WaitGroup
+	sameEmail := make([]error, writers)
+	ownEmail := make([]error, writers)
+	for i := 0; i < writers; i++ {
+		wg.Add(2)
+		go func(i int) {
+			defer wg.Done()
+			u, err := user.NewUser("race@example.com", fmt.Sprintf("Racer %d", i))
+			if err == nil {
+				err = s.repository.Create(s.ctx, u)
+			}
+			sameEmail[i] = err
+		}(i)
+		go func(i int) {
+			defer wg.Done()
+			u, err := user.NewUser(fmt.Sprintf("runner%d@example.com", i), fmt.Sprintf("Runner %d", i))
+			if err == nil {
+				err = s.repository.Create(s.ctx, u)
+			}
+			ownEmail[i] = err
+		}(i)
+	}
+	wg.Wait()
+
+	// Exactly one writer wins the shared email; everyone else is told it is taken
+	var created int
+	for _, err := range sameEmail {
+		if err == nil {
+			created++
+			continue
+		}
+		s.Equal(errors.ErrDuplicateEmail, err)
+	}
+	s.Equal(1, created)
+
+	for _, err := range ownEmail {
+		s.NoError(err)
+	}
+
+	count, err := s.repository.Count(s.ctx, user.Filter{})
+	s.Require().NoError(err)
+	s.Equal(int64(writers+1), count)
+}
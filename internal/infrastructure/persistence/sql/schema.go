@@ -0,0 +1,49 @@
+package sql
+
+import "github.com/captain-corgi/go-graphql-example/internal/infrastructure/database"
+
+// ExpectedSchema is the shape of the tables this repository's queries rely on, as the Postgres
+// migrations leave them. Update it together with any migration that changes these tables;
+// the startup schema drift check compares the live database against it
+var ExpectedSchema = []database.TableSchema{usersTable}
+
+// usersTable is the users table after every migration in migrations/
+var usersTable = database.TableSchema{
+	Name: "users",
+	Columns: []database.ColumnSchema{
+		{Name: "id", Type: "uuid", Default: "gen_random_uuid()"},
+		{Name: "email", Type: "character varying(255)"},
+		{Name: "name", Type: "character varying(255)"},
+		{Name: "created_at", Type: "timestamp with time zone", Nullable: true, Default: "now()"},
+		{Name: "updated_at", Type: "timestamp with time zone", Nullable: true, Default: "now()"},
+		{Name: "deleted_at", Type: "timestamp with time zone", Nullable: true},
+		{Name: "status", Type: "character varying(20)", Default: "'ACTIVE'::character varying"},
+		{Name: "version", Type: "bigint", Default: "1"},
+		// Generated; Postgres reports the generation expression as the column default
+		{Name: "search_vector", Type: "tsvector", Nullable: true, Default: searchVectorExpression},
+	},
+	Indexes: []database.IndexSchema{
+		{Name: "idx_users_email", Columns: []string{"email"}},
+		{Name: "idx_users_name_id", Columns: []string{"name", "id"}},
+		{Name: "idx_users_email_id", Columns: []string{"email", "id"}},
+		{Name: "idx_users_created_at_id", Columns: []string{"created_at", "id"}},
+		{Name: "idx_users_updated_at_id", Columns: []string{"updated_at", "id"}},
+		{Name: "idx_users_status", Columns: []string{"status"}},
+		{Name: "idx_users_search_vector", Columns: []string{"search_vector"}},
+		{Name: "idx_users_name_trgm", Columns: []string{"name"}},
+		{Name: "idx_users_email_trgm", Columns: []string{"email"}},
+		// isDuplicateEmail relies on this name
+		{Name: usersEmailLiveKey, Columns: []string{"email"}, Unique: true, Predicate: "deleted_at IS NULL"},
+	},
+	Constraints: []database.ConstraintSchema{
+		{Name: "users_pkey", Kind: database.ConstraintPrimaryKey, Columns: []string{"id"}},
+		{Name: "users_status_check", Kind: database.ConstraintCheck, Columns: []string{"status"}},
+	},
+	Triggers: []database.TriggerSchema{
+		{Name: "update_users_updated_at", Timing: "BEFORE", Events: []string{"UPDATE"}, ForEachRow: true, Function: "update_updated_at_column"},
+	},
+}
+
+// searchVectorExpression is the definition of users.search_vector as Postgres prints it back
+const searchVectorExpression = `(setweight(to_tsvector('simple'::regconfig, (name)::text), 'A'::"char") || ` +
+	`setweight(to_tsvector('simple'::regconfig, translate((email)::text, '@.+_-'::text, '     '::text)), 'B'::"char"))`
@@ -0,0 +1,34 @@
+package sql
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/captain-corgi/go-graphql-example/internal/infrastructure/database"
+	"github.com/captain-corgi/go-graphql-example/migrations"
+)
+
+func TestExpectedSchemaMatchesMigrations(t *testing.T) {
+	db, cleanup := database.TestDBSetup(t, migrations.Postgres)
+	defer cleanup()
+	ctx := context.Background()
+
+	drift, err := db.DetectSchemaDrift(ctx, ExpectedSchema)
+	require.NoError(t, err)
+	assert.Empty(t, drift.Differences)
+	assert.Empty(t, drift.Additions)
+
+	// A hand-applied hotfix is reported
+	_, err = db.ExecContext(ctx, `CREATE UNIQUE INDEX hotfix_users_name ON users(name)`)
+	require.NoError(t, err)
+	_, err = db.ExecContext(ctx, `ALTER TABLE users ADD COLUMN nickname TEXT`)
+	require.NoError(t, err)
+
+	drift, err = db.DetectSchemaDrift(ctx, ExpectedSchema)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"users: unexpected unique index hotfix_users_name on UNIQUE (name)"}, drift.Differences)
+	assert.Equal(t, []string{"users: unexpected column nickname text"}, drift.Additions)
+}
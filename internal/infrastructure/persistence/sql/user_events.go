@@ -0,0 +1,102 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+
+	"github.com/captain-corgi/go-graphql-example/internal/domain/events"
+	"github.com/captain-corgi/go-graphql-example/internal/domain/user"
+	"github.com/captain-corgi/go-graphql-example/internal/infrastructure/outbox"
+)
+
+// userDetailsPayload is the outbox payload of events carrying a user's details
+type userDetailsPayload struct {
+	UserID string `json:"userId"`
+	Email  string `json:"email"`
+	Name   string `json:"name"`
+}
+
+// userChangePayload is the outbox payload of events changing one of a user's fields
+type userChangePayload struct {
+	UserID string `json:"userId"`
+	From   string `json:"from"`
+	To     string `json:"to"`
+}
+
+// userStatusPayload is the outbox payload of UserStatusChanged
+type userStatusPayload struct {
+	UserID string `json:"userId"`
+	From   string `json:"from"`
+	To     string `json:"to"`
+	Actor  string `json:"actor"`
+	Reason string `json:"reason"`
+}
+
+// appendEvents writes the user's pending events to the outbox within tx
+func (r *userRepository) appendEvents(ctx context.Context, tx *sql.Tx, u *user.User) error {
+	messages, err := eventMessages(u)
+	if err != nil || len(messages) == 0 {
+		return err
+	}
+
+	if err := outbox.Append(ctx, tx, messages...); err != nil {
+		r.logger.ErrorContext(ctx, "Failed to write user events to outbox", "error", err, "user_id", u.ID().String())
+		return err
+	}
+
+	return nil
+}
+
+// eventMessages encodes the user's pending events as outbox messages
+func eventMessages(u *user.User) ([]outbox.Message, error) {
+	pending := u.PendingEvents()
+
+	messages := make([]outbox.Message, 0, len(pending))
+	for _, event := range pending {
+		payload, err := userEventPayload(event)
+		if err != nil {
+			return nil, err
+		}
+
+		messages = append(messages, outbox.Message{
+			EventName:   event.EventName(),
+			AggregateID: event.AggregateID(),
+			Payload:     payload,
+			OccurredAt:  event.OccurredAt(),
+		})
+	}
+
+	return messages, nil
+}
+
+// userEventPayload encodes a user domain event as JSON
+func userEventPayload(event events.Event) (json.RawMessage, error) {
+	var payload interface{}
+
+	switch e := event.(type) {
+	case user.UserRegistered:
+		payload = userDetailsPayload{UserID: e.UserID.String(), Email: e.Email.String(), Name: e.Name.String()}
+	case user.UserDeleted:
+		payload = userDetailsPayload{UserID: e.UserID.String(), Email: e.Email.String(), Name: e.Name.String()}
+	case user.UserRestored:
+		payload = userDetailsPayload{UserID: e.UserID.String(), Email: e.Email.String(), Name: e.Name.String()}
+	case user.UserPurged:
+		payload = userDetailsPayload{UserID: e.UserID.String(), Email: e.Email.String(), Name: e.Name.String()}
+	case user.UserStatusChanged:
+		payload = userStatusPayload{UserID: e.UserID.String(), From: string(e.From), To: string(e.To), Actor: e.Actor, Reason: e.Reason}
+	case user.UserEmailChanged:
+		payload = userChangePayload{UserID: e.UserID.String(), From: e.From.String(), To: e.To.String()}
+	case user.UserRenamed:
+		payload = userChangePayload{UserID: e.UserID.String(), From: e.From.String(), To: e.To.String()}
+	default:
+		return nil, fmt.Errorf("unsupported user event %s", event.EventName())
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s: %w", event.EventName(), err)
+	}
+	return data, nil
+}
@@ -0,0 +1,47 @@
+package sql
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/captain-corgi/go-graphql-example/internal/domain/events"
+	"github.com/captain-corgi/go-graphql-example/internal/domain/user"
+)
+
+func TestUserEventPayload(t *testing.T) {
+	u, err := user.NewUserWithID("123e4567-e89b-12d3-a456-426614174000", "old@example.com", "Old Name", time.Now(), time.Now())
+	require.NoError(t, err)
+	require.NoError(t, u.UpdateEmail("new@example.com"))
+	require.NoError(t, u.UpdateName("New Name"))
+	require.NoError(t, u.Delete())
+	require.NoError(t, u.Restore())
+	_, err = u.ChangeStatus(user.StatusSuspended, "jane.ops", "Spam")
+	require.NoError(t, err)
+	u.Purge()
+
+	pending := u.PendingEvents()
+	require.Len(t, pending, 6)
+
+	want := []string{
+		`{"userId":"123e4567-e89b-12d3-a456-426614174000","from":"old@example.com","to":"new@example.com"}`,
+		`{"userId":"123e4567-e89b-12d3-a456-426614174000","from":"Old Name","to":"New Name"}`,
+		`{"userId":"123e4567-e89b-12d3-a456-426614174000","email":"new@example.com","name":"New Name"}`,
+		`{"userId":"123e4567-e89b-12d3-a456-426614174000","email":"new@example.com","name":"New Name"}`,
+		`{"userId":"123e4567-e89b-12d3-a456-426614174000","from":"ACTIVE","to":"SUSPENDED","actor":"jane.ops","reason":"Spam"}`,
+		`{"userId":"123e4567-e89b-12d3-a456-426614174000","email":"new@example.com","name":"New Name"}`,
+	}
+	for i, event := range pending {
+		payload, err := userEventPayload(event)
+		require.NoError(t, err)
+		assert.JSONEq(t, want[i], string(payload))
+	}
+
+	// Peeking leaves the events for the caller to drain
+	assert.Len(t, u.DrainEvents(), 6)
+
+	_, err = userEventPayload(struct{ events.Base }{events.NewBase("user.unknown", "id", time.Now())})
+	assert.Error(t, err)
+}
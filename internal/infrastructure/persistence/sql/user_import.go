@@ -0,0 +1,148 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/captain-corgi/go-graphql-example/internal/domain/errors"
+	"github.com/captain-corgi/go-graphql-example/internal/domain/user"
+	"github.com/captain-corgi/go-graphql-example/internal/infrastructure/outbox"
+	"github.com/lib/pq"
+)
+
+var _ user.ImportRepository = (*userRepository)(nil)
+
+// ExistingEmails returns those of the given emails already held by live users
+// It reads through db.Executor rather than a replica, since imports act on the answer straight away
+func (r *userRepository) ExistingEmails(ctx context.Context, emails []user.Email) ([]user.Email, error) {
+	r.logger.DebugContext(ctx, "Finding existing emails", "count", len(emails))
+
+	if len(emails) == 0 {
+		return nil, nil
+	}
+
+	rawEmails := make([]string, len(emails))
+	for i, email := range emails {
+		rawEmails[i] = email.String()
+	}
+
+	rows, err := r.db.Executor(ctx).QueryContext(ctx, `
+		SELECT email 
+		FROM users 
+		WHERE email = ANY($1) AND deleted_at IS NULL`, pq.Array(rawEmails))
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Failed to query existing emails", "error", err)
+		return nil, fmt.Errorf("failed to query existing emails: %w", err)
+	}
+	defer rows.Close()
+
+	var existing []user.Email
+	for rows.Next() {
+		var raw string
+		if err := rows.Scan(&raw); err != nil {
+			r.logger.ErrorContext(ctx, "Failed to scan existing email", "error", err)
+			return nil, fmt.Errorf("failed to scan existing email: %w", err)
+		}
+
+		email, err := user.NewEmail(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid email stored for a user: %w", err)
+		}
+		existing = append(existing, email)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.ErrorContext(ctx, "Error iterating over existing emails", "error", err)
+		return nil, fmt.Errorf("error iterating over existing emails: %w", err)
+	}
+
+	return existing, nil
+}
+
+// CreateBatch persists new users and their pending events in one transaction
+// Users and events are each streamed in a single COPY, so a batch costs a few round trips
+// however many users it holds
+func (r *userRepository) CreateBatch(ctx context.Context, users []*user.User) error {
+	r.logger.DebugContext(ctx, "Creating users in bulk", "count", len(users))
+
+	if len(users) == 0 {
+		return nil
+	}
+
+	err := r.txManager.Execute(ctx, "create users in bulk", func(tx *sql.Tx) error {
+		if err := r.copyUsers(ctx, tx, users); err != nil {
+			if isDuplicateEmail(err) {
+				r.logger.WarnContext(ctx, "Duplicate email constraint violation in bulk create")
+				return errors.ErrDuplicateEmail
+			}
+			r.logger.ErrorContext(ctx, "Failed to create users in bulk", "error", err)
+			return err
+		}
+
+		var messages []outbox.Message
+		for _, u := range users {
+			pending, err := eventMessages(u)
+			if err != nil {
+				return err
+			}
+			messages = append(messages, pending...)
+		}
+
+		if err := outbox.CopyIn(ctx, tx, messages...); err != nil {
+			r.logger.ErrorContext(ctx, "Failed to write user events to outbox", "error", err)
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	r.logger.InfoContext(ctx, "Successfully created users in bulk", "count", len(users))
+	return nil
+}
+
+// copyUsers streams the users into the users table with COPY
+// Constraint violations are reported as the *pq.Error of the failing row
+func (r *userRepository) copyUsers(ctx context.Context, tx *sql.Tx, users []*user.User) error {
+	stmt, err := tx.PrepareContext(ctx, pq.CopyIn("users", "id", "email", "name", "created_at", "updated_at", "status", "version"))
+	if err != nil {
+		return fmt.Errorf("failed to start copying users: %w", err)
+	}
+
+	for _, u := range users {
+		_, err := stmt.ExecContext(ctx,
+			u.ID().String(),
+			u.Email().String(),
+			u.Name().String(),
+			u.CreatedAt(),
+			u.UpdatedAt(),
+			string(u.Status()),
+			u.Version(),
+		)
+		if err != nil {
+			stmt.Close()
+			return unwrapCopyError(err)
+		}
+	}
+
+	// An empty Exec flushes the rows; errors in them may only surface on Close
+	if _, err := stmt.ExecContext(ctx); err != nil {
+		stmt.Close()
+		return unwrapCopyError(err)
+	}
+	if err := stmt.Close(); err != nil {
+		return unwrapCopyError(err)
+	}
+
+	return nil
+}
+
+// unwrapCopyError keeps the *pq.Error of a failed COPY as is, so isDuplicateEmail can inspect it
+func unwrapCopyError(err error) error {
+	if _, ok := err.(*pq.Error); ok {
+		return err
+	}
+	return fmt.Errorf("failed to copy users: %w", err)
+}
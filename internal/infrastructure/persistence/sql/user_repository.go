@@ -5,7 +5,6 @@ import (
 	"database/sql"
 	"fmt"
 	"log/slog"
-	"time"
 
 	"github.com/captain-corgi/go-graphql-example/internal/domain/errors"
 	"github.com/captain-corgi/go-graphql-example/internal/domain/user"
@@ -15,15 +14,31 @@ import (
 
 // userRepository implements the user.Repository interface using SQL
 type userRepository struct {
-	db     *database.DB
-	logger *slog.Logger
+	db        *database.DB
+	txManager *database.TxManager
+	logger    *slog.Logger
+}
+
+// userColumns lists the users columns every lookup selects, in scanUser order
+const userColumns = `id, email, name, created_at, updated_at, deleted_at, status, version`
+
+// usersEmailLiveKey is the partial unique index keeping emails unique among live users
+const usersEmailLiveKey = "users_email_live_key"
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
 }
 
 // NewUserRepository creates a new SQL-based user repository
-func NewUserRepository(db *database.DB, logger *slog.Logger) user.Repository {
+// Writes that carry domain events run through txManager so the events land in the outbox atomically.
+// FindByID, FindByIDs, FindAll, Count, ExistsByEmail and Search read through db.Reader, so they may be served by a replica.
+// The repository also implements user.SearchRepository and user.ImportRepository
+func NewUserRepository(db *database.DB, txManager *database.TxManager, logger *slog.Logger) user.Repository {
 	return &userRepository{
-		db:     db,
-		logger: logger,
+		db:        db,
+		txManager: txManager,
+		logger:    logger,
 	}
 }
 
@@ -32,17 +47,11 @@ func (r *userRepository) FindByID(ctx context.Context, id user.UserID) (*user.Us
 	r.logger.DebugContext(ctx, "Finding user by ID", "user_id", id.String())
 
 	query := `
-		SELECT id, email, name, created_at, updated_at 
+		SELECT ` + userColumns + ` 
 		FROM users 
-		WHERE id = $1`
-
-	var userID, email, name string
-	var createdAt, updatedAt time.Time
-
-	err := r.db.QueryRowContext(ctx, query, id.String()).Scan(
-		&userID, &email, &name, &createdAt, &updatedAt,
-	)
+		WHERE id = $1 AND deleted_at IS NULL`
 
+	domainUser, err := r.scanUser(ctx, r.db.Reader(ctx).QueryRowContext(ctx, query, id.String()))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			r.logger.DebugContext(ctx, "User not found", "user_id", id.String())
@@ -52,14 +61,53 @@ func (r *userRepository) FindByID(ctx context.Context, id user.UserID) (*user.Us
 		return nil, fmt.Errorf("failed to find user by ID: %w", err)
 	}
 
-	domainUser, err := user.NewUserWithID(userID, email, name, createdAt, updatedAt)
+	r.logger.DebugContext(ctx, "Successfully found user by ID", "user_id", id.String())
+	return domainUser, nil
+}
+
+// FindByIDs retrieves the users with the given IDs in a single query
+func (r *userRepository) FindByIDs(ctx context.Context, ids []user.UserID) ([]*user.User, error) {
+	r.logger.DebugContext(ctx, "Finding users by IDs", "count", len(ids))
+
+	if len(ids) == 0 {
+		return []*user.User{}, nil
+	}
+
+	rawIDs := make([]string, len(ids))
+	for i, id := range ids {
+		rawIDs[i] = id.String()
+	}
+
+	query := `
+		SELECT ` + userColumns + ` 
+		FROM users 
+		WHERE id = ANY($1::uuid[]) AND deleted_at IS NULL`
+
+	rows, err := r.db.Reader(ctx).QueryContext(ctx, query, pq.Array(rawIDs))
 	if err != nil {
-		r.logger.ErrorContext(ctx, "Failed to create domain user from database record", "error", err)
-		return nil, fmt.Errorf("failed to create domain user: %w", err)
+		r.logger.ErrorContext(ctx, "Failed to query users by IDs", "error", err)
+		return nil, fmt.Errorf("failed to query users by IDs: %w", err)
 	}
+	defer rows.Close()
 
-	r.logger.DebugContext(ctx, "Successfully found user by ID", "user_id", id.String())
-	return domainUser, nil
+	users := make([]*user.User, 0, len(ids))
+	for rows.Next() {
+		domainUser, err := r.scanUser(ctx, rows)
+		if err != nil {
+			r.logger.ErrorContext(ctx, "Failed to scan user row", "error", err)
+			return nil, fmt.Errorf("failed to scan user row: %w", err)
+		}
+
+		users = append(users, domainUser)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.ErrorContext(ctx, "Error iterating over user rows", "error", err)
+		return nil, fmt.Errorf("error iterating over user rows: %w", err)
+	}
+
+	r.logger.DebugContext(ctx, "Successfully found users by IDs", "requested", len(ids), "found", len(users))
+	return users, nil
 }
 
 // FindByEmail retrieves a user by their email address
@@ -67,17 +115,11 @@ func (r *userRepository) FindByEmail(ctx context.Context, email user.Email) (*us
 	r.logger.DebugContext(ctx, "Finding user by email", "email", email.String())
 
 	query := `
-		SELECT id, email, name, created_at, updated_at 
+		SELECT ` + userColumns + ` 
 		FROM users 
-		WHERE email = $1`
-
-	var userID, userEmail, name string
-	var createdAt, updatedAt time.Time
-
-	err := r.db.QueryRowContext(ctx, query, email.String()).Scan(
-		&userID, &userEmail, &name, &createdAt, &updatedAt,
-	)
+		WHERE email = $1 AND deleted_at IS NULL`
 
+	domainUser, err := r.scanUser(ctx, r.db.Executor(ctx).QueryRowContext(ctx, query, email.String()))
 	if err != nil {
 		if err == sql.ErrNoRows {
 			r.logger.DebugContext(ctx, "User not found by email", "email", email.String())
@@ -87,181 +129,434 @@ func (r *userRepository) FindByEmail(ctx context.Context, email user.Email) (*us
 		return nil, fmt.Errorf("failed to find user by email: %w", err)
 	}
 
-	domainUser, err := user.NewUserWithID(userID, userEmail, name, createdAt, updatedAt)
-	if err != nil {
-		r.logger.ErrorContext(ctx, "Failed to create domain user from database record", "error", err)
-		return nil, fmt.Errorf("failed to create domain user: %w", err)
-	}
-
 	r.logger.DebugContext(ctx, "Successfully found user by email", "email", email.String())
 	return domainUser, nil
 }
 
-// FindAll retrieves users with pagination support
-func (r *userRepository) FindAll(ctx context.Context, limit int, cursor string) ([]*user.User, string, error) {
-	r.logger.DebugContext(ctx, "Finding all users", "limit", limit, "cursor", cursor)
-
-	var query string
-	var args []interface{}
-
-	if cursor == "" {
-		// First page
-		query = `
-			SELECT id, email, name, created_at, updated_at 
-			FROM users 
-			ORDER BY created_at DESC, id DESC 
-			LIMIT $1`
-		args = []interface{}{limit}
-	} else {
-		// Subsequent pages - cursor-based pagination using created_at and id
-		query = `
-			SELECT id, email, name, created_at, updated_at 
-			FROM users 
-			WHERE (created_at, id) < (
-				SELECT created_at, id FROM users WHERE id = $1
-			)
-			ORDER BY created_at DESC, id DESC 
-			LIMIT $2`
-		args = []interface{}{cursor, limit}
-	}
-
-	rows, err := r.db.QueryContext(ctx, query, args...)
+// FindAll retrieves a keyset page of the users matching the criteria
+func (r *userRepository) FindAll(ctx context.Context, criteria user.Criteria, page user.PageRequest) (*user.Page, error) {
+	r.logger.DebugContext(ctx, "Finding all users",
+		"first", page.First,
+		"last", page.Last,
+		"has_after", page.After != nil,
+		"has_before", page.Before != nil,
+		"sort_field", criteria.Sort.Field,
+		"sort_direction", criteria.Sort.Direction,
+	)
+
+	column, ok := sortColumns[criteria.Sort.Field]
+	if !ok {
+		return nil, errors.ErrInvalidSort
+	}
+
+	afterOp, beforeOp := keysetOps(criteria.Sort.Direction)
+
+	limit := page.First
+	order := criteria.Sort.Direction
+	if page.IsBackward() {
+		// Walk the keyset from the other end and reverse the rows afterwards
+		limit = page.Last
+		order = reverse(order)
+	}
+
+	var b conditionBuilder
+	b.addFilter(criteria.Filter)
+	if page.After != nil {
+		b.addKeyset(criteria.Sort.Field, afterOp, *page.After)
+	}
+	if page.Before != nil {
+		b.addKeyset(criteria.Sort.Field, beforeOp, *page.Before)
+	}
+
+	// Fetch one extra row to detect whether another page exists in the walking direction
+	query := fmt.Sprintf(`
+		SELECT %s 
+		FROM users%s
+		ORDER BY %s %s, id %s 
+		LIMIT %s`, userColumns, b.where(), column, order, order, b.arg(limit+1))
+
+	rows, err := r.db.Reader(ctx).QueryContext(ctx, query, b.args...)
 	if err != nil {
 		r.logger.ErrorContext(ctx, "Failed to query users", "error", err)
-		return nil, "", fmt.Errorf("failed to query users: %w", err)
+		return nil, fmt.Errorf("failed to query users: %w", err)
 	}
 	defer rows.Close()
 
 	var users []*user.User
-	var nextCursor string
 
 	for rows.Next() {
-		var userID, email, name string
-		var createdAt, updatedAt time.Time
-
-		if err := rows.Scan(&userID, &email, &name, &createdAt, &updatedAt); err != nil {
-			r.logger.ErrorContext(ctx, "Failed to scan user row", "error", err)
-			return nil, "", fmt.Errorf("failed to scan user row: %w", err)
-		}
-
-		domainUser, err := user.NewUserWithID(userID, email, name, createdAt, updatedAt)
+		domainUser, err := r.scanUser(ctx, rows)
 		if err != nil {
-			r.logger.ErrorContext(ctx, "Failed to create domain user from database record", "error", err)
-			return nil, "", fmt.Errorf("failed to create domain user: %w", err)
+			r.logger.ErrorContext(ctx, "Failed to scan user row", "error", err)
+			return nil, fmt.Errorf("failed to scan user row: %w", err)
 		}
 
 		users = append(users, domainUser)
-		nextCursor = userID // Use the last user's ID as the next cursor
 	}
 
 	if err := rows.Err(); err != nil {
 		r.logger.ErrorContext(ctx, "Error iterating over user rows", "error", err)
-		return nil, "", fmt.Errorf("error iterating over user rows: %w", err)
+		return nil, fmt.Errorf("error iterating over user rows: %w", err)
+	}
+
+	hasMore := len(users) > limit
+	if hasMore {
+		users = users[:limit]
+	}
+
+	result := &user.Page{Users: users}
+
+	if page.IsBackward() {
+		for i, j := 0, len(users)-1; i < j; i, j = i+1, j-1 {
+			users[i], users[j] = users[j], users[i]
+		}
+		result.HasPreviousPage = hasMore
+		if page.Before != nil {
+			// Anything at or past the before cursor is on the next page
+			result.HasNextPage, err = r.existsBeyond(ctx, criteria, afterOp+"=", *page.Before)
+		}
+	} else {
+		result.HasNextPage = hasMore
+		if page.After != nil {
+			// Anything at or ahead of the after cursor is on the previous page
+			result.HasPreviousPage, err = r.existsBeyond(ctx, criteria, beforeOp+"=", *page.After)
+		}
+	}
+	if err != nil {
+		return nil, err
 	}
 
-	r.logger.DebugContext(ctx, "Successfully found users", "count", len(users), "next_cursor", nextCursor)
-	return users, nextCursor, nil
+	r.logger.DebugContext(ctx, "Successfully found users",
+		"count", len(users),
+		"has_next_page", result.HasNextPage,
+		"has_previous_page", result.HasPreviousPage,
+	)
+	return result, nil
 }
 
-// Create persists a new user
+// existsBeyond reports whether any user matching the criteria lies on the op side of the cursor
+func (r *userRepository) existsBeyond(ctx context.Context, criteria user.Criteria, op string, cursor user.Cursor) (bool, error) {
+	var b conditionBuilder
+	b.addFilter(criteria.Filter)
+	b.addKeyset(criteria.Sort.Field, op, cursor)
+
+	query := fmt.Sprintf(`SELECT EXISTS(SELECT 1 FROM users%s)`, b.where())
+
+	var exists bool
+	if err := r.db.Reader(ctx).QueryRowContext(ctx, query, b.args...).Scan(&exists); err != nil {
+		r.logger.ErrorContext(ctx, "Failed to check for adjacent users", "error", err)
+		return false, fmt.Errorf("failed to check for adjacent users: %w", err)
+	}
+
+	return exists, nil
+}
+
+// Create persists a new user together with its pending events
 func (r *userRepository) Create(ctx context.Context, u *user.User) error {
 	r.logger.DebugContext(ctx, "Creating user", "user_id", u.ID().String(), "email", u.Email().String())
 
 	query := `
-		INSERT INTO users (id, email, name, created_at, updated_at) 
-		VALUES ($1, $2, $3, $4, $5)`
-
-	_, err := r.db.ExecContext(ctx, query,
-		u.ID().String(),
-		u.Email().String(),
-		u.Name().String(),
-		u.CreatedAt(),
-		u.UpdatedAt(),
-	)
+		INSERT INTO users (id, email, name, created_at, updated_at, status, version) 
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`
+
+	err := r.txManager.Execute(ctx, "create user", func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(ctx, query,
+			u.ID().String(),
+			u.Email().String(),
+			u.Name().String(),
+			u.CreatedAt(),
+			u.UpdatedAt(),
+			string(u.Status()),
+			u.Version(),
+		)
 
-	if err != nil {
-		// Check for unique constraint violation (duplicate email)
-		if pqErr, ok := err.(*pq.Error); ok {
-			if pqErr.Code == "23505" && pqErr.Constraint == "users_email_key" {
+		if err != nil {
+			// Check for unique constraint violation (duplicate email)
+			if isDuplicateEmail(err) {
 				r.logger.WarnContext(ctx, "Duplicate email constraint violation", "email", u.Email().String())
 				return errors.ErrDuplicateEmail
 			}
+			r.logger.ErrorContext(ctx, "Failed to create user", "error", err, "user_id", u.ID().String())
+			return fmt.Errorf("failed to create user: %w", err)
 		}
-		r.logger.ErrorContext(ctx, "Failed to create user", "error", err, "user_id", u.ID().String())
-		return fmt.Errorf("failed to create user: %w", err)
+
+		return r.appendEvents(ctx, tx, u)
+	})
+	if err != nil {
+		return err
 	}
 
 	r.logger.InfoContext(ctx, "Successfully created user", "user_id", u.ID().String(), "email", u.Email().String())
 	return nil
 }
 
-// Update modifies an existing user
+// Update modifies an existing user if nobody else has written it since it was read
 func (r *userRepository) Update(ctx context.Context, u *user.User) error {
 	r.logger.DebugContext(ctx, "Updating user", "user_id", u.ID().String(), "email", u.Email().String())
 
 	query := `
 		UPDATE users 
-		SET email = $2, name = $3, updated_at = $4 
-		WHERE id = $1`
-
-	result, err := r.db.ExecContext(ctx, query,
-		u.ID().String(),
-		u.Email().String(),
-		u.Name().String(),
-		u.UpdatedAt(),
-	)
+		SET email = $2, name = $3, updated_at = $4, version = version + 1 
+		WHERE id = $1 AND version = $5 AND deleted_at IS NULL`
+
+	err := r.txManager.Execute(ctx, "update user", func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, query,
+			u.ID().String(),
+			u.Email().String(),
+			u.Name().String(),
+			u.UpdatedAt(),
+			u.Version(),
+		)
 
-	if err != nil {
-		// Check for unique constraint violation (duplicate email)
-		if pqErr, ok := err.(*pq.Error); ok {
-			if pqErr.Code == "23505" && pqErr.Constraint == "users_email_key" {
+		if err != nil {
+			// Check for unique constraint violation (duplicate email)
+			if isDuplicateEmail(err) {
 				r.logger.WarnContext(ctx, "Duplicate email constraint violation during update", "email", u.Email().String())
 				return errors.ErrDuplicateEmail
 			}
+			r.logger.ErrorContext(ctx, "Failed to update user", "error", err, "user_id", u.ID().String())
+			return fmt.Errorf("failed to update user: %w", err)
 		}
-		r.logger.ErrorContext(ctx, "Failed to update user", "error", err, "user_id", u.ID().String())
-		return fmt.Errorf("failed to update user: %w", err)
-	}
 
-	rowsAffected, err := result.RowsAffected()
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			r.logger.ErrorContext(ctx, "Failed to get rows affected after update", "error", err)
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+
+		if rowsAffected == 0 {
+			return r.updateMissed(ctx, tx, u)
+		}
+
+		return r.appendEvents(ctx, tx, u)
+	})
 	if err != nil {
-		r.logger.ErrorContext(ctx, "Failed to get rows affected after update", "error", err)
-		return fmt.Errorf("failed to get rows affected: %w", err)
+		return err
+	}
+
+	u.IncrementVersion()
+
+	r.logger.InfoContext(ctx, "Successfully updated user", "user_id", u.ID().String(), "email", u.Email().String())
+	return nil
+}
+
+// UpdateStatus stores the user's new status and records the transition in one transaction
+func (r *userRepository) UpdateStatus(ctx context.Context, u *user.User, t user.StatusTransition) error {
+	r.logger.DebugContext(ctx, "Updating user status",
+		"user_id", u.ID().String(),
+		"from", t.From,
+		"to", t.To,
+	)
+
+	err := r.txManager.Execute(ctx, "update user status", func(tx *sql.Tx) error {
+		// Guard on the previous status so concurrent transitions cannot skip the table,
+		// and on the version so they cannot overwrite other writes either
+		result, err := tx.ExecContext(ctx, `
+			UPDATE users 
+			SET status = $2, updated_at = $3, version = version + 1 
+			WHERE id = $1 AND status = $4 AND version = $5 AND deleted_at IS NULL`,
+			u.ID().String(), string(t.To), u.UpdatedAt(), string(t.From), u.Version(),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to update user status: %w", err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+
+		if rowsAffected == 0 {
+			var current string
+			var version int64
+			err := tx.QueryRowContext(ctx,
+				`SELECT status, version FROM users WHERE id = $1 AND deleted_at IS NULL`, u.ID().String(),
+			).Scan(&current, &version)
+			if err == sql.ErrNoRows {
+				return errors.ErrUserNotFound
+			}
+			if err != nil {
+				return fmt.Errorf("failed to read current user status: %w", err)
+			}
+			if user.Status(current) != t.From {
+				return user.NewInvalidStatusTransitionError(user.Status(current), t.To)
+			}
+			return errors.NewConflictError(version)
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO user_status_transitions (user_id, from_status, to_status, actor, reason, occurred_at) 
+			VALUES ($1, $2, $3, $4, $5, $6)`,
+			t.UserID.String(), string(t.From), string(t.To), t.Actor, t.Reason, t.OccurredAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to record status transition: %w", err)
+		}
+
+		return r.appendEvents(ctx, tx, u)
+	})
+
+	switch err.(type) {
+	case nil:
+	case errors.DomainError, errors.ConflictError:
+		r.logger.WarnContext(ctx, "User status not updated", "error", err, "user_id", u.ID().String())
+		return err
+	default:
+		r.logger.ErrorContext(ctx, "Failed to update user status", "error", err, "user_id", u.ID().String())
+		return err
 	}
 
-	if rowsAffected == 0 {
+	u.IncrementVersion()
+
+	r.logger.InfoContext(ctx, "Successfully updated user status",
+		"user_id", u.ID().String(),
+		"from", t.From,
+		"to", t.To,
+	)
+	return nil
+}
+
+// updateMissed explains why a versioned update matched no rows
+func (r *userRepository) updateMissed(ctx context.Context, tx *sql.Tx, u *user.User) error {
+	var current int64
+	err := tx.QueryRowContext(ctx,
+		`SELECT version FROM users WHERE id = $1 AND deleted_at IS NULL`, u.ID().String(),
+	).Scan(&current)
+	if err == sql.ErrNoRows {
 		r.logger.WarnContext(ctx, "No rows affected during user update", "user_id", u.ID().String())
 		return errors.ErrUserNotFound
 	}
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Failed to read current user version", "error", err, "user_id", u.ID().String())
+		return fmt.Errorf("failed to read current user version: %w", err)
+	}
 
-	r.logger.InfoContext(ctx, "Successfully updated user", "user_id", u.ID().String(), "email", u.Email().String())
-	return nil
+	r.logger.WarnContext(ctx, "User update conflicted with a concurrent write",
+		"user_id", u.ID().String(),
+		"expected_version", u.Version(),
+		"current_version", current,
+	)
+	return errors.NewConflictError(current)
 }
 
-// Delete removes a user by their ID
-func (r *userRepository) Delete(ctx context.Context, id user.UserID) error {
-	r.logger.DebugContext(ctx, "Deleting user", "user_id", id.String())
+// Delete soft-deletes a live user together with its pending events
+func (r *userRepository) Delete(ctx context.Context, u *user.User) error {
+	r.logger.DebugContext(ctx, "Deleting user", "user_id", u.ID().String())
 
-	query := `DELETE FROM users WHERE id = $1`
+	query := `UPDATE users SET deleted_at = COALESCE($2, NOW()), version = version + 1 WHERE id = $1 AND deleted_at IS NULL`
 
-	result, err := r.db.ExecContext(ctx, query, id.String())
+	err := r.txManager.Execute(ctx, "delete user", func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, query, u.ID().String(), u.DeletedAt())
+		if err != nil {
+			r.logger.ErrorContext(ctx, "Failed to delete user", "error", err, "user_id", u.ID().String())
+			return fmt.Errorf("failed to delete user: %w", err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			r.logger.ErrorContext(ctx, "Failed to get rows affected after delete", "error", err)
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+
+		if rowsAffected == 0 {
+			r.logger.WarnContext(ctx, "No rows affected during user delete", "user_id", u.ID().String())
+			return errors.ErrUserNotFound
+		}
+
+		return r.appendEvents(ctx, tx, u)
+	})
 	if err != nil {
-		r.logger.ErrorContext(ctx, "Failed to delete user", "error", err, "user_id", id.String())
-		return fmt.Errorf("failed to delete user: %w", err)
+		return err
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	u.IncrementVersion()
+
+	r.logger.InfoContext(ctx, "Successfully deleted user", "user_id", u.ID().String())
+	return nil
+}
+
+// Restore brings a soft-deleted user back together with its pending events
+func (r *userRepository) Restore(ctx context.Context, u *user.User) error {
+	r.logger.DebugContext(ctx, "Restoring user", "user_id", u.ID().String())
+
+	query := `UPDATE users SET deleted_at = NULL, version = version + 1 WHERE id = $1 AND deleted_at IS NOT NULL`
+
+	err := r.txManager.Execute(ctx, "restore user", func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, query, u.ID().String())
+		if err != nil {
+			// Another live user may have taken the email while this one was deleted
+			if isDuplicateEmail(err) {
+				r.logger.WarnContext(ctx, "Duplicate email constraint violation during restore", "user_id", u.ID().String())
+				return errors.ErrDuplicateEmail
+			}
+			r.logger.ErrorContext(ctx, "Failed to restore user", "error", err, "user_id", u.ID().String())
+			return fmt.Errorf("failed to restore user: %w", err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			r.logger.ErrorContext(ctx, "Failed to get rows affected after restore", "error", err)
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+
+		if rowsAffected == 0 {
+			// Tell a live user apart from a missing one
+			var exists bool
+			if err := tx.QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM users WHERE id = $1)`, u.ID().String()).Scan(&exists); err != nil {
+				r.logger.ErrorContext(ctx, "Failed to check user existence after restore", "error", err, "user_id", u.ID().String())
+				return fmt.Errorf("failed to check user existence: %w", err)
+			}
+			if exists {
+				r.logger.WarnContext(ctx, "User to restore is not deleted", "user_id", u.ID().String())
+				return errors.ErrUserNotDeleted
+			}
+			r.logger.WarnContext(ctx, "No rows affected during user restore", "user_id", u.ID().String())
+			return errors.ErrUserNotFound
+		}
+
+		return r.appendEvents(ctx, tx, u)
+	})
 	if err != nil {
-		r.logger.ErrorContext(ctx, "Failed to get rows affected after delete", "error", err)
-		return fmt.Errorf("failed to get rows affected: %w", err)
+		return err
 	}
 
-	if rowsAffected == 0 {
-		r.logger.WarnContext(ctx, "No rows affected during user delete", "user_id", id.String())
-		return errors.ErrUserNotFound
+	u.IncrementVersion()
+
+	r.logger.InfoContext(ctx, "Successfully restored user", "user_id", u.ID().String())
+	return nil
+}
+
+// Purge permanently removes a user together with its pending events
+func (r *userRepository) Purge(ctx context.Context, u *user.User) error {
+	r.logger.DebugContext(ctx, "Purging user", "user_id", u.ID().String())
+
+	query := `DELETE FROM users WHERE id = $1`
+
+	err := r.txManager.Execute(ctx, "purge user", func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, query, u.ID().String())
+		if err != nil {
+			r.logger.ErrorContext(ctx, "Failed to purge user", "error", err, "user_id", u.ID().String())
+			return fmt.Errorf("failed to purge user: %w", err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			r.logger.ErrorContext(ctx, "Failed to get rows affected after purge", "error", err)
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+
+		if rowsAffected == 0 {
+			r.logger.WarnContext(ctx, "No rows affected during user purge", "user_id", u.ID().String())
+			return errors.ErrUserNotFound
+		}
+
+		return r.appendEvents(ctx, tx, u)
+	})
+	if err != nil {
+		return err
 	}
 
-	r.logger.InfoContext(ctx, "Successfully deleted user", "user_id", id.String())
+	r.logger.InfoContext(ctx, "Successfully purged user", "user_id", u.ID().String())
 	return nil
 }
 
@@ -269,10 +564,10 @@ func (r *userRepository) Delete(ctx context.Context, id user.UserID) error {
 func (r *userRepository) ExistsByEmail(ctx context.Context, email user.Email) (bool, error) {
 	r.logger.DebugContext(ctx, "Checking if user exists by email", "email", email.String())
 
-	query := `SELECT EXISTS(SELECT 1 FROM users WHERE email = $1)`
+	query := `SELECT EXISTS(SELECT 1 FROM users WHERE email = $1 AND deleted_at IS NULL)`
 
 	var exists bool
-	err := r.db.QueryRowContext(ctx, query, email.String()).Scan(&exists)
+	err := r.db.Reader(ctx).QueryRowContext(ctx, query, email.String()).Scan(&exists)
 	if err != nil {
 		r.logger.ErrorContext(ctx, "Failed to check if user exists by email", "error", err, "email", email.String())
 		return false, fmt.Errorf("failed to check if user exists by email: %w", err)
@@ -282,14 +577,17 @@ func (r *userRepository) ExistsByEmail(ctx context.Context, email user.Email) (b
 	return exists, nil
 }
 
-// Count returns the total number of users
-func (r *userRepository) Count(ctx context.Context) (int64, error) {
-	r.logger.DebugContext(ctx, "Counting total users")
+// Count returns the number of users matching the filter
+func (r *userRepository) Count(ctx context.Context, filter user.Filter) (int64, error) {
+	r.logger.DebugContext(ctx, "Counting users")
 
-	query := `SELECT COUNT(*) FROM users`
+	var b conditionBuilder
+	b.addFilter(filter)
+
+	query := `SELECT COUNT(*) FROM users` + b.where()
 
 	var count int64
-	err := r.db.QueryRowContext(ctx, query).Scan(&count)
+	err := r.db.Reader(ctx).QueryRowContext(ctx, query, b.args...).Scan(&count)
 	if err != nil {
 		r.logger.ErrorContext(ctx, "Failed to count users", "error", err)
 		return 0, fmt.Errorf("failed to count users: %w", err)
@@ -298,3 +596,91 @@ func (r *userRepository) Count(ctx context.Context) (int64, error) {
 	r.logger.DebugContext(ctx, "Successfully counted users", "count", count)
 	return count, nil
 }
+
+// CountSignups returns the number of users created in each interval of the query range
+// Soft-deleted users are included: they still signed up
+func (r *userRepository) CountSignups(ctx context.Context, q user.SignupStatsQuery) ([]user.SignupBucket, error) {
+	r.logger.DebugContext(ctx, "Counting signups",
+		"from", q.From,
+		"to", q.To,
+		"interval", q.Interval,
+	)
+
+	unit, ok := statsUnits[q.Interval]
+	if !ok {
+		return nil, errors.ErrInvalidStatsInterval
+	}
+
+	// Buckets are computed in UTC. The series supplies every bucket so empty ones
+	// report zero, and the grouped subquery only scans rows inside the range.
+	query := `
+		SELECT b.bucket, COALESCE(c.signups, 0)
+		FROM generate_series(
+			date_trunc($1::text, $2::timestamptz AT TIME ZONE 'UTC'),
+			date_trunc($1::text, ($3::timestamptz AT TIME ZONE 'UTC') - interval '1 microsecond'),
+			('1 ' || $1::text)::interval
+		) AS b(bucket)
+		LEFT JOIN (
+			SELECT date_trunc($1::text, created_at AT TIME ZONE 'UTC') AS bucket, COUNT(*) AS signups
+			FROM users
+			WHERE created_at >= $2 AND created_at < $3
+			GROUP BY 1
+		) c ON c.bucket = b.bucket
+		ORDER BY b.bucket`
+
+	rows, err := r.db.Executor(ctx).QueryContext(ctx, query, unit, q.From, q.To)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Failed to count signups", "error", err)
+		return nil, fmt.Errorf("failed to count signups: %w", err)
+	}
+	defer rows.Close()
+
+	var buckets []user.SignupBucket
+	for rows.Next() {
+		var bucket user.SignupBucket
+		if err := rows.Scan(&bucket.Start, &bucket.Count); err != nil {
+			r.logger.ErrorContext(ctx, "Failed to scan signup bucket", "error", err)
+			return nil, fmt.Errorf("failed to scan signup bucket: %w", err)
+		}
+		bucket.Start = bucket.Start.UTC()
+		buckets = append(buckets, bucket)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.ErrorContext(ctx, "Error iterating over signup buckets", "error", err)
+		return nil, fmt.Errorf("error iterating over signup buckets: %w", err)
+	}
+
+	r.logger.DebugContext(ctx, "Successfully counted signups", "buckets", len(buckets))
+	return buckets, nil
+}
+
+// scanUser reads a row selected with userColumns into a domain user
+func (r *userRepository) scanUser(ctx context.Context, row rowScanner) (*user.User, error) {
+	var snapshot user.Snapshot
+	var deletedAt sql.NullTime
+
+	if err := row.Scan(
+		&snapshot.ID, &snapshot.Email, &snapshot.Name, &snapshot.CreatedAt, &snapshot.UpdatedAt, &deletedAt, &snapshot.Status, &snapshot.Version,
+	); err != nil {
+		return nil, err
+	}
+
+	if deletedAt.Valid {
+		snapshot.DeletedAt = &deletedAt.Time
+	}
+
+	domainUser, err := user.NewUserFromSnapshot(snapshot)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Failed to create domain user from database record", "error", err)
+		return nil, fmt.Errorf("failed to create domain user: %w", err)
+	}
+
+	return domainUser, nil
+}
+
+// isDuplicateEmail reports whether err is a violation of live email uniqueness
+func isDuplicateEmail(err error) bool {
+	pqErr, ok := err.(*pq.Error)
+	return ok && pqErr.Code == "23505" && pqErr.Constraint == usersEmailLiveKey
+}
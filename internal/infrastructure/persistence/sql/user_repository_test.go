@@ -12,6 +12,8 @@ import (
 	"github.com/captain-corgi/go-graphql-example/internal/domain/errors"
 	"github.com/captain-corgi/go-graphql-example/internal/domain/user"
 	"github.com/captain-corgi/go-graphql-example/internal/infrastructure/database"
+	"github.com/captain-corgi/go-graphql-example/internal/infrastructure/persistence/repotest"
+	"github.com/captain-corgi/go-graphql-example/migrations"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"github.com/stretchr/testify/suite"
@@ -31,12 +33,12 @@ func (suite *UserRepositoryTestSuite) SetupSuite() {
 	suite.ctx = context.Background()
 
 	// Use test database utilities for setup
-	db, cleanup := database.TestDBSetup(suite.T(), "../../../../migrations")
+	db, cleanup := database.TestDBSetup(suite.T(), migrations.Postgres)
 	suite.db = db
 	suite.cleanup = cleanup
 
 	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelDebug}))
-	suite.repository = NewUserRepository(db, logger)
+	suite.repository = NewUserRepository(db, database.NewTxManager(db, logger), logger)
 }
 
 // TearDownSuite cleans up the test suite
@@ -48,9 +50,11 @@ func (suite *UserRepositoryTestSuite) TearDownSuite() {
 
 // SetupTest sets up each test
 func (suite *UserRepositoryTestSuite) SetupTest() {
-	// Clean up users table before each test
+	// Clean up users and outbox tables before each test
 	_, err := suite.db.ExecContext(suite.ctx, "DELETE FROM users")
 	require.NoError(suite.T(), err)
+	_, err = suite.db.ExecContext(suite.ctx, "DELETE FROM outbox")
+	require.NoError(suite.T(), err)
 }
 
 // TestCreateUser tests user creation
@@ -112,6 +116,29 @@ func (suite *UserRepositoryTestSuite) TestFindByIDNotFound() {
 	assert.Equal(suite.T(), errors.ErrUserNotFound, err)
 }
 
+// TestFindByIDs tests batch retrieval by ID
+func (suite *UserRepositoryTestSuite) TestFindByIDs() {
+	alice, err := user.NewUser("alice@example.com", "Alice")
+	require.NoError(suite.T(), err)
+	require.NoError(suite.T(), suite.repository.Create(suite.ctx, alice))
+
+	bob, err := user.NewUser("bob@example.com", "Bob")
+	require.NoError(suite.T(), err)
+	require.NoError(suite.T(), suite.repository.Create(suite.ctx, bob))
+
+	found, err := suite.repository.FindByIDs(suite.ctx, []user.UserID{alice.ID(), user.GenerateUserID(), bob.ID(), alice.ID()})
+	assert.NoError(suite.T(), err)
+	require.Len(suite.T(), found, 2)
+
+	ids := []user.UserID{found[0].ID(), found[1].ID()}
+	assert.ElementsMatch(suite.T(), []user.UserID{alice.ID(), bob.ID()}, ids)
+
+	// No IDs needs no query
+	found, err = suite.repository.FindByIDs(suite.ctx, nil)
+	assert.NoError(suite.T(), err)
+	assert.Empty(suite.T(), found)
+}
+
 // TestFindByEmail tests finding user by email
 func (suite *UserRepositoryTestSuite) TestFindByEmail() {
 	// Create a test user
@@ -164,7 +191,7 @@ func (suite *UserRepositoryTestSuite) TestDeleteUser() {
 	require.NoError(suite.T(), err)
 
 	// Delete user
-	err = suite.repository.Delete(suite.ctx, testUser.ID())
+	err = suite.repository.Delete(suite.ctx, testUser)
 	assert.NoError(suite.T(), err)
 
 	// Verify deletion
@@ -172,6 +199,269 @@ func (suite *UserRepositoryTestSuite) TestDeleteUser() {
 	assert.Equal(suite.T(), errors.ErrUserNotFound, err)
 }
 
+// TestDeleteUserIsSoft tests that deleted users stay listable on request and free their email
+func (suite *UserRepositoryTestSuite) TestDeleteUserIsSoft() {
+	testUser, err := user.NewUser("test@example.com", "Test User")
+	require.NoError(suite.T(), err)
+	require.NoError(suite.T(), suite.repository.Create(suite.ctx, testUser))
+
+	require.NoError(suite.T(), suite.repository.Delete(suite.ctx, testUser))
+
+	// Deleting twice reports the user as missing
+	assert.Equal(suite.T(), errors.ErrUserNotFound, suite.repository.Delete(suite.ctx, testUser))
+
+	// Hidden from lookups by default
+	_, err = suite.repository.FindByEmail(suite.ctx, testUser.Email())
+	assert.Equal(suite.T(), errors.ErrUserNotFound, err)
+	exists, err := suite.repository.ExistsByEmail(suite.ctx, testUser.Email())
+	assert.NoError(suite.T(), err)
+	assert.False(suite.T(), exists)
+	found, err := suite.repository.FindByIDs(suite.ctx, []user.UserID{testUser.ID()})
+	assert.NoError(suite.T(), err)
+	assert.Empty(suite.T(), found)
+
+	page, err := suite.repository.FindAll(suite.ctx, newestFirst, user.PageRequest{First: 10})
+	assert.NoError(suite.T(), err)
+	assert.Empty(suite.T(), page.Users)
+
+	// Listed with a deleted-at timestamp when asked for
+	withDeleted := user.Criteria{Sort: user.DefaultSort, Filter: user.Filter{IncludeDeleted: true}}
+	page, err = suite.repository.FindAll(suite.ctx, withDeleted, user.PageRequest{First: 10})
+	assert.NoError(suite.T(), err)
+	require.Len(suite.T(), page.Users, 1)
+	assert.True(suite.T(), page.Users[0].IsDeleted())
+
+	count, err := suite.repository.Count(suite.ctx, withDeleted.Filter)
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(1), count)
+
+	// The email can be reused by a new live user
+	replacement, err := user.NewUser("test@example.com", "Replacement User")
+	require.NoError(suite.T(), err)
+	assert.NoError(suite.T(), suite.repository.Create(suite.ctx, replacement))
+}
+
+// TestRestoreUser tests restoring soft-deleted users
+func (suite *UserRepositoryTestSuite) TestRestoreUser() {
+	testUser, err := user.NewUser("test@example.com", "Test User")
+	require.NoError(suite.T(), err)
+	require.NoError(suite.T(), suite.repository.Create(suite.ctx, testUser))
+
+	// A live user cannot be restored
+	assert.Equal(suite.T(), errors.ErrUserNotDeleted, suite.repository.Restore(suite.ctx, testUser))
+
+	require.NoError(suite.T(), suite.repository.Delete(suite.ctx, testUser))
+	require.NoError(suite.T(), suite.repository.Restore(suite.ctx, testUser))
+
+	restored, err := suite.repository.FindByID(suite.ctx, testUser.ID())
+	require.NoError(suite.T(), err)
+	assert.False(suite.T(), restored.IsDeleted())
+
+	// Restoring is refused once another live user holds the email
+	require.NoError(suite.T(), suite.repository.Delete(suite.ctx, testUser))
+	replacement, err := user.NewUser("test@example.com", "Replacement User")
+	require.NoError(suite.T(), err)
+	require.NoError(suite.T(), suite.repository.Create(suite.ctx, replacement))
+	assert.Equal(suite.T(), errors.ErrDuplicateEmail, suite.repository.Restore(suite.ctx, testUser))
+
+	// Unknown users are reported missing
+	missing, err := user.NewUser("missing@example.com", "Missing User")
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), errors.ErrUserNotFound, suite.repository.Restore(suite.ctx, missing))
+}
+
+// TestUpdateUserVersionConflict tests that updates from a stale read are rejected
+func (suite *UserRepositoryTestSuite) TestUpdateUserVersionConflict() {
+	testUser, err := user.NewUser("test@example.com", "Test User")
+	require.NoError(suite.T(), err)
+	require.NoError(suite.T(), suite.repository.Create(suite.ctx, testUser))
+
+	first, err := suite.repository.FindByID(suite.ctx, testUser.ID())
+	require.NoError(suite.T(), err)
+	second, err := suite.repository.FindByID(suite.ctx, testUser.ID())
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(1), first.Version())
+
+	require.NoError(suite.T(), first.UpdateName("First Writer"))
+	require.NoError(suite.T(), suite.repository.Update(suite.ctx, first))
+	assert.Equal(suite.T(), int64(2), first.Version())
+
+	// The second writer read version 1 and must not overwrite the first
+	require.NoError(suite.T(), second.UpdateName("Second Writer"))
+	assert.Equal(suite.T(), errors.NewConflictError(2), suite.repository.Update(suite.ctx, second))
+
+	stored, err := suite.repository.FindByID(suite.ctx, testUser.ID())
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "First Writer", stored.Name().String())
+	assert.Equal(suite.T(), int64(2), stored.Version())
+}
+
+// TestWritesEventsToOutbox tests that pending events are stored only with a successful write
+func (suite *UserRepositoryTestSuite) TestWritesEventsToOutbox() {
+	testUser, err := user.NewUser("test@example.com", "Test User")
+	require.NoError(suite.T(), err)
+	require.NoError(suite.T(), suite.repository.Create(suite.ctx, testUser))
+	testUser.DrainEvents()
+
+	stale, err := suite.repository.FindByID(suite.ctx, testUser.ID())
+	require.NoError(suite.T(), err)
+
+	require.NoError(suite.T(), testUser.UpdateName("Renamed User"))
+	require.NoError(suite.T(), suite.repository.Update(suite.ctx, testUser))
+	testUser.DrainEvents()
+
+	// A conflicting update rolls back its events along with the change
+	require.NoError(suite.T(), stale.UpdateName("Lost Update"))
+	assert.IsType(suite.T(), errors.ConflictError{}, suite.repository.Update(suite.ctx, stale))
+
+	require.NoError(suite.T(), testUser.Delete())
+	require.NoError(suite.T(), suite.repository.Delete(suite.ctx, testUser))
+	testUser.DrainEvents()
+
+	require.NoError(suite.T(), testUser.Restore())
+	require.NoError(suite.T(), suite.repository.Restore(suite.ctx, testUser))
+	testUser.DrainEvents()
+
+	transition, err := testUser.ChangeStatus(user.StatusSuspended, "admin", "Spam")
+	require.NoError(suite.T(), err)
+	require.NoError(suite.T(), suite.repository.UpdateStatus(suite.ctx, testUser, transition))
+	testUser.DrainEvents()
+
+	testUser.Purge()
+	require.NoError(suite.T(), suite.repository.Purge(suite.ctx, testUser))
+
+	rows, err := suite.db.QueryContext(suite.ctx,
+		`SELECT event_name, aggregate_id, payload FROM outbox ORDER BY id`)
+	require.NoError(suite.T(), err)
+	defer rows.Close()
+
+	var names, payloads []string
+	for rows.Next() {
+		var name, aggregateID, payload string
+		require.NoError(suite.T(), rows.Scan(&name, &aggregateID, &payload))
+		assert.Equal(suite.T(), testUser.ID().String(), aggregateID)
+		names = append(names, name)
+		payloads = append(payloads, payload)
+	}
+	require.NoError(suite.T(), rows.Err())
+
+	assert.Equal(suite.T(), []string{
+		user.EventUserRegistered,
+		user.EventUserRenamed,
+		user.EventUserDeleted,
+		user.EventUserRestored,
+		user.EventUserStatusChanged,
+		user.EventUserPurged,
+	}, names)
+	assert.JSONEq(suite.T(),
+		fmt.Sprintf(`{"userId":%q,"from":"Test User","to":"Renamed User"}`, testUser.ID().String()),
+		payloads[1])
+}
+
+// TestRestoreAndPurgePinPrimary tests that restores and purges pin the request to the primary
+func (suite *UserRepositoryTestSuite) TestRestoreAndPurgePinPrimary() {
+	testUser, err := user.NewUser("test@example.com", "Test User")
+	require.NoError(suite.T(), err)
+	require.NoError(suite.T(), suite.repository.Create(suite.ctx, testUser))
+	require.NoError(suite.T(), suite.repository.Delete(suite.ctx, testUser))
+
+	ctx := database.WithReadYourWrites(suite.ctx)
+	require.NoError(suite.T(), suite.repository.Restore(ctx, testUser))
+	assert.True(suite.T(), database.PinnedToPrimary(ctx))
+
+	ctx = database.WithReadYourWrites(suite.ctx)
+	require.NoError(suite.T(), suite.repository.Purge(ctx, testUser))
+	assert.True(suite.T(), database.PinnedToPrimary(ctx))
+}
+
+// TestPurgeUser tests permanent removal of live and deleted users
+func (suite *UserRepositoryTestSuite) TestPurgeUser() {
+	live, err := user.NewUser("live@example.com", "Live User")
+	require.NoError(suite.T(), err)
+	require.NoError(suite.T(), suite.repository.Create(suite.ctx, live))
+
+	deleted, err := user.NewUser("deleted@example.com", "Deleted User")
+	require.NoError(suite.T(), err)
+	require.NoError(suite.T(), suite.repository.Create(suite.ctx, deleted))
+	require.NoError(suite.T(), suite.repository.Delete(suite.ctx, deleted))
+
+	assert.NoError(suite.T(), suite.repository.Purge(suite.ctx, live))
+	assert.NoError(suite.T(), suite.repository.Purge(suite.ctx, deleted))
+
+	count, err := suite.repository.Count(suite.ctx, user.Filter{IncludeDeleted: true})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(0), count)
+
+	assert.Equal(suite.T(), errors.ErrUserNotFound, suite.repository.Purge(suite.ctx, live))
+	assert.Equal(suite.T(), errors.ErrUserNotFound, suite.repository.Restore(suite.ctx, deleted))
+}
+
+// TestUpdateStatus tests status changes and their recorded transitions
+func (suite *UserRepositoryTestSuite) TestUpdateStatus() {
+	testUser, err := user.NewUser("test@example.com", "Test User")
+	require.NoError(suite.T(), err)
+	require.NoError(suite.T(), suite.repository.Create(suite.ctx, testUser))
+
+	// A stale copy still believes the user is active
+	stale, err := suite.repository.FindByID(suite.ctx, testUser.ID())
+	require.NoError(suite.T(), err)
+
+	transition, err := testUser.ChangeStatus(user.StatusSuspended, "admin", "Chargeback investigation")
+	require.NoError(suite.T(), err)
+	require.NoError(suite.T(), suite.repository.UpdateStatus(suite.ctx, testUser, transition))
+
+	found, err := suite.repository.FindByID(suite.ctx, testUser.ID())
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), user.StatusSuspended, found.Status())
+
+	var actor, reason, from, to string
+	err = suite.db.QueryRowContext(suite.ctx,
+		`SELECT actor, reason, from_status, to_status FROM user_status_transitions WHERE user_id = $1`,
+		testUser.ID().String(),
+	).Scan(&actor, &reason, &from, &to)
+	require.NoError(suite.T(), err)
+	assert.Equal(suite.T(), "admin", actor)
+	assert.Equal(suite.T(), "Chargeback investigation", reason)
+	assert.Equal(suite.T(), "ACTIVE", from)
+	assert.Equal(suite.T(), "SUSPENDED", to)
+
+	// The stale transition no longer matches the stored status
+	staleTransition, err := stale.ChangeStatus(user.StatusDeactivated, "admin", "Closed")
+	require.NoError(suite.T(), err)
+	err = suite.repository.UpdateStatus(suite.ctx, stale, staleTransition)
+	assert.Equal(suite.T(), user.NewInvalidStatusTransitionError(user.StatusSuspended, user.StatusDeactivated), err)
+
+	var transitions int
+	require.NoError(suite.T(), suite.db.QueryRowContext(suite.ctx,
+		`SELECT COUNT(*) FROM user_status_transitions WHERE user_id = $1`, testUser.ID().String(),
+	).Scan(&transitions))
+	assert.Equal(suite.T(), 1, transitions)
+}
+
+// TestFindAllStatusFilter tests filtering users by status
+func (suite *UserRepositoryTestSuite) TestFindAllStatusFilter() {
+	active, err := user.NewUser("active@example.com", "Active User")
+	require.NoError(suite.T(), err)
+	require.NoError(suite.T(), suite.repository.Create(suite.ctx, active))
+
+	suspended, err := user.NewUser("suspended@example.com", "Suspended User")
+	require.NoError(suite.T(), err)
+	require.NoError(suite.T(), suite.repository.Create(suite.ctx, suspended))
+	transition, err := suspended.ChangeStatus(user.StatusSuspended, "admin", "Spam")
+	require.NoError(suite.T(), err)
+	require.NoError(suite.T(), suite.repository.UpdateStatus(suite.ctx, suspended, transition))
+
+	criteria := user.Criteria{Sort: user.DefaultSort, Filter: user.Filter{Statuses: []user.Status{user.StatusSuspended}}}
+	page, err := suite.repository.FindAll(suite.ctx, criteria, user.PageRequest{First: 10})
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), page.Users, 1)
+	assert.Equal(suite.T(), suspended.ID(), page.Users[0].ID())
+
+	count, err := suite.repository.Count(suite.ctx, user.Filter{Statuses: []user.Status{user.StatusActive, user.StatusSuspended}})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(2), count)
+}
+
 // TestExistsByEmail tests checking user existence by email
 func (suite *UserRepositoryTestSuite) TestExistsByEmail() {
 	// Create a test user
@@ -193,6 +483,9 @@ func (suite *UserRepositoryTestSuite) TestExistsByEmail() {
 	assert.True(suite.T(), exists)
 }
 
+// newestFirst lists all users in the default order
+var newestFirst = user.Criteria{Sort: user.DefaultSort}
+
 // TestFindAll tests pagination
 func (suite *UserRepositoryTestSuite) TestFindAll() {
 	// Create multiple test users
@@ -210,23 +503,87 @@ func (suite *UserRepositoryTestSuite) TestFindAll() {
 		time.Sleep(10 * time.Millisecond)
 	}
 
-	// Test first page
-	foundUsers, nextCursor, err := suite.repository.FindAll(suite.ctx, 3, "")
+	// Test first page (newest first)
+	firstPage, err := suite.repository.FindAll(suite.ctx, newestFirst, user.PageRequest{First: 3})
 	assert.NoError(suite.T(), err)
-	assert.Len(suite.T(), foundUsers, 3)
-	assert.NotEmpty(suite.T(), nextCursor)
+	require.Len(suite.T(), firstPage.Users, 3)
+	assert.Equal(suite.T(), users[4].ID(), firstPage.Users[0].ID())
+	assert.True(suite.T(), firstPage.HasNextPage)
+	assert.False(suite.T(), firstPage.HasPreviousPage)
 
 	// Test second page
-	remainingUsers, finalCursor, err := suite.repository.FindAll(suite.ctx, 3, nextCursor)
+	after := user.CursorOf(firstPage.Users[2], user.DefaultSort)
+	secondPage, err := suite.repository.FindAll(suite.ctx, newestFirst, user.PageRequest{First: 3, After: &after})
+	assert.NoError(suite.T(), err)
+	require.Len(suite.T(), secondPage.Users, 2)
+	assert.Equal(suite.T(), users[1].ID(), secondPage.Users[0].ID())
+	assert.Equal(suite.T(), users[0].ID(), secondPage.Users[1].ID())
+	assert.False(suite.T(), secondPage.HasNextPage)
+	assert.True(suite.T(), secondPage.HasPreviousPage)
+}
+
+// TestFindAllBackward tests backward pagination with last/before
+func (suite *UserRepositoryTestSuite) TestFindAllBackward() {
+	users := make([]*user.User, 5)
+	for i := 0; i < 5; i++ {
+		u, err := user.NewUser(fmt.Sprintf("user%d@example.com", i), fmt.Sprintf("User %d", i))
+		require.NoError(suite.T(), err)
+
+		err = suite.repository.Create(suite.ctx, u)
+		require.NoError(suite.T(), err)
+
+		users[i] = u
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	// Last page keeps the forward ordering (newest first)
+	lastPage, err := suite.repository.FindAll(suite.ctx, newestFirst, user.PageRequest{Last: 2})
+	assert.NoError(suite.T(), err)
+	require.Len(suite.T(), lastPage.Users, 2)
+	assert.Equal(suite.T(), users[1].ID(), lastPage.Users[0].ID())
+	assert.Equal(suite.T(), users[0].ID(), lastPage.Users[1].ID())
+	assert.True(suite.T(), lastPage.HasPreviousPage)
+	assert.False(suite.T(), lastPage.HasNextPage)
+
+	// Page backward from the start of the last page
+	before := user.CursorOf(lastPage.Users[0], user.DefaultSort)
+	previousPage, err := suite.repository.FindAll(suite.ctx, newestFirst, user.PageRequest{Last: 3, Before: &before})
+	assert.NoError(suite.T(), err)
+	require.Len(suite.T(), previousPage.Users, 3)
+	assert.Equal(suite.T(), users[4].ID(), previousPage.Users[0].ID())
+	assert.Equal(suite.T(), users[2].ID(), previousPage.Users[2].ID())
+	assert.False(suite.T(), previousPage.HasPreviousPage)
+	assert.True(suite.T(), previousPage.HasNextPage)
+}
+
+// TestFindAllCursorOfDeletedUser tests that a cursor stays valid after its row is deleted
+func (suite *UserRepositoryTestSuite) TestFindAllCursorOfDeletedUser() {
+	users := make([]*user.User, 3)
+	for i := 0; i < 3; i++ {
+		u, err := user.NewUser(fmt.Sprintf("user%d@example.com", i), fmt.Sprintf("User %d", i))
+		require.NoError(suite.T(), err)
+
+		err = suite.repository.Create(suite.ctx, u)
+		require.NoError(suite.T(), err)
+
+		users[i] = u
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	after := user.CursorOf(users[1], user.DefaultSort)
+	require.NoError(suite.T(), suite.repository.Delete(suite.ctx, users[1]))
+
+	page, err := suite.repository.FindAll(suite.ctx, newestFirst, user.PageRequest{First: 10, After: &after})
 	assert.NoError(suite.T(), err)
-	assert.Len(suite.T(), remainingUsers, 2)
-	assert.NotEmpty(suite.T(), finalCursor)
+	require.Len(suite.T(), page.Users, 1)
+	assert.Equal(suite.T(), users[0].ID(), page.Users[0].ID())
+	assert.True(suite.T(), page.HasPreviousPage)
 }
 
 // TestCount tests user count
 func (suite *UserRepositoryTestSuite) TestCount() {
 	// Initial count should be 0
-	count, err := suite.repository.Count(suite.ctx)
+	count, err := suite.repository.Count(suite.ctx, user.Filter{})
 	assert.NoError(suite.T(), err)
 	assert.Equal(suite.T(), int64(0), count)
 
@@ -240,9 +597,74 @@ func (suite *UserRepositoryTestSuite) TestCount() {
 	}
 
 	// Count should be 3
-	count, err = suite.repository.Count(suite.ctx)
+	count, err = suite.repository.Count(suite.ctx, user.Filter{})
 	assert.NoError(suite.T(), err)
 	assert.Equal(suite.T(), int64(3), count)
+
+	// Count respects the filter
+	contains := "User 1"
+	count, err = suite.repository.Count(suite.ctx, user.Filter{Name: &user.TextFilter{Contains: &contains}})
+	assert.NoError(suite.T(), err)
+	assert.Equal(suite.T(), int64(1), count)
+}
+
+// TestCountSignups tests signup bucketing by day, week and month
+func (suite *UserRepositoryTestSuite) TestCountSignups() {
+	// Wednesday 2025-01-01 through Monday 2025-02-03
+	signups := []time.Time{
+		time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC),
+		time.Date(2025, 1, 1, 23, 59, 0, 0, time.UTC),
+		time.Date(2025, 1, 3, 12, 0, 0, 0, time.UTC),
+		time.Date(2025, 1, 6, 8, 0, 0, 0, time.UTC),
+		time.Date(2025, 2, 3, 8, 0, 0, 0, time.UTC),
+		time.Date(2024, 12, 31, 23, 59, 0, 0, time.UTC), // before the range
+	}
+	for i, createdAt := range signups {
+		u, err := user.NewUserWithID(user.GenerateUserID().String(), fmt.Sprintf("signup%d@example.com", i), "Signup", createdAt, createdAt)
+		require.NoError(suite.T(), err)
+		require.NoError(suite.T(), suite.repository.Create(suite.ctx, u))
+	}
+
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	suite.Run("day", func() {
+		buckets, err := suite.repository.CountSignups(suite.ctx, user.SignupStatsQuery{
+			From: from, To: from.AddDate(0, 0, 4), Interval: user.StatsIntervalDay,
+		})
+		require.NoError(suite.T(), err)
+		require.Len(suite.T(), buckets, 4)
+		assert.Equal(suite.T(), []int64{2, 0, 1, 0}, bucketCounts(buckets))
+		assert.True(suite.T(), from.Equal(buckets[0].Start))
+	})
+
+	suite.Run("week", func() {
+		buckets, err := suite.repository.CountSignups(suite.ctx, user.SignupStatsQuery{
+			From: from, To: from.AddDate(0, 0, 14), Interval: user.StatsIntervalWeek,
+		})
+		require.NoError(suite.T(), err)
+		require.Len(suite.T(), buckets, 3)
+		// Weeks start on Monday, so the first bucket begins before the range
+		assert.True(suite.T(), time.Date(2024, 12, 30, 0, 0, 0, 0, time.UTC).Equal(buckets[0].Start))
+		assert.Equal(suite.T(), []int64{3, 1, 0}, bucketCounts(buckets))
+	})
+
+	suite.Run("month", func() {
+		buckets, err := suite.repository.CountSignups(suite.ctx, user.SignupStatsQuery{
+			From: from, To: time.Date(2025, 3, 1, 0, 0, 0, 0, time.UTC), Interval: user.StatsIntervalMonth,
+		})
+		require.NoError(suite.T(), err)
+		require.Len(suite.T(), buckets, 2)
+		assert.Equal(suite.T(), []int64{4, 1}, bucketCounts(buckets))
+	})
+}
+
+// bucketCounts returns the counts of the buckets in order
+func bucketCounts(buckets []user.SignupBucket) []int64 {
+	counts := make([]int64, len(buckets))
+	for i, b := range buckets {
+		counts[i] = b.Count
+	}
+	return counts
 }
 
 // TestConcurrentOperations tests concurrent repository operations
@@ -288,7 +710,7 @@ func (suite *UserRepositoryTestSuite) TestConcurrentOperations() {
 	assert.Len(suite.T(), createdUsers, numUsers)
 
 	// Verify count
-	count, err := suite.repository.Count(suite.ctx)
+	count, err := suite.repository.Count(suite.ctx, user.Filter{})
 	assert.NoError(suite.T(), err)
 	assert.Equal(suite.T(), int64(numUsers), count)
 }
@@ -377,11 +799,11 @@ func (suite *UserRepositoryTestSuite) TestRepositoryPerformance() {
 
 	// Measure query time
 	start = time.Now()
-	users, _, err := suite.repository.FindAll(suite.ctx, numUsers, "")
+	page, err := suite.repository.FindAll(suite.ctx, newestFirst, user.PageRequest{First: numUsers})
 	queryTime := time.Since(start)
 
 	assert.NoError(suite.T(), err)
-	assert.Len(suite.T(), users, numUsers)
+	assert.Len(suite.T(), page.Users, numUsers)
 	suite.T().Logf("Queried %d users in %v", numUsers, queryTime)
 
 	// Performance assertions (adjust thresholds based on your requirements)
@@ -439,20 +861,194 @@ func (suite *UserRepositoryTestSuite) TestRepositoryEdgeCases() {
 	assert.NoError(suite.T(), err)
 
 	// Test pagination with edge cases
-	// Empty result set
-	users, cursor, err := suite.repository.FindAll(suite.ctx, 10, "nonexistent-cursor")
+	// Empty result set past the oldest possible position
+	oldest := user.Cursor{Value: time.Unix(0, 0), ID: longUser.ID()}
+	page, err := suite.repository.FindAll(suite.ctx, newestFirst, user.PageRequest{First: 10, After: &oldest})
 	assert.NoError(suite.T(), err)
-	assert.Empty(suite.T(), users)
-	assert.Empty(suite.T(), cursor)
+	assert.Empty(suite.T(), page.Users)
+	assert.False(suite.T(), page.HasNextPage)
 
 	// Zero limit (should handle gracefully)
-	users, cursor, err = suite.repository.FindAll(suite.ctx, 0, "")
+	page, err = suite.repository.FindAll(suite.ctx, newestFirst, user.PageRequest{})
 	assert.NoError(suite.T(), err)
-	assert.Empty(suite.T(), users)
-	assert.Empty(suite.T(), cursor)
+	assert.Empty(suite.T(), page.Users)
+}
+
+// TestFindAllSortOrders tests keyset pagination under every sort order, including ties
+func (suite *UserRepositoryTestSuite) TestFindAllSortOrders() {
+	// Duplicate names force the id tie-breaker to decide the order
+	names := []string{"Carol", "alice", "Bob", "Carol", "alice", "Dave"}
+	for i, name := range names {
+		u, err := user.NewUser(fmt.Sprintf("user%d@example.com", i), name)
+		require.NoError(suite.T(), err)
+		require.NoError(suite.T(), suite.repository.Create(suite.ctx, u))
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	for _, field := range []user.SortField{user.SortByName, user.SortByEmail, user.SortByCreatedAt, user.SortByUpdatedAt} {
+		for _, direction := range []user.SortDirection{user.SortAsc, user.SortDesc} {
+			criteria := user.Criteria{Sort: user.Sort{Field: field, Direction: direction}}
+			name := fmt.Sprintf("%s_%s", field, direction)
+
+			suite.Run(name, func() {
+				// The whole listing in one page is the reference ordering
+				all, err := suite.repository.FindAll(suite.ctx, criteria, user.PageRequest{First: 100})
+				require.NoError(suite.T(), err)
+				require.Len(suite.T(), all.Users, len(names))
+
+				// Walking forward two at a time must visit the same users in the same order
+				var forward []string
+				page := user.PageRequest{First: 2}
+				for {
+					result, err := suite.repository.FindAll(suite.ctx, criteria, page)
+					require.NoError(suite.T(), err)
+					for _, u := range result.Users {
+						forward = append(forward, u.ID().String())
+					}
+					if !result.HasNextPage {
+						break
+					}
+					after := user.CursorOf(result.Users[len(result.Users)-1], criteria.Sort)
+					page = user.PageRequest{First: 2, After: &after}
+				}
+
+				// Walking backward must too
+				var backward []string
+				page = user.PageRequest{Last: 2}
+				for {
+					result, err := suite.repository.FindAll(suite.ctx, criteria, page)
+					require.NoError(suite.T(), err)
+					ids := make([]string, 0, len(result.Users))
+					for _, u := range result.Users {
+						ids = append(ids, u.ID().String())
+					}
+					backward = append(ids, backward...)
+					if !result.HasPreviousPage {
+						break
+					}
+					before := user.CursorOf(result.Users[0], criteria.Sort)
+					page = user.PageRequest{Last: 2, Before: &before}
+				}
+
+				expected := make([]string, len(all.Users))
+				for i, u := range all.Users {
+					expected[i] = u.ID().String()
+				}
+				assert.Equal(suite.T(), expected, forward)
+				assert.Equal(suite.T(), expected, backward)
+			})
+		}
+	}
+}
+
+// TestFindAllFilter tests filtering by text, time ranges and ids
+func (suite *UserRepositoryTestSuite) TestFindAllFilter() {
+	alice, err := user.NewUser("alice@example.com", "Alice 100%")
+	require.NoError(suite.T(), err)
+	require.NoError(suite.T(), suite.repository.Create(suite.ctx, alice))
+
+	bob, err := user.NewUser("bob@example.org", "Bob")
+	require.NoError(suite.T(), err)
+	require.NoError(suite.T(), suite.repository.Create(suite.ctx, bob))
+
+	carol, err := user.NewUser("carol@example.com", "Carol")
+	require.NoError(suite.T(), err)
+	require.NoError(suite.T(), suite.repository.Create(suite.ctx, carol))
+
+	text := func(s string) *string { return &s }
+	future := time.Now().Add(time.Hour)
+
+	tests := []struct {
+		name   string
+		filter user.Filter
+		want   []*user.User
+	}{
+		{
+			name:   "email equals",
+			filter: user.Filter{Email: &user.TextFilter{Equals: text("bob@example.org")}},
+			want:   []*user.User{bob},
+		},
+		{
+			name:   "email contains is case-insensitive",
+			filter: user.Filter{Email: &user.TextFilter{Contains: text("EXAMPLE.COM")}},
+			want:   []*user.User{carol, alice},
+		},
+		{
+			name:   "name contains treats wildcards literally",
+			filter: user.Filter{Name: &user.TextFilter{Contains: text("%")}},
+			want:   []*user.User{alice},
+		},
+		{
+			name:   "created before a bound in the future",
+			filter: user.Filter{CreatedAt: &user.TimeRange{To: &future}},
+			want:   []*user.User{carol, bob, alice},
+		},
+		{
+			name:   "updated after a bound in the future",
+			filter: user.Filter{UpdatedAt: &user.TimeRange{From: &future}},
+			want:   nil,
+		},
+		{
+			name:   "id in list",
+			filter: user.Filter{IDs: []user.UserID{alice.ID(), carol.ID()}},
+			want:   []*user.User{carol, alice},
+		},
+		{
+			name:   "empty id list matches nothing",
+			filter: user.Filter{IDs: []user.UserID{}},
+			want:   nil,
+		},
+		{
+			name: "conditions are combined",
+			filter: user.Filter{
+				Email: &user.TextFilter{Contains: text("example.com")},
+				Name:  &user.TextFilter{Equals: text("Carol")},
+			},
+			want: []*user.User{carol},
+		},
+	}
+
+	for _, tt := range tests {
+		suite.Run(tt.name, func() {
+			page, err := suite.repository.FindAll(suite.ctx, user.Criteria{Filter: tt.filter, Sort: user.DefaultSort}, user.PageRequest{First: 10})
+			require.NoError(suite.T(), err)
+			require.Len(suite.T(), page.Users, len(tt.want))
+			for i, u := range tt.want {
+				assert.Equal(suite.T(), u.ID(), page.Users[i].ID())
+			}
+		})
+	}
+
+	// Adjacent-page detection honours the filter
+	comUsers := user.Criteria{Filter: user.Filter{Email: &user.TextFilter{Contains: text(".com")}}, Sort: user.DefaultSort}
+	after := user.CursorOf(carol, user.DefaultSort)
+	page, err := suite.repository.FindAll(suite.ctx, comUsers, user.PageRequest{First: 1, After: &after})
+	require.NoError(suite.T(), err)
+	require.Len(suite.T(), page.Users, 1)
+	assert.Equal(suite.T(), alice.ID(), page.Users[0].ID())
+	assert.False(suite.T(), page.HasNextPage)
+	assert.True(suite.T(), page.HasPreviousPage)
 }
 
 // TestUserRepositoryIntegration runs the integration test suite
 func TestUserRepositoryIntegration(t *testing.T) {
 	suite.Run(t, new(UserRepositoryTestSuite))
 }
+
+// TestUserRepositoryContract runs the shared repository behavior suite against PostgreSQL
+func TestUserRepositoryContract(t *testing.T) {
+	db, cleanup := database.TestDBSetup(t, migrations.Postgres)
+	defer cleanup()
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	repository := NewUserRepository(db, database.NewTxManager(db, logger), logger)
+
+	repotest.Run(t, func(t *testing.T) user.Repository {
+		// Status transitions go with their users
+		_, err := db.ExecContext(context.Background(), "DELETE FROM users")
+		require.NoError(t, err)
+		_, err = db.ExecContext(context.Background(), "DELETE FROM outbox")
+		require.NoError(t, err)
+		return repository
+	})
+}
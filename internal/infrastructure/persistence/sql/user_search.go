@@ -0,0 +1,99 @@
+package sql
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/captain-corgi/go-graphql-example/internal/domain/user"
+)
+
+var _ user.SearchRepository = (*userRepository)(nil)
+
+// searchRank scores a user against the search: the full-text rank of the query terms in
+// search_vector plus the trigram word similarity of the whole query to the closer of name and email.
+// It is computed in float8 so the ranks written into cursors compare exactly on the next page
+const searchRank = `(ts_rank(search_vector, q.terms) + GREATEST(word_similarity($1, name), word_similarity($1, email)))::float8`
+
+// Search ranks live users by how well their name and email match the query
+// Users match when a query term begins a word of their name or email, or when the whole query
+// reaches pg_trgm.word_similarity_threshold (0.6 unless configured) against either field.
+// Both conditions are answered by the GIN indexes of migration 008
+func (r *userRepository) Search(ctx context.Context, query user.SearchQuery) (*user.SearchPage, error) {
+	r.logger.DebugContext(ctx, "Searching users", "first", query.First, "has_after", query.After != nil)
+
+	// Terms are letters and digits only, so they can be joined into tsquery syntax;
+	// each matches as a prefix and any of them is enough
+	terms := query.Terms()
+	prefixes := make([]string, len(terms))
+	for i, term := range terms {
+		prefixes[i] = term + ":*"
+	}
+
+	args := []interface{}{query.Text, strings.Join(prefixes, " | ")}
+
+	var keyset string
+	if query.After != nil {
+		// Ranks descend and ties ascend by id
+		args = append(args, query.After.Rank, query.After.ID.String())
+		keyset = "\n\t\tWHERE rank < $3 OR (rank = $3 AND id > $4)"
+	}
+
+	// Fetch one extra row to detect whether another page exists
+	args = append(args, query.First+1)
+	q := fmt.Sprintf(`
+		SELECT %s, rank
+		FROM (
+			SELECT %s, %s AS rank
+			FROM users, to_tsquery('simple', $2) AS q(terms)
+			WHERE deleted_at IS NULL
+			  AND (search_vector @@ q.terms OR $1 <%% name OR $1 <%% email)
+		) AS matches%s
+		ORDER BY rank DESC, id
+		LIMIT $%d`, userColumns, userColumns, searchRank, keyset, len(args))
+
+	rows, err := r.db.Reader(ctx).QueryContext(ctx, q, args...)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Failed to search users", "error", err)
+		return nil, fmt.Errorf("failed to search users: %w", err)
+	}
+	defer rows.Close()
+
+	var hits []user.SearchHit
+	for rows.Next() {
+		var rank float64
+		domainUser, err := r.scanUser(ctx, rankedRow{rows: rows, rank: &rank})
+		if err != nil {
+			r.logger.ErrorContext(ctx, "Failed to scan search result", "error", err)
+			return nil, fmt.Errorf("failed to scan search result: %w", err)
+		}
+
+		hits = append(hits, user.SearchHit{User: domainUser, Rank: rank})
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.ErrorContext(ctx, "Error iterating over search results", "error", err)
+		return nil, fmt.Errorf("error iterating over search results: %w", err)
+	}
+
+	page := &user.SearchPage{Hits: hits}
+	if len(hits) > query.First {
+		page.Hits = hits[:query.First]
+		page.HasNextPage = true
+	}
+
+	r.logger.DebugContext(ctx, "Successfully searched users", "count", len(page.Hits), "has_next_page", page.HasNextPage)
+	return page, nil
+}
+
+// rankedRow reads the rank selected after userColumns along with the user
+type rankedRow struct {
+	rows *sql.Rows
+	rank *float64
+}
+
+// Scan scans the user columns into dest and the rank that follows them
+func (r rankedRow) Scan(dest ...interface{}) error {
+	return r.rows.Scan(append(dest, r.rank)...)
+}
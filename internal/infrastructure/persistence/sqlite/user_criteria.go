@@ -0,0 +1,150 @@
+package sqlite
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/captain-corgi/go-graphql-example/internal/domain/user"
+)
+
+// timeLayout is how times are stored: fixed-width UTC, so text order is time order
+const timeLayout = "2006-01-02T15:04:05.000000000Z"
+
+// sortColumns maps domain sort fields to the users columns they order by
+var sortColumns = map[user.SortField]string{
+	user.SortByName:      "name",
+	user.SortByEmail:     "email",
+	user.SortByCreatedAt: "created_at",
+	user.SortByUpdatedAt: "updated_at",
+}
+
+// likeEscaper escapes LIKE wildcards so user input is matched literally
+var likeEscaper = strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+
+// formatTime encodes a time as stored in the users table
+func formatTime(t time.Time) string {
+	return t.UTC().Format(timeLayout)
+}
+
+// parseTime decodes a time stored by formatTime
+func parseTime(s string) (time.Time, error) {
+	return time.Parse(timeLayout, s)
+}
+
+// dbValue converts a cursor or filter value into its stored form
+func dbValue(value interface{}) interface{} {
+	if t, ok := value.(time.Time); ok {
+		return formatTime(t)
+	}
+	return value
+}
+
+// conditionBuilder accumulates parameterized WHERE conditions for the users table
+type conditionBuilder struct {
+	conditions []string
+	args       []interface{}
+}
+
+// arg registers a query argument and returns its placeholder
+func (b *conditionBuilder) arg(value interface{}) string {
+	b.args = append(b.args, dbValue(value))
+	return fmt.Sprintf("?%d", len(b.args))
+}
+
+// in registers one argument per value and returns the parenthesized placeholder list
+func (b *conditionBuilder) in(values []string) string {
+	placeholders := make([]string, len(values))
+	for i, value := range values {
+		placeholders[i] = b.arg(value)
+	}
+	return "(" + strings.Join(placeholders, ", ") + ")"
+}
+
+// add appends a condition; conditions are joined with AND
+func (b *conditionBuilder) add(condition string) {
+	b.conditions = append(b.conditions, condition)
+}
+
+// where renders the WHERE clause, or an empty string when there are no conditions
+func (b *conditionBuilder) where() string {
+	if len(b.conditions) == 0 {
+		return ""
+	}
+	return "\n\t\tWHERE " + strings.Join(b.conditions, " AND ")
+}
+
+// addFilter translates a domain filter into conditions
+func (b *conditionBuilder) addFilter(filter user.Filter) {
+	if !filter.IncludeDeleted {
+		b.add("deleted_at IS NULL")
+	}
+	b.addTextFilter("email", filter.Email)
+	b.addTextFilter("name", filter.Name)
+	b.addTimeRange("created_at", filter.CreatedAt)
+	b.addTimeRange("updated_at", filter.UpdatedAt)
+
+	// A non-nil empty list matches no users
+	if filter.IDs != nil {
+		ids := make([]string, len(filter.IDs))
+		for i, id := range filter.IDs {
+			ids[i] = id.String()
+		}
+		b.add("id IN " + b.in(ids))
+	}
+
+	if filter.Statuses != nil {
+		statuses := make([]string, len(filter.Statuses))
+		for i, status := range filter.Statuses {
+			statuses[i] = string(status)
+		}
+		b.add("status IN " + b.in(statuses))
+	}
+}
+
+// addTextFilter matches Contains with LIKE, which SQLite already applies case-insensitively to ASCII
+func (b *conditionBuilder) addTextFilter(column string, filter *user.TextFilter) {
+	if filter == nil {
+		return
+	}
+	if filter.Equals != nil {
+		b.add(fmt.Sprintf("%s = %s", column, b.arg(*filter.Equals)))
+	}
+	if filter.Contains != nil {
+		b.add(fmt.Sprintf(`%s LIKE %s ESCAPE '\'`, column, b.arg("%"+likeEscaper.Replace(*filter.Contains)+"%")))
+	}
+}
+
+func (b *conditionBuilder) addTimeRange(column string, r *user.TimeRange) {
+	if r == nil {
+		return
+	}
+	if r.From != nil {
+		b.add(fmt.Sprintf("%s >= %s", column, b.arg(*r.From)))
+	}
+	if r.To != nil {
+		b.add(fmt.Sprintf("%s < %s", column, b.arg(*r.To)))
+	}
+}
+
+// addKeyset restricts rows to one side of a cursor, comparing (sort column, id) as a row
+func (b *conditionBuilder) addKeyset(field user.SortField, op string, cursor user.Cursor) {
+	b.add(fmt.Sprintf("(%s, id) %s (%s, %s)", sortColumns[field], op, b.arg(cursor.Value), b.arg(cursor.ID.String())))
+}
+
+// keysetOps returns the comparisons selecting rows that sort after and before a cursor
+// in the given direction
+func keysetOps(direction user.SortDirection) (after, before string) {
+	if direction == user.SortAsc {
+		return ">", "<"
+	}
+	return "<", ">"
+}
+
+// reverse returns the opposite sort direction
+func reverse(direction user.SortDirection) user.SortDirection {
+	if direction == user.SortAsc {
+		return user.SortDesc
+	}
+	return user.SortAsc
+}
@@ -0,0 +1,69 @@
+package sqlite
+
+import (
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/captain-corgi/go-graphql-example/internal/domain/user"
+)
+
+func TestFormatTime_SortsInTimeOrder(t *testing.T) {
+	earlier := time.Date(2025, 1, 19, 22, 30, 0, 0, time.FixedZone("UTC-5", -5*60*60))
+	later := earlier.Add(time.Nanosecond)
+
+	assert.Equal(t, "2025-01-20T03:30:00.000000000Z", formatTime(earlier))
+	assert.Less(t, formatTime(earlier), formatTime(later))
+
+	parsed, err := parseTime(formatTime(later))
+	require.NoError(t, err)
+	assert.True(t, later.Equal(parsed))
+}
+
+func TestConditionBuilder_AddFilter(t *testing.T) {
+	from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	contains := "50%"
+	id := user.GenerateUserID()
+
+	var b conditionBuilder
+	b.addFilter(user.Filter{
+		Name:      &user.TextFilter{Contains: &contains},
+		CreatedAt: &user.TimeRange{From: &from},
+		IDs:       []user.UserID{id},
+		Statuses:  []user.Status{user.StatusActive, user.StatusInvited},
+	})
+
+	assert.Equal(t, "\n\t\tWHERE deleted_at IS NULL AND name LIKE ?1 ESCAPE '\\' AND created_at >= ?2 AND id IN (?3) AND status IN (?4, ?5)", b.where())
+	assert.Equal(t, []interface{}{`%50\%%`, "2025-01-01T00:00:00.000000000Z", id.String(), "ACTIVE", "INVITED"}, b.args)
+}
+
+// codedError mimics a driver error exposing SQLite's extended result code
+type codedError struct {
+	code int
+	msg  string
+}
+
+func (e codedError) Error() string { return e.msg }
+func (e codedError) Code() int     { return e.code }
+
+func TestIsDuplicateEmail(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"live email index", codedError{2067, "constraint failed: UNIQUE constraint failed: users.email (2067)"}, true},
+		{"primary key", codedError{1555, "constraint failed: UNIQUE constraint failed: users.id (1555)"}, false},
+		{"check constraint", codedError{275, "constraint failed: CHECK constraint failed: users_status_check (275)"}, false},
+		{"other error", fmt.Errorf("UNIQUE constraint failed: users.email"), false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, isDuplicateEmail(tt.err))
+		})
+	}
+}
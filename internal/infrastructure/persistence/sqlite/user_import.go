@@ -0,0 +1,108 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/captain-corgi/go-graphql-example/internal/domain/errors"
+	"github.com/captain-corgi/go-graphql-example/internal/domain/user"
+)
+
+var _ user.ImportRepository = (*userRepository)(nil)
+
+// ExistingEmails returns those of the given emails already held by live users
+func (r *userRepository) ExistingEmails(ctx context.Context, emails []user.Email) ([]user.Email, error) {
+	r.logger.DebugContext(ctx, "Finding existing emails", "count", len(emails))
+
+	if len(emails) == 0 {
+		return nil, nil
+	}
+
+	placeholders := make([]string, len(emails))
+	args := make([]interface{}, len(emails))
+	for i, email := range emails {
+		placeholders[i] = "?"
+		args[i] = email.String()
+	}
+
+	rows, err := r.db.Executor(ctx).QueryContext(ctx, `
+		SELECT email
+		FROM users
+		WHERE email IN (`+strings.Join(placeholders, ", ")+`) AND deleted_at IS NULL`, args...)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Failed to query existing emails", "error", err)
+		return nil, fmt.Errorf("failed to query existing emails: %w", err)
+	}
+	defer rows.Close()
+
+	var existing []user.Email
+	for rows.Next() {
+		var raw string
+		if err := rows.Scan(&raw); err != nil {
+			r.logger.ErrorContext(ctx, "Failed to scan existing email", "error", err)
+			return nil, fmt.Errorf("failed to scan existing email: %w", err)
+		}
+
+		email, err := user.NewEmail(raw)
+		if err != nil {
+			return nil, fmt.Errorf("invalid email stored for a user: %w", err)
+		}
+		existing = append(existing, email)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.ErrorContext(ctx, "Error iterating over existing emails", "error", err)
+		return nil, fmt.Errorf("error iterating over existing emails: %w", err)
+	}
+
+	return existing, nil
+}
+
+// CreateBatch persists new users in one transaction
+// SQLite has no COPY; a prepared insert per user is cheap within a single transaction
+func (r *userRepository) CreateBatch(ctx context.Context, users []*user.User) error {
+	r.logger.DebugContext(ctx, "Creating users in bulk", "count", len(users))
+
+	if len(users) == 0 {
+		return nil
+	}
+
+	err := r.txManager.Execute(ctx, "create users in bulk", func(tx *sql.Tx) error {
+		stmt, err := tx.PrepareContext(ctx, `
+			INSERT INTO users (id, email, name, created_at, updated_at, status, version)
+			VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7)`)
+		if err != nil {
+			return fmt.Errorf("failed to prepare bulk insert: %w", err)
+		}
+		defer stmt.Close()
+
+		for _, u := range users {
+			_, err := stmt.ExecContext(ctx,
+				u.ID().String(),
+				u.Email().String(),
+				u.Name().String(),
+				formatTime(u.CreatedAt()),
+				formatTime(u.UpdatedAt()),
+				string(u.Status()),
+				u.Version(),
+			)
+			if err != nil {
+				if isDuplicateEmail(err) {
+					r.logger.WarnContext(ctx, "Duplicate email constraint violation in bulk create", "email", u.Email().String())
+					return errors.ErrDuplicateEmail
+				}
+				r.logger.ErrorContext(ctx, "Failed to create users in bulk", "error", err, "user_id", u.ID().String())
+				return fmt.Errorf("failed to create user: %w", err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	r.logger.InfoContext(ctx, "Successfully created users in bulk", "count", len(users))
+	return nil
+}
@@ -0,0 +1,690 @@
+package sqlite
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log/slog"
+	"strings"
+	"time"
+
+	"github.com/captain-corgi/go-graphql-example/internal/domain/errors"
+	"github.com/captain-corgi/go-graphql-example/internal/domain/user"
+	"github.com/captain-corgi/go-graphql-example/internal/infrastructure/database"
+)
+
+// userRepository implements the user.Repository interface on a SQLite database
+// There is no outbox: pending domain events are left on the user for the
+// application service to dispatch in process.
+type userRepository struct {
+	db        *database.DB
+	txManager *database.TxManager
+	logger    *slog.Logger
+}
+
+// userColumns lists the users columns every lookup selects, in scanUser order
+const userColumns = `id, email, name, created_at, updated_at, deleted_at, status, version`
+
+// sqliteConstraintUnique is SQLite's extended result code for a UNIQUE constraint violation
+const sqliteConstraintUnique = 2067
+
+// usersEmailColumn is how SQLite names the column of the live email index in its violation message
+const usersEmailColumn = "users.email"
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// NewUserRepository creates a new SQLite-based user repository
+// Multi-statement writes run through txManager so they join the caller's unit of work.
+// The repository also implements user.SearchRepository and user.ImportRepository
+func NewUserRepository(db *database.DB, txManager *database.TxManager, logger *slog.Logger) user.Repository {
+	return &userRepository{
+		db:        db,
+		txManager: txManager,
+		logger:    logger,
+	}
+}
+
+// FindByID retrieves a user by their ID
+func (r *userRepository) FindByID(ctx context.Context, id user.UserID) (*user.User, error) {
+	r.logger.DebugContext(ctx, "Finding user by ID", "user_id", id.String())
+
+	query := `
+		SELECT ` + userColumns + `
+		FROM users
+		WHERE id = ?1 AND deleted_at IS NULL`
+
+	domainUser, err := r.scanUser(ctx, r.db.Executor(ctx).QueryRowContext(ctx, query, id.String()))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			r.logger.DebugContext(ctx, "User not found", "user_id", id.String())
+			return nil, errors.ErrUserNotFound
+		}
+		r.logger.ErrorContext(ctx, "Failed to find user by ID", "error", err, "user_id", id.String())
+		return nil, fmt.Errorf("failed to find user by ID: %w", err)
+	}
+
+	r.logger.DebugContext(ctx, "Successfully found user by ID", "user_id", id.String())
+	return domainUser, nil
+}
+
+// FindByIDs retrieves the users with the given IDs in a single query
+func (r *userRepository) FindByIDs(ctx context.Context, ids []user.UserID) ([]*user.User, error) {
+	r.logger.DebugContext(ctx, "Finding users by IDs", "count", len(ids))
+
+	if len(ids) == 0 {
+		return []*user.User{}, nil
+	}
+
+	rawIDs := make([]string, len(ids))
+	for i, id := range ids {
+		rawIDs[i] = id.String()
+	}
+
+	var b conditionBuilder
+	b.add("id IN " + b.in(rawIDs))
+	b.add("deleted_at IS NULL")
+
+	query := `
+		SELECT ` + userColumns + `
+		FROM users` + b.where()
+
+	rows, err := r.db.Executor(ctx).QueryContext(ctx, query, b.args...)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Failed to query users by IDs", "error", err)
+		return nil, fmt.Errorf("failed to query users by IDs: %w", err)
+	}
+	defer rows.Close()
+
+	users := make([]*user.User, 0, len(ids))
+	for rows.Next() {
+		domainUser, err := r.scanUser(ctx, rows)
+		if err != nil {
+			r.logger.ErrorContext(ctx, "Failed to scan user row", "error", err)
+			return nil, fmt.Errorf("failed to scan user row: %w", err)
+		}
+
+		users = append(users, domainUser)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.ErrorContext(ctx, "Error iterating over user rows", "error", err)
+		return nil, fmt.Errorf("error iterating over user rows: %w", err)
+	}
+
+	r.logger.DebugContext(ctx, "Successfully found users by IDs", "requested", len(ids), "found", len(users))
+	return users, nil
+}
+
+// FindByEmail retrieves a user by their email address
+func (r *userRepository) FindByEmail(ctx context.Context, email user.Email) (*user.User, error) {
+	r.logger.DebugContext(ctx, "Finding user by email", "email", email.String())
+
+	query := `
+		SELECT ` + userColumns + `
+		FROM users
+		WHERE email = ?1 AND deleted_at IS NULL`
+
+	domainUser, err := r.scanUser(ctx, r.db.Executor(ctx).QueryRowContext(ctx, query, email.String()))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			r.logger.DebugContext(ctx, "User not found by email", "email", email.String())
+			return nil, errors.ErrUserNotFound
+		}
+		r.logger.ErrorContext(ctx, "Failed to find user by email", "error", err, "email", email.String())
+		return nil, fmt.Errorf("failed to find user by email: %w", err)
+	}
+
+	r.logger.DebugContext(ctx, "Successfully found user by email", "email", email.String())
+	return domainUser, nil
+}
+
+// FindAll retrieves a keyset page of the users matching the criteria
+func (r *userRepository) FindAll(ctx context.Context, criteria user.Criteria, page user.PageRequest) (*user.Page, error) {
+	r.logger.DebugContext(ctx, "Finding all users",
+		"first", page.First,
+		"last", page.Last,
+		"has_after", page.After != nil,
+		"has_before", page.Before != nil,
+		"sort_field", criteria.Sort.Field,
+		"sort_direction", criteria.Sort.Direction,
+	)
+
+	column, ok := sortColumns[criteria.Sort.Field]
+	if !ok {
+		return nil, errors.ErrInvalidSort
+	}
+
+	afterOp, beforeOp := keysetOps(criteria.Sort.Direction)
+
+	limit := page.First
+	order := criteria.Sort.Direction
+	if page.IsBackward() {
+		// Walk the keyset from the other end and reverse the rows afterwards
+		limit = page.Last
+		order = reverse(order)
+	}
+
+	var b conditionBuilder
+	b.addFilter(criteria.Filter)
+	if page.After != nil {
+		b.addKeyset(criteria.Sort.Field, afterOp, *page.After)
+	}
+	if page.Before != nil {
+		b.addKeyset(criteria.Sort.Field, beforeOp, *page.Before)
+	}
+
+	// Fetch one extra row to detect whether another page exists in the walking direction
+	query := fmt.Sprintf(`
+		SELECT %s
+		FROM users%s
+		ORDER BY %s %s, id %s
+		LIMIT %s`, userColumns, b.where(), column, order, order, b.arg(limit+1))
+
+	rows, err := r.db.Executor(ctx).QueryContext(ctx, query, b.args...)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Failed to query users", "error", err)
+		return nil, fmt.Errorf("failed to query users: %w", err)
+	}
+	defer rows.Close()
+
+	var users []*user.User
+
+	for rows.Next() {
+		domainUser, err := r.scanUser(ctx, rows)
+		if err != nil {
+			r.logger.ErrorContext(ctx, "Failed to scan user row", "error", err)
+			return nil, fmt.Errorf("failed to scan user row: %w", err)
+		}
+
+		users = append(users, domainUser)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.ErrorContext(ctx, "Error iterating over user rows", "error", err)
+		return nil, fmt.Errorf("error iterating over user rows: %w", err)
+	}
+
+	hasMore := len(users) > limit
+	if hasMore {
+		users = users[:limit]
+	}
+
+	result := &user.Page{Users: users}
+
+	if page.IsBackward() {
+		for i, j := 0, len(users)-1; i < j; i, j = i+1, j-1 {
+			users[i], users[j] = users[j], users[i]
+		}
+		result.HasPreviousPage = hasMore
+		if page.Before != nil {
+			// Anything at or past the before cursor is on the next page
+			result.HasNextPage, err = r.existsBeyond(ctx, criteria, afterOp+"=", *page.Before)
+		}
+	} else {
+		result.HasNextPage = hasMore
+		if page.After != nil {
+			// Anything at or ahead of the after cursor is on the previous page
+			result.HasPreviousPage, err = r.existsBeyond(ctx, criteria, beforeOp+"=", *page.After)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	r.logger.DebugContext(ctx, "Successfully found users",
+		"count", len(users),
+		"has_next_page", result.HasNextPage,
+		"has_previous_page", result.HasPreviousPage,
+	)
+	return result, nil
+}
+
+// existsBeyond reports whether any user matching the criteria lies on the op side of the cursor
+func (r *userRepository) existsBeyond(ctx context.Context, criteria user.Criteria, op string, cursor user.Cursor) (bool, error) {
+	var b conditionBuilder
+	b.addFilter(criteria.Filter)
+	b.addKeyset(criteria.Sort.Field, op, cursor)
+
+	query := fmt.Sprintf(`SELECT EXISTS(SELECT 1 FROM users%s)`, b.where())
+
+	var exists bool
+	if err := r.db.Executor(ctx).QueryRowContext(ctx, query, b.args...).Scan(&exists); err != nil {
+		r.logger.ErrorContext(ctx, "Failed to check for adjacent users", "error", err)
+		return false, fmt.Errorf("failed to check for adjacent users: %w", err)
+	}
+
+	return exists, nil
+}
+
+// Create persists a new user
+func (r *userRepository) Create(ctx context.Context, u *user.User) error {
+	r.logger.DebugContext(ctx, "Creating user", "user_id", u.ID().String(), "email", u.Email().String())
+
+	query := `
+		INSERT INTO users (id, email, name, created_at, updated_at, status, version)
+		VALUES (?1, ?2, ?3, ?4, ?5, ?6, ?7)`
+
+	_, err := r.db.Executor(ctx).ExecContext(ctx, query,
+		u.ID().String(),
+		u.Email().String(),
+		u.Name().String(),
+		formatTime(u.CreatedAt()),
+		formatTime(u.UpdatedAt()),
+		string(u.Status()),
+		u.Version(),
+	)
+	if err != nil {
+		// Check for unique constraint violation (duplicate email)
+		if isDuplicateEmail(err) {
+			r.logger.WarnContext(ctx, "Duplicate email constraint violation", "email", u.Email().String())
+			return errors.ErrDuplicateEmail
+		}
+		r.logger.ErrorContext(ctx, "Failed to create user", "error", err, "user_id", u.ID().String())
+		return fmt.Errorf("failed to create user: %w", err)
+	}
+
+	r.logger.InfoContext(ctx, "Successfully created user", "user_id", u.ID().String(), "email", u.Email().String())
+	return nil
+}
+
+// Update modifies an existing user if nobody else has written it since it was read
+func (r *userRepository) Update(ctx context.Context, u *user.User) error {
+	r.logger.DebugContext(ctx, "Updating user", "user_id", u.ID().String(), "email", u.Email().String())
+
+	query := `
+		UPDATE users
+		SET email = ?2, name = ?3, updated_at = ?4, version = version + 1
+		WHERE id = ?1 AND version = ?5 AND deleted_at IS NULL`
+
+	err := r.txManager.Execute(ctx, "update user", func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, query,
+			u.ID().String(),
+			u.Email().String(),
+			u.Name().String(),
+			formatTime(u.UpdatedAt()),
+			u.Version(),
+		)
+
+		if err != nil {
+			// Check for unique constraint violation (duplicate email)
+			if isDuplicateEmail(err) {
+				r.logger.WarnContext(ctx, "Duplicate email constraint violation during update", "email", u.Email().String())
+				return errors.ErrDuplicateEmail
+			}
+			r.logger.ErrorContext(ctx, "Failed to update user", "error", err, "user_id", u.ID().String())
+			return fmt.Errorf("failed to update user: %w", err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			r.logger.ErrorContext(ctx, "Failed to get rows affected after update", "error", err)
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+
+		if rowsAffected == 0 {
+			return r.updateMissed(ctx, tx, u)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	u.IncrementVersion()
+
+	r.logger.InfoContext(ctx, "Successfully updated user", "user_id", u.ID().String(), "email", u.Email().String())
+	return nil
+}
+
+// UpdateStatus stores the user's new status and records the transition in one transaction
+func (r *userRepository) UpdateStatus(ctx context.Context, u *user.User, t user.StatusTransition) error {
+	r.logger.DebugContext(ctx, "Updating user status",
+		"user_id", u.ID().String(),
+		"from", t.From,
+		"to", t.To,
+	)
+
+	err := r.txManager.Execute(ctx, "update user status", func(tx *sql.Tx) error {
+		// Guard on the previous status so concurrent transitions cannot skip the table,
+		// and on the version so they cannot overwrite other writes either
+		result, err := tx.ExecContext(ctx, `
+			UPDATE users
+			SET status = ?2, updated_at = ?3, version = version + 1
+			WHERE id = ?1 AND status = ?4 AND version = ?5 AND deleted_at IS NULL`,
+			u.ID().String(), string(t.To), formatTime(u.UpdatedAt()), string(t.From), u.Version(),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to update user status: %w", err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+
+		if rowsAffected == 0 {
+			var current string
+			var version int64
+			err := tx.QueryRowContext(ctx,
+				`SELECT status, version FROM users WHERE id = ?1 AND deleted_at IS NULL`, u.ID().String(),
+			).Scan(&current, &version)
+			if err == sql.ErrNoRows {
+				return errors.ErrUserNotFound
+			}
+			if err != nil {
+				return fmt.Errorf("failed to read current user status: %w", err)
+			}
+			if user.Status(current) != t.From {
+				return user.NewInvalidStatusTransitionError(user.Status(current), t.To)
+			}
+			return errors.NewConflictError(version)
+		}
+
+		_, err = tx.ExecContext(ctx, `
+			INSERT INTO user_status_transitions (user_id, from_status, to_status, actor, reason, occurred_at)
+			VALUES (?1, ?2, ?3, ?4, ?5, ?6)`,
+			t.UserID.String(), string(t.From), string(t.To), t.Actor, t.Reason, formatTime(t.OccurredAt),
+		)
+		if err != nil {
+			return fmt.Errorf("failed to record status transition: %w", err)
+		}
+
+		return nil
+	})
+
+	switch err.(type) {
+	case nil:
+	case errors.DomainError, errors.ConflictError:
+		r.logger.WarnContext(ctx, "User status not updated", "error", err, "user_id", u.ID().String())
+		return err
+	default:
+		r.logger.ErrorContext(ctx, "Failed to update user status", "error", err, "user_id", u.ID().String())
+		return err
+	}
+
+	u.IncrementVersion()
+
+	r.logger.InfoContext(ctx, "Successfully updated user status",
+		"user_id", u.ID().String(),
+		"from", t.From,
+		"to", t.To,
+	)
+	return nil
+}
+
+// updateMissed explains why a versioned update matched no rows
+func (r *userRepository) updateMissed(ctx context.Context, tx *sql.Tx, u *user.User) error {
+	var current int64
+	err := tx.QueryRowContext(ctx,
+		`SELECT version FROM users WHERE id = ?1 AND deleted_at IS NULL`, u.ID().String(),
+	).Scan(&current)
+	if err == sql.ErrNoRows {
+		r.logger.WarnContext(ctx, "No rows affected during user update", "user_id", u.ID().String())
+		return errors.ErrUserNotFound
+	}
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Failed to read current user version", "error", err, "user_id", u.ID().String())
+		return fmt.Errorf("failed to read current user version: %w", err)
+	}
+
+	r.logger.WarnContext(ctx, "User update conflicted with a concurrent write",
+		"user_id", u.ID().String(),
+		"expected_version", u.Version(),
+		"current_version", current,
+	)
+	return errors.NewConflictError(current)
+}
+
+// Delete soft-deletes a live user
+func (r *userRepository) Delete(ctx context.Context, u *user.User) error {
+	r.logger.DebugContext(ctx, "Deleting user", "user_id", u.ID().String())
+
+	deletedAt := time.Now()
+	if u.DeletedAt() != nil {
+		deletedAt = *u.DeletedAt()
+	}
+
+	query := `UPDATE users SET deleted_at = ?2, version = version + 1 WHERE id = ?1 AND deleted_at IS NULL`
+
+	result, err := r.db.Executor(ctx).ExecContext(ctx, query, u.ID().String(), formatTime(deletedAt))
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Failed to delete user", "error", err, "user_id", u.ID().String())
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Failed to get rows affected after delete", "error", err)
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		r.logger.WarnContext(ctx, "No rows affected during user delete", "user_id", u.ID().String())
+		return errors.ErrUserNotFound
+	}
+
+	u.IncrementVersion()
+
+	r.logger.InfoContext(ctx, "Successfully deleted user", "user_id", u.ID().String())
+	return nil
+}
+
+// Restore brings a soft-deleted user back
+func (r *userRepository) Restore(ctx context.Context, u *user.User) error {
+	id := u.ID()
+	r.logger.DebugContext(ctx, "Restoring user", "user_id", id.String())
+
+	query := `UPDATE users SET deleted_at = NULL, version = version + 1 WHERE id = ?1 AND deleted_at IS NOT NULL`
+
+	result, err := r.db.Executor(ctx).ExecContext(ctx, query, id.String())
+	if err != nil {
+		// Another live user may have taken the email while this one was deleted
+		if isDuplicateEmail(err) {
+			r.logger.WarnContext(ctx, "Duplicate email constraint violation during restore", "user_id", id.String())
+			return errors.ErrDuplicateEmail
+		}
+		r.logger.ErrorContext(ctx, "Failed to restore user", "error", err, "user_id", id.String())
+		return fmt.Errorf("failed to restore user: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Failed to get rows affected after restore", "error", err)
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		// Tell a live user apart from a missing one
+		var exists bool
+		if err := r.db.Executor(ctx).QueryRowContext(ctx, `SELECT EXISTS(SELECT 1 FROM users WHERE id = ?1)`, id.String()).Scan(&exists); err != nil {
+			r.logger.ErrorContext(ctx, "Failed to check user existence after restore", "error", err, "user_id", id.String())
+			return fmt.Errorf("failed to check user existence: %w", err)
+		}
+		if exists {
+			r.logger.WarnContext(ctx, "User to restore is not deleted", "user_id", id.String())
+			return errors.ErrUserNotDeleted
+		}
+		r.logger.WarnContext(ctx, "No rows affected during user restore", "user_id", id.String())
+		return errors.ErrUserNotFound
+	}
+
+	u.IncrementVersion()
+
+	r.logger.InfoContext(ctx, "Successfully restored user", "user_id", id.String())
+	return nil
+}
+
+// Purge permanently removes a user and its status history
+// The history is deleted explicitly because SQLite only cascades when foreign keys are enabled
+func (r *userRepository) Purge(ctx context.Context, u *user.User) error {
+	id := u.ID()
+	r.logger.DebugContext(ctx, "Purging user", "user_id", id.String())
+
+	err := r.txManager.Execute(ctx, "purge user", func(tx *sql.Tx) error {
+		if _, err := tx.ExecContext(ctx, `DELETE FROM user_status_transitions WHERE user_id = ?1`, id.String()); err != nil {
+			return fmt.Errorf("failed to purge user status transitions: %w", err)
+		}
+
+		result, err := tx.ExecContext(ctx, `DELETE FROM users WHERE id = ?1`, id.String())
+		if err != nil {
+			return fmt.Errorf("failed to purge user: %w", err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+
+		if rowsAffected == 0 {
+			return errors.ErrUserNotFound
+		}
+
+		return nil
+	})
+
+	switch err {
+	case nil:
+	case errors.ErrUserNotFound:
+		r.logger.WarnContext(ctx, "No rows affected during user purge", "user_id", id.String())
+		return err
+	default:
+		r.logger.ErrorContext(ctx, "Failed to purge user", "error", err, "user_id", id.String())
+		return err
+	}
+
+	r.logger.InfoContext(ctx, "Successfully purged user", "user_id", id.String())
+	return nil
+}
+
+// ExistsByEmail checks if a user with the given email exists
+func (r *userRepository) ExistsByEmail(ctx context.Context, email user.Email) (bool, error) {
+	r.logger.DebugContext(ctx, "Checking if user exists by email", "email", email.String())
+
+	query := `SELECT EXISTS(SELECT 1 FROM users WHERE email = ?1 AND deleted_at IS NULL)`
+
+	var exists bool
+	err := r.db.Executor(ctx).QueryRowContext(ctx, query, email.String()).Scan(&exists)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Failed to check if user exists by email", "error", err, "email", email.String())
+		return false, fmt.Errorf("failed to check if user exists by email: %w", err)
+	}
+
+	r.logger.DebugContext(ctx, "Successfully checked user existence by email", "email", email.String(), "exists", exists)
+	return exists, nil
+}
+
+// Count returns the number of users matching the filter
+func (r *userRepository) Count(ctx context.Context, filter user.Filter) (int64, error) {
+	r.logger.DebugContext(ctx, "Counting users")
+
+	var b conditionBuilder
+	b.addFilter(filter)
+
+	query := `SELECT COUNT(*) FROM users` + b.where()
+
+	var count int64
+	err := r.db.Executor(ctx).QueryRowContext(ctx, query, b.args...).Scan(&count)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Failed to count users", "error", err)
+		return 0, fmt.Errorf("failed to count users: %w", err)
+	}
+
+	r.logger.DebugContext(ctx, "Successfully counted users", "count", count)
+	return count, nil
+}
+
+// CountSignups returns the number of users created in each UTC interval of the query range
+// Soft-deleted users are included: they still signed up. SQLite has no date_trunc,
+// so the creation times in range are read and bucketed in Go.
+func (r *userRepository) CountSignups(ctx context.Context, q user.SignupStatsQuery) ([]user.SignupBucket, error) {
+	r.logger.DebugContext(ctx, "Counting signups",
+		"from", q.From,
+		"to", q.To,
+		"interval", q.Interval,
+	)
+
+	if !q.Interval.IsValid() {
+		return nil, errors.ErrInvalidStatsInterval
+	}
+
+	query := `SELECT created_at FROM users WHERE created_at >= ?1 AND created_at < ?2`
+
+	rows, err := r.db.Executor(ctx).QueryContext(ctx, query, formatTime(q.From), formatTime(q.To))
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Failed to count signups", "error", err)
+		return nil, fmt.Errorf("failed to count signups: %w", err)
+	}
+	defer rows.Close()
+
+	var createdAt []time.Time
+	for rows.Next() {
+		var raw string
+		if err := rows.Scan(&raw); err != nil {
+			r.logger.ErrorContext(ctx, "Failed to scan signup time", "error", err)
+			return nil, fmt.Errorf("failed to scan signup time: %w", err)
+		}
+		t, err := parseTime(raw)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse signup time: %w", err)
+		}
+		createdAt = append(createdAt, t)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.ErrorContext(ctx, "Error iterating over signup times", "error", err)
+		return nil, fmt.Errorf("error iterating over signup times: %w", err)
+	}
+
+	buckets := q.Bucket(createdAt)
+
+	r.logger.DebugContext(ctx, "Successfully counted signups", "buckets", len(buckets))
+	return buckets, nil
+}
+
+// scanUser reads a row selected with userColumns into a domain user
+func (r *userRepository) scanUser(ctx context.Context, row rowScanner) (*user.User, error) {
+	var snapshot user.Snapshot
+	var createdAt, updatedAt string
+	var deletedAt sql.NullString
+
+	if err := row.Scan(
+		&snapshot.ID, &snapshot.Email, &snapshot.Name, &createdAt, &updatedAt, &deletedAt, &snapshot.Status, &snapshot.Version,
+	); err != nil {
+		return nil, err
+	}
+
+	var err error
+	if snapshot.CreatedAt, err = parseTime(createdAt); err != nil {
+		return nil, fmt.Errorf("failed to parse created_at: %w", err)
+	}
+	if snapshot.UpdatedAt, err = parseTime(updatedAt); err != nil {
+		return nil, fmt.Errorf("failed to parse updated_at: %w", err)
+	}
+	if deletedAt.Valid {
+		t, err := parseTime(deletedAt.String)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse deleted_at: %w", err)
+		}
+		snapshot.DeletedAt = &t
+	}
+
+	domainUser, err := user.NewUserFromSnapshot(snapshot)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Failed to create domain user from database record", "error", err)
+		return nil, fmt.Errorf("failed to create domain user: %w", err)
+	}
+
+	return domainUser, nil
+}
+
+// isDuplicateEmail reports whether err is a violation of live email uniqueness
+// It is the SQLite counterpart of the Postgres 23505 check: the driver's error exposes
+// the extended result code, and the message names the violated column.
+func isDuplicateEmail(err error) bool {
+	coded, ok := err.(interface{ Code() int })
+	return ok && coded.Code() == sqliteConstraintUnique && strings.Contains(err.Error(), usersEmailColumn)
+}
@@ -0,0 +1,107 @@
+//go:build sqlite
+
+package sqlite
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/captain-corgi/go-graphql-example/internal/domain/errors"
+	"github.com/captain-corgi/go-graphql-example/internal/domain/user"
+	"github.com/captain-corgi/go-graphql-example/internal/infrastructure/config"
+	"github.com/captain-corgi/go-graphql-example/internal/infrastructure/database"
+	"github.com/captain-corgi/go-graphql-example/internal/infrastructure/persistence/repotest"
+	"github.com/captain-corgi/go-graphql-example/migrations"
+)
+
+// newTestDB opens a migrated SQLite database in a file that lives as long as the test
+func newTestDB(t *testing.T) *database.DB {
+	t.Helper()
+
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+
+	db, err := database.NewConnection(config.DatabaseConfig{
+		Driver:          config.DatabaseDriverSQLite,
+		URL:             "file:" + filepath.Join(t.TempDir(), "users.db"),
+		MaxOpenConns:    1,
+		MaxIdleConns:    1,
+		ConnMaxLifetime: time.Hour,
+		ConnMaxIdleTime: time.Hour,
+	}, logger)
+	require.NoError(t, err)
+	t.Cleanup(func() { db.Close() })
+
+	err = database.NewMigrationManager(db, logger).RunMigrations(context.Background(), migrations.SQLite)
+	require.NoError(t, err)
+
+	return db
+}
+
+// newTestRepository returns a repository on a fresh database
+func newTestRepository(t *testing.T) (user.Repository, *database.DB) {
+	db := newTestDB(t)
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	return NewUserRepository(db, database.NewTxManager(db, logger), logger), db
+}
+
+// TestUserRepositoryContract runs the shared repository behavior suite
+func TestUserRepositoryContract(t *testing.T) {
+	repotest.Run(t, func(t *testing.T) user.Repository {
+		repo, _ := newTestRepository(t)
+		return repo
+	})
+}
+
+func TestUserRepository_PurgeRemovesStatusHistory(t *testing.T) {
+	ctx := context.Background()
+	repo, db := newTestRepository(t)
+
+	u, err := user.NewUser("alice@example.com", "Alice")
+	require.NoError(t, err)
+	require.NoError(t, repo.Create(ctx, u))
+
+	transition, err := u.ChangeStatus(user.StatusSuspended, "admin", "spam")
+	require.NoError(t, err)
+	require.NoError(t, repo.UpdateStatus(ctx, u, transition))
+
+	require.NoError(t, repo.Purge(ctx, u))
+
+	var history int
+	require.NoError(t, db.QueryRowContext(ctx, `SELECT COUNT(*) FROM user_status_transitions`).Scan(&history))
+	assert.Zero(t, history)
+
+	assert.Equal(t, errors.ErrUserNotFound, repo.Purge(ctx, u))
+}
+
+func TestUserRepository_StoresTimesInUTC(t *testing.T) {
+	ctx := context.Background()
+	repo, db := newTestRepository(t)
+
+	createdAt := time.Date(2025, 1, 19, 22, 30, 0, 123456789, time.FixedZone("UTC-5", -5*60*60))
+	u, err := user.NewUserFromSnapshot(user.Snapshot{
+		ID:        "5a8d1a34-6a0b-4f0e-9a5e-3f1d2c4b5a60",
+		Email:     "alice@example.com",
+		Name:      "Alice",
+		CreatedAt: createdAt,
+		UpdatedAt: createdAt,
+		Status:    user.StatusActive,
+		Version:   1,
+	})
+	require.NoError(t, err)
+	require.NoError(t, repo.Create(ctx, u))
+
+	var stored string
+	require.NoError(t, db.QueryRowContext(ctx, `SELECT created_at FROM users`).Scan(&stored))
+	assert.Equal(t, "2025-01-20T03:30:00.123456789Z", stored)
+
+	found, err := repo.FindByID(ctx, u.ID())
+	require.NoError(t, err)
+	assert.True(t, createdAt.Equal(found.CreatedAt()))
+}
@@ -0,0 +1,48 @@
+package sqlite
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/captain-corgi/go-graphql-example/internal/domain/user"
+)
+
+var _ user.SearchRepository = (*userRepository)(nil)
+
+// Search ranks live users by how well their name and email match the query
+// SQLite has neither tsvector nor pg_trgm, so every live user is read and ranked in Go;
+// ranks approximate those of the Postgres store (see user.SearchQuery.Rank).
+// That suits the local databases this store is meant for
+func (r *userRepository) Search(ctx context.Context, query user.SearchQuery) (*user.SearchPage, error) {
+	r.logger.DebugContext(ctx, "Searching users", "first", query.First, "has_after", query.After != nil)
+
+	rows, err := r.db.Executor(ctx).QueryContext(ctx, `
+		SELECT `+userColumns+`
+		FROM users
+		WHERE deleted_at IS NULL`)
+	if err != nil {
+		r.logger.ErrorContext(ctx, "Failed to search users", "error", err)
+		return nil, fmt.Errorf("failed to search users: %w", err)
+	}
+	defer rows.Close()
+
+	var users []*user.User
+	for rows.Next() {
+		domainUser, err := r.scanUser(ctx, rows)
+		if err != nil {
+			r.logger.ErrorContext(ctx, "Failed to scan user row", "error", err)
+			return nil, fmt.Errorf("failed to scan user row: %w", err)
+		}
+		users = append(users, domainUser)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.ErrorContext(ctx, "Error iterating over user rows", "error", err)
+		return nil, fmt.Errorf("error iterating over user rows: %w", err)
+	}
+
+	page := query.RankUsers(users)
+
+	r.logger.DebugContext(ctx, "Successfully searched users", "count", len(page.Hits), "has_next_page", page.HasNextPage)
+	return page, nil
+}
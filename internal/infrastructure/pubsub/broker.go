@@ -0,0 +1,123 @@
+package pubsub
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+)
+
+// Broker is an in-process publish/subscribe hub.
+//
+// Publishing never blocks. Each subscriber owns a bounded buffer, and a
+// subscriber whose buffer is full when an event arrives is dropped and its
+// channel closed, so one slow consumer cannot stall publishers or other
+// subscribers. Dropped subscribers are expected to resubscribe and refetch.
+type Broker[T any] struct {
+	bufferSize int
+	logger     *slog.Logger
+
+	mu          sync.Mutex
+	subscribers map[*subscriber[T]]struct{}
+	closed      bool
+}
+
+// subscriber is a single registered consumer
+type subscriber[T any] struct {
+	ch    chan T
+	match func(T) bool
+	stop  func() bool // cancels the context watcher
+}
+
+// NewBroker creates a broker whose subscribers buffer up to bufferSize events
+func NewBroker[T any](bufferSize int, logger *slog.Logger) *Broker[T] {
+	if bufferSize < 1 {
+		bufferSize = 1
+	}
+
+	return &Broker[T]{
+		bufferSize:  bufferSize,
+		logger:      logger,
+		subscribers: make(map[*subscriber[T]]struct{}),
+	}
+}
+
+// Publish delivers event to every subscriber whose match function accepts it
+func (b *Broker[T]) Publish(event T) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		return
+	}
+
+	for sub := range b.subscribers {
+		if sub.match != nil && !sub.match(event) {
+			continue
+		}
+
+		select {
+		case sub.ch <- event:
+		default:
+			b.remove(sub)
+			b.logger.Warn("Dropped slow subscriber",
+				slog.Int("buffer_size", b.bufferSize),
+				slog.Int("subscribers", len(b.subscribers)),
+			)
+		}
+	}
+}
+
+// Subscribe registers a subscriber for the events accepted by match; a nil match accepts all events.
+// The returned channel is closed when ctx is done, when the subscriber is dropped, or when the broker closes.
+func (b *Broker[T]) Subscribe(ctx context.Context, match func(T) bool) <-chan T {
+	sub := &subscriber[T]{
+		ch:    make(chan T, b.bufferSize),
+		match: match,
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.closed {
+		close(sub.ch)
+		return sub.ch
+	}
+
+	b.subscribers[sub] = struct{}{}
+	sub.stop = context.AfterFunc(ctx, func() {
+		b.mu.Lock()
+		defer b.mu.Unlock()
+		b.remove(sub)
+	})
+
+	return sub.ch
+}
+
+// SubscriberCount returns the number of active subscribers
+func (b *Broker[T]) SubscriberCount() int {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return len(b.subscribers)
+}
+
+// Close closes every subscriber channel; later subscriptions are closed immediately
+func (b *Broker[T]) Close() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for sub := range b.subscribers {
+		b.remove(sub)
+	}
+}
+
+// remove unregisters a subscriber and closes its channel; b.mu must be held
+func (b *Broker[T]) remove(sub *subscriber[T]) {
+	if _, ok := b.subscribers[sub]; !ok {
+		return
+	}
+
+	delete(b.subscribers, sub)
+	sub.stop()
+	close(sub.ch)
+}
@@ -0,0 +1,102 @@
+package pubsub
+
+import (
+	"context"
+	"log/slog"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// receive reads one event or fails after a short timeout
+func receive(t *testing.T, ch <-chan string) (string, bool) {
+	t.Helper()
+
+	select {
+	case event, ok := <-ch:
+		return event, ok
+	case <-time.After(time.Second):
+		t.Fatal("timed out waiting for event")
+		return "", false
+	}
+}
+
+func TestBroker_PublishFansOutToMatchingSubscribers(t *testing.T) {
+	broker := NewBroker[string](4, slog.Default())
+
+	all := broker.Subscribe(context.Background(), nil)
+	onlyB := broker.Subscribe(context.Background(), func(event string) bool { return event == "b" })
+
+	broker.Publish("a")
+	broker.Publish("b")
+
+	event, _ := receive(t, all)
+	assert.Equal(t, "a", event)
+	event, _ = receive(t, all)
+	assert.Equal(t, "b", event)
+
+	event, _ = receive(t, onlyB)
+	assert.Equal(t, "b", event)
+	assert.Len(t, onlyB, 0)
+}
+
+func TestBroker_DropsSlowSubscriber(t *testing.T) {
+	broker := NewBroker[string](2, slog.Default())
+
+	slow := broker.Subscribe(context.Background(), nil)
+	fast := broker.Subscribe(context.Background(), nil)
+
+	broker.Publish("1")
+	broker.Publish("2")
+	_, _ = receive(t, fast)
+	_, _ = receive(t, fast)
+
+	// The slow subscriber's buffer is full, so this event evicts it
+	broker.Publish("3")
+
+	assert.Equal(t, 1, broker.SubscriberCount())
+
+	event, _ := receive(t, fast)
+	assert.Equal(t, "3", event)
+
+	// Buffered events are still delivered before the channel reports closed
+	event, ok := receive(t, slow)
+	assert.True(t, ok)
+	assert.Equal(t, "1", event)
+	event, ok = receive(t, slow)
+	assert.True(t, ok)
+	assert.Equal(t, "2", event)
+	_, ok = receive(t, slow)
+	assert.False(t, ok)
+}
+
+func TestBroker_UnsubscribesWhenContextEnds(t *testing.T) {
+	broker := NewBroker[string](1, slog.Default())
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ch := broker.Subscribe(ctx, nil)
+	require.Equal(t, 1, broker.SubscriberCount())
+
+	cancel()
+
+	_, ok := receive(t, ch)
+	assert.False(t, ok)
+	assert.Equal(t, 0, broker.SubscriberCount())
+}
+
+func TestBroker_Close(t *testing.T) {
+	broker := NewBroker[string](1, slog.Default())
+	ch := broker.Subscribe(context.Background(), nil)
+
+	broker.Close()
+
+	_, ok := receive(t, ch)
+	assert.False(t, ok)
+
+	// Publishing after close is a no-op and new subscriptions end immediately
+	broker.Publish("ignored")
+	_, ok = receive(t, broker.Subscribe(context.Background(), nil))
+	assert.False(t, ok)
+}
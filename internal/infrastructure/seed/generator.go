@@ -0,0 +1,192 @@
+package seed
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+
+	"github.com/captain-corgi/go-graphql-example/internal/domain/user"
+)
+
+var firstNames = []string{
+	"Aaliyah", "Adam", "Aisha", "Alejandro", "Amelia", "Andrei", "Ava", "Benjamin", "Camila", "Chen",
+	"Chloe", "Daniel", "Diego", "Elena", "Emma", "Ethan", "Fatima", "Gabriel", "Grace", "Hannah",
+	"Hiroshi", "Isabella", "Ivan", "Jack", "James", "Julia", "Kwame", "Layla", "Leo", "Liam",
+	"Lucas", "Maria", "Mateo", "Mia", "Mohammed", "Nadia", "Noah", "Olivia", "Omar", "Priya",
+	"Rafael", "Ravi", "Sakura", "Samuel", "Sofia", "Thomas", "Valentina", "Wei", "Yusuf", "Zoe",
+}
+
+var lastNames = []string{
+	"Adeyemi", "Anderson", "Brown", "Chen", "Costa", "Davis", "Dubois", "Fernandez", "Fischer", "Garcia",
+	"Gonzalez", "Hansen", "Ibrahim", "Ito", "Jackson", "Johnson", "Kim", "Kowalski", "Kumar", "Lee",
+	"Lopez", "Martin", "Martinez", "Moreau", "Mueller", "Nakamura", "Nguyen", "Novak", "Okafor", "Olsen",
+	"Patel", "Petrov", "Rossi", "Santos", "Schmidt", "Silva", "Singh", "Smith", "Sousa", "Tanaka",
+	"Taylor", "Thomas", "Walker", "Wang", "White", "Williams", "Wilson", "Yamamoto", "Young", "Zhang",
+}
+
+var emailDomains = []string{"example.com", "example.org", "example.net"}
+
+// hourWeights makes signups cluster in working hours and evenings rather than at night
+var hourWeights = [24]int{1, 1, 1, 1, 1, 2, 3, 5, 8, 10, 11, 11, 10, 10, 11, 11, 10, 9, 8, 8, 7, 5, 3, 2}
+
+// statusWeights gives most generated accounts the active status, and a few every other one
+var statusWeights = []struct {
+	status user.Status
+	weight int
+}{
+	{user.StatusActive, 88},
+	{user.StatusInvited, 5},
+	{user.StatusSuspended, 2},
+	{user.StatusDeactivated, 5},
+}
+
+// Generator produces fake users deterministically: the same seed always yields the same IDs,
+// names, emails and statuses, and the same timestamps for the same now
+// Each user depends only on the seed and its index, so asking for more users extends the
+// set seeded before instead of replacing it
+type Generator struct {
+	seed uint64
+	now  time.Time
+	span time.Duration
+}
+
+// NewGenerator creates a generator whose users signed up during the span before now
+func NewGenerator(seed uint64, now time.Time, span time.Duration) *Generator {
+	return &Generator{seed: seed, now: now, span: span}
+}
+
+// Users returns the first n fake users
+func (g *Generator) Users(n int) ([]*user.User, error) {
+	users := make([]*user.User, 0, n)
+	for i := 0; i < n; i++ {
+		u, err := g.User(i)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, u)
+	}
+	return users, nil
+}
+
+// User returns the fake user at index i
+func (g *Generator) User(i int) (*user.User, error) {
+	rng := rand.New(rand.NewPCG(g.seed, uint64(i)))
+
+	id := randomUUID(rng)
+	first := firstNames[rng.IntN(len(firstNames))]
+	last := lastNames[rng.IntN(len(lastNames))]
+	domain := emailDomains[rng.IntN(len(emailDomains))]
+
+	// The index keeps emails unique however often a name repeats
+	email := fmt.Sprintf("%s.%s.%d@%s", strings.ToLower(first), strings.ToLower(last), i, domain)
+
+	createdAt := g.signupTime(rng)
+	updatedAt := createdAt
+	// Most users never change their profile; the rest did so at some point since signing up
+	if rng.IntN(100) < 40 {
+		updatedAt = createdAt.Add(time.Duration(rng.Int64N(int64(g.now.Sub(createdAt)) + 1)))
+	}
+
+	return build(id, email, first+" "+last, createdAt, updatedAt, pickStatus(rng))
+}
+
+// signupTime picks when a user signed up
+// Signups grow over the span, so recent days get more of them, fall off at weekends
+// and follow the daily rhythm of hourWeights
+func (g *Generator) signupTime(rng *rand.Rand) time.Time {
+	start := g.now.Add(-g.span)
+	days := int(g.span / (24 * time.Hour))
+	if days < 1 {
+		// Spans shorter than a day are spread evenly
+		return start.Add(time.Duration(rng.Int64N(int64(g.span) + 1)))
+	}
+
+	var day time.Time
+	for attempt := 0; attempt < 3; attempt++ {
+		// The square root of a uniform sample has a density rising linearly towards now
+		offset := int(float64(days) * math.Sqrt(rng.Float64()))
+		day = start.Truncate(24*time.Hour).AddDate(0, 0, offset+1)
+		if weekday := day.Weekday(); weekday != time.Saturday && weekday != time.Sunday {
+			break
+		}
+		// Keep about half of the weekend picks
+		if rng.IntN(2) == 0 {
+			break
+		}
+	}
+
+	at := day.Add(time.Duration(pickHour(rng))*time.Hour + time.Duration(rng.IntN(3600))*time.Second)
+	if at.After(g.now) {
+		at = at.AddDate(0, 0, -1)
+	}
+	if at.Before(start) {
+		at = start
+	}
+	return at
+}
+
+// build creates a user through user.NewUser, so that it passes the same validation as real signups,
+// then gives it a fixed identity, history and status. The rebuilt user records no events
+func build(id, email, name string, createdAt, updatedAt time.Time, status user.Status) (*user.User, error) {
+	u, err := user.NewUser(email, name)
+	if err != nil {
+		return nil, fmt.Errorf("invalid seed user %s: %w", email, err)
+	}
+
+	snapshot := u.Snapshot()
+	snapshot.ID = id
+	snapshot.CreatedAt = createdAt.UTC().Truncate(time.Microsecond)
+	snapshot.UpdatedAt = updatedAt.UTC().Truncate(time.Microsecond)
+	snapshot.Status = status
+
+	return user.NewUserFromSnapshot(snapshot)
+}
+
+// randomUUID draws a version 4 UUID from rng rather than from crypto/rand, so that it can be reproduced
+func randomUUID(rng *rand.Rand) string {
+	var id uuid.UUID
+	for i := range id {
+		id[i] = byte(rng.Uint32())
+	}
+	id[6] = (id[6] & 0x0f) | 0x40
+	id[8] = (id[8] & 0x3f) | 0x80
+	return id.String()
+}
+
+// pickHour draws an hour of the day according to hourWeights
+func pickHour(rng *rand.Rand) int {
+	total := 0
+	for _, w := range hourWeights {
+		total += w
+	}
+
+	n := rng.IntN(total)
+	for hour, w := range hourWeights {
+		if n < w {
+			return hour
+		}
+		n -= w
+	}
+	return len(hourWeights) - 1
+}
+
+// pickStatus draws a status according to statusWeights
+func pickStatus(rng *rand.Rand) user.Status {
+	total := 0
+	for _, s := range statusWeights {
+		total += s.weight
+	}
+
+	n := rng.IntN(total)
+	for _, s := range statusWeights {
+		if n < s.weight {
+			return s.status
+		}
+		n -= s.weight
+	}
+	return user.StatusActive
+}
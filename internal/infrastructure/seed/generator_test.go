@@ -0,0 +1,81 @@
+package seed
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+var testNow = time.Date(2024, 6, 14, 15, 30, 0, 0, time.UTC)
+
+func TestGenerator_IsDeterministic(t *testing.T) {
+	first, err := NewGenerator(7, testNow, 90*24*time.Hour).Users(50)
+	require.NoError(t, err)
+	second, err := NewGenerator(7, testNow, 90*24*time.Hour).Users(50)
+	require.NoError(t, err)
+
+	for i := range first {
+		assert.Equal(t, first[i].Snapshot(), second[i].Snapshot())
+	}
+
+	// A different seed gives different users
+	other, err := NewGenerator(8, testNow, 90*24*time.Hour).User(0)
+	require.NoError(t, err)
+	assert.NotEqual(t, first[0].ID(), other.ID())
+}
+
+func TestGenerator_MoreUsersExtendTheSet(t *testing.T) {
+	few, err := NewGenerator(1, testNow, 90*24*time.Hour).Users(10)
+	require.NoError(t, err)
+
+	// Identity does not depend on when the users are generated either
+	many, err := NewGenerator(1, testNow.Add(72*time.Hour), 90*24*time.Hour).Users(100)
+	require.NoError(t, err)
+
+	for i := range few {
+		assert.Equal(t, few[i].ID(), many[i].ID())
+		assert.Equal(t, few[i].Email(), many[i].Email())
+		assert.Equal(t, few[i].Name(), many[i].Name())
+	}
+}
+
+func TestGenerator_ProducesValidUsers(t *testing.T) {
+	span := 365 * 24 * time.Hour
+	users, err := NewGenerator(1, testNow, span).Users(2000)
+	require.NoError(t, err)
+
+	emails := map[string]bool{}
+	recent := 0
+	for _, u := range users {
+		require.NoError(t, u.Validate())
+		assert.Empty(t, u.PendingEvents())
+		assert.True(t, u.Status().IsValid())
+
+		assert.False(t, u.CreatedAt().Before(testNow.Add(-span)), "created %s", u.CreatedAt())
+		assert.False(t, u.CreatedAt().After(testNow), "created %s", u.CreatedAt())
+		assert.False(t, u.UpdatedAt().Before(u.CreatedAt()))
+		assert.False(t, u.UpdatedAt().After(testNow))
+
+		assert.False(t, emails[u.Email().String()], "duplicate email %s", u.Email())
+		emails[u.Email().String()] = true
+
+		if u.CreatedAt().After(testNow.Add(-span / 2)) {
+			recent++
+		}
+	}
+
+	// Signups grow over time, so the later half of the span holds about three quarters of them
+	assert.Greater(t, recent, len(users)*2/3)
+}
+
+func TestFixtureUsers(t *testing.T) {
+	users, err := fixtureUsers(testNow)
+	require.NoError(t, err)
+	require.Len(t, users, len(fixtures))
+
+	assert.Equal(t, "550e8400-e29b-41d4-a716-446655440001", users[0].ID().String())
+	assert.Equal(t, "john.doe@example.com", users[0].Email().String())
+	assert.Equal(t, testNow.Add(-30*24*time.Hour), users[0].CreatedAt())
+}
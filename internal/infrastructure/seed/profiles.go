@@ -0,0 +1,114 @@
+package seed
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/captain-corgi/go-graphql-example/internal/domain/user"
+	"github.com/captain-corgi/go-graphql-example/internal/infrastructure/config"
+)
+
+// Profile is a named set of users to seed and the environments it may be seeded into
+type Profile struct {
+	Name        string
+	Description string
+	// Environments lists the config environments the profile may run in; none of them is production
+	Environments []string
+	// Fixtures adds the hand-written users with well-known IDs that the examples and docs refer to
+	Fixtures bool
+	// Generated is how many fake users the profile adds by default
+	Generated int
+}
+
+// profiles are the available profiles, in the order they are listed
+var profiles = []Profile{
+	{
+		Name:         "dev",
+		Description:  "the example users plus a few dozen fake ones, for local development and tests",
+		Environments: []string{config.EnvironmentDevelopment, config.EnvironmentTest},
+		Fixtures:     true,
+		Generated:    40,
+	},
+	{
+		Name:         "demo",
+		Description:  "the example users plus a few hundred fake ones, for demos on staging",
+		Environments: []string{config.EnvironmentDevelopment, config.EnvironmentStaging},
+		Fixtures:     true,
+		Generated:    500,
+	},
+	{
+		Name:         "loadtest",
+		Description:  "many fake users, for load and performance testing",
+		Environments: []string{config.EnvironmentDevelopment, config.EnvironmentTest, config.EnvironmentStaging},
+		Generated:    10000,
+	},
+}
+
+// Profiles returns every available profile
+func Profiles() []Profile {
+	return slices.Clone(profiles)
+}
+
+// LookupProfile returns the profile with the given name
+func LookupProfile(name string) (Profile, error) {
+	for _, p := range profiles {
+		if p.Name == name {
+			return p, nil
+		}
+	}
+
+	names := make([]string, len(profiles))
+	for i, p := range profiles {
+		names[i] = p.Name
+	}
+	return Profile{}, fmt.Errorf("unknown seed profile %q; expected one of %s", name, strings.Join(names, ", "))
+}
+
+// AllowedIn reports whether the profile may be seeded in the given environment
+func (p Profile) AllowedIn(environment string) bool {
+	return slices.Contains(p.Environments, environment)
+}
+
+// CheckEnvironment returns an error unless the profile may be seeded in the given environment
+func (p Profile) CheckEnvironment(environment string) error {
+	if p.AllowedIn(environment) {
+		return nil
+	}
+	return fmt.Errorf("seed profile %s may not run in the %s environment; it is allowed in %s",
+		p.Name, environment, strings.Join(p.Environments, ", "))
+}
+
+// fixture is a hand-written user; its times are relative to when it is seeded
+type fixture struct {
+	id, email, name  string
+	age, sinceUpdate time.Duration
+}
+
+// fixtures are the users the development seed migration used to insert
+var fixtures = []fixture{
+	{"550e8400-e29b-41d4-a716-446655440001", "john.doe@example.com", "John Doe", 30 * 24 * time.Hour, 5 * 24 * time.Hour},
+	{"550e8400-e29b-41d4-a716-446655440002", "jane.smith@example.com", "Jane Smith", 25 * 24 * time.Hour, 3 * 24 * time.Hour},
+	{"550e8400-e29b-41d4-a716-446655440003", "bob.wilson@example.com", "Bob Wilson", 20 * 24 * time.Hour, 24 * time.Hour},
+	{"550e8400-e29b-41d4-a716-446655440004", "alice.johnson@example.com", "Alice Johnson", 15 * 24 * time.Hour, 2 * time.Hour},
+	{"550e8400-e29b-41d4-a716-446655440005", "charlie.brown@example.com", "Charlie Brown", 10 * 24 * time.Hour, 30 * time.Minute},
+	{"550e8400-e29b-41d4-a716-446655440006", "diana.prince@example.com", "Diana Prince", 5 * 24 * time.Hour, 10 * time.Minute},
+	{"550e8400-e29b-41d4-a716-446655440007", "edward.norton@example.com", "Edward Norton", 3 * 24 * time.Hour, 5 * time.Minute},
+	{"550e8400-e29b-41d4-a716-446655440008", "fiona.gallagher@example.com", "Fiona Gallagher", 2 * 24 * time.Hour, time.Minute},
+	{"550e8400-e29b-41d4-a716-446655440009", "george.washington@example.com", "George Washington", 24 * time.Hour, 0},
+	{"550e8400-e29b-41d4-a716-446655440010", "helen.keller@example.com", "Helen Keller", 12 * time.Hour, 0},
+}
+
+// fixtureUsers builds the fixture users as of now
+func fixtureUsers(now time.Time) ([]*user.User, error) {
+	users := make([]*user.User, 0, len(fixtures))
+	for _, f := range fixtures {
+		u, err := build(f.id, f.email, f.name, now.Add(-f.age), now.Add(-f.sinceUpdate), user.StatusActive)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, u)
+	}
+	return users, nil
+}
@@ -0,0 +1,139 @@
+package seed
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/captain-corgi/go-graphql-example/internal/domain/errors"
+	"github.com/captain-corgi/go-graphql-example/internal/domain/user"
+)
+
+// Result counts what a seeding run did
+type Result struct {
+	// Created is the number of users stored by this run
+	Created int
+	// Existing is the number of users already present from an earlier run, including soft-deleted ones
+	Existing int
+	// EmailTaken is the number of users skipped because another user already has their email
+	EmailTaken int
+}
+
+// Seeder stores the users of a profile, skipping those already present, so running it again changes nothing
+type Seeder struct {
+	repo        user.Repository
+	environment string
+	generator   *Generator
+	now         time.Time
+	logger      *slog.Logger
+}
+
+// NewSeeder creates a seeder for the given environment; its generator and fixtures are anchored at now
+func NewSeeder(repo user.Repository, environment string, generator *Generator, now time.Time, logger *slog.Logger) *Seeder {
+	return &Seeder{
+		repo:        repo,
+		environment: environment,
+		generator:   generator,
+		now:         now,
+		logger:      logger,
+	}
+}
+
+// Seed stores the users of the profile; a non-negative count overrides how many fake users it adds
+// Seeded users are written directly through the repository, so they record no domain events
+func (s *Seeder) Seed(ctx context.Context, profile Profile, count int) (Result, error) {
+	if err := profile.CheckEnvironment(s.environment); err != nil {
+		return Result{}, err
+	}
+
+	users, err := s.users(profile, count)
+	if err != nil {
+		return Result{}, err
+	}
+
+	s.logger.InfoContext(ctx, "Seeding users", "profile", profile.Name, "environment", s.environment, "users", len(users))
+
+	var result Result
+	for start := 0; start < len(users); start += user.MaxFilterIDs {
+		batch := users[start:min(start+user.MaxFilterIDs, len(users))]
+		if err := s.seedBatch(ctx, batch, &result); err != nil {
+			return result, err
+		}
+	}
+
+	s.logger.InfoContext(ctx, "Seeding completed",
+		"profile", profile.Name, "created", result.Created, "existing", result.Existing, "email_taken", result.EmailTaken)
+	return result, nil
+}
+
+// users lists the users the profile seeds
+func (s *Seeder) users(profile Profile, count int) ([]*user.User, error) {
+	var users []*user.User
+	if profile.Fixtures {
+		fixed, err := fixtureUsers(s.now)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, fixed...)
+	}
+
+	if count < 0 {
+		count = profile.Generated
+	}
+	generated, err := s.generator.Users(count)
+	if err != nil {
+		return nil, err
+	}
+	return append(users, generated...), nil
+}
+
+// seedBatch creates the users of a batch that do not exist yet
+func (s *Seeder) seedBatch(ctx context.Context, batch []*user.User, result *Result) error {
+	existing, err := s.existingIDs(ctx, batch)
+	if err != nil {
+		return err
+	}
+
+	for _, u := range batch {
+		if existing[u.ID().String()] {
+			result.Existing++
+			continue
+		}
+
+		err := s.repo.Create(ctx, u)
+		if err == errors.ErrDuplicateEmail {
+			s.logger.WarnContext(ctx, "Skipping seed user whose email is taken", "user_id", u.ID().String(), "email", u.Email().String())
+			result.EmailTaken++
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to seed user %s: %w", u.ID().String(), err)
+		}
+		result.Created++
+	}
+	return nil
+}
+
+// existingIDs returns which users of the batch are already stored, soft-deleted or not
+func (s *Seeder) existingIDs(ctx context.Context, batch []*user.User) (map[string]bool, error) {
+	ids := make([]user.UserID, len(batch))
+	for i, u := range batch {
+		ids[i] = u.ID()
+	}
+
+	criteria := user.Criteria{
+		Filter: user.Filter{IDs: ids, IncludeDeleted: true},
+		Sort:   user.DefaultSort,
+	}
+	page, err := s.repo.FindAll(ctx, criteria, user.PageRequest{First: len(ids)})
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up seeded users: %w", err)
+	}
+
+	existing := make(map[string]bool, len(page.Users))
+	for _, u := range page.Users {
+		existing[u.ID().String()] = true
+	}
+	return existing, nil
+}
@@ -0,0 +1,94 @@
+package seed
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/captain-corgi/go-graphql-example/internal/domain/user"
+	"github.com/captain-corgi/go-graphql-example/internal/infrastructure/config"
+	"github.com/captain-corgi/go-graphql-example/internal/infrastructure/persistence/memory"
+)
+
+func newTestSeeder(repo user.Repository, environment string) *Seeder {
+	logger := slog.New(slog.NewTextHandler(os.Stdout, &slog.HandlerOptions{Level: slog.LevelError}))
+	return NewSeeder(repo, environment, NewGenerator(1, testNow, 30*24*time.Hour), testNow, logger)
+}
+
+func countUsers(t *testing.T, repo user.Repository) int64 {
+	t.Helper()
+	n, err := repo.Count(context.Background(), user.Filter{IncludeDeleted: true})
+	require.NoError(t, err)
+	return n
+}
+
+func TestSeeder_IsIdempotent(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewUserRepository(slog.Default())
+	seeder := newTestSeeder(repo, config.EnvironmentDevelopment)
+	profile, err := LookupProfile("dev")
+	require.NoError(t, err)
+
+	result, err := seeder.Seed(ctx, profile, 150)
+	require.NoError(t, err)
+	assert.Equal(t, Result{Created: len(fixtures) + 150}, result)
+
+	result, err = seeder.Seed(ctx, profile, 150)
+	require.NoError(t, err)
+	assert.Equal(t, Result{Existing: len(fixtures) + 150}, result)
+	assert.Equal(t, int64(len(fixtures)+150), countUsers(t, repo))
+
+	// Soft-deleted seed users count as present rather than being created again
+	john, err := user.NewUserID(fixtures[0].id)
+	require.NoError(t, err)
+	u, err := repo.FindByID(ctx, john)
+	require.NoError(t, err)
+	require.NoError(t, u.Delete())
+	require.NoError(t, repo.Delete(ctx, u))
+
+	result, err = seeder.Seed(ctx, profile, 160)
+	require.NoError(t, err)
+	assert.Equal(t, Result{Created: 10, Existing: len(fixtures) + 150}, result)
+}
+
+func TestSeeder_SkipsTakenEmails(t *testing.T) {
+	ctx := context.Background()
+	repo := memory.NewUserRepository(slog.Default())
+
+	taken, err := user.NewUser(fixtures[1].email, "Someone Else")
+	require.NoError(t, err)
+	require.NoError(t, repo.Create(ctx, taken))
+
+	profile, err := LookupProfile("dev")
+	require.NoError(t, err)
+	result, err := newTestSeeder(repo, config.EnvironmentTest).Seed(ctx, profile, 0)
+	require.NoError(t, err)
+	assert.Equal(t, Result{Created: len(fixtures) - 1, EmailTaken: 1}, result)
+}
+
+func TestSeeder_GatesProfilesByEnvironment(t *testing.T) {
+	repo := memory.NewUserRepository(slog.Default())
+
+	for _, p := range Profiles() {
+		assert.False(t, p.AllowedIn(config.EnvironmentProduction), "profile %s", p.Name)
+
+		_, err := newTestSeeder(repo, config.EnvironmentProduction).Seed(context.Background(), p, 1)
+		assert.ErrorContains(t, err, "may not run in the production environment")
+	}
+
+	demo, err := LookupProfile("demo")
+	require.NoError(t, err)
+	_, err = newTestSeeder(repo, config.EnvironmentTest).Seed(context.Background(), demo, 1)
+	assert.Error(t, err)
+	assert.Zero(t, countUsers(t, repo))
+}
+
+func TestLookupProfile_Unknown(t *testing.T) {
+	_, err := LookupProfile("prod")
+	assert.ErrorContains(t, err, "expected one of dev, demo, loadtest")
+}
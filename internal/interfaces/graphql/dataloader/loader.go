@@ -0,0 +1,127 @@
+package dataloader
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// BatchFunc fetches the values for a batch of distinct keys
+// Keys missing from the returned map resolve to the zero value
+type BatchFunc[K comparable, V any] func(ctx context.Context, keys []K) (map[K]V, error)
+
+// Loader coalesces individual loads issued close together into batches and
+// memoizes results, so each key is fetched at most once per loader.
+// A Loader is meant to live for a single request; it never evicts entries.
+type Loader[K comparable, V any] struct {
+	fetch    BatchFunc[K, V]
+	wait     time.Duration
+	maxBatch int
+
+	mu      sync.Mutex
+	cache   map[K]*result[V]
+	pending *batch[K, V]
+}
+
+// result is the eventual outcome of loading one key
+type result[V any] struct {
+	done  chan struct{}
+	value V
+	err   error
+}
+
+// batch collects keys until it is dispatched
+type batch[K comparable, V any] struct {
+	ctx     context.Context // context of the load that opened the batch
+	keys    []K
+	results []*result[V]
+	timer   *time.Timer
+}
+
+// NewLoader creates a loader that waits up to wait for more keys before fetching,
+// and fetches at most maxBatch keys per call to fetch
+func NewLoader[K comparable, V any](fetch BatchFunc[K, V], wait time.Duration, maxBatch int) *Loader[K, V] {
+	return &Loader[K, V]{
+		fetch:    fetch,
+		wait:     wait,
+		maxBatch: maxBatch,
+		cache:    make(map[K]*result[V]),
+	}
+}
+
+// Load returns the value for key, batching the fetch with other loads issued within the wait window
+func (l *Loader[K, V]) Load(ctx context.Context, key K) (V, error) {
+	l.mu.Lock()
+	res, ok := l.cache[key]
+	if !ok {
+		res = &result[V]{done: make(chan struct{})}
+		l.cache[key] = res
+		l.enqueue(ctx, key, res)
+	}
+	l.mu.Unlock()
+
+	select {
+	case <-res.done:
+		return res.value, res.err
+	case <-ctx.Done():
+		var zero V
+		return zero, ctx.Err()
+	}
+}
+
+// enqueue adds a key to the pending batch, dispatching it when full; l.mu must be held
+func (l *Loader[K, V]) enqueue(ctx context.Context, key K, res *result[V]) {
+	if l.pending == nil {
+		b := &batch[K, V]{ctx: ctx}
+		b.timer = time.AfterFunc(l.wait, func() { l.dispatch(b) })
+		l.pending = b
+	}
+
+	b := l.pending
+	b.keys = append(b.keys, key)
+	b.results = append(b.results, res)
+
+	if len(b.keys) >= l.maxBatch {
+		b.timer.Stop()
+		l.pending = nil
+		go l.run(b)
+	}
+}
+
+// dispatch runs the batch if it is still pending
+func (l *Loader[K, V]) dispatch(b *batch[K, V]) {
+	l.mu.Lock()
+	if l.pending != b {
+		// Already dispatched because it filled up
+		l.mu.Unlock()
+		return
+	}
+	l.pending = nil
+	l.mu.Unlock()
+
+	l.run(b)
+}
+
+// run fetches a batch and resolves its results
+// Failed keys are dropped from the cache so a later load can retry them
+func (l *Loader[K, V]) run(b *batch[K, V]) {
+	values, err := l.fetch(b.ctx, b.keys)
+
+	if err != nil {
+		l.mu.Lock()
+		for _, key := range b.keys {
+			delete(l.cache, key)
+		}
+		l.mu.Unlock()
+	}
+
+	for i, key := range b.keys {
+		res := b.results[i]
+		if err != nil {
+			res.err = err
+		} else {
+			res.value = values[key]
+		}
+		close(res.done)
+	}
+}
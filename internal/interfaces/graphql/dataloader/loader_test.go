@@ -0,0 +1,146 @@
+package dataloader
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingFetch returns a batch function that echoes keys and records every batch it receives
+func recordingFetch() (BatchFunc[int, string], func() [][]int) {
+	var mu sync.Mutex
+	var batches [][]int
+
+	fetch := func(_ context.Context, keys []int) (map[int]string, error) {
+		mu.Lock()
+		sorted := append([]int(nil), keys...)
+		sort.Ints(sorted)
+		batches = append(batches, sorted)
+		mu.Unlock()
+
+		values := make(map[int]string, len(keys))
+		for _, k := range keys {
+			if k >= 0 {
+				values[k] = fmt.Sprintf("value-%d", k)
+			}
+		}
+		return values, nil
+	}
+
+	return fetch, func() [][]int {
+		mu.Lock()
+		defer mu.Unlock()
+		return batches
+	}
+}
+
+// loadConcurrently loads every key from its own goroutine and returns the results in key order
+func loadConcurrently(t *testing.T, loader *Loader[int, string], keys []int) []string {
+	t.Helper()
+
+	values := make([]string, len(keys))
+	var wg sync.WaitGroup
+	for i, key := range keys {
+		wg.Add(1)
+		go func(i, key int) {
+			defer wg.Done()
+			value, err := loader.Load(context.Background(), key)
+			assert.NoError(t, err)
+			values[i] = value
+		}(i, key)
+	}
+	wg.Wait()
+
+	return values
+}
+
+func TestLoader_BatchesAndDeduplicates(t *testing.T) {
+	fetch, batches := recordingFetch()
+	loader := NewLoader(fetch, 10*time.Millisecond, 100)
+
+	values := loadConcurrently(t, loader, []int{1, 2, 1, 3, 2})
+
+	assert.Equal(t, []string{"value-1", "value-2", "value-1", "value-3", "value-2"}, values)
+	assert.Equal(t, [][]int{{1, 2, 3}}, batches())
+}
+
+func TestLoader_CachesAcrossBatches(t *testing.T) {
+	fetch, batches := recordingFetch()
+	loader := NewLoader(fetch, time.Millisecond, 100)
+
+	_, err := loader.Load(context.Background(), 1)
+	require.NoError(t, err)
+	value, err := loader.Load(context.Background(), 1)
+	require.NoError(t, err)
+
+	assert.Equal(t, "value-1", value)
+	assert.Len(t, batches(), 1)
+}
+
+func TestLoader_SplitsAtMaxBatch(t *testing.T) {
+	fetch, batches := recordingFetch()
+	loader := NewLoader(fetch, 10*time.Millisecond, 2)
+
+	loadConcurrently(t, loader, []int{1, 2, 3, 4, 5})
+
+	total := 0
+	for _, b := range batches() {
+		assert.LessOrEqual(t, len(b), 2)
+		total += len(b)
+	}
+	assert.Equal(t, 5, total)
+}
+
+func TestLoader_MissingKeyResolvesToZero(t *testing.T) {
+	fetch, _ := recordingFetch()
+	loader := NewLoader(fetch, time.Millisecond, 100)
+
+	value, err := loader.Load(context.Background(), -1)
+
+	assert.NoError(t, err)
+	assert.Empty(t, value)
+}
+
+func TestLoader_ErrorsAreNotCached(t *testing.T) {
+	calls := 0
+	fetch := func(_ context.Context, keys []int) (map[int]string, error) {
+		calls++
+		if calls == 1 {
+			return nil, errors.New("database unavailable")
+		}
+		return map[int]string{1: "value-1"}, nil
+	}
+	loader := NewLoader(fetch, time.Millisecond, 100)
+
+	_, err := loader.Load(context.Background(), 1)
+	assert.EqualError(t, err, "database unavailable")
+
+	value, err := loader.Load(context.Background(), 1)
+	assert.NoError(t, err)
+	assert.Equal(t, "value-1", value)
+	assert.Equal(t, 2, calls)
+}
+
+func TestLoader_LoadHonoursContextCancellation(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+
+	fetch := func(_ context.Context, keys []int) (map[int]string, error) {
+		<-release
+		return nil, nil
+	}
+	loader := NewLoader(fetch, time.Millisecond, 100)
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+
+	_, err := loader.Load(ctx, 1)
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+}
@@ -0,0 +1,69 @@
+package dataloader
+
+import (
+	"context"
+	"time"
+
+	"github.com/captain-corgi/go-graphql-example/internal/application/user"
+	"github.com/captain-corgi/go-graphql-example/internal/domain/errors"
+)
+
+const (
+	// batchWait is how long a loader waits for more keys before fetching
+	batchWait = 2 * time.Millisecond
+
+	// maxBatchSize bounds the number of keys fetched in one round trip
+	maxBatchSize = 100
+)
+
+// loadersKey is the context key for the per-request loaders
+type loadersKey struct{}
+
+// Loaders holds the dataloaders available to resolvers for one request
+type Loaders struct {
+	// UserByID loads users by ID; missing users resolve to nil
+	UserByID *Loader[string, *user.UserDTO]
+}
+
+// NewLoaders creates a fresh set of loaders backed by the given service
+func NewLoaders(userService user.Service) *Loaders {
+	return &Loaders{
+		UserByID: NewLoader(usersByID(userService), batchWait, maxBatchSize),
+	}
+}
+
+// WithLoaders returns a copy of ctx carrying the loaders
+func WithLoaders(ctx context.Context, loaders *Loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, loaders)
+}
+
+// For returns the loaders carried by ctx, or nil if none were installed
+func For(ctx context.Context) *Loaders {
+	loaders, _ := ctx.Value(loadersKey{}).(*Loaders)
+	return loaders
+}
+
+// usersByID batches user lookups through the application service
+func usersByID(userService user.Service) BatchFunc[string, *user.UserDTO] {
+	return func(ctx context.Context, ids []string) (map[string]*user.UserDTO, error) {
+		resp, err := userService.GetUsersByIDs(ctx, user.GetUsersByIDsRequest{IDs: ids})
+		if err != nil {
+			return nil, err
+		}
+
+		if len(resp.Errors) > 0 {
+			firstError := resp.Errors[0]
+			return nil, errors.DomainError{
+				Code:    firstError.Code,
+				Message: firstError.Message,
+				Field:   firstError.Field,
+			}
+		}
+
+		users := make(map[string]*user.UserDTO, len(resp.Users))
+		for _, u := range resp.Users {
+			users[u.ID] = u
+		}
+		return users, nil
+	}
+}
@@ -0,0 +1,83 @@
+package dataloader
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/golang/mock/gomock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/captain-corgi/go-graphql-example/internal/application/user"
+	"github.com/captain-corgi/go-graphql-example/internal/application/user/mocks"
+)
+
+func TestFor(t *testing.T) {
+	assert.Nil(t, For(context.Background()))
+
+	loaders := &Loaders{}
+	assert.Same(t, loaders, For(WithLoaders(context.Background(), loaders)))
+}
+
+func TestUserByID(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	const (
+		aliceID   = "123e4567-e89b-12d3-a456-426614174001"
+		bobID     = "123e4567-e89b-12d3-a456-426614174002"
+		missingID = "123e4567-e89b-12d3-a456-426614174999"
+	)
+
+	mockService := mocks.NewMockService(ctrl)
+	mockService.EXPECT().
+		GetUsersByIDs(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, req user.GetUsersByIDsRequest) (*user.GetUsersByIDsResponse, error) {
+			assert.ElementsMatch(t, []string{aliceID, bobID, missingID}, req.IDs)
+			return &user.GetUsersByIDsResponse{Users: []*user.UserDTO{
+				{ID: bobID, Name: "Bob"},
+				{ID: aliceID, Name: "Alice"},
+			}}, nil
+		}).
+		Times(1)
+
+	loaders := NewLoaders(mockService)
+
+	ids := []string{aliceID, bobID, aliceID, missingID}
+	got := make([]*user.UserDTO, len(ids))
+	var wg sync.WaitGroup
+	for i, id := range ids {
+		wg.Add(1)
+		go func(i int, id string) {
+			defer wg.Done()
+			u, err := loaders.UserByID.Load(context.Background(), id)
+			assert.NoError(t, err)
+			got[i] = u
+		}(i, id)
+	}
+	wg.Wait()
+
+	require.NotNil(t, got[0])
+	assert.Equal(t, "Alice", got[0].Name)
+	require.NotNil(t, got[1])
+	assert.Equal(t, "Bob", got[1].Name)
+	assert.Same(t, got[0], got[2])
+	assert.Nil(t, got[3])
+}
+
+func TestUserByID_ServiceError(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	defer ctrl.Finish()
+
+	mockService := mocks.NewMockService(ctrl)
+	mockService.EXPECT().
+		GetUsersByIDs(gomock.Any(), gomock.Any()).
+		Return(&user.GetUsersByIDsResponse{Errors: []user.ErrorDTO{
+			{Code: "REPOSITORY_OPERATION", Message: "Repository operation failed"},
+		}}, nil)
+
+	_, err := NewLoaders(mockService).UserByID.Load(context.Background(), "123e4567-e89b-12d3-a456-426614174001")
+
+	assert.EqualError(t, err, "Repository operation failed")
+}
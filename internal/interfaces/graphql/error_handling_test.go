@@ -39,7 +39,7 @@ func TestGraphQLErrorHandling(t *testing.T) {
 	t.Run("Invalid GraphQL Syntax", func(t *testing.T) {
 		invalidQuery := `
 			query {
-				user(id: "test") {
+				user(id: "123e4567-e89b-12d3-a456-426614174000") {
 					id
 					email
 					// Missing closing brace
@@ -63,7 +63,7 @@ func TestGraphQLErrorHandling(t *testing.T) {
 	t.Run("Unknown Field", func(t *testing.T) {
 		query := `
 			query {
-				user(id: "test") {
+				user(id: "123e4567-e89b-12d3-a456-426614174000") {
 					id
 					email
 					unknownField
@@ -98,7 +98,7 @@ func TestGraphQLErrorHandling(t *testing.T) {
 	})
 
 	t.Run("Invalid JSON Request", func(t *testing.T) {
-		invalidJSON := `{"query": "{ user(id: "test") { id } }", "variables": invalid}`
+		invalidJSON := `{"query": "{ user(id: "123e4567-e89b-12d3-a456-426614174000") { id } }", "variables": invalid}`
 
 		resp, err := http.Post(
 			fmt.Sprintf("%s/query", testServer.URL),
@@ -22,6 +22,7 @@ import (
 	"github.com/captain-corgi/go-graphql-example/internal/application/user"
 	"github.com/captain-corgi/go-graphql-example/internal/application/user/mocks"
 	"github.com/captain-corgi/go-graphql-example/internal/interfaces/graphql/generated"
+	"github.com/captain-corgi/go-graphql-example/internal/interfaces/graphql/relay"
 	"github.com/captain-corgi/go-graphql-example/internal/interfaces/graphql/resolver"
 )
 
@@ -62,7 +63,7 @@ func TestGraphQLIntegration(t *testing.T) {
 	t.Run("User Query - Success", func(t *testing.T) {
 		// Mock successful user retrieval
 		expectedUser := &user.UserDTO{
-			ID:        "user-123",
+			ID:        "123e4567-e89b-12d3-a456-426614174000",
 			Email:     "john@example.com",
 			Name:      "John Doe",
 			CreatedAt: time.Now(),
@@ -70,7 +71,7 @@ func TestGraphQLIntegration(t *testing.T) {
 		}
 
 		mockUserService.EXPECT().
-			GetUser(gomock.Any(), user.GetUserRequest{ID: "user-123"}).
+			GetUser(gomock.Any(), user.GetUserRequest{ID: "123e4567-e89b-12d3-a456-426614174000"}).
 			Return(&user.GetUserResponse{User: expectedUser}, nil)
 
 		query := `
@@ -86,7 +87,7 @@ func TestGraphQLIntegration(t *testing.T) {
 		`
 
 		variables := map[string]interface{}{
-			"id": "user-123",
+			"id": "123e4567-e89b-12d3-a456-426614174000",
 		}
 
 		response := executeGraphQLRequest(t, testServer.URL, query, variables)
@@ -96,7 +97,7 @@ func TestGraphQLIntegration(t *testing.T) {
 		assert.NotNil(t, response.Data)
 
 		userData := response.Data.(map[string]interface{})["user"].(map[string]interface{})
-		assert.Equal(t, expectedUser.ID, userData["id"])
+		assert.Equal(t, relay.ToGlobalID("User", expectedUser.ID), userData["id"])
 		assert.Equal(t, expectedUser.Email, userData["email"])
 		assert.Equal(t, expectedUser.Name, userData["name"])
 		assert.NotEmpty(t, userData["createdAt"])
@@ -105,7 +106,7 @@ func TestGraphQLIntegration(t *testing.T) {
 
 	t.Run("User Query - Not Found", func(t *testing.T) {
 		mockUserService.EXPECT().
-			GetUser(gomock.Any(), user.GetUserRequest{ID: "nonexistent"}).
+			GetUser(gomock.Any(), user.GetUserRequest{ID: "123e4567-e89b-12d3-a456-426614174999"}).
 			Return(&user.GetUserResponse{
 				Errors: []user.ErrorDTO{{
 					Message: "User not found",
@@ -124,7 +125,7 @@ func TestGraphQLIntegration(t *testing.T) {
 		`
 
 		variables := map[string]interface{}{
-			"id": "nonexistent",
+			"id": "123e4567-e89b-12d3-a456-426614174999",
 		}
 
 		response := executeGraphQLRequest(t, testServer.URL, query, variables)
@@ -163,6 +164,109 @@ func TestGraphQLIntegration(t *testing.T) {
 		assert.Contains(t, response.Errors[0].Message, "Invalid user ID")
 	})
 
+	t.Run("User Query - Global ID", func(t *testing.T) {
+		// A global ID is decoded before reaching the service
+		mockUserService.EXPECT().
+			GetUser(gomock.Any(), user.GetUserRequest{ID: "123e4567-e89b-12d3-a456-426614174000"}).
+			Return(&user.GetUserResponse{User: &user.UserDTO{
+				ID:        "123e4567-e89b-12d3-a456-426614174000",
+				Email:     "john@example.com",
+				Name:      "John Doe",
+				CreatedAt: time.Now(),
+				UpdatedAt: time.Now(),
+			}}, nil)
+
+		query := `
+			query GetUser($id: ID!) {
+				user(id: $id) {
+					name
+				}
+			}
+		`
+
+		variables := map[string]interface{}{
+			"id": relay.ToGlobalID("User", "123e4567-e89b-12d3-a456-426614174000"),
+		}
+
+		response := executeGraphQLRequest(t, testServer.URL, query, variables)
+
+		assert.Nil(t, response.Errors)
+		userData := response.Data.(map[string]interface{})["user"].(map[string]interface{})
+		assert.Equal(t, "John Doe", userData["name"])
+	})
+
+	t.Run("Node Queries", func(t *testing.T) {
+		const (
+			johnID    = "123e4567-e89b-12d3-a456-426614174000"
+			missingID = "123e4567-e89b-12d3-a456-426614174999"
+		)
+		john := &user.UserDTO{
+			ID:        johnID,
+			Email:     "john@example.com",
+			Name:      "John Doe",
+			CreatedAt: time.Now(),
+			UpdatedAt: time.Now(),
+		}
+
+		// node and nodes resolve through one batched lookup per field
+		mockUserService.EXPECT().
+			GetUsersByIDs(gomock.Any(), user.GetUsersByIDsRequest{IDs: []string{johnID}}).
+			Return(&user.GetUsersByIDsResponse{Users: []*user.UserDTO{john}}, nil)
+		mockUserService.EXPECT().
+			GetUsersByIDs(gomock.Any(), user.GetUsersByIDsRequest{IDs: []string{missingID, johnID}}).
+			Return(&user.GetUsersByIDsResponse{Users: []*user.UserDTO{john}}, nil)
+
+		query := `
+			query Nodes($id: ID!, $ids: [ID!]!) {
+				node(id: $id) {
+					id
+					... on User {
+						email
+					}
+				}
+				nodes(ids: $ids) {
+					id
+				}
+			}
+		`
+
+		variables := map[string]interface{}{
+			"id":  relay.ToGlobalID("User", johnID),
+			"ids": []string{relay.ToGlobalID("User", missingID), relay.ToGlobalID("User", johnID)},
+		}
+
+		response := executeGraphQLRequest(t, testServer.URL, query, variables)
+
+		assert.Nil(t, response.Errors)
+		data := response.Data.(map[string]interface{})
+
+		node := data["node"].(map[string]interface{})
+		assert.Equal(t, relay.ToGlobalID("User", johnID), node["id"])
+		assert.Equal(t, "john@example.com", node["email"])
+
+		nodes := data["nodes"].([]interface{})
+		require.Len(t, nodes, 2)
+		assert.Nil(t, nodes[0])
+		assert.Equal(t, relay.ToGlobalID("User", johnID), nodes[1].(map[string]interface{})["id"])
+	})
+
+	t.Run("Node Query - Invalid ID", func(t *testing.T) {
+		query := `
+			query Node($id: ID!) {
+				node(id: $id) {
+					id
+				}
+			}
+		`
+
+		for _, id := range []string{"123e4567-e89b-12d3-a456-426614174000", relay.ToGlobalID("Order", "42")} {
+			response := executeGraphQLRequest(t, testServer.URL, query, map[string]interface{}{"id": id})
+
+			require.Len(t, response.Errors, 1)
+			assert.Contains(t, response.Errors[0].Message, "Invalid global ID")
+		}
+	})
+
 	t.Run("Users Query - Success with Pagination", func(t *testing.T) {
 		users := []*user.UserEdgeDTO{
 			{
@@ -242,7 +346,7 @@ func TestGraphQLIntegration(t *testing.T) {
 		// Verify first user
 		firstEdge := edges[0].(map[string]interface{})
 		firstNode := firstEdge["node"].(map[string]interface{})
-		assert.Equal(t, "user-1", firstNode["id"])
+		assert.Equal(t, relay.ToGlobalID("User", "user-1"), firstNode["id"])
 		assert.Equal(t, "user1@example.com", firstNode["email"])
 		assert.Equal(t, "user-1", firstEdge["cursor"])
 
@@ -279,6 +383,168 @@ func TestGraphQLIntegration(t *testing.T) {
 		assert.Contains(t, response.Errors[0].Message, "First parameter must be non-negative")
 	})
 
+	t.Run("Users Query - Total Count", func(t *testing.T) {
+		total := int64(42)
+		mockUserService.EXPECT().
+			ListUsers(gomock.Any(), user.ListUsersRequest{First: 10, IncludeTotalCount: true}).
+			Return(&user.ListUsersResponse{Users: &user.UserConnectionDTO{
+				Edges:      []*user.UserEdgeDTO{},
+				PageInfo:   &user.PageInfoDTO{},
+				TotalCount: &total,
+			}}, nil)
+
+		query := `
+			query CountUsers {
+				users {
+					totalCount
+				}
+			}
+		`
+
+		response := executeGraphQLRequest(t, testServer.URL, query, nil)
+
+		assert.Nil(t, response.Errors)
+		usersData := response.Data.(map[string]interface{})["users"].(map[string]interface{})
+		assert.Equal(t, float64(42), usersData["totalCount"])
+	})
+
+	t.Run("Users Query - Include Deleted", func(t *testing.T) {
+		deletedAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+		mockUserService.EXPECT().
+			ListUsers(gomock.Any(), user.ListUsersRequest{First: 10, IncludeDeleted: true}).
+			Return(&user.ListUsersResponse{Users: &user.UserConnectionDTO{
+				Edges: []*user.UserEdgeDTO{{
+					Node: &user.UserDTO{
+						ID:        "123e4567-e89b-12d3-a456-426614174000",
+						Email:     "deleted@example.com",
+						Name:      "Deleted User",
+						CreatedAt: deletedAt.Add(-time.Hour),
+						UpdatedAt: deletedAt,
+						DeletedAt: &deletedAt,
+					},
+					Cursor: "cursor1",
+				}},
+				PageInfo: &user.PageInfoDTO{},
+			}}, nil)
+
+		query := `
+			query DeletedUsers {
+				users(includeDeleted: true) {
+					edges {
+						node {
+							deletedAt
+						}
+					}
+				}
+			}
+		`
+
+		response := executeGraphQLRequest(t, testServer.URL, query, nil)
+
+		assert.Nil(t, response.Errors)
+		usersData := response.Data.(map[string]interface{})["users"].(map[string]interface{})
+		edges := usersData["edges"].([]interface{})
+		require.Len(t, edges, 1)
+		node := edges[0].(map[string]interface{})["node"].(map[string]interface{})
+		assert.Equal(t, "2024-03-01T12:00:00Z", node["deletedAt"])
+	})
+
+	t.Run("Users Query - Status Filter", func(t *testing.T) {
+		mockUserService.EXPECT().
+			ListUsers(gomock.Any(), user.ListUsersRequest{
+				First:  10,
+				Filter: &user.UserFilterDTO{Statuses: []string{"SUSPENDED", "DEACTIVATED"}},
+			}).
+			Return(&user.ListUsersResponse{Users: &user.UserConnectionDTO{
+				Edges:    []*user.UserEdgeDTO{},
+				PageInfo: &user.PageInfoDTO{},
+			}}, nil)
+
+		query := `
+			query DisabledUsers {
+				users(filter: { status: [SUSPENDED, DEACTIVATED] }) {
+					edges {
+						node {
+							status
+						}
+					}
+				}
+			}
+		`
+
+		response := executeGraphQLRequest(t, testServer.URL, query, nil)
+
+		assert.Nil(t, response.Errors)
+	})
+
+	t.Run("UserStats Query - Success", func(t *testing.T) {
+		from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+		to := from.AddDate(0, 2, 0)
+
+		mockUserService.EXPECT().
+			GetUserStats(gomock.Any(), user.GetUserStatsRequest{From: from, To: to, Interval: "MONTH"}).
+			Return(&user.GetUserStatsResponse{Stats: &user.UserStatsDTO{
+				From:         from,
+				To:           to,
+				Interval:     "MONTH",
+				TotalSignups: 5,
+				Signups: []*user.SignupBucketDTO{
+					{Start: from, Count: 4},
+					{Start: from.AddDate(0, 1, 0), Count: 1},
+				},
+			}}, nil)
+
+		query := `
+			query Stats($from: DateTime!, $to: DateTime!) {
+				userStats(from: $from, to: $to, interval: MONTH) {
+					interval
+					totalSignups
+					signups {
+						start
+						count
+					}
+				}
+			}
+		`
+
+		variables := map[string]interface{}{
+			"from": "2025-01-01T00:00:00Z",
+			"to":   "2025-03-01T00:00:00Z",
+		}
+
+		response := executeGraphQLRequest(t, testServer.URL, query, variables)
+
+		assert.Nil(t, response.Errors)
+		stats := response.Data.(map[string]interface{})["userStats"].(map[string]interface{})
+		assert.Equal(t, "MONTH", stats["interval"])
+		assert.Equal(t, float64(5), stats["totalSignups"])
+
+		signups := stats["signups"].([]interface{})
+		assert.Len(t, signups, 2)
+		assert.Equal(t, "2025-02-01T00:00:00Z", signups[1].(map[string]interface{})["start"])
+	})
+
+	t.Run("UserStats Query - Invalid Range", func(t *testing.T) {
+		mockUserService.EXPECT().
+			GetUserStats(gomock.Any(), gomock.Any()).
+			Return(&user.GetUserStatsResponse{Errors: []user.ErrorDTO{
+				{Code: "INVALID_STATS_RANGE", Message: "Stats range start must be before its end", Field: "from"},
+			}}, nil)
+
+		query := `
+			query {
+				userStats(from: "2025-02-01T00:00:00Z", to: "2025-01-01T00:00:00Z", interval: DAY) {
+					totalSignups
+				}
+			}
+		`
+
+		response := executeGraphQLRequest(t, testServer.URL, query, nil)
+
+		assert.NotNil(t, response.Errors)
+		assert.Contains(t, response.Errors[0].Message, "Stats range start must be before its end")
+	})
+
 	t.Run("CreateUser Mutation - Success", func(t *testing.T) {
 		expectedUser := &user.UserDTO{
 			ID:        "new-user-id",
@@ -329,7 +595,7 @@ func TestGraphQLIntegration(t *testing.T) {
 
 		createUserData := response.Data.(map[string]interface{})["createUser"].(map[string]interface{})
 		userData := createUserData["user"].(map[string]interface{})
-		assert.Equal(t, expectedUser.ID, userData["id"])
+		assert.Equal(t, relay.ToGlobalID("User", expectedUser.ID), userData["id"])
 		assert.Equal(t, expectedUser.Email, userData["email"])
 		assert.Equal(t, expectedUser.Name, userData["name"])
 
@@ -448,7 +714,7 @@ func TestGraphQLIntegration(t *testing.T) {
 		newEmail := "updated@example.com"
 		newName := "Updated Name"
 		expectedUser := &user.UserDTO{
-			ID:        "user-123",
+			ID:        "123e4567-e89b-12d3-a456-426614174000",
 			Email:     "updated@example.com",
 			Name:      "Updated Name",
 			CreatedAt: time.Now().Add(-24 * time.Hour),
@@ -457,7 +723,7 @@ func TestGraphQLIntegration(t *testing.T) {
 
 		mockUserService.EXPECT().
 			UpdateUser(gomock.Any(), user.UpdateUserRequest{
-				ID:    "user-123",
+				ID:    "123e4567-e89b-12d3-a456-426614174000",
 				Email: &newEmail,
 				Name:  &newName,
 			}).
@@ -483,7 +749,7 @@ func TestGraphQLIntegration(t *testing.T) {
 		`
 
 		variables := map[string]interface{}{
-			"id": "user-123",
+			"id": "123e4567-e89b-12d3-a456-426614174000",
 			"input": map[string]interface{}{
 				"email": "updated@example.com",
 				"name":  "Updated Name",
@@ -498,7 +764,7 @@ func TestGraphQLIntegration(t *testing.T) {
 
 		updateUserData := response.Data.(map[string]interface{})["updateUser"].(map[string]interface{})
 		userData := updateUserData["user"].(map[string]interface{})
-		assert.Equal(t, expectedUser.ID, userData["id"])
+		assert.Equal(t, relay.ToGlobalID("User", expectedUser.ID), userData["id"])
 		assert.Equal(t, expectedUser.Email, userData["email"])
 		assert.Equal(t, expectedUser.Name, userData["name"])
 
@@ -524,7 +790,7 @@ func TestGraphQLIntegration(t *testing.T) {
 		`
 
 		variables := map[string]interface{}{
-			"id":    "user-123",
+			"id":    "123e4567-e89b-12d3-a456-426614174000",
 			"input": map[string]interface{}{}, // No fields to update
 		}
 
@@ -549,9 +815,58 @@ func TestGraphQLIntegration(t *testing.T) {
 		}
 	})
 
+	t.Run("UpdateUser Mutation - Version Conflict", func(t *testing.T) {
+		newName := "Updated Name"
+		expectedVersion := int64(2)
+		currentVersion := int64(3)
+
+		mockUserService.EXPECT().
+			UpdateUser(gomock.Any(), user.UpdateUserRequest{
+				ID:              "123e4567-e89b-12d3-a456-426614174000",
+				Name:            &newName,
+				ExpectedVersion: &expectedVersion,
+			}).
+			Return(&user.UpdateUserResponse{
+				Errors: []user.ErrorDTO{{
+					Message:        "User was modified concurrently; current version is 3",
+					Code:           "CONFLICT",
+					CurrentVersion: &currentVersion,
+				}},
+			}, nil)
+
+		mutation := `
+			mutation UpdateUser($id: ID!, $input: UpdateUserInput!) {
+				updateUser(id: $id, input: $input) {
+					errors {
+						message
+						code
+						currentVersion
+					}
+				}
+			}
+		`
+
+		variables := map[string]interface{}{
+			"id": "123e4567-e89b-12d3-a456-426614174000",
+			"input": map[string]interface{}{
+				"name":            "Updated Name",
+				"expectedVersion": 2,
+			},
+		}
+
+		response := executeGraphQLRequest(t, testServer.URL, mutation, variables)
+
+		assert.Nil(t, response.Errors)
+		payload := response.Data.(map[string]interface{})["updateUser"].(map[string]interface{})
+		errors := payload["errors"].([]interface{})
+		require.Len(t, errors, 1)
+		assert.Equal(t, "CONFLICT", errors[0].(map[string]interface{})["code"])
+		assert.Equal(t, float64(3), errors[0].(map[string]interface{})["currentVersion"])
+	})
+
 	t.Run("DeleteUser Mutation - Success", func(t *testing.T) {
 		mockUserService.EXPECT().
-			DeleteUser(gomock.Any(), user.DeleteUserRequest{ID: "user-123"}).
+			DeleteUser(gomock.Any(), user.DeleteUserRequest{ID: "123e4567-e89b-12d3-a456-426614174000"}).
 			Return(&user.DeleteUserResponse{Success: true}, nil)
 
 		mutation := `
@@ -568,7 +883,7 @@ func TestGraphQLIntegration(t *testing.T) {
 		`
 
 		variables := map[string]interface{}{
-			"id": "user-123",
+			"id": "123e4567-e89b-12d3-a456-426614174000",
 		}
 
 		response := executeGraphQLRequest(t, testServer.URL, mutation, variables)
@@ -587,7 +902,7 @@ func TestGraphQLIntegration(t *testing.T) {
 
 	t.Run("DeleteUser Mutation - User Not Found", func(t *testing.T) {
 		mockUserService.EXPECT().
-			DeleteUser(gomock.Any(), user.DeleteUserRequest{ID: "nonexistent"}).
+			DeleteUser(gomock.Any(), user.DeleteUserRequest{ID: "123e4567-e89b-12d3-a456-426614174999"}).
 			Return(&user.DeleteUserResponse{
 				Success: false,
 				Errors: []user.ErrorDTO{{
@@ -610,7 +925,7 @@ func TestGraphQLIntegration(t *testing.T) {
 		`
 
 		variables := map[string]interface{}{
-			"id": "nonexistent",
+			"id": "123e4567-e89b-12d3-a456-426614174999",
 		}
 
 		response := executeGraphQLRequest(t, testServer.URL, mutation, variables)
@@ -629,6 +944,243 @@ func TestGraphQLIntegration(t *testing.T) {
 		assert.Equal(t, "User not found", firstError["message"])
 		assert.Equal(t, "USER_NOT_FOUND", firstError["code"])
 	})
+
+	t.Run("RestoreUser Mutation - Success", func(t *testing.T) {
+		mockUserService.EXPECT().
+			RestoreUser(gomock.Any(), user.RestoreUserRequest{ID: "123e4567-e89b-12d3-a456-426614174000"}).
+			Return(&user.RestoreUserResponse{User: &user.UserDTO{
+				ID:        "123e4567-e89b-12d3-a456-426614174000",
+				Email:     "restored@example.com",
+				Name:      "Restored User",
+				CreatedAt: time.Now(),
+				UpdatedAt: time.Now(),
+			}}, nil)
+
+		mutation := `
+			mutation RestoreUser($id: ID!) {
+				restoreUser(id: $id) {
+					user {
+						email
+						deletedAt
+					}
+					errors {
+						code
+					}
+				}
+			}
+		`
+
+		variables := map[string]interface{}{
+			"id": "123e4567-e89b-12d3-a456-426614174000",
+		}
+
+		response := executeGraphQLRequest(t, testServer.URL, mutation, variables)
+
+		assert.Nil(t, response.Errors)
+		restoreUserData := response.Data.(map[string]interface{})["restoreUser"].(map[string]interface{})
+		userData := restoreUserData["user"].(map[string]interface{})
+		assert.Equal(t, "restored@example.com", userData["email"])
+		assert.Nil(t, userData["deletedAt"])
+		assert.Nil(t, restoreUserData["errors"])
+	})
+
+	t.Run("SuspendUser Mutation - Success", func(t *testing.T) {
+		occurredAt := time.Date(2024, 3, 1, 12, 0, 0, 0, time.UTC)
+		mockUserService.EXPECT().
+			ChangeUserStatus(gomock.Any(), user.ChangeUserStatusRequest{
+				ID:     "123e4567-e89b-12d3-a456-426614174000",
+				Status: "SUSPENDED",
+				Reason: "Chargeback investigation",
+			}).
+			Return(&user.ChangeUserStatusResponse{
+				User: &user.UserDTO{
+					ID:        "123e4567-e89b-12d3-a456-426614174000",
+					Email:     "test@example.com",
+					Name:      "Test User",
+					CreatedAt: occurredAt.Add(-time.Hour),
+					UpdatedAt: occurredAt,
+					Status:    "SUSPENDED",
+				},
+				Transition: &user.StatusTransitionDTO{
+					From:       "ACTIVE",
+					To:         "SUSPENDED",
+					Actor:      "admin",
+					Reason:     "Chargeback investigation",
+					OccurredAt: occurredAt,
+				},
+			}, nil)
+
+		mutation := `
+			mutation SuspendUser($id: ID!, $reason: String!) {
+				suspendUser(id: $id, reason: $reason) {
+					user {
+						status
+					}
+					transition {
+						from
+						to
+						actor
+						reason
+						occurredAt
+					}
+					errors {
+						code
+					}
+				}
+			}
+		`
+
+		variables := map[string]interface{}{
+			"id":     "123e4567-e89b-12d3-a456-426614174000",
+			"reason": "  Chargeback investigation  ",
+		}
+
+		response := executeGraphQLRequest(t, testServer.URL, mutation, variables)
+
+		assert.Nil(t, response.Errors)
+		payload := response.Data.(map[string]interface{})["suspendUser"].(map[string]interface{})
+		assert.Equal(t, "SUSPENDED", payload["user"].(map[string]interface{})["status"])
+		assert.Equal(t, map[string]interface{}{
+			"from":       "ACTIVE",
+			"to":         "SUSPENDED",
+			"actor":      "admin",
+			"reason":     "Chargeback investigation",
+			"occurredAt": "2024-03-01T12:00:00Z",
+		}, payload["transition"])
+		assert.Nil(t, payload["errors"])
+	})
+
+	t.Run("ReactivateUser Mutation - Illegal Transition", func(t *testing.T) {
+		mockUserService.EXPECT().
+			ChangeUserStatus(gomock.Any(), user.ChangeUserStatusRequest{
+				ID:     "123e4567-e89b-12d3-a456-426614174000",
+				Status: "ACTIVE",
+				Reason: "Mistake",
+			}).
+			Return(&user.ChangeUserStatusResponse{
+				Errors: []user.ErrorDTO{{
+					Message: "Cannot change status from ACTIVE to ACTIVE",
+					Field:   "status",
+					Code:    "INVALID_STATUS_TRANSITION",
+				}},
+			}, nil)
+
+		mutation := `
+			mutation ReactivateUser($id: ID!, $reason: String!) {
+				reactivateUser(id: $id, reason: $reason) {
+					transition {
+						to
+					}
+					errors {
+						message
+						code
+					}
+				}
+			}
+		`
+
+		variables := map[string]interface{}{
+			"id":     "123e4567-e89b-12d3-a456-426614174000",
+			"reason": "Mistake",
+		}
+
+		response := executeGraphQLRequest(t, testServer.URL, mutation, variables)
+
+		assert.Nil(t, response.Errors)
+		payload := response.Data.(map[string]interface{})["reactivateUser"].(map[string]interface{})
+		assert.Nil(t, payload["transition"])
+		errors := payload["errors"].([]interface{})
+		require.Len(t, errors, 1)
+		assert.Equal(t, "INVALID_STATUS_TRANSITION", errors[0].(map[string]interface{})["code"])
+		assert.Equal(t, "Cannot change status from ACTIVE to ACTIVE", errors[0].(map[string]interface{})["message"])
+	})
+
+	t.Run("ImportJob Query - UUID Canonicalized", func(t *testing.T) {
+		mockUserService.EXPECT().
+			GetImportJob(gomock.Any(), user.GetImportJobRequest{ID: "5b0c0a8e-8f53-4a43-9d0c-0f1d6f4b1f7e"}).
+			Return(&user.GetImportJobResponse{Job: &user.ImportJobDTO{
+				ID:     "5b0c0a8e-8f53-4a43-9d0c-0f1d6f4b1f7e",
+				Format: user.ImportFormatCSV,
+				Status: user.ImportJobCompleted,
+			}}, nil)
+
+		query := `
+			query ImportJob($id: UUID!) {
+				importJob(id: $id) {
+					id
+					status
+				}
+			}
+		`
+
+		variables := map[string]interface{}{
+			"id": "5B0C0A8E-8F53-4A43-9D0C-0F1D6F4B1F7E",
+		}
+
+		response := executeGraphQLRequest(t, testServer.URL, query, variables)
+
+		assert.Nil(t, response.Errors)
+		jobData := response.Data.(map[string]interface{})["importJob"].(map[string]interface{})
+		assert.Equal(t, "5b0c0a8e-8f53-4a43-9d0c-0f1d6f4b1f7e", jobData["id"])
+		assert.Equal(t, "COMPLETED", jobData["status"])
+	})
+
+	t.Run("ImportJob Query - Invalid UUID", func(t *testing.T) {
+		// Rejected while parsing variables, so the service is never called
+		query := `
+			query ImportJob($id: UUID!) {
+				importJob(id: $id) {
+					id
+				}
+			}
+		`
+
+		variables := map[string]interface{}{
+			"id": "job-123",
+		}
+
+		response := executeGraphQLRequest(t, testServer.URL, query, variables)
+
+		require.NotEmpty(t, response.Errors)
+		assert.Contains(t, response.Errors[0].Message, "invalid UUID")
+	})
+
+	t.Run("PurgeUser Mutation - Forbidden", func(t *testing.T) {
+		mockUserService.EXPECT().
+			PurgeUser(gomock.Any(), user.PurgeUserRequest{ID: "123e4567-e89b-12d3-a456-426614174000"}).
+			Return(&user.PurgeUserResponse{
+				Success: false,
+				Errors: []user.ErrorDTO{{
+					Message: "Operation requires administrator privileges",
+					Code:    "FORBIDDEN",
+				}},
+			}, nil)
+
+		mutation := `
+			mutation PurgeUser($id: ID!) {
+				purgeUser(id: $id) {
+					success
+					errors {
+						message
+						code
+					}
+				}
+			}
+		`
+
+		variables := map[string]interface{}{
+			"id": "123e4567-e89b-12d3-a456-426614174000",
+		}
+
+		response := executeGraphQLRequest(t, testServer.URL, mutation, variables)
+
+		assert.Nil(t, response.Errors)
+		purgeUserData := response.Data.(map[string]interface{})["purgeUser"].(map[string]interface{})
+		assert.False(t, purgeUserData["success"].(bool))
+		errors := purgeUserData["errors"].([]interface{})
+		require.Len(t, errors, 1)
+		assert.Equal(t, "FORBIDDEN", errors[0].(map[string]interface{})["code"])
+	})
 }
 
 // createTestServer creates a test server with the given resolver
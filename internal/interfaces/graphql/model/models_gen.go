@@ -2,6 +2,25 @@
 
 package model
 
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strconv"
+	"time"
+)
+
+type Node interface {
+	IsNode()
+	GetID() string
+}
+
+type ChangeUserStatusPayload struct {
+	User       *User             `json:"user"`
+	Transition *StatusTransition `json:"transition,omitempty"`
+	Errors     []*Error          `json:"errors,omitempty"`
+}
+
 type CreateUserInput struct {
 	Email string `json:"email"`
 	Name  string `json:"name"`
@@ -12,15 +31,50 @@ type CreateUserPayload struct {
 	Errors []*Error `json:"errors,omitempty"`
 }
 
+type DateTimeRange struct {
+	From *time.Time `json:"from,omitempty"`
+	To   *time.Time `json:"to,omitempty"`
+}
+
 type DeleteUserPayload struct {
 	Success bool     `json:"success"`
 	Errors  []*Error `json:"errors,omitempty"`
 }
 
 type Error struct {
-	Message string  `json:"message"`
-	Field   *string `json:"field,omitempty"`
-	Code    *string `json:"code,omitempty"`
+	Message        string  `json:"message"`
+	Field          *string `json:"field,omitempty"`
+	Code           *string `json:"code,omitempty"`
+	CurrentVersion *int    `json:"currentVersion,omitempty"`
+}
+
+type ImportJob struct {
+	ID            string             `json:"id"`
+	FileName      string             `json:"fileName"`
+	Format        ImportFormat       `json:"format"`
+	Status        ImportJobStatus    `json:"status"`
+	Progress      float64            `json:"progress"`
+	ProcessedRows int                `json:"processedRows"`
+	CreatedCount  int                `json:"createdCount"`
+	RejectedCount int                `json:"rejectedCount"`
+	Error         *Error             `json:"error,omitempty"`
+	CreatedAt     time.Time          `json:"createdAt"`
+	StartedAt     *time.Time         `json:"startedAt,omitempty"`
+	FinishedAt    *time.Time         `json:"finishedAt,omitempty"`
+	Rows          []*ImportRowResult `json:"rows"`
+}
+
+type ImportRowResult struct {
+	Line   int             `json:"line"`
+	Email  string          `json:"email"`
+	Status ImportRowStatus `json:"status"`
+	UserID *string         `json:"userId,omitempty"`
+	Error  *Error          `json:"error,omitempty"`
+}
+
+type ImportUsersPayload struct {
+	Job    *ImportJob `json:"job,omitempty"`
+	Errors []*Error   `json:"errors,omitempty"`
 }
 
 type Mutation struct {
@@ -33,12 +87,44 @@ type PageInfo struct {
 	EndCursor       *string `json:"endCursor,omitempty"`
 }
 
+type PurgeUserPayload struct {
+	Success bool     `json:"success"`
+	Errors  []*Error `json:"errors,omitempty"`
+}
+
 type Query struct {
 }
 
+type RestoreUserPayload struct {
+	User   *User    `json:"user"`
+	Errors []*Error `json:"errors,omitempty"`
+}
+
+type SignupBucket struct {
+	Start time.Time `json:"start"`
+	Count int       `json:"count"`
+}
+
+type StatusTransition struct {
+	From       UserStatus `json:"from"`
+	To         UserStatus `json:"to"`
+	Actor      string     `json:"actor"`
+	Reason     string     `json:"reason"`
+	OccurredAt time.Time  `json:"occurredAt"`
+}
+
+type StringFilter struct {
+	Equals   *string `json:"equals,omitempty"`
+	Contains *string `json:"contains,omitempty"`
+}
+
+type Subscription struct {
+}
+
 type UpdateUserInput struct {
-	Email *string `json:"email,omitempty"`
-	Name  *string `json:"name,omitempty"`
+	Email           *string `json:"email,omitempty"`
+	Name            *string `json:"name,omitempty"`
+	ExpectedVersion *int    `json:"expectedVersion,omitempty"`
 }
 
 type UpdateUserPayload struct {
@@ -47,19 +133,470 @@ type UpdateUserPayload struct {
 }
 
 type User struct {
-	ID        string `json:"id"`
-	Email     string `json:"email"`
-	Name      string `json:"name"`
-	CreatedAt string `json:"createdAt"`
-	UpdatedAt string `json:"updatedAt"`
+	ID        string     `json:"id"`
+	Email     string     `json:"email"`
+	Name      string     `json:"name"`
+	CreatedAt time.Time  `json:"createdAt"`
+	UpdatedAt time.Time  `json:"updatedAt"`
+	Status    UserStatus `json:"status"`
+	Version   int        `json:"version"`
+	DeletedAt *time.Time `json:"deletedAt,omitempty"`
 }
 
+func (User) IsNode()            {}
+func (this User) GetID() string { return this.ID }
+
 type UserConnection struct {
-	Edges    []*UserEdge `json:"edges"`
-	PageInfo *PageInfo   `json:"pageInfo"`
+	Edges      []*UserEdge `json:"edges"`
+	PageInfo   *PageInfo   `json:"pageInfo"`
+	TotalCount int         `json:"totalCount"`
 }
 
 type UserEdge struct {
 	Node   *User  `json:"node"`
 	Cursor string `json:"cursor"`
 }
+
+type UserFilter struct {
+	Email     *StringFilter  `json:"email,omitempty"`
+	Name      *StringFilter  `json:"name,omitempty"`
+	CreatedAt *DateTimeRange `json:"createdAt,omitempty"`
+	UpdatedAt *DateTimeRange `json:"updatedAt,omitempty"`
+	Ids       []string       `json:"ids,omitempty"`
+	Status    []UserStatus   `json:"status,omitempty"`
+}
+
+type UserOrder struct {
+	Field     UserOrderField `json:"field"`
+	Direction OrderDirection `json:"direction"`
+}
+
+type UserSearchConnection struct {
+	Edges    []*UserSearchEdge `json:"edges"`
+	PageInfo *PageInfo         `json:"pageInfo"`
+}
+
+type UserSearchEdge struct {
+	Node       *User                 `json:"node"`
+	Cursor     string                `json:"cursor"`
+	Rank       float64               `json:"rank"`
+	Highlights *UserSearchHighlights `json:"highlights"`
+}
+
+type UserSearchHighlights struct {
+	Name  *string `json:"name,omitempty"`
+	Email *string `json:"email,omitempty"`
+}
+
+type UserStats struct {
+	From         time.Time       `json:"from"`
+	To           time.Time       `json:"to"`
+	Interval     StatsInterval   `json:"interval"`
+	TotalSignups int             `json:"totalSignups"`
+	Signups      []*SignupBucket `json:"signups"`
+}
+
+type ImportFormat string
+
+const (
+	ImportFormatCSV    ImportFormat = "CSV"
+	ImportFormatNdjson ImportFormat = "NDJSON"
+)
+
+var AllImportFormat = []ImportFormat{
+	ImportFormatCSV,
+	ImportFormatNdjson,
+}
+
+func (e ImportFormat) IsValid() bool {
+	switch e {
+	case ImportFormatCSV, ImportFormatNdjson:
+		return true
+	}
+	return false
+}
+
+func (e ImportFormat) String() string {
+	return string(e)
+}
+
+func (e *ImportFormat) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ImportFormat(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ImportFormat", str)
+	}
+	return nil
+}
+
+func (e ImportFormat) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ImportFormat) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ImportFormat) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type ImportJobStatus string
+
+const (
+	ImportJobStatusPending   ImportJobStatus = "PENDING"
+	ImportJobStatusRunning   ImportJobStatus = "RUNNING"
+	ImportJobStatusCompleted ImportJobStatus = "COMPLETED"
+	ImportJobStatusFailed    ImportJobStatus = "FAILED"
+)
+
+var AllImportJobStatus = []ImportJobStatus{
+	ImportJobStatusPending,
+	ImportJobStatusRunning,
+	ImportJobStatusCompleted,
+	ImportJobStatusFailed,
+}
+
+func (e ImportJobStatus) IsValid() bool {
+	switch e {
+	case ImportJobStatusPending, ImportJobStatusRunning, ImportJobStatusCompleted, ImportJobStatusFailed:
+		return true
+	}
+	return false
+}
+
+func (e ImportJobStatus) String() string {
+	return string(e)
+}
+
+func (e *ImportJobStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ImportJobStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ImportJobStatus", str)
+	}
+	return nil
+}
+
+func (e ImportJobStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ImportJobStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ImportJobStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type ImportRowStatus string
+
+const (
+	ImportRowStatusCreated           ImportRowStatus = "CREATED"
+	ImportRowStatusInvalid           ImportRowStatus = "INVALID"
+	ImportRowStatusDuplicateInFile   ImportRowStatus = "DUPLICATE_IN_FILE"
+	ImportRowStatusDuplicateExisting ImportRowStatus = "DUPLICATE_EXISTING"
+	ImportRowStatusFailed            ImportRowStatus = "FAILED"
+)
+
+var AllImportRowStatus = []ImportRowStatus{
+	ImportRowStatusCreated,
+	ImportRowStatusInvalid,
+	ImportRowStatusDuplicateInFile,
+	ImportRowStatusDuplicateExisting,
+	ImportRowStatusFailed,
+}
+
+func (e ImportRowStatus) IsValid() bool {
+	switch e {
+	case ImportRowStatusCreated, ImportRowStatusInvalid, ImportRowStatusDuplicateInFile, ImportRowStatusDuplicateExisting, ImportRowStatusFailed:
+		return true
+	}
+	return false
+}
+
+func (e ImportRowStatus) String() string {
+	return string(e)
+}
+
+func (e *ImportRowStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = ImportRowStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid ImportRowStatus", str)
+	}
+	return nil
+}
+
+func (e ImportRowStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *ImportRowStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e ImportRowStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type OrderDirection string
+
+const (
+	OrderDirectionAsc  OrderDirection = "ASC"
+	OrderDirectionDesc OrderDirection = "DESC"
+)
+
+var AllOrderDirection = []OrderDirection{
+	OrderDirectionAsc,
+	OrderDirectionDesc,
+}
+
+func (e OrderDirection) IsValid() bool {
+	switch e {
+	case OrderDirectionAsc, OrderDirectionDesc:
+		return true
+	}
+	return false
+}
+
+func (e OrderDirection) String() string {
+	return string(e)
+}
+
+func (e *OrderDirection) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = OrderDirection(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid OrderDirection", str)
+	}
+	return nil
+}
+
+func (e OrderDirection) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *OrderDirection) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e OrderDirection) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type StatsInterval string
+
+const (
+	StatsIntervalDay   StatsInterval = "DAY"
+	StatsIntervalWeek  StatsInterval = "WEEK"
+	StatsIntervalMonth StatsInterval = "MONTH"
+)
+
+var AllStatsInterval = []StatsInterval{
+	StatsIntervalDay,
+	StatsIntervalWeek,
+	StatsIntervalMonth,
+}
+
+func (e StatsInterval) IsValid() bool {
+	switch e {
+	case StatsIntervalDay, StatsIntervalWeek, StatsIntervalMonth:
+		return true
+	}
+	return false
+}
+
+func (e StatsInterval) String() string {
+	return string(e)
+}
+
+func (e *StatsInterval) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = StatsInterval(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid StatsInterval", str)
+	}
+	return nil
+}
+
+func (e StatsInterval) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *StatsInterval) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e StatsInterval) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type UserOrderField string
+
+const (
+	UserOrderFieldName      UserOrderField = "NAME"
+	UserOrderFieldEmail     UserOrderField = "EMAIL"
+	UserOrderFieldCreatedAt UserOrderField = "CREATED_AT"
+	UserOrderFieldUpdatedAt UserOrderField = "UPDATED_AT"
+)
+
+var AllUserOrderField = []UserOrderField{
+	UserOrderFieldName,
+	UserOrderFieldEmail,
+	UserOrderFieldCreatedAt,
+	UserOrderFieldUpdatedAt,
+}
+
+func (e UserOrderField) IsValid() bool {
+	switch e {
+	case UserOrderFieldName, UserOrderFieldEmail, UserOrderFieldCreatedAt, UserOrderFieldUpdatedAt:
+		return true
+	}
+	return false
+}
+
+func (e UserOrderField) String() string {
+	return string(e)
+}
+
+func (e *UserOrderField) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = UserOrderField(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid UserOrderField", str)
+	}
+	return nil
+}
+
+func (e UserOrderField) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *UserOrderField) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e UserOrderField) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
+
+type UserStatus string
+
+const (
+	UserStatusInvited     UserStatus = "INVITED"
+	UserStatusActive      UserStatus = "ACTIVE"
+	UserStatusSuspended   UserStatus = "SUSPENDED"
+	UserStatusDeactivated UserStatus = "DEACTIVATED"
+)
+
+var AllUserStatus = []UserStatus{
+	UserStatusInvited,
+	UserStatusActive,
+	UserStatusSuspended,
+	UserStatusDeactivated,
+}
+
+func (e UserStatus) IsValid() bool {
+	switch e {
+	case UserStatusInvited, UserStatusActive, UserStatusSuspended, UserStatusDeactivated:
+		return true
+	}
+	return false
+}
+
+func (e UserStatus) String() string {
+	return string(e)
+}
+
+func (e *UserStatus) UnmarshalGQL(v any) error {
+	str, ok := v.(string)
+	if !ok {
+		return fmt.Errorf("enums must be strings")
+	}
+
+	*e = UserStatus(str)
+	if !e.IsValid() {
+		return fmt.Errorf("%s is not a valid UserStatus", str)
+	}
+	return nil
+}
+
+func (e UserStatus) MarshalGQL(w io.Writer) {
+	fmt.Fprint(w, strconv.Quote(e.String()))
+}
+
+func (e *UserStatus) UnmarshalJSON(b []byte) error {
+	s, err := strconv.Unquote(string(b))
+	if err != nil {
+		return err
+	}
+	return e.UnmarshalGQL(s)
+}
+
+func (e UserStatus) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	e.MarshalGQL(&buf)
+	return buf.Bytes(), nil
+}
@@ -13,6 +13,7 @@ import (
 
 	"github.com/captain-corgi/go-graphql-example/internal/application/user"
 	"github.com/captain-corgi/go-graphql-example/internal/application/user/mocks"
+	"github.com/captain-corgi/go-graphql-example/internal/interfaces/graphql/relay"
 	"github.com/captain-corgi/go-graphql-example/internal/interfaces/graphql/resolver"
 )
 
@@ -206,11 +207,11 @@ func TestGraphQLPaginationEdgeCases(t *testing.T) {
 		// Verify users
 		firstEdge := edges[0].(map[string]interface{})
 		firstNode := firstEdge["node"].(map[string]interface{})
-		assert.Equal(t, "user-3", firstNode["id"])
+		assert.Equal(t, relay.ToGlobalID("User", "user-3"), firstNode["id"])
 
 		secondEdge := edges[1].(map[string]interface{})
 		secondNode := secondEdge["node"].(map[string]interface{})
-		assert.Equal(t, "user-4", secondNode["id"])
+		assert.Equal(t, relay.ToGlobalID("User", "user-4"), secondNode["id"])
 
 		// Verify pagination info
 		pageInfo := usersData["pageInfo"].(map[string]interface{})
@@ -245,6 +246,151 @@ func TestGraphQLPaginationEdgeCases(t *testing.T) {
 		assert.Contains(t, response.Errors[0].Message, "Cursor cannot be empty")
 	})
 
+	t.Run("Backward Pagination with Before Cursor", func(t *testing.T) {
+		edges := []*user.UserEdgeDTO{
+			{
+				Node: &user.UserDTO{
+					ID:        "user-1",
+					Email:     "user1@example.com",
+					Name:      "User One",
+					CreatedAt: time.Now(),
+					UpdatedAt: time.Now(),
+				},
+				Cursor: "opaque-1",
+			},
+		}
+
+		connection := &user.UserConnectionDTO{
+			Edges: edges,
+			PageInfo: &user.PageInfoDTO{
+				HasNextPage:     true,
+				HasPreviousPage: false,
+				StartCursor:     &edges[0].Cursor,
+				EndCursor:       &edges[0].Cursor,
+			},
+		}
+
+		// No default first is applied when paging backward
+		mockUserService.EXPECT().
+			ListUsers(gomock.Any(), user.ListUsersRequest{Last: 1, Before: "opaque-2"}).
+			Return(&user.ListUsersResponse{Users: connection}, nil)
+
+		query := `
+			query ListUsers($last: Int, $before: String) {
+				users(last: $last, before: $before) {
+					edges {
+						node {
+							id
+						}
+						cursor
+					}
+					pageInfo {
+						hasNextPage
+						hasPreviousPage
+					}
+				}
+			}
+		`
+
+		variables := map[string]interface{}{
+			"last":   1,
+			"before": "opaque-2",
+		}
+
+		response := executeGraphQLRequest(t, testServer.URL, query, variables)
+
+		assert.Nil(t, response.Errors)
+		usersData := response.Data.(map[string]interface{})["users"].(map[string]interface{})
+		assert.Len(t, usersData["edges"].([]interface{}), 1)
+
+		pageInfo := usersData["pageInfo"].(map[string]interface{})
+		assert.True(t, pageInfo["hasNextPage"].(bool))
+		assert.False(t, pageInfo["hasPreviousPage"].(bool))
+	})
+
+	t.Run("First Combined With Last", func(t *testing.T) {
+		query := `
+			query ListUsers {
+				users(first: 5, last: 5) {
+					edges {
+						node {
+							id
+						}
+					}
+				}
+			}
+		`
+
+		response := executeGraphQLRequest(t, testServer.URL, query, nil)
+
+		assert.NotNil(t, response.Errors)
+		assert.Len(t, response.Errors, 1)
+		assert.Contains(t, response.Errors[0].Message, "Cannot combine first and last")
+	})
+
+	t.Run("Filter And OrderBy", func(t *testing.T) {
+		contains := "example.com"
+		from := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+
+		mockUserService.EXPECT().
+			ListUsers(gomock.Any(), user.ListUsersRequest{
+				First: 10,
+				Filter: &user.UserFilterDTO{
+					Email:     &user.StringFilterDTO{Contains: &contains},
+					CreatedAt: &user.TimeRangeDTO{From: &from},
+					IDs:       []string{"123e4567-e89b-12d3-a456-426614174000"},
+				},
+				OrderBy: &user.UserOrderDTO{Field: "NAME", Direction: "ASC"},
+			}).
+			Return(&user.ListUsersResponse{Users: &user.UserConnectionDTO{
+				Edges:    []*user.UserEdgeDTO{},
+				PageInfo: &user.PageInfoDTO{},
+			}}, nil)
+
+		query := `
+			query ListUsers($filter: UserFilter, $orderBy: UserOrder) {
+				users(filter: $filter, orderBy: $orderBy) {
+					edges {
+						node {
+							id
+						}
+					}
+				}
+			}
+		`
+
+		variables := map[string]interface{}{
+			"filter": map[string]interface{}{
+				"email":     map[string]interface{}{"contains": "example.com"},
+				"createdAt": map[string]interface{}{"from": "2025-01-01T00:00:00Z"},
+				"ids":       []string{"123e4567-e89b-12d3-a456-426614174000"},
+			},
+			"orderBy": map[string]interface{}{"field": "NAME", "direction": "ASC"},
+		}
+
+		response := executeGraphQLRequest(t, testServer.URL, query, variables)
+
+		assert.Nil(t, response.Errors)
+	})
+
+	t.Run("Unknown OrderBy Field", func(t *testing.T) {
+		query := `
+			query ListUsers {
+				users(orderBy: { field: PASSWORD, direction: ASC }) {
+					edges {
+						node {
+							id
+						}
+					}
+				}
+			}
+		`
+
+		response := executeGraphQLRequest(t, testServer.URL, query, nil)
+
+		assert.NotNil(t, response.Errors)
+	})
+
 	t.Run("Large Page Size Within Limit", func(t *testing.T) {
 		// Create 50 users for the response
 		users := make([]*user.UserEdgeDTO, 50)
@@ -323,7 +469,7 @@ func TestGraphQLInputSanitization(t *testing.T) {
 
 	t.Run("Whitespace Trimming in User ID", func(t *testing.T) {
 		expectedUser := &user.UserDTO{
-			ID:        "user-123",
+			ID:        "123e4567-e89b-12d3-a456-426614174000",
 			Email:     "test@example.com",
 			Name:      "Test User",
 			CreatedAt: time.Now(),
@@ -332,7 +478,7 @@ func TestGraphQLInputSanitization(t *testing.T) {
 
 		// Expect the service to be called with trimmed ID
 		mockUserService.EXPECT().
-			GetUser(gomock.Any(), user.GetUserRequest{ID: "user-123"}).
+			GetUser(gomock.Any(), user.GetUserRequest{ID: "123e4567-e89b-12d3-a456-426614174000"}).
 			Return(&user.GetUserResponse{User: expectedUser}, nil)
 
 		query := `
@@ -346,7 +492,7 @@ func TestGraphQLInputSanitization(t *testing.T) {
 		`
 
 		variables := map[string]interface{}{
-			"id": "  user-123  ", // ID with extra whitespace
+			"id": "  123e4567-e89b-12d3-a456-426614174000  ", // ID with extra whitespace
 		}
 
 		response := executeGraphQLRequest(t, testServer.URL, query, variables)
@@ -356,7 +502,7 @@ func TestGraphQLInputSanitization(t *testing.T) {
 		assert.NotNil(t, response.Data)
 
 		userData := response.Data.(map[string]interface{})["user"].(map[string]interface{})
-		assert.Equal(t, expectedUser.ID, userData["id"])
+		assert.Equal(t, relay.ToGlobalID("User", expectedUser.ID), userData["id"])
 	})
 
 	t.Run("Whitespace Trimming in Create User Input", func(t *testing.T) {
@@ -406,7 +552,7 @@ func TestGraphQLInputSanitization(t *testing.T) {
 
 		createUserData := response.Data.(map[string]interface{})["createUser"].(map[string]interface{})
 		userData := createUserData["user"].(map[string]interface{})
-		assert.Equal(t, expectedUser.ID, userData["id"])
+		assert.Equal(t, relay.ToGlobalID("User", expectedUser.ID), userData["id"])
 		assert.Equal(t, expectedUser.Email, userData["email"])
 		assert.Equal(t, expectedUser.Name, userData["name"])
 	})
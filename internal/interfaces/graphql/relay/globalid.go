@@ -0,0 +1,46 @@
+package relay
+
+import (
+	"encoding/base64"
+	"strings"
+
+	"github.com/captain-corgi/go-graphql-example/internal/domain/errors"
+)
+
+// ErrInvalidID is returned for IDs that are not well-formed global IDs of a registered type
+var ErrInvalidID = errors.DomainError{
+	Code:    "INVALID_ID",
+	Message: "Invalid global ID",
+	Field:   "id",
+}
+
+// ToGlobalID encodes a type name and a type-local ID as an opaque global ID, base64("Type:id")
+func ToGlobalID(typeName, id string) string {
+	return base64.StdEncoding.EncodeToString([]byte(typeName + ":" + id))
+}
+
+// FromGlobalID splits a global ID into its type name and type-local ID
+func FromGlobalID(globalID string) (typeName, id string, err error) {
+	raw, err := base64.StdEncoding.DecodeString(globalID)
+	if err != nil {
+		return "", "", ErrInvalidID
+	}
+
+	typeName, id, ok := strings.Cut(string(raw), ":")
+	if !ok || typeName == "" || id == "" {
+		return "", "", ErrInvalidID
+	}
+
+	return typeName, id, nil
+}
+
+// LocalID returns the type-local ID when globalID is a global ID of typeName,
+// and globalID unchanged otherwise, so callers keep accepting bare IDs.
+// Unchanged values are left for the application layer to validate.
+func LocalID(typeName, globalID string) string {
+	decodedType, id, err := FromGlobalID(globalID)
+	if err != nil || decodedType != typeName {
+		return globalID
+	}
+	return id
+}
@@ -0,0 +1,49 @@
+package relay
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGlobalIDRoundTrip(t *testing.T) {
+	globalID := ToGlobalID("User", "123e4567-e89b-12d3-a456-426614174000")
+
+	assert.Equal(t, "VXNlcjoxMjNlNDU2Ny1lODliLTEyZDMtYTQ1Ni00MjY2MTQxNzQwMDA=", globalID)
+
+	typeName, id, err := FromGlobalID(globalID)
+	require.NoError(t, err)
+	assert.Equal(t, "User", typeName)
+	assert.Equal(t, "123e4567-e89b-12d3-a456-426614174000", id)
+}
+
+func TestFromGlobalID_Invalid(t *testing.T) {
+	tests := []struct {
+		name     string
+		globalID string
+	}{
+		{name: "empty", globalID: ""},
+		{name: "not base64", globalID: "123e4567-e89b-12d3-a456-426614174000"},
+		{name: "no separator", globalID: "VXNlcg=="},   // "User"
+		{name: "empty type", globalID: "OjEyMw=="},     // ":123"
+		{name: "empty local id", globalID: "VXNlcjo="}, // "User:"
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := FromGlobalID(tt.globalID)
+			assert.Equal(t, ErrInvalidID, err)
+		})
+	}
+}
+
+func TestLocalID(t *testing.T) {
+	const uuid = "123e4567-e89b-12d3-a456-426614174000"
+
+	assert.Equal(t, uuid, LocalID("User", ToGlobalID("User", uuid)))
+	assert.Equal(t, uuid, LocalID("User", uuid), "bare IDs pass through")
+
+	otherType := ToGlobalID("Order", uuid)
+	assert.Equal(t, otherType, LocalID("User", otherType), "IDs of other types pass through")
+}
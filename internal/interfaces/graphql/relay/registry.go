@@ -0,0 +1,76 @@
+package relay
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/captain-corgi/go-graphql-example/internal/interfaces/graphql/model"
+)
+
+// Fetcher loads nodes of one type by type-local ID
+// IDs missing from the returned map resolve to null
+type Fetcher func(ctx context.Context, ids []string) (map[string]model.Node, error)
+
+// Registry routes global IDs to the fetcher registered for their type
+type Registry struct {
+	fetchers map[string]Fetcher
+}
+
+// NewRegistry creates an empty registry
+func NewRegistry() *Registry {
+	return &Registry{
+		fetchers: make(map[string]Fetcher),
+	}
+}
+
+// Register makes nodes of typeName resolvable; registering a type twice panics
+func (r *Registry) Register(typeName string, fetch Fetcher) {
+	if _, exists := r.fetchers[typeName]; exists {
+		panic(fmt.Sprintf("relay: node type %q registered twice", typeName))
+	}
+	r.fetchers[typeName] = fetch
+}
+
+// Resolve loads the nodes for globalIDs, calling each type's fetcher once.
+// The result is aligned with globalIDs and holds nil for nodes that do not exist.
+// IDs that are malformed or name an unregistered type fail the whole call with ErrInvalidID.
+func (r *Registry) Resolve(ctx context.Context, globalIDs []string) ([]model.Node, error) {
+	type ref struct{ typeName, id string }
+
+	refs := make([]ref, len(globalIDs))
+	byType := make(map[string][]string)
+	seen := make(map[ref]bool)
+	for i, globalID := range globalIDs {
+		typeName, id, err := FromGlobalID(globalID)
+		if err != nil {
+			return nil, err
+		}
+		if _, ok := r.fetchers[typeName]; !ok {
+			return nil, ErrInvalidID
+		}
+
+		refs[i] = ref{typeName: typeName, id: id}
+		if !seen[refs[i]] {
+			seen[refs[i]] = true
+			byType[typeName] = append(byType[typeName], id)
+		}
+	}
+
+	found := make(map[string]map[string]model.Node, len(byType))
+	for typeName, ids := range byType {
+		nodes, err := r.fetchers[typeName](ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+		found[typeName] = nodes
+	}
+
+	result := make([]model.Node, len(refs))
+	for i, ref := range refs {
+		if node, ok := found[ref.typeName][ref.id]; ok {
+			result[i] = node
+		}
+	}
+
+	return result, nil
+}
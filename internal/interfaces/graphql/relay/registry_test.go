@@ -0,0 +1,86 @@
+package relay
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/captain-corgi/go-graphql-example/internal/interfaces/graphql/model"
+)
+
+// recordingFetcher returns users for known IDs and records every call
+func recordingFetcher(known ...string) (Fetcher, *[][]string) {
+	var calls [][]string
+
+	fetch := func(_ context.Context, ids []string) (map[string]model.Node, error) {
+		calls = append(calls, ids)
+
+		nodes := make(map[string]model.Node)
+		for _, id := range ids {
+			for _, k := range known {
+				if id == k {
+					nodes[id] = &model.User{ID: ToGlobalID("User", id)}
+				}
+			}
+		}
+		return nodes, nil
+	}
+
+	return fetch, &calls
+}
+
+func TestRegistry_Resolve(t *testing.T) {
+	fetch, calls := recordingFetcher("1", "2")
+	registry := NewRegistry()
+	registry.Register("User", fetch)
+
+	nodes, err := registry.Resolve(context.Background(), []string{
+		ToGlobalID("User", "2"),
+		ToGlobalID("User", "404"),
+		ToGlobalID("User", "1"),
+		ToGlobalID("User", "2"),
+	})
+	require.NoError(t, err)
+
+	require.Len(t, nodes, 4)
+	assert.Equal(t, ToGlobalID("User", "2"), nodes[0].(*model.User).ID)
+	assert.Nil(t, nodes[1])
+	assert.Equal(t, ToGlobalID("User", "1"), nodes[2].(*model.User).ID)
+	assert.Equal(t, ToGlobalID("User", "2"), nodes[3].(*model.User).ID)
+
+	// One call per type, with duplicates removed
+	assert.Equal(t, [][]string{{"2", "404", "1"}}, *calls)
+}
+
+func TestRegistry_ResolveRejectsUnknownTypes(t *testing.T) {
+	fetch, calls := recordingFetcher("1")
+	registry := NewRegistry()
+	registry.Register("User", fetch)
+
+	_, err := registry.Resolve(context.Background(), []string{ToGlobalID("User", "1"), ToGlobalID("Order", "1")})
+
+	assert.Equal(t, ErrInvalidID, err)
+	assert.Empty(t, *calls)
+}
+
+func TestRegistry_ResolvePropagatesFetchErrors(t *testing.T) {
+	registry := NewRegistry()
+	registry.Register("User", func(context.Context, []string) (map[string]model.Node, error) {
+		return nil, errors.New("database unavailable")
+	})
+
+	_, err := registry.Resolve(context.Background(), []string{ToGlobalID("User", "1")})
+
+	assert.EqualError(t, err, "database unavailable")
+}
+
+func TestRegistry_RegisterTwicePanics(t *testing.T) {
+	registry := NewRegistry()
+	fetch, _ := recordingFetcher()
+	registry.Register("User", fetch)
+
+	assert.Panics(t, func() { registry.Register("User", fetch) })
+}
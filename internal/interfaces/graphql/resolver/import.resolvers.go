@@ -0,0 +1,64 @@
+package resolver
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.78
+
+import (
+	"context"
+
+	"github.com/captain-corgi/go-graphql-example/internal/application/user"
+	domainErrors "github.com/captain-corgi/go-graphql-example/internal/domain/errors"
+	"github.com/captain-corgi/go-graphql-example/internal/interfaces/graphql/generated"
+	"github.com/captain-corgi/go-graphql-example/internal/interfaces/graphql/model"
+)
+
+// Rows is the resolver for the rows field.
+func (r *importJobResolver) Rows(ctx context.Context, obj *model.ImportJob, status *model.ImportRowStatus, first *int, offset *int) ([]*model.ImportRowResult, error) {
+	// Log operation start
+	r.logOperation(ctx, "ImportJobRows", map[string]interface{}{
+		"id":     obj.ID,
+		"status": status,
+		"first":  first,
+		"offset": offset,
+	})
+
+	// Call application service; the rows are read afresh, so they may be ahead of the job's counts
+	req := user.GetImportJobRequest{ID: obj.ID, RowsFirst: 100}
+	if status != nil {
+		req.RowStatus = string(*status)
+	}
+	if first != nil {
+		req.RowsFirst = *first
+	}
+	if offset != nil {
+		req.RowsOffset = *offset
+	}
+	resp, err := r.userService.GetImportJob(ctx, req)
+	if err != nil {
+		return nil, r.handleGraphQLError(ctx, err, "ImportJobRows")
+	}
+
+	// Handle application-level errors
+	if len(resp.Errors) > 0 {
+		// Return the first error as GraphQL error
+		firstError := resp.Errors[0]
+		domainErr := domainErrors.DomainError{
+			Code:    firstError.Code,
+			Message: firstError.Message,
+			Field:   firstError.Field,
+		}
+		return nil, r.handleGraphQLError(ctx, domainErr, "ImportJobRows")
+	}
+
+	// Map result to GraphQL model
+	result := mapImportRowResultDTOsToGraphQL(resp.Job.Rows)
+
+	r.logOperationSuccess(ctx, "ImportJobRows", result)
+	return result, nil
+}
+
+// ImportJob returns generated.ImportJobResolver implementation.
+func (r *Resolver) ImportJob() generated.ImportJobResolver { return &importJobResolver{r} }
+
+type importJobResolver struct{ *Resolver }
@@ -11,6 +11,7 @@ import (
 	"github.com/captain-corgi/go-graphql-example/internal/application/user/mocks"
 	"github.com/captain-corgi/go-graphql-example/internal/interfaces/graphql/generated"
 	"github.com/captain-corgi/go-graphql-example/internal/interfaces/graphql/model"
+	"github.com/captain-corgi/go-graphql-example/internal/interfaces/graphql/relay"
 	"github.com/golang/mock/gomock"
 )
 
@@ -57,7 +58,7 @@ func TestGraphQLSchemaExecution(t *testing.T) {
 			t.Fatal("Expected user result, got nil")
 		}
 
-		if result.ID != expectedUser.ID {
+		if result.ID != relay.ToGlobalID("User", expectedUser.ID) {
 			t.Fatalf("Expected user ID %s, got %s", expectedUser.ID, result.ID)
 		}
 	})
@@ -96,7 +97,7 @@ func TestGraphQLSchemaExecution(t *testing.T) {
 			t.Fatal("Expected user in result, got nil")
 		}
 
-		if result.User.ID != expectedUser.ID {
+		if result.User.ID != relay.ToGlobalID("User", expectedUser.ID) {
 			t.Fatalf("Expected user ID %s, got %s", expectedUser.ID, result.User.ID)
 		}
 	})
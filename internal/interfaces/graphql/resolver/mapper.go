@@ -1,10 +1,9 @@
 package resolver
 
 import (
-	"time"
-
 	"github.com/captain-corgi/go-graphql-example/internal/application/user"
 	"github.com/captain-corgi/go-graphql-example/internal/interfaces/graphql/model"
+	"github.com/captain-corgi/go-graphql-example/internal/interfaces/graphql/relay"
 )
 
 // mapUserDTOToGraphQL converts a UserDTO to a GraphQL User model
@@ -14,11 +13,29 @@ func mapUserDTOToGraphQL(dto *user.UserDTO) *model.User {
 	}
 
 	return &model.User{
-		ID:        dto.ID,
+		ID:        relay.ToGlobalID(userTypeName, dto.ID),
 		Email:     dto.Email,
 		Name:      dto.Name,
-		CreatedAt: dto.CreatedAt.Format(time.RFC3339),
-		UpdatedAt: dto.UpdatedAt.Format(time.RFC3339),
+		CreatedAt: dto.CreatedAt,
+		UpdatedAt: dto.UpdatedAt,
+		Status:    model.UserStatus(dto.Status),
+		Version:   int(dto.Version),
+		DeletedAt: dto.DeletedAt,
+	}
+}
+
+// mapStatusTransitionDTOToGraphQL converts a StatusTransitionDTO to a GraphQL StatusTransition model
+func mapStatusTransitionDTOToGraphQL(dto *user.StatusTransitionDTO) *model.StatusTransition {
+	if dto == nil {
+		return nil
+	}
+
+	return &model.StatusTransition{
+		From:       model.UserStatus(dto.From),
+		To:         model.UserStatus(dto.To),
+		Actor:      dto.Actor,
+		Reason:     dto.Reason,
+		OccurredAt: dto.OccurredAt,
 	}
 }
 
@@ -36,7 +53,42 @@ func mapUserConnectionDTOToGraphQL(dto *user.UserConnectionDTO) *model.UserConne
 		}
 	}
 
-	return &model.UserConnection{
+	connection := &model.UserConnection{
+		Edges: edges,
+		PageInfo: &model.PageInfo{
+			HasNextPage:     dto.PageInfo.HasNextPage,
+			HasPreviousPage: dto.PageInfo.HasPreviousPage,
+			StartCursor:     dto.PageInfo.StartCursor,
+			EndCursor:       dto.PageInfo.EndCursor,
+		},
+	}
+	if dto.TotalCount != nil {
+		connection.TotalCount = int(*dto.TotalCount)
+	}
+
+	return connection
+}
+
+// mapUserSearchConnectionDTOToGraphQL converts a UserSearchConnectionDTO to a GraphQL UserSearchConnection model
+func mapUserSearchConnectionDTOToGraphQL(dto *user.UserSearchConnectionDTO) *model.UserSearchConnection {
+	if dto == nil {
+		return nil
+	}
+
+	edges := make([]*model.UserSearchEdge, len(dto.Edges))
+	for i, edge := range dto.Edges {
+		edges[i] = &model.UserSearchEdge{
+			Node:   mapUserDTOToGraphQL(edge.Node),
+			Cursor: edge.Cursor,
+			Rank:   edge.Rank,
+			Highlights: &model.UserSearchHighlights{
+				Name:  edge.Highlights.Name,
+				Email: edge.Highlights.Email,
+			},
+		}
+	}
+
+	return &model.UserSearchConnection{
 		Edges: edges,
 		PageInfo: &model.PageInfo{
 			HasNextPage:     dto.PageInfo.HasNextPage,
@@ -47,6 +99,79 @@ func mapUserConnectionDTOToGraphQL(dto *user.UserConnectionDTO) *model.UserConne
 	}
 }
 
+// mapUserStatsDTOToGraphQL converts a UserStatsDTO to a GraphQL UserStats model
+func mapUserStatsDTOToGraphQL(dto *user.UserStatsDTO) *model.UserStats {
+	if dto == nil {
+		return nil
+	}
+
+	signups := make([]*model.SignupBucket, len(dto.Signups))
+	for i, bucket := range dto.Signups {
+		signups[i] = &model.SignupBucket{
+			Start: bucket.Start,
+			Count: int(bucket.Count),
+		}
+	}
+
+	return &model.UserStats{
+		From:         dto.From,
+		To:           dto.To,
+		Interval:     model.StatsInterval(dto.Interval),
+		TotalSignups: int(dto.TotalSignups),
+		Signups:      signups,
+	}
+}
+
+// mapImportJobDTOToGraphQL converts an ImportJobDTO to a GraphQL ImportJob model
+// Rows are left to the rows field resolver
+func mapImportJobDTOToGraphQL(dto *user.ImportJobDTO) *model.ImportJob {
+	if dto == nil {
+		return nil
+	}
+
+	var jobErr *model.Error
+	if dto.Error != nil {
+		jobErr = mapErrorDTOToGraphQL(*dto.Error)
+	}
+
+	return &model.ImportJob{
+		ID:            dto.ID,
+		FileName:      dto.FileName,
+		Format:        model.ImportFormat(dto.Format),
+		Status:        model.ImportJobStatus(dto.Status),
+		Progress:      dto.Progress,
+		ProcessedRows: dto.Created + dto.Rejected,
+		CreatedCount:  dto.Created,
+		RejectedCount: dto.Rejected,
+		Error:         jobErr,
+		CreatedAt:     dto.CreatedAt,
+		StartedAt:     dto.StartedAt,
+		FinishedAt:    dto.FinishedAt,
+	}
+}
+
+// mapImportRowResultDTOsToGraphQL converts ImportRowResultDTOs to GraphQL ImportRowResult models
+func mapImportRowResultDTOsToGraphQL(dtos []user.ImportRowResultDTO) []*model.ImportRowResult {
+	rows := make([]*model.ImportRowResult, len(dtos))
+	for i, dto := range dtos {
+		row := &model.ImportRowResult{
+			Line:   dto.Line,
+			Email:  dto.Email,
+			Status: model.ImportRowStatus(dto.Status),
+		}
+		if dto.UserID != "" {
+			userID := relay.ToGlobalID(userTypeName, dto.UserID)
+			row.UserID = &userID
+		}
+		if dto.Error != nil {
+			row.Error = mapErrorDTOToGraphQL(*dto.Error)
+		}
+		rows[i] = row
+	}
+
+	return rows
+}
+
 // mapErrorDTOsToGraphQL converts ErrorDTOs to GraphQL Error models
 func mapErrorDTOsToGraphQL(dtos []user.ErrorDTO) []*model.Error {
 	if len(dtos) == 0 {
@@ -73,10 +198,17 @@ func mapErrorDTOToGraphQL(dto user.ErrorDTO) *model.Error {
 		code = &dto.Code
 	}
 
+	var currentVersion *int
+	if dto.CurrentVersion != nil {
+		v := int(*dto.CurrentVersion)
+		currentVersion = &v
+	}
+
 	return &model.Error{
-		Message: dto.Message,
-		Field:   field,
-		Code:    code,
+		Message:        dto.Message,
+		Field:          field,
+		Code:           code,
+		CurrentVersion: currentVersion,
 	}
 }
 
@@ -90,9 +222,94 @@ func mapCreateUserInputToRequest(input model.CreateUserInput) user.CreateUserReq
 
 // mapUpdateUserInputToRequest converts GraphQL UpdateUserInput to application request
 func mapUpdateUserInputToRequest(id string, input model.UpdateUserInput) user.UpdateUserRequest {
+	var expectedVersion *int64
+	if input.ExpectedVersion != nil {
+		v := int64(*input.ExpectedVersion)
+		expectedVersion = &v
+	}
+
 	return user.UpdateUserRequest{
-		ID:    id,
-		Email: input.Email,
-		Name:  input.Name,
+		ID:              id,
+		Email:           input.Email,
+		Name:            input.Name,
+		ExpectedVersion: expectedVersion,
+	}
+}
+
+// mapUserFilterToDTO converts a GraphQL UserFilter to an application filter
+func mapUserFilterToDTO(filter *model.UserFilter) *user.UserFilterDTO {
+	if filter == nil {
+		return nil
+	}
+
+	return &user.UserFilterDTO{
+		Email:     mapStringFilterToDTO(filter.Email),
+		Name:      mapStringFilterToDTO(filter.Name),
+		CreatedAt: mapDateTimeRangeToDTO(filter.CreatedAt),
+		UpdatedAt: mapDateTimeRangeToDTO(filter.UpdatedAt),
+		IDs:       mapUserIDsToDTO(filter.Ids),
+		Statuses:  mapUserStatusesToDTO(filter.Status),
+	}
+}
+
+// mapUserStatusesToDTO converts GraphQL statuses to application statuses, keeping nil distinct from empty
+func mapUserStatusesToDTO(statuses []model.UserStatus) []string {
+	if statuses == nil {
+		return nil
+	}
+
+	mapped := make([]string, len(statuses))
+	for i, status := range statuses {
+		mapped[i] = string(status)
+	}
+	return mapped
+}
+
+// mapUserIDsToDTO converts global User IDs or bare UUIDs to application IDs, keeping nil distinct from empty
+func mapUserIDsToDTO(ids []string) []string {
+	if ids == nil {
+		return nil
+	}
+
+	local := make([]string, len(ids))
+	for i, id := range ids {
+		local[i] = localUserID(id)
+	}
+	return local
+}
+
+// mapStringFilterToDTO converts a GraphQL StringFilter to an application string filter
+func mapStringFilterToDTO(filter *model.StringFilter) *user.StringFilterDTO {
+	if filter == nil {
+		return nil
+	}
+
+	return &user.StringFilterDTO{
+		Equals:   filter.Equals,
+		Contains: filter.Contains,
+	}
+}
+
+// mapDateTimeRangeToDTO converts a GraphQL DateTimeRange to an application time range
+func mapDateTimeRangeToDTO(r *model.DateTimeRange) *user.TimeRangeDTO {
+	if r == nil {
+		return nil
+	}
+
+	return &user.TimeRangeDTO{
+		From: r.From,
+		To:   r.To,
+	}
+}
+
+// mapUserOrderToDTO converts a GraphQL UserOrder to an application order
+func mapUserOrderToDTO(order *model.UserOrder) *user.UserOrderDTO {
+	if order == nil {
+		return nil
+	}
+
+	return &user.UserOrderDTO{
+		Field:     string(order.Field),
+		Direction: string(order.Direction),
 	}
 }
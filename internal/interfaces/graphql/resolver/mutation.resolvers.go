@@ -7,6 +7,7 @@ package resolver
 import (
 	"context"
 
+	"github.com/99designs/gqlgen/graphql"
 	"github.com/captain-corgi/go-graphql-example/internal/application/user"
 	"github.com/captain-corgi/go-graphql-example/internal/interfaces/graphql/generated"
 	"github.com/captain-corgi/go-graphql-example/internal/interfaces/graphql/model"
@@ -75,10 +76,11 @@ func (r *mutationResolver) UpdateUser(ctx context.Context, id string, input mode
 	})
 
 	// Validate and sanitize input
-	sanitizedID := sanitizeString(id)
+	sanitizedID := localUserID(sanitizeString(id))
 	sanitizedInput := model.UpdateUserInput{
-		Email: sanitizeStringPointer(input.Email),
-		Name:  sanitizeStringPointer(input.Name),
+		Email:           sanitizeStringPointer(input.Email),
+		Name:            sanitizeStringPointer(input.Name),
+		ExpectedVersion: input.ExpectedVersion,
 	}
 
 	// Validate user ID
@@ -141,7 +143,7 @@ func (r *mutationResolver) DeleteUser(ctx context.Context, id string) (*model.De
 	})
 
 	// Validate and sanitize input
-	sanitizedID := sanitizeString(id)
+	sanitizedID := localUserID(sanitizeString(id))
 	if err := r.validateInput(ctx, "DeleteUser", func() error {
 		return validateUserID(sanitizedID)
 	}); err != nil {
@@ -184,6 +186,161 @@ func (r *mutationResolver) DeleteUser(ctx context.Context, id string) (*model.De
 	return result, nil
 }
 
+// RestoreUser is the resolver for the restoreUser field.
+func (r *mutationResolver) RestoreUser(ctx context.Context, id string) (*model.RestoreUserPayload, error) {
+	// Log operation start
+	r.logOperation(ctx, "RestoreUser", map[string]interface{}{
+		"id": id,
+	})
+
+	// Validate and sanitize input
+	sanitizedID := localUserID(sanitizeString(id))
+	if err := r.validateInput(ctx, "RestoreUser", func() error {
+		return validateUserID(sanitizedID)
+	}); err != nil {
+		return &model.RestoreUserPayload{
+			Errors: []*model.Error{mapErrorDTOToGraphQL(user.ErrorDTO{
+				Message: err.Error(),
+				Code:    "VALIDATION_ERROR",
+			})},
+		}, nil
+	}
+
+	// Call application service
+	req := user.RestoreUserRequest{ID: sanitizedID}
+	resp, err := r.userService.RestoreUser(ctx, req)
+	if err != nil {
+		return &model.RestoreUserPayload{
+			Errors: []*model.Error{mapErrorDTOToGraphQL(user.ErrorDTO{
+				Message: "Failed to restore user",
+				Code:    "INTERNAL_ERROR",
+			})},
+		}, nil
+	}
+
+	// Handle application-level errors
+	if len(resp.Errors) > 0 {
+		return &model.RestoreUserPayload{
+			Errors: mapErrorDTOsToGraphQL(resp.Errors),
+		}, nil
+	}
+
+	// Map successful result
+	result := &model.RestoreUserPayload{
+		User: mapUserDTOToGraphQL(resp.User),
+	}
+
+	r.logOperationSuccess(ctx, "RestoreUser", result)
+	return result, nil
+}
+
+// PurgeUser is the resolver for the purgeUser field.
+func (r *mutationResolver) PurgeUser(ctx context.Context, id string) (*model.PurgeUserPayload, error) {
+	// Log operation start
+	r.logOperation(ctx, "PurgeUser", map[string]interface{}{
+		"id": id,
+	})
+
+	// Validate and sanitize input
+	sanitizedID := localUserID(sanitizeString(id))
+	if err := r.validateInput(ctx, "PurgeUser", func() error {
+		return validateUserID(sanitizedID)
+	}); err != nil {
+		return &model.PurgeUserPayload{
+			Success: false,
+			Errors: []*model.Error{mapErrorDTOToGraphQL(user.ErrorDTO{
+				Message: err.Error(),
+				Code:    "VALIDATION_ERROR",
+			})},
+		}, nil
+	}
+
+	// Call application service; it checks the caller is an admin
+	req := user.PurgeUserRequest{ID: sanitizedID}
+	resp, err := r.userService.PurgeUser(ctx, req)
+	if err != nil {
+		return &model.PurgeUserPayload{
+			Success: false,
+			Errors: []*model.Error{mapErrorDTOToGraphQL(user.ErrorDTO{
+				Message: "Failed to purge user",
+				Code:    "INTERNAL_ERROR",
+			})},
+		}, nil
+	}
+
+	// Handle application-level errors
+	if len(resp.Errors) > 0 {
+		return &model.PurgeUserPayload{
+			Success: false,
+			Errors:  mapErrorDTOsToGraphQL(resp.Errors),
+		}, nil
+	}
+
+	// Map successful result
+	result := &model.PurgeUserPayload{
+		Success: resp.Success,
+	}
+
+	r.logOperationSuccess(ctx, "PurgeUser", result)
+	return result, nil
+}
+
+// SuspendUser is the resolver for the suspendUser field.
+func (r *mutationResolver) SuspendUser(ctx context.Context, id string, reason string) (*model.ChangeUserStatusPayload, error) {
+	return r.changeUserStatus(ctx, "SuspendUser", id, model.UserStatusSuspended, reason)
+}
+
+// ReactivateUser is the resolver for the reactivateUser field.
+func (r *mutationResolver) ReactivateUser(ctx context.Context, id string, reason string) (*model.ChangeUserStatusPayload, error) {
+	return r.changeUserStatus(ctx, "ReactivateUser", id, model.UserStatusActive, reason)
+}
+
+// DeactivateUser is the resolver for the deactivateUser field.
+func (r *mutationResolver) DeactivateUser(ctx context.Context, id string, reason string) (*model.ChangeUserStatusPayload, error) {
+	return r.changeUserStatus(ctx, "DeactivateUser", id, model.UserStatusDeactivated, reason)
+}
+
+// ImportUsers is the resolver for the importUsers field.
+func (r *mutationResolver) ImportUsers(ctx context.Context, file graphql.Upload) (*model.ImportUsersPayload, error) {
+	// Log operation start
+	r.logOperation(ctx, "ImportUsers", map[string]interface{}{
+		"fileName":    file.Filename,
+		"contentType": file.ContentType,
+		"size":        file.Size,
+	})
+
+	// Call application service; it checks the caller is an admin and reads the file before returning
+	req := user.ImportUsersRequest{
+		File:        file.File,
+		FileName:    sanitizeString(file.Filename),
+		ContentType: file.ContentType,
+	}
+	resp, err := r.userService.ImportUsers(ctx, req)
+	if err != nil {
+		return &model.ImportUsersPayload{
+			Errors: []*model.Error{mapErrorDTOToGraphQL(user.ErrorDTO{
+				Message: "Failed to import users",
+				Code:    "INTERNAL_ERROR",
+			})},
+		}, nil
+	}
+
+	// Handle application-level errors
+	if len(resp.Errors) > 0 {
+		return &model.ImportUsersPayload{
+			Errors: mapErrorDTOsToGraphQL(resp.Errors),
+		}, nil
+	}
+
+	// Map successful result
+	result := &model.ImportUsersPayload{
+		Job: mapImportJobDTOToGraphQL(resp.Job),
+	}
+
+	r.logOperationSuccess(ctx, "ImportUsers", result)
+	return result, nil
+}
+
 // Mutation returns generated.MutationResolver implementation.
 func (r *Resolver) Mutation() generated.MutationResolver { return &mutationResolver{r} }
 
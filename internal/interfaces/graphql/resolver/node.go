@@ -0,0 +1,83 @@
+package resolver
+
+import (
+	"context"
+
+	"github.com/captain-corgi/go-graphql-example/internal/application/user"
+	domainErrors "github.com/captain-corgi/go-graphql-example/internal/domain/errors"
+	"github.com/captain-corgi/go-graphql-example/internal/interfaces/graphql/model"
+	"github.com/captain-corgi/go-graphql-example/internal/interfaces/graphql/relay"
+	"github.com/google/uuid"
+)
+
+const (
+	// userTypeName is the type prefix of global User IDs
+	userTypeName = "User"
+
+	// maxNodeIDs bounds the number of ids a nodes query may fetch
+	maxNodeIDs = 100
+)
+
+// newNodeRegistry registers a fetcher for every type implementing Node
+func (r *Resolver) newNodeRegistry() *relay.Registry {
+	registry := relay.NewRegistry()
+	registry.Register(userTypeName, r.fetchUserNodes)
+	return registry
+}
+
+// fetchUserNodes loads users for node(id:) and nodes(ids:) in one service call
+// IDs are canonicalized first, as stores key users by the lowercase hyphenated form
+func (r *Resolver) fetchUserNodes(ctx context.Context, ids []string) (map[string]model.Node, error) {
+	canonical := make([]string, 0, len(ids))
+	seen := make(map[string]bool, len(ids))
+	for _, id := range ids {
+		if c := canonicalUserID(id); !seen[c] {
+			seen[c] = true
+			canonical = append(canonical, c)
+		}
+	}
+
+	resp, err := r.userService.GetUsersByIDs(ctx, user.GetUsersByIDsRequest{IDs: canonical})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(resp.Errors) > 0 {
+		firstError := resp.Errors[0]
+		return nil, domainErrors.DomainError{
+			Code:    firstError.Code,
+			Message: firstError.Message,
+			Field:   firstError.Field,
+		}
+	}
+
+	users := make(map[string]*user.UserDTO, len(resp.Users))
+	for _, u := range resp.Users {
+		users[u.ID] = u
+	}
+
+	// The registry looks nodes up by the ids it was given, which may differ from
+	// the stored canonical form in case or hyphenation
+	nodes := make(map[string]model.Node, len(ids))
+	for _, id := range ids {
+		if u, ok := users[canonicalUserID(id)]; ok {
+			nodes[id] = mapUserDTOToGraphQL(u)
+		}
+	}
+	return nodes, nil
+}
+
+// localUserID accepts a global User ID or a bare UUID and returns the canonical UUID form
+func localUserID(id string) string {
+	return canonicalUserID(relay.LocalID(userTypeName, id))
+}
+
+// canonicalUserID returns a UUID in the lowercase hyphenated form users are stored under
+// Anything else is returned unchanged for the service to reject
+func canonicalUserID(id string) string {
+	parsed, err := uuid.Parse(id)
+	if err != nil {
+		return id
+	}
+	return parsed.String()
+}
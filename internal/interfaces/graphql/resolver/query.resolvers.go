@@ -6,13 +6,59 @@ package resolver
 
 import (
 	"context"
+	"time"
 
 	"github.com/captain-corgi/go-graphql-example/internal/application/user"
-	"github.com/captain-corgi/go-graphql-example/internal/domain/errors"
+	domainErrors "github.com/captain-corgi/go-graphql-example/internal/domain/errors"
+	"github.com/captain-corgi/go-graphql-example/internal/interfaces/graphql/dataloader"
 	"github.com/captain-corgi/go-graphql-example/internal/interfaces/graphql/generated"
 	"github.com/captain-corgi/go-graphql-example/internal/interfaces/graphql/model"
 )
 
+// Node is the resolver for the node field.
+func (r *queryResolver) Node(ctx context.Context, id string) (model.Node, error) {
+	// Log operation start
+	r.logOperation(ctx, "Node", map[string]interface{}{
+		"id": id,
+	})
+
+	nodes, err := r.nodes.Resolve(ctx, []string{sanitizeString(id)})
+	if err != nil {
+		return nil, r.handleGraphQLError(ctx, err, "Node")
+	}
+
+	r.logOperationSuccess(ctx, "Node", nodes[0])
+	return nodes[0], nil
+}
+
+// Nodes is the resolver for the nodes field.
+func (r *queryResolver) Nodes(ctx context.Context, ids []string) ([]model.Node, error) {
+	// Log operation start
+	r.logOperation(ctx, "Nodes", map[string]interface{}{
+		"count": len(ids),
+	})
+
+	// Validate input
+	if err := r.validateInput(ctx, "Nodes", func() error {
+		return validateNodeIDs(ids)
+	}); err != nil {
+		return nil, err
+	}
+
+	sanitizedIDs := make([]string, len(ids))
+	for i, id := range ids {
+		sanitizedIDs[i] = sanitizeString(id)
+	}
+
+	nodes, err := r.nodes.Resolve(ctx, sanitizedIDs)
+	if err != nil {
+		return nil, r.handleGraphQLError(ctx, err, "Nodes")
+	}
+
+	r.logOperationSuccess(ctx, "Nodes", nodes)
+	return nodes, nil
+}
+
 // User is the resolver for the user field.
 func (r *queryResolver) User(ctx context.Context, id string) (*model.User, error) {
 	// Log operation start
@@ -21,13 +67,29 @@ func (r *queryResolver) User(ctx context.Context, id string) (*model.User, error
 	})
 
 	// Validate and sanitize input
-	sanitizedID := sanitizeString(id)
+	sanitizedID := localUserID(sanitizeString(id))
 	if err := r.validateInput(ctx, "User", func() error {
 		return validateUserID(sanitizedID)
 	}); err != nil {
 		return nil, err
 	}
 
+	// Batch the lookup with others in the same request when loaders are installed
+	if loaders := dataloader.For(ctx); loaders != nil {
+		dto, err := loaders.UserByID.Load(ctx, sanitizedID)
+		if err != nil {
+			return nil, r.handleGraphQLError(ctx, err, "User")
+		}
+		if dto == nil {
+			return nil, r.handleGraphQLError(ctx, domainErrors.ErrUserNotFound, "User")
+		}
+
+		result := mapUserDTOToGraphQL(dto)
+		r.logOperationSuccess(ctx, "User", result)
+
+		return result, nil
+	}
+
 	// Call application service
 	req := user.GetUserRequest{ID: sanitizedID}
 	resp, err := r.userService.GetUser(ctx, req)
@@ -39,7 +101,7 @@ func (r *queryResolver) User(ctx context.Context, id string) (*model.User, error
 	if len(resp.Errors) > 0 {
 		// Return the first error as GraphQL error
 		firstError := resp.Errors[0]
-		domainErr := errors.DomainError{
+		domainErr := domainErrors.DomainError{
 			Code:    firstError.Code,
 			Message: firstError.Message,
 			Field:   firstError.Field,
@@ -55,36 +117,56 @@ func (r *queryResolver) User(ctx context.Context, id string) (*model.User, error
 }
 
 // Users is the resolver for the users field.
-func (r *queryResolver) Users(ctx context.Context, first *int, after *string) (*model.UserConnection, error) {
+func (r *queryResolver) Users(ctx context.Context, first *int, after *string, last *int, before *string, filter *model.UserFilter, orderBy *model.UserOrder, includeDeleted *bool) (*model.UserConnection, error) {
 	// Log operation start
 	r.logOperation(ctx, "Users", map[string]interface{}{
-		"first": first,
-		"after": after,
+		"first":          first,
+		"after":          after,
+		"last":           last,
+		"before":         before,
+		"filter":         filter,
+		"orderBy":        orderBy,
+		"includeDeleted": includeDeleted,
 	})
 
 	// Validate pagination parameters
 	if err := r.validateInput(ctx, "Users", func() error {
-		return validatePaginationParams(first, after)
+		return validatePaginationParams(first, after, last, before)
 	}); err != nil {
 		return nil, err
 	}
 
-	// Sanitize after parameter
-	var sanitizedAfter string
+	// Sanitize cursor parameters
+	var sanitizedAfter, sanitizedBefore string
 	if after != nil {
 		sanitizedAfter = sanitizeString(*after)
 	}
+	if before != nil {
+		sanitizedBefore = sanitizeString(*before)
+	}
 
-	// Set default first value
-	firstValue := 10 // Default page size
-	if first != nil {
-		firstValue = *first
+	// Page forward by default; page backward only when last is given
+	firstValue, lastValue := 0, 0
+	if last != nil {
+		lastValue = *last
+	} else {
+		firstValue = 10 // Default page size
+		if first != nil {
+			firstValue = *first
+		}
 	}
 
 	// Call application service
 	req := user.ListUsersRequest{
-		First: firstValue,
-		After: sanitizedAfter,
+		First:   firstValue,
+		After:   sanitizedAfter,
+		Last:    lastValue,
+		Before:  sanitizedBefore,
+		Filter:  mapUserFilterToDTO(filter),
+		OrderBy: mapUserOrderToDTO(orderBy),
+
+		IncludeTotalCount: isFieldSelected(ctx, "totalCount"),
+		IncludeDeleted:    includeDeleted != nil && *includeDeleted,
 	}
 	resp, err := r.userService.ListUsers(ctx, req)
 	if err != nil {
@@ -95,7 +177,7 @@ func (r *queryResolver) Users(ctx context.Context, first *int, after *string) (*
 	if len(resp.Errors) > 0 {
 		// Return the first error as GraphQL error
 		firstError := resp.Errors[0]
-		domainErr := errors.DomainError{
+		domainErr := domainErrors.DomainError{
 			Code:    firstError.Code,
 			Message: firstError.Message,
 			Field:   firstError.Field,
@@ -105,8 +187,131 @@ func (r *queryResolver) Users(ctx context.Context, first *int, after *string) (*
 
 	// Map result to GraphQL model
 	result := mapUserConnectionDTOToGraphQL(resp.Users)
+
 	r.logOperationSuccess(ctx, "Users", result)
+	return result, nil
+}
+
+// SearchUsers is the resolver for the searchUsers field.
+func (r *queryResolver) SearchUsers(ctx context.Context, query string, first *int, after *string) (*model.UserSearchConnection, error) {
+	// Log operation start
+	r.logOperation(ctx, "SearchUsers", map[string]interface{}{
+		"query": query,
+		"first": first,
+		"after": after,
+	})
+
+	// Validate pagination parameters; search only pages forward
+	if err := r.validateInput(ctx, "SearchUsers", func() error {
+		return validatePaginationParams(first, after, nil, nil)
+	}); err != nil {
+		return nil, err
+	}
+
+	// Call application service
+	req := user.SearchUsersRequest{
+		Query: sanitizeString(query),
+		First: 10, // Default page size
+	}
+	if first != nil {
+		req.First = *first
+	}
+	if after != nil {
+		req.After = sanitizeString(*after)
+	}
+	resp, err := r.userService.SearchUsers(ctx, req)
+	if err != nil {
+		return nil, r.handleGraphQLError(ctx, err, "SearchUsers")
+	}
+
+	// Handle application-level errors
+	if len(resp.Errors) > 0 {
+		// Return the first error as GraphQL error
+		firstError := resp.Errors[0]
+		domainErr := domainErrors.DomainError{
+			Code:    firstError.Code,
+			Message: firstError.Message,
+			Field:   firstError.Field,
+		}
+		return nil, r.handleGraphQLError(ctx, domainErr, "SearchUsers")
+	}
+
+	// Map result to GraphQL model
+	result := mapUserSearchConnectionDTOToGraphQL(resp.Results)
+
+	r.logOperationSuccess(ctx, "SearchUsers", result)
+	return result, nil
+}
+
+// UserStats is the resolver for the userStats field.
+func (r *queryResolver) UserStats(ctx context.Context, from time.Time, to time.Time, interval model.StatsInterval) (*model.UserStats, error) {
+	// Log operation start
+	r.logOperation(ctx, "UserStats", map[string]interface{}{
+		"from":     from,
+		"to":       to,
+		"interval": interval,
+	})
+
+	// Call application service
+	req := user.GetUserStatsRequest{
+		From:     from,
+		To:       to,
+		Interval: string(interval),
+	}
+	resp, err := r.userService.GetUserStats(ctx, req)
+	if err != nil {
+		return nil, r.handleGraphQLError(ctx, err, "UserStats")
+	}
+
+	// Handle application-level errors
+	if len(resp.Errors) > 0 {
+		// Return the first error as GraphQL error
+		firstError := resp.Errors[0]
+		domainErr := domainErrors.DomainError{
+			Code:    firstError.Code,
+			Message: firstError.Message,
+			Field:   firstError.Field,
+		}
+		return nil, r.handleGraphQLError(ctx, domainErr, "UserStats")
+	}
+
+	// Map result to GraphQL model
+	result := mapUserStatsDTOToGraphQL(resp.Stats)
+
+	r.logOperationSuccess(ctx, "UserStats", result)
+	return result, nil
+}
+
+// ImportJob is the resolver for the importJob field.
+func (r *queryResolver) ImportJob(ctx context.Context, id string) (*model.ImportJob, error) {
+	// Log operation start
+	r.logOperation(ctx, "ImportJob", map[string]interface{}{
+		"id": id,
+	})
+
+	// Call application service; row results are fetched by the rows field
+	req := user.GetImportJobRequest{ID: sanitizeString(id)}
+	resp, err := r.userService.GetImportJob(ctx, req)
+	if err != nil {
+		return nil, r.handleGraphQLError(ctx, err, "ImportJob")
+	}
+
+	// Handle application-level errors
+	if len(resp.Errors) > 0 {
+		// Return the first error as GraphQL error
+		firstError := resp.Errors[0]
+		domainErr := domainErrors.DomainError{
+			Code:    firstError.Code,
+			Message: firstError.Message,
+			Field:   firstError.Field,
+		}
+		return nil, r.handleGraphQLError(ctx, domainErr, "ImportJob")
+	}
+
+	// Map result to GraphQL model
+	result := mapImportJobDTOToGraphQL(resp.Job)
 
+	r.logOperationSuccess(ctx, "ImportJob", result)
 	return result, nil
 }
 
@@ -4,6 +4,8 @@ import (
 	"log/slog"
 
 	"github.com/captain-corgi/go-graphql-example/internal/application/user"
+	"github.com/captain-corgi/go-graphql-example/internal/interfaces/graphql/dataloader"
+	"github.com/captain-corgi/go-graphql-example/internal/interfaces/graphql/relay"
 )
 
 // This file will not be regenerated automatically.
@@ -13,13 +15,22 @@ import (
 // Resolver holds the dependencies for GraphQL resolvers
 type Resolver struct {
 	userService user.Service
+	nodes       *relay.Registry
 	logger      *slog.Logger
 }
 
 // NewResolver creates a new resolver with the given dependencies
 func NewResolver(userService user.Service, logger *slog.Logger) *Resolver {
-	return &Resolver{
+	r := &Resolver{
 		userService: userService,
 		logger:      logger,
 	}
+	r.nodes = r.newNodeRegistry()
+
+	return r
+}
+
+// NewLoaders creates the per-request dataloaders backed by the resolver's services
+func (r *Resolver) NewLoaders() *dataloader.Loaders {
+	return dataloader.NewLoaders(r.userService)
 }
@@ -4,12 +4,16 @@ import (
 	"context"
 	"log/slog"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
+	"github.com/99designs/gqlgen/graphql"
 	"github.com/captain-corgi/go-graphql-example/internal/application/user"
 	"github.com/captain-corgi/go-graphql-example/internal/application/user/mocks"
+	"github.com/captain-corgi/go-graphql-example/internal/interfaces/graphql/dataloader"
 	"github.com/captain-corgi/go-graphql-example/internal/interfaces/graphql/model"
+	"github.com/captain-corgi/go-graphql-example/internal/interfaces/graphql/relay"
 	"github.com/golang/mock/gomock"
 	"github.com/stretchr/testify/assert"
 )
@@ -41,7 +45,7 @@ func TestResolverIntegration(t *testing.T) {
 
 		assert.NoError(t, err)
 		assert.NotNil(t, result)
-		assert.Equal(t, expectedUser.ID, result.ID)
+		assert.Equal(t, relay.ToGlobalID("User", expectedUser.ID), result.ID)
 		assert.Equal(t, expectedUser.Email, result.Email)
 		assert.Equal(t, expectedUser.Name, result.Name)
 	})
@@ -71,6 +75,49 @@ func TestResolverIntegration(t *testing.T) {
 		assert.Contains(t, err.Error(), "Invalid user ID")
 	})
 
+	t.Run("User Query - Mixed Case ID Through Loader", func(t *testing.T) {
+		expectedUser := &user.UserDTO{
+			ID:    "123e4567-e89b-12d3-a456-426614174000",
+			Email: "john@example.com",
+			Name:  "John Doe",
+		}
+
+		mockUserService.EXPECT().
+			GetUsersByIDs(gomock.Any(), user.GetUsersByIDsRequest{IDs: []string{expectedUser.ID}}).
+			Return(&user.GetUsersByIDsResponse{Users: []*user.UserDTO{expectedUser}}, nil)
+
+		loaderCtx := dataloader.WithLoaders(ctx, dataloader.NewLoaders(mockUserService))
+		result, err := resolver.Query().User(loaderCtx, "123E4567E89B12D3A456426614174000")
+
+		assert.NoError(t, err)
+		assert.NotNil(t, result)
+		assert.Equal(t, relay.ToGlobalID("User", expectedUser.ID), result.ID)
+	})
+
+	t.Run("Nodes Query - Mixed Case Global IDs", func(t *testing.T) {
+		expectedUser := &user.UserDTO{
+			ID:    "123e4567-e89b-12d3-a456-426614174000",
+			Email: "john@example.com",
+			Name:  "John Doe",
+		}
+		upper := relay.ToGlobalID("User", "123E4567-E89B-12D3-A456-426614174000")
+
+		mockUserService.EXPECT().
+			GetUsersByIDs(gomock.Any(), user.GetUsersByIDsRequest{IDs: []string{expectedUser.ID}}).
+			Return(&user.GetUsersByIDsResponse{Users: []*user.UserDTO{expectedUser}}, nil)
+
+		lower := relay.ToGlobalID("User", expectedUser.ID)
+		result, err := resolver.Query().Nodes(ctx, []string{upper, lower})
+
+		assert.NoError(t, err)
+		assert.Len(t, result, 2)
+		for _, node := range result {
+			if assert.NotNil(t, node) {
+				assert.Equal(t, lower, node.(*model.User).ID)
+			}
+		}
+	})
+
 	t.Run("Users Query - Success", func(t *testing.T) {
 		users := []*user.UserEdgeDTO{
 			{
@@ -109,16 +156,71 @@ func TestResolverIntegration(t *testing.T) {
 			ListUsers(gomock.Any(), user.ListUsersRequest{First: 10, After: ""}).
 			Return(&user.ListUsersResponse{Users: connection}, nil)
 
-		result, err := resolver.Query().Users(ctx, nil, nil)
+		result, err := resolver.Query().Users(ctx, nil, nil, nil, nil, nil, nil, nil)
 
 		assert.NoError(t, err)
 		assert.NotNil(t, result)
 		assert.Len(t, result.Edges, 2)
-		assert.Equal(t, "user-1", result.Edges[0].Node.ID)
-		assert.Equal(t, "user-2", result.Edges[1].Node.ID)
+		assert.Equal(t, relay.ToGlobalID("User", "user-1"), result.Edges[0].Node.ID)
+		assert.Equal(t, relay.ToGlobalID("User", "user-2"), result.Edges[1].Node.ID)
 		assert.False(t, result.PageInfo.HasNextPage)
 	})
 
+	t.Run("SearchUsers Query - Success", func(t *testing.T) {
+		highlighted := "John <mark>Doe</mark>"
+		cursor := "signed-search-cursor"
+		results := &user.UserSearchConnectionDTO{
+			Edges: []*user.UserSearchEdgeDTO{
+				{
+					Node: &user.UserDTO{
+						ID:        "user-1",
+						Email:     "john@example.com",
+						Name:      "John Doe",
+						CreatedAt: time.Now(),
+						UpdatedAt: time.Now(),
+					},
+					Cursor:     cursor,
+					Rank:       1.2,
+					Highlights: &user.UserSearchHighlightsDTO{Name: &highlighted},
+				},
+			},
+			PageInfo: &user.PageInfoDTO{HasNextPage: true, StartCursor: &cursor, EndCursor: &cursor},
+		}
+
+		mockUserService.EXPECT().
+			SearchUsers(gomock.Any(), user.SearchUsersRequest{Query: "doe", First: 10}).
+			Return(&user.SearchUsersResponse{Results: results}, nil)
+
+		result, err := resolver.Query().SearchUsers(ctx, "  doe ", nil, nil)
+
+		assert.NoError(t, err)
+		assert.Len(t, result.Edges, 1)
+		assert.Equal(t, relay.ToGlobalID("User", "user-1"), result.Edges[0].Node.ID)
+		assert.Equal(t, 1.2, result.Edges[0].Rank)
+		assert.Equal(t, &highlighted, result.Edges[0].Highlights.Name)
+		assert.Nil(t, result.Edges[0].Highlights.Email)
+		assert.True(t, result.PageInfo.HasNextPage)
+	})
+
+	t.Run("SearchUsers Query - Invalid Query", func(t *testing.T) {
+		mockUserService.EXPECT().
+			SearchUsers(gomock.Any(), user.SearchUsersRequest{Query: "@@", First: 5}).
+			Return(&user.SearchUsersResponse{
+				Errors: []user.ErrorDTO{{
+					Message: "Search query must contain a letter or digit and be at most 200 characters",
+					Code:    "INVALID_SEARCH_QUERY",
+					Field:   "query",
+				}},
+			}, nil)
+
+		first := 5
+		result, err := resolver.Query().SearchUsers(ctx, "@@", &first, nil)
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+		assert.Contains(t, err.Error(), "Search query must contain")
+	})
+
 	t.Run("CreateUser Mutation - Success", func(t *testing.T) {
 		input := model.CreateUserInput{
 			Email: "new@example.com",
@@ -145,7 +247,7 @@ func TestResolverIntegration(t *testing.T) {
 		assert.NoError(t, err)
 		assert.NotNil(t, result)
 		assert.NotNil(t, result.User)
-		assert.Equal(t, expectedUser.ID, result.User.ID)
+		assert.Equal(t, relay.ToGlobalID("User", expectedUser.ID), result.User.ID)
 		assert.Equal(t, expectedUser.Email, result.User.Email)
 		assert.Nil(t, result.Errors)
 	})
@@ -226,7 +328,7 @@ func TestResolverIntegration(t *testing.T) {
 		assert.NoError(t, err)
 		assert.NotNil(t, result)
 		assert.NotNil(t, result.User)
-		assert.Equal(t, expectedUser.ID, result.User.ID)
+		assert.Equal(t, relay.ToGlobalID("User", expectedUser.ID), result.User.ID)
 		assert.Equal(t, expectedUser.Email, result.User.Email)
 		assert.Equal(t, expectedUser.Name, result.User.Name)
 		assert.Nil(t, result.Errors)
@@ -279,6 +381,108 @@ func TestResolverIntegration(t *testing.T) {
 		assert.Equal(t, "User not found", result.Errors[0].Message)
 	})
 
+	t.Run("ImportUsers Mutation - Success", func(t *testing.T) {
+		file := strings.NewReader("email,name\njane@example.com,Jane Doe\n")
+		createdAt := time.Now()
+
+		mockUserService.EXPECT().
+			ImportUsers(gomock.Any(), user.ImportUsersRequest{File: file, FileName: "users.csv", ContentType: "text/csv"}).
+			Return(&user.ImportUsersResponse{Job: &user.ImportJobDTO{
+				ID:        "job-123",
+				FileName:  "users.csv",
+				Format:    user.ImportFormatCSV,
+				Status:    user.ImportJobPending,
+				CreatedAt: createdAt,
+			}}, nil)
+
+		result, err := resolver.Mutation().ImportUsers(ctx, graphql.Upload{
+			File:        file,
+			Filename:    " users.csv ",
+			ContentType: "text/csv",
+		})
+
+		assert.NoError(t, err)
+		assert.Nil(t, result.Errors)
+		assert.Equal(t, "job-123", result.Job.ID)
+		assert.Equal(t, model.ImportFormatCSV, result.Job.Format)
+		assert.Equal(t, model.ImportJobStatusPending, result.Job.Status)
+		assert.Equal(t, createdAt, result.Job.CreatedAt)
+	})
+
+	t.Run("ImportUsers Mutation - Forbidden", func(t *testing.T) {
+		mockUserService.EXPECT().
+			ImportUsers(gomock.Any(), gomock.Any()).
+			Return(&user.ImportUsersResponse{
+				Errors: []user.ErrorDTO{{
+					Message: "Forbidden",
+					Code:    "FORBIDDEN",
+				}},
+			}, nil)
+
+		result, err := resolver.Mutation().ImportUsers(ctx, graphql.Upload{File: strings.NewReader(""), Filename: "users.csv"})
+
+		assert.NoError(t, err)
+		assert.Nil(t, result.Job)
+		assert.Len(t, result.Errors, 1)
+		assert.Equal(t, "FORBIDDEN", *result.Errors[0].Code)
+	})
+
+	t.Run("ImportJob Query - Progress And Rows", func(t *testing.T) {
+		job := &user.ImportJobDTO{
+			ID:       "job-123",
+			Status:   user.ImportJobRunning,
+			Progress: 0.5,
+			Created:  1,
+			Rejected: 1,
+		}
+
+		mockUserService.EXPECT().
+			GetImportJob(gomock.Any(), user.GetImportJobRequest{ID: "job-123"}).
+			Return(&user.GetImportJobResponse{Job: job}, nil)
+
+		result, err := resolver.Query().ImportJob(ctx, "job-123")
+
+		assert.NoError(t, err)
+		assert.Equal(t, model.ImportJobStatusRunning, result.Status)
+		assert.Equal(t, 0.5, result.Progress)
+		assert.Equal(t, 2, result.ProcessedRows)
+
+		status := model.ImportRowStatusCreated
+		first := 10
+		mockUserService.EXPECT().
+			GetImportJob(gomock.Any(), user.GetImportJobRequest{ID: "job-123", RowStatus: "CREATED", RowsFirst: 10}).
+			Return(&user.GetImportJobResponse{Job: &user.ImportJobDTO{
+				ID: "job-123",
+				Rows: []user.ImportRowResultDTO{
+					{Line: 2, Email: "jane@example.com", Status: user.ImportRowCreated, UserID: "user-123"},
+				},
+			}}, nil)
+
+		rows, err := resolver.ImportJob().Rows(ctx, result, &status, &first, nil)
+
+		assert.NoError(t, err)
+		assert.Len(t, rows, 1)
+		assert.Equal(t, 2, rows[0].Line)
+		assert.Equal(t, relay.ToGlobalID("User", "user-123"), *rows[0].UserID)
+		assert.Nil(t, rows[0].Error)
+	})
+
+	t.Run("ImportJob Query - Not Found", func(t *testing.T) {
+		mockUserService.EXPECT().
+			GetImportJob(gomock.Any(), user.GetImportJobRequest{ID: "nonexistent"}).
+			Return(&user.GetImportJobResponse{
+				Errors: []user.ErrorDTO{{
+					Message: "Import job not found",
+					Code:    "IMPORT_JOB_NOT_FOUND",
+				}},
+			}, nil)
+
+		result, err := resolver.Query().ImportJob(ctx, "nonexistent")
+
+		assert.Error(t, err)
+		assert.Nil(t, result)
+	})
+
 	t.Run("Input Sanitization", func(t *testing.T) {
 		// Test that whitespace is properly trimmed
 		expectedUser := &user.UserDTO{
@@ -298,7 +502,7 @@ func TestResolverIntegration(t *testing.T) {
 
 		assert.NoError(t, err)
 		assert.NotNil(t, result)
-		assert.Equal(t, expectedUser.ID, result.ID)
+		assert.Equal(t, relay.ToGlobalID("User", expectedUser.ID), result.ID)
 	})
 
 	t.Run("Context Propagation", func(t *testing.T) {
@@ -0,0 +1,22 @@
+package resolver
+
+import (
+	"context"
+
+	"github.com/99designs/gqlgen/graphql"
+)
+
+// isFieldSelected reports whether the client selected the named sub-field of the field being resolved
+// It lets resolvers skip work, such as extra queries, for fields nobody asked for
+func isFieldSelected(ctx context.Context, name string) bool {
+	if graphql.GetFieldContext(ctx) == nil || graphql.GetOperationContext(ctx) == nil {
+		return false
+	}
+
+	for _, field := range graphql.CollectAllFields(ctx) {
+		if field == name {
+			return true
+		}
+	}
+	return false
+}
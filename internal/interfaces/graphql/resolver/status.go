@@ -0,0 +1,62 @@
+package resolver
+
+import (
+	"context"
+
+	"github.com/captain-corgi/go-graphql-example/internal/application/user"
+	"github.com/captain-corgi/go-graphql-example/internal/interfaces/graphql/model"
+)
+
+// changeUserStatus moves a user to the target status on behalf of a status mutation
+func (r *Resolver) changeUserStatus(ctx context.Context, operation, id string, status model.UserStatus, reason string) (*model.ChangeUserStatusPayload, error) {
+	// Log operation start
+	r.logOperation(ctx, operation, map[string]interface{}{
+		"id":     id,
+		"status": status,
+	})
+
+	// Validate and sanitize input
+	sanitizedID := localUserID(sanitizeString(id))
+	if err := r.validateInput(ctx, operation, func() error {
+		return validateUserID(sanitizedID)
+	}); err != nil {
+		return &model.ChangeUserStatusPayload{
+			Errors: []*model.Error{mapErrorDTOToGraphQL(user.ErrorDTO{
+				Message: err.Error(),
+				Code:    "VALIDATION_ERROR",
+			})},
+		}, nil
+	}
+
+	// Call application service; it checks the caller is an admin and applies the transition table
+	req := user.ChangeUserStatusRequest{
+		ID:     sanitizedID,
+		Status: string(status),
+		Reason: sanitizeString(reason),
+	}
+	resp, err := r.userService.ChangeUserStatus(ctx, req)
+	if err != nil {
+		return &model.ChangeUserStatusPayload{
+			Errors: []*model.Error{mapErrorDTOToGraphQL(user.ErrorDTO{
+				Message: "Failed to change user status",
+				Code:    "INTERNAL_ERROR",
+			})},
+		}, nil
+	}
+
+	// Handle application-level errors
+	if len(resp.Errors) > 0 {
+		return &model.ChangeUserStatusPayload{
+			Errors: mapErrorDTOsToGraphQL(resp.Errors),
+		}, nil
+	}
+
+	// Map successful result
+	result := &model.ChangeUserStatusPayload{
+		User:       mapUserDTOToGraphQL(resp.User),
+		Transition: mapStatusTransitionDTOToGraphQL(resp.Transition),
+	}
+
+	r.logOperationSuccess(ctx, operation, result)
+	return result, nil
+}
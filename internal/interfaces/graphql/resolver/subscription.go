@@ -0,0 +1,52 @@
+package resolver
+
+import (
+	"context"
+
+	"github.com/captain-corgi/go-graphql-example/internal/application/user"
+	domainErrors "github.com/captain-corgi/go-graphql-example/internal/domain/errors"
+	"github.com/captain-corgi/go-graphql-example/internal/interfaces/graphql/model"
+)
+
+// subscribeUserEvents opens a user event subscription and adapts it to GraphQL users
+func (r *Resolver) subscribeUserEvents(ctx context.Context, operation string, req user.SubscribeUserEventsRequest) (<-chan *model.User, error) {
+	resp, err := r.userService.SubscribeUserEvents(ctx, req)
+	if err != nil {
+		return nil, r.handleGraphQLError(ctx, err, operation)
+	}
+
+	// Handle application-level errors
+	if len(resp.Errors) > 0 {
+		firstError := resp.Errors[0]
+		domainErr := domainErrors.DomainError{
+			Code:    firstError.Code,
+			Message: firstError.Message,
+			Field:   firstError.Field,
+		}
+		return nil, r.handleGraphQLError(ctx, domainErr, operation)
+	}
+
+	r.logOperationSuccess(ctx, operation, resp)
+	return forwardUserEvents(ctx, resp.Events), nil
+}
+
+// forwardUserEvents maps events onto an unbuffered channel read by the transport
+// The output closes when the event stream ends, which completes the subscription.
+// While the client is slow to read, events queue in the broker's per-subscriber
+// buffer rather than here, so the broker's backpressure policy applies.
+func forwardUserEvents(ctx context.Context, events <-chan *user.UserEventDTO) <-chan *model.User {
+	out := make(chan *model.User)
+
+	go func() {
+		defer close(out)
+		for event := range events {
+			select {
+			case out <- mapUserDTOToGraphQL(event.User):
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
+	return out
+}
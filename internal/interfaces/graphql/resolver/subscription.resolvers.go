@@ -0,0 +1,59 @@
+package resolver
+
+// This file will be automatically regenerated based on the schema, any resolver implementations
+// will be copied through when generating and any unknown code will be moved to the end.
+// Code generated by github.com/99designs/gqlgen version v0.17.78
+
+import (
+	"context"
+
+	"github.com/captain-corgi/go-graphql-example/internal/application/user"
+	"github.com/captain-corgi/go-graphql-example/internal/interfaces/graphql/generated"
+	"github.com/captain-corgi/go-graphql-example/internal/interfaces/graphql/model"
+)
+
+// UserCreated is the resolver for the userCreated field.
+func (r *subscriptionResolver) UserCreated(ctx context.Context) (<-chan *model.User, error) {
+	// Log operation start
+	r.logOperation(ctx, "UserCreated", nil)
+
+	return r.subscribeUserEvents(ctx, "UserCreated", user.SubscribeUserEventsRequest{
+		Type: user.EventUserCreated,
+	})
+}
+
+// UserUpdated is the resolver for the userUpdated field.
+func (r *subscriptionResolver) UserUpdated(ctx context.Context, id string) (<-chan *model.User, error) {
+	// Log operation start
+	r.logOperation(ctx, "UserUpdated", map[string]interface{}{
+		"id": id,
+	})
+
+	// Validate and sanitize input
+	sanitizedID := localUserID(sanitizeString(id))
+	if err := r.validateInput(ctx, "UserUpdated", func() error {
+		return validateUserID(sanitizedID)
+	}); err != nil {
+		return nil, err
+	}
+
+	return r.subscribeUserEvents(ctx, "UserUpdated", user.SubscribeUserEventsRequest{
+		Type:   user.EventUserUpdated,
+		UserID: sanitizedID,
+	})
+}
+
+// UserDeleted is the resolver for the userDeleted field.
+func (r *subscriptionResolver) UserDeleted(ctx context.Context) (<-chan *model.User, error) {
+	// Log operation start
+	r.logOperation(ctx, "UserDeleted", nil)
+
+	return r.subscribeUserEvents(ctx, "UserDeleted", user.SubscribeUserEventsRequest{
+		Type: user.EventUserDeleted,
+	})
+}
+
+// Subscription returns generated.SubscriptionResolver implementation.
+func (r *Resolver) Subscription() generated.SubscriptionResolver { return &subscriptionResolver{r} }
+
+type subscriptionResolver struct{ *Resolver }
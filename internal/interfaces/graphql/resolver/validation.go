@@ -15,6 +15,18 @@ func validateUserID(id string) error {
 	return nil
 }
 
+// validateNodeIDs validates the ids of a nodes query
+func validateNodeIDs(ids []string) error {
+	if len(ids) > maxNodeIDs {
+		return errors.DomainError{
+			Code:    "TOO_MANY_IDS",
+			Message: "Cannot fetch more than 100 nodes at once",
+			Field:   "ids",
+		}
+	}
+	return nil
+}
+
 // validateCreateUserInput validates CreateUserInput
 func validateCreateUserInput(input model.CreateUserInput) error {
 	// Validate email
@@ -60,11 +72,20 @@ func validateUpdateUserInput(input model.UpdateUserInput) error {
 		return errors.ErrInvalidName
 	}
 
+	// Versions start at 1, so anything lower can never match
+	if input.ExpectedVersion != nil && *input.ExpectedVersion < 1 {
+		return errors.DomainError{
+			Code:    "INVALID_VERSION",
+			Message: "Expected version must be at least 1",
+			Field:   "expectedVersion",
+		}
+	}
+
 	return nil
 }
 
 // validatePaginationParams validates pagination parameters
-func validatePaginationParams(first *int, after *string) error {
+func validatePaginationParams(first *int, after *string, last *int, before *string) error {
 	if first != nil {
 		if *first < 0 {
 			return errors.DomainError{
@@ -82,6 +103,28 @@ func validatePaginationParams(first *int, after *string) error {
 		}
 	}
 
+	if last != nil {
+		if *last < 0 {
+			return errors.DomainError{
+				Code:    "INVALID_LAST",
+				Message: "Last parameter must be non-negative",
+				Field:   "last",
+			}
+		}
+		if *last > 100 {
+			return errors.DomainError{
+				Code:    "INVALID_LAST",
+				Message: "Last parameter cannot exceed 100",
+				Field:   "last",
+			}
+		}
+	}
+
+	// Relay discourages mixing both directions in a single request
+	if first != nil && last != nil {
+		return errors.ErrInvalidPagination
+	}
+
 	// After parameter validation (cursor should be non-empty if provided)
 	if after != nil && strings.TrimSpace(*after) == "" {
 		return errors.DomainError{
@@ -91,6 +134,14 @@ func validatePaginationParams(first *int, after *string) error {
 		}
 	}
 
+	if before != nil && strings.TrimSpace(*before) == "" {
+		return errors.DomainError{
+			Code:    "INVALID_CURSOR",
+			Message: "Cursor cannot be empty",
+			Field:   "before",
+		}
+	}
+
 	return nil
 }
 
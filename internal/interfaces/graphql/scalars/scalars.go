@@ -0,0 +1,111 @@
+package scalars
+
+import (
+	"fmt"
+	"io"
+	"net/mail"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql"
+	"github.com/google/uuid"
+)
+
+// DateFormat is the wire format of the Date scalar (RFC 3339 full-date)
+const DateFormat = "2006-01-02"
+
+// MarshalDateTime serializes a time.Time as an RFC 3339 string with nanosecond precision in UTC
+func MarshalDateTime(t time.Time) graphql.Marshaler {
+	if t.IsZero() {
+		return graphql.Null
+	}
+
+	return graphql.WriterFunc(func(w io.Writer) {
+		_, _ = io.WriteString(w, strconv.Quote(t.UTC().Format(time.RFC3339Nano)))
+	})
+}
+
+// UnmarshalDateTime parses an RFC 3339 string into a time.Time
+// An explicit offset or Z designator is required so the instant is unambiguous
+func UnmarshalDateTime(v interface{}) (time.Time, error) {
+	s, ok := v.(string)
+	if !ok {
+		return time.Time{}, fmt.Errorf("expected an RFC 3339 DateTime string, got %T", v)
+	}
+
+	t, err := time.Parse(time.RFC3339Nano, strings.TrimSpace(s))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid DateTime %q: expected RFC 3339 with a timezone offset", s)
+	}
+
+	return t, nil
+}
+
+// MarshalDate serializes a time.Time as a calendar date (YYYY-MM-DD)
+func MarshalDate(t time.Time) graphql.Marshaler {
+	if t.IsZero() {
+		return graphql.Null
+	}
+
+	return graphql.WriterFunc(func(w io.Writer) {
+		_, _ = io.WriteString(w, strconv.Quote(t.Format(DateFormat)))
+	})
+}
+
+// UnmarshalDate parses a YYYY-MM-DD string into a time.Time at midnight UTC
+func UnmarshalDate(v interface{}) (time.Time, error) {
+	s, ok := v.(string)
+	if !ok {
+		return time.Time{}, fmt.Errorf("expected a YYYY-MM-DD Date string, got %T", v)
+	}
+
+	t, err := time.ParseInLocation(DateFormat, strings.TrimSpace(s), time.UTC)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid Date %q: expected YYYY-MM-DD", s)
+	}
+
+	return t, nil
+}
+
+// MarshalUUID serializes a UUID string
+func MarshalUUID(id string) graphql.Marshaler {
+	return graphql.MarshalString(id)
+}
+
+// UnmarshalUUID validates a UUID literal and returns it in canonical lowercase form
+func UnmarshalUUID(v interface{}) (string, error) {
+	s, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("expected a UUID string, got %T", v)
+	}
+
+	id, err := uuid.Parse(strings.TrimSpace(s))
+	if err != nil {
+		return "", fmt.Errorf("invalid UUID %q", s)
+	}
+
+	return id.String(), nil
+}
+
+// MarshalEmailAddress serializes an email address string
+func MarshalEmailAddress(email string) graphql.Marshaler {
+	return graphql.MarshalString(email)
+}
+
+// UnmarshalEmailAddress validates an email address literal and returns it trimmed and lowercased
+// Display names ("Jane <jane@example.com>") are rejected; only a bare addr-spec is accepted
+func UnmarshalEmailAddress(v interface{}) (string, error) {
+	s, ok := v.(string)
+	if !ok {
+		return "", fmt.Errorf("expected an email address string, got %T", v)
+	}
+
+	email := strings.ToLower(strings.TrimSpace(s))
+	addr, err := mail.ParseAddress(email)
+	if err != nil || addr.Address != email || !strings.Contains(email[strings.LastIndex(email, "@")+1:], ".") {
+		return "", fmt.Errorf("invalid email address %q", s)
+	}
+
+	return email, nil
+}
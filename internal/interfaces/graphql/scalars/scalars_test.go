@@ -0,0 +1,190 @@
+package scalars
+
+import (
+	"bytes"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDateTime(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   interface{}
+		want    time.Time
+		wantErr bool
+	}{
+		{
+			name:  "UTC with nanoseconds",
+			input: "2025-01-16T09:30:00.123456789Z",
+			want:  time.Date(2025, 1, 16, 9, 30, 0, 123456789, time.UTC),
+		},
+		{
+			name:  "explicit offset",
+			input: "2025-01-16T11:30:00+02:00",
+			want:  time.Date(2025, 1, 16, 9, 30, 0, 0, time.UTC),
+		},
+		{
+			name:    "missing timezone",
+			input:   "2025-01-16T09:30:00",
+			wantErr: true,
+		},
+		{
+			name:    "date only",
+			input:   "2025-01-16",
+			wantErr: true,
+		},
+		{
+			name:    "non-string input",
+			input:   1737019800,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := UnmarshalDateTime(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.True(t, tt.want.Equal(got), "expected %v, got %v", tt.want, got)
+		})
+	}
+
+	t.Run("marshals in UTC with nanoseconds", func(t *testing.T) {
+		loc := time.FixedZone("UTC+2", 2*60*60)
+		var buf bytes.Buffer
+		MarshalDateTime(time.Date(2025, 1, 16, 11, 30, 0, 5, loc)).MarshalGQL(&buf)
+		assert.Equal(t, `"2025-01-16T09:30:00.000000005Z"`, buf.String())
+	})
+
+	t.Run("marshals zero time as null", func(t *testing.T) {
+		var buf bytes.Buffer
+		MarshalDateTime(time.Time{}).MarshalGQL(&buf)
+		assert.Equal(t, "null", buf.String())
+	})
+}
+
+func TestDate(t *testing.T) {
+	got, err := UnmarshalDate("2025-01-16")
+	require.NoError(t, err)
+	assert.Equal(t, time.Date(2025, 1, 16, 0, 0, 0, 0, time.UTC), got)
+
+	_, err = UnmarshalDate("2025-13-01")
+	assert.Error(t, err)
+
+	_, err = UnmarshalDate("2025-01-16T00:00:00Z")
+	assert.Error(t, err)
+
+	var buf bytes.Buffer
+	MarshalDate(got).MarshalGQL(&buf)
+	assert.Equal(t, `"2025-01-16"`, buf.String())
+}
+
+func TestUUID(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   interface{}
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "canonical",
+			input: "123e4567-e89b-12d3-a456-426614174000",
+			want:  "123e4567-e89b-12d3-a456-426614174000",
+		},
+		{
+			name:  "uppercase is canonicalized",
+			input: "123E4567-E89B-12D3-A456-426614174000",
+			want:  "123e4567-e89b-12d3-a456-426614174000",
+		},
+		{
+			name:    "empty",
+			input:   "",
+			wantErr: true,
+		},
+		{
+			name:    "not a uuid",
+			input:   "user-123",
+			wantErr: true,
+		},
+		{
+			name:    "non-string input",
+			input:   123,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := UnmarshalUUID(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestEmailAddress(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   interface{}
+		want    string
+		wantErr bool
+	}{
+		{
+			name:  "valid address",
+			input: "jane@example.com",
+			want:  "jane@example.com",
+		},
+		{
+			name:  "trimmed and lowercased",
+			input: "  Jane.Doe@Example.COM ",
+			want:  "jane.doe@example.com",
+		},
+		{
+			name:    "empty",
+			input:   "",
+			wantErr: true,
+		},
+		{
+			name:    "missing at sign",
+			input:   "jane.example.com",
+			wantErr: true,
+		},
+		{
+			name:    "missing top-level domain",
+			input:   "jane@localhost",
+			wantErr: true,
+		},
+		{
+			name:    "display name",
+			input:   "Jane <jane@example.com>",
+			wantErr: true,
+		},
+		{
+			name:    "non-string input",
+			input:   true,
+			wantErr: true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := UnmarshalEmailAddress(tt.input)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
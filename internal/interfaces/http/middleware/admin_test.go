@@ -0,0 +1,112 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/captain-corgi/go-graphql-example/internal/application/auth"
+)
+
+func TestAdminAuth(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		name          string
+		token         string
+		authorization string
+		actor         string
+		wantAdmin     bool
+		wantSubject   string
+	}{
+		{
+			name:          "matching bearer token",
+			token:         "secret",
+			authorization: "Bearer secret",
+			actor:         " jane.ops ",
+			wantAdmin:     true,
+			wantSubject:   "jane.ops",
+		},
+		{
+			name:          "matching bearer token without actor",
+			token:         "secret",
+			authorization: "Bearer secret",
+			wantAdmin:     false,
+		},
+		{
+			name:          "actor with control characters",
+			token:         "secret",
+			authorization: "Bearer secret",
+			actor:         "jane\x00ops",
+			wantAdmin:     false,
+		},
+		{
+			name:          "actor too long",
+			token:         "secret",
+			authorization: "Bearer secret",
+			actor:         strings.Repeat("a", 101),
+			wantAdmin:     false,
+		},
+		{
+			name:          "wrong token",
+			token:         "secret",
+			authorization: "Bearer guess",
+			actor:         "jane.ops",
+			wantAdmin:     false,
+		},
+		{
+			name:          "missing bearer prefix",
+			token:         "secret",
+			authorization: "secret",
+			wantAdmin:     false,
+		},
+		{
+			name:          "no header",
+			token:         "secret",
+			authorization: "",
+			wantAdmin:     false,
+		},
+		{
+			name:          "admin access disabled",
+			token:         "",
+			authorization: "Bearer ",
+			wantAdmin:     false,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := gin.New()
+			router.Use(AdminAuth(tt.token))
+
+			var isAdmin bool
+			var subject string
+			router.GET("/test", func(c *gin.Context) {
+				isAdmin = auth.IsAdmin(c.Request.Context())
+				if principal, ok := auth.FromContext(c.Request.Context()); ok {
+					subject = principal.Subject
+				}
+				c.Status(http.StatusOK)
+			})
+
+			req := httptest.NewRequest(http.MethodGet, "/test", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			if tt.actor != "" {
+				req.Header.Set(AdminActorHeader, tt.actor)
+			}
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, req)
+
+			// Requests are never rejected, only left anonymous
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.wantAdmin, isAdmin)
+			assert.Equal(t, tt.wantSubject, subject)
+		})
+	}
+}
@@ -0,0 +1,19 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/captain-corgi/go-graphql-example/internal/interfaces/graphql/dataloader"
+)
+
+// Dataloaders middleware installs a fresh set of dataloaders in each request context
+// so lookups made while resolving one request are batched and deduplicated,
+// and nothing is cached across requests
+func Dataloaders(newLoaders func() *dataloader.Loaders) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ctx := dataloader.WithLoaders(c.Request.Context(), newLoaders())
+		c.Request = c.Request.WithContext(ctx)
+
+		c.Next()
+	}
+}
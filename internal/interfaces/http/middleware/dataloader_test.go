@@ -0,0 +1,45 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/captain-corgi/go-graphql-example/internal/interfaces/graphql/dataloader"
+)
+
+func TestDataloaders(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	created := 0
+	newLoaders := func() *dataloader.Loaders {
+		created++
+		return &dataloader.Loaders{}
+	}
+
+	router := gin.New()
+	router.Use(Dataloaders(newLoaders))
+
+	var captured []*dataloader.Loaders
+	router.GET("/test", func(c *gin.Context) {
+		captured = append(captured, dataloader.For(c.Request.Context()))
+		c.Status(http.StatusOK)
+	})
+
+	for i := 0; i < 2; i++ {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/test", nil))
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+
+	// Each request gets its own loaders so nothing is cached across requests
+	require.Len(t, captured, 2)
+	assert.NotNil(t, captured[0])
+	assert.NotNil(t, captured[1])
+	assert.NotSame(t, captured[0], captured[1])
+	assert.Equal(t, 2, created)
+}
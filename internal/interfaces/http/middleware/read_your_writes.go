@@ -0,0 +1,16 @@
+package middleware
+
+import (
+	"github.com/gin-gonic/gin"
+
+	"github.com/captain-corgi/go-graphql-example/internal/infrastructure/database"
+)
+
+// ReadYourWrites middleware scopes primary pinning to the request: once the
+// request commits a write, its remaining reads skip the replicas and see it
+func ReadYourWrites() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.Request = c.Request.WithContext(database.WithReadYourWrites(c.Request.Context()))
+		c.Next()
+	}
+}
@@ -0,0 +1,35 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+
+	"github.com/captain-corgi/go-graphql-example/internal/infrastructure/database"
+)
+
+func TestReadYourWrites(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	router := gin.New()
+	router.Use(ReadYourWrites())
+
+	var before, after bool
+	router.GET("/test", func(c *gin.Context) {
+		ctx := c.Request.Context()
+		before = database.PinnedToPrimary(ctx)
+		database.PinPrimary(ctx)
+		after = database.PinnedToPrimary(ctx)
+	})
+
+	for i := 0; i < 2; i++ {
+		router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/test", nil))
+
+		// Every request starts unpinned, even after an earlier one pinned itself
+		assert.False(t, before)
+		assert.True(t, after)
+	}
+}
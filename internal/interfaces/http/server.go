@@ -8,8 +8,12 @@ import (
 	"time"
 
 	"github.com/99designs/gqlgen/graphql/handler"
+	"github.com/99designs/gqlgen/graphql/handler/extension"
+	"github.com/99designs/gqlgen/graphql/handler/lru"
+	"github.com/99designs/gqlgen/graphql/handler/transport"
 	"github.com/99designs/gqlgen/graphql/playground"
 	"github.com/gin-gonic/gin"
+	"github.com/vektah/gqlparser/v2/ast"
 
 	"github.com/captain-corgi/go-graphql-example/internal/infrastructure/config"
 	"github.com/captain-corgi/go-graphql-example/internal/interfaces/graphql/generated"
@@ -24,10 +28,15 @@ type Server struct {
 	config   *config.ServerConfig
 	logger   *slog.Logger
 	resolver *resolver.Resolver
+
+	// initHooks authenticate and prepare subscription connections
+	initHooks []ConnectionInitHook
 }
 
 // NewServer creates a new HTTP server with the given configuration and dependencies
-func NewServer(cfg *config.ServerConfig, resolver *resolver.Resolver, logger *slog.Logger) *Server {
+// Subscription connections must pass every init hook; when a WebSocket auth token
+// is configured, a bearer token check runs before the given hooks
+func NewServer(cfg *config.ServerConfig, resolver *resolver.Resolver, logger *slog.Logger, initHooks ...ConnectionInitHook) *Server {
 	// Set Gin mode based on environment
 	gin.SetMode(gin.ReleaseMode)
 
@@ -40,12 +49,19 @@ func NewServer(cfg *config.ServerConfig, resolver *resolver.Resolver, logger *sl
 	router.Use(middleware.RequestID())
 	router.Use(middleware.Logger(logger))
 	router.Use(middleware.CORS())
+	router.Use(middleware.AdminAuth(cfg.AdminToken))
+	router.Use(middleware.ReadYourWrites())
+
+	if cfg.WebSocket.AuthToken != "" {
+		initHooks = append([]ConnectionInitHook{BearerTokenAuth(cfg.WebSocket.AuthToken)}, initHooks...)
+	}
 
 	server := &Server{
-		router:   router,
-		config:   cfg,
-		logger:   logger,
-		resolver: resolver,
+		router:    router,
+		config:    cfg,
+		logger:    logger,
+		resolver:  resolver,
+		initHooks: initHooks,
 	}
 
 	server.setupRoutes()
@@ -61,7 +77,11 @@ func (s *Server) setupRoutes() {
 
 	// GraphQL handler
 	graphqlHandler := s.createGraphQLHandler()
-	s.router.POST("/query", gin.WrapH(graphqlHandler))
+	s.router.POST("/query", middleware.Dataloaders(s.resolver.NewLoaders), gin.WrapH(graphqlHandler))
+
+	// Subscriptions upgrade GET requests to WebSocket; loaders are not installed
+	// because they would cache users for the lifetime of the connection
+	s.router.GET("/query", gin.WrapH(graphqlHandler))
 
 	// GraphQL Playground (only in development)
 	playgroundHandler := playground.Handler("GraphQL Playground", "/query")
@@ -77,7 +97,20 @@ func (s *Server) createGraphQLHandler() *handler.Server {
 	})
 
 	// Create the GraphQL handler
-	srv := handler.NewDefaultServer(schema)
+	srv := handler.New(schema)
+
+	srv.AddTransport(s.websocketTransport())
+	srv.AddTransport(transport.Options{})
+	srv.AddTransport(transport.GET{})
+	srv.AddTransport(transport.POST{})
+	srv.AddTransport(transport.MultipartForm{})
+
+	srv.SetQueryCache(lru.New[*ast.QueryDocument](1000))
+
+	srv.Use(extension.Introspection{})
+	srv.Use(extension.AutomaticPersistedQuery{
+		Cache: lru.New[string](100),
+	})
 
 	// Add custom error handling if needed
 	// srv.SetErrorPresenter(func(ctx context.Context, e error) *gqlerror.Error {
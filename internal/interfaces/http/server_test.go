@@ -1,10 +1,12 @@
 package http
 
 import (
+	"context"
 	"log/slog"
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 	"time"
 
@@ -12,8 +14,15 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 
+	"github.com/captain-corgi/go-graphql-example/internal/application/auth"
+	"github.com/captain-corgi/go-graphql-example/internal/application/unitofwork"
+	"github.com/captain-corgi/go-graphql-example/internal/application/user"
 	"github.com/captain-corgi/go-graphql-example/internal/application/user/mocks"
+	domainEvents "github.com/captain-corgi/go-graphql-example/internal/domain/events"
+	domainUser "github.com/captain-corgi/go-graphql-example/internal/domain/user"
+	domainMocks "github.com/captain-corgi/go-graphql-example/internal/domain/user/mocks"
 	"github.com/captain-corgi/go-graphql-example/internal/infrastructure/config"
+	"github.com/captain-corgi/go-graphql-example/internal/infrastructure/pubsub"
 	"github.com/captain-corgi/go-graphql-example/internal/interfaces/graphql/resolver"
 )
 
@@ -180,3 +189,129 @@ func TestServerConfiguration(t *testing.T) {
 	assert.Equal(t, 60*time.Second, server.server.WriteTimeout)
 	assert.Equal(t, 180*time.Second, server.server.IdleTimeout)
 }
+
+func TestServerBatchesUserLookups(t *testing.T) {
+	cfg := &config.ServerConfig{
+		Port:         "8080",
+		ReadTimeout:  30 * time.Second,
+		WriteTimeout: 30 * time.Second,
+		IdleTimeout:  120 * time.Second,
+	}
+
+	ctrl := gomock.NewController(t)
+	mockUserService := mocks.NewMockService(ctrl)
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	const (
+		aliceID = "123e4567-e89b-12d3-a456-426614174001"
+		bobID   = "123e4567-e89b-12d3-a456-426614174002"
+	)
+
+	// Three aliased lookups of two distinct users resolve in a single batched call
+	mockUserService.EXPECT().
+		GetUsersByIDs(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(_ context.Context, req user.GetUsersByIDsRequest) (*user.GetUsersByIDsResponse, error) {
+			assert.ElementsMatch(t, []string{aliceID, bobID}, req.IDs)
+			return &user.GetUsersByIDsResponse{Users: []*user.UserDTO{
+				{ID: aliceID, Email: "alice@example.com", Name: "Alice", CreatedAt: time.Now(), UpdatedAt: time.Now()},
+				{ID: bobID, Email: "bob@example.com", Name: "Bob", CreatedAt: time.Now(), UpdatedAt: time.Now()},
+			}}, nil
+		}).
+		Times(1)
+
+	server := NewServer(cfg, resolver.NewResolver(mockUserService, logger), logger)
+
+	body := `{"query":"{ a: user(id: \"` + aliceID + `\") { name } b: user(id: \"` + bobID + `\") { name } c: user(id: \"` + aliceID + `\") { name } }"}`
+	req := httptest.NewRequest(http.MethodPost, "/query", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	server.router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"data":{"a":{"name":"Alice"},"b":{"name":"Bob"},"c":{"name":"Alice"}}}`, w.Body.String())
+}
+
+func TestServerGrantsAdminWithToken(t *testing.T) {
+	cfg := &config.ServerConfig{
+		Port:         "8080",
+		ReadTimeout:  30 * time.Second,
+		WriteTimeout: 30 * time.Second,
+		IdleTimeout:  120 * time.Second,
+		AdminToken:   "admin-secret",
+	}
+
+	ctrl := gomock.NewController(t)
+	mockUserService := mocks.NewMockService(ctrl)
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	// The service sees an admin principal only when the admin token was sent
+	var admins []bool
+	mockUserService.EXPECT().
+		PurgeUser(gomock.Any(), gomock.Any()).
+		DoAndReturn(func(ctx context.Context, _ user.PurgeUserRequest) (*user.PurgeUserResponse, error) {
+			admins = append(admins, auth.IsAdmin(ctx))
+			return &user.PurgeUserResponse{Success: true}, nil
+		}).
+		Times(3)
+
+	server := NewServer(cfg, resolver.NewResolver(mockUserService, logger), logger)
+
+	body := `{"query":"mutation { purgeUser(id: \"123e4567-e89b-12d3-a456-426614174000\") { success } }"}`
+	for _, authorization := range []string{"Bearer admin-secret", "Bearer wrong", ""} {
+		req := httptest.NewRequest(http.MethodPost, "/query", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		req.Header.Set("X-Admin-Actor", "jane.ops")
+		w := httptest.NewRecorder()
+
+		server.router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusOK, w.Code)
+	}
+
+	assert.Equal(t, []bool{true, false, false}, admins)
+}
+
+func TestServerRecordsAdminActorOnStatusChange(t *testing.T) {
+	cfg := &config.ServerConfig{
+		Port:         "8080",
+		ReadTimeout:  30 * time.Second,
+		WriteTimeout: 30 * time.Second,
+		IdleTimeout:  120 * time.Second,
+		AdminToken:   "admin-secret",
+	}
+
+	ctrl := gomock.NewController(t)
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	u, err := domainUser.NewUser("alice@example.com", "Alice")
+	require.NoError(t, err)
+
+	// The actor named in X-Admin-Actor is recorded on the transition the repository saves
+	repo := domainMocks.NewMockRepository(ctrl)
+	repo.EXPECT().FindByID(gomock.Any(), u.ID()).Return(u, nil)
+	repo.EXPECT().
+		UpdateStatus(gomock.Any(), u, gomock.Any()).
+		DoAndReturn(func(_ context.Context, _ *domainUser.User, transition domainUser.StatusTransition) error {
+			assert.Equal(t, "jane.ops", transition.Actor)
+			return nil
+		})
+
+	service := user.NewService(repo, nil, nil, user.NewCursorCodec([]byte("test-cursor-secret")),
+		pubsub.NewBroker[*user.UserEventDTO](1, logger), domainEvents.NewDomainEventDispatcher(logger), unitofwork.Passthrough{}, logger)
+	server := NewServer(cfg, resolver.NewResolver(service, logger), logger)
+
+	body := `{"query":"mutation { suspendUser(id: \"` + u.ID().String() + `\", reason: \"Chargeback\") { transition { actor } errors { code } } }"}`
+	req := httptest.NewRequest(http.MethodPost, "/query", strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer admin-secret")
+	req.Header.Set("X-Admin-Actor", "jane.ops")
+	w := httptest.NewRecorder()
+
+	server.router.ServeHTTP(w, req)
+
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.JSONEq(t, `{"data":{"suspendUser":{"transition":{"actor":"jane.ops"},"errors":null}}}`, w.Body.String())
+}
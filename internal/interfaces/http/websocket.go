@@ -0,0 +1,71 @@
+package http
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/gorilla/websocket"
+)
+
+// ErrUnauthorized is returned by connection init hooks that reject a client
+var ErrUnauthorized = errors.New("unauthorized")
+
+// ConnectionInitHook inspects the connection_init payload of a subscription connection.
+// It may return an enriched context, or an error to reject the connection.
+type ConnectionInitHook func(ctx context.Context, payload transport.InitPayload) (context.Context, error)
+
+// BearerTokenAuth rejects connections whose init payload does not carry
+// {"Authorization": "Bearer <token>"}
+func BearerTokenAuth(token string) ConnectionInitHook {
+	return func(ctx context.Context, payload transport.InitPayload) (context.Context, error) {
+		presented, ok := strings.CutPrefix(payload.Authorization(), "Bearer ")
+		if !ok || subtle.ConstantTimeCompare([]byte(presented), []byte(token)) != 1 {
+			return ctx, ErrUnauthorized
+		}
+		return ctx, nil
+	}
+}
+
+// websocketTransport creates the graphql-ws/graphql-transport-ws transport for subscriptions
+func (s *Server) websocketTransport() transport.Websocket {
+	cfg := s.config.WebSocket
+
+	return transport.Websocket{
+		Upgrader: websocket.Upgrader{
+			// Origins are not restricted, matching the CORS policy
+			CheckOrigin: func(r *http.Request) bool { return true },
+		},
+		InitFunc:    s.initWebSocket,
+		InitTimeout: cfg.InitTimeout,
+		// graphql-ws clients receive "ka" messages; graphql-transport-ws clients
+		// are pinged and dropped if they stop answering
+		KeepAlivePingInterval: cfg.KeepAliveInterval,
+		PingPongInterval:      cfg.KeepAliveInterval,
+		ErrorFunc: func(ctx context.Context, err error) {
+			s.logger.WarnContext(ctx, "WebSocket error", slog.String("error", err.Error()))
+		},
+		CloseFunc: func(ctx context.Context, closeCode int) {
+			s.logger.DebugContext(ctx, "WebSocket closed", slog.Int("close_code", closeCode))
+		},
+	}
+}
+
+// initWebSocket runs the connection init hooks in order, stopping at the first rejection
+func (s *Server) initWebSocket(ctx context.Context, payload transport.InitPayload) (context.Context, *transport.InitPayload, error) {
+	for _, hook := range s.initHooks {
+		var err error
+		ctx, err = hook(ctx, payload)
+		if err != nil {
+			s.logger.WarnContext(ctx, "WebSocket connection rejected", slog.String("error", err.Error()))
+			return ctx, nil, err
+		}
+	}
+
+	s.logger.InfoContext(ctx, "WebSocket connection initialized")
+	return ctx, nil, nil
+}
@@ -0,0 +1,177 @@
+package http
+
+import (
+	"context"
+	"log/slog"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/99designs/gqlgen/graphql/handler/transport"
+	"github.com/golang/mock/gomock"
+	"github.com/gorilla/websocket"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/captain-corgi/go-graphql-example/internal/application/user"
+	"github.com/captain-corgi/go-graphql-example/internal/application/user/mocks"
+	"github.com/captain-corgi/go-graphql-example/internal/infrastructure/config"
+	"github.com/captain-corgi/go-graphql-example/internal/interfaces/graphql/relay"
+	"github.com/captain-corgi/go-graphql-example/internal/interfaces/graphql/resolver"
+)
+
+// wsMessage is a graphql-transport-ws protocol message
+type wsMessage struct {
+	ID      string                 `json:"id,omitempty"`
+	Type    string                 `json:"type"`
+	Payload map[string]interface{} `json:"payload,omitempty"`
+}
+
+// newWebSocketTestServer starts a server for the given config and service
+func newWebSocketTestServer(t *testing.T, wsCfg config.WebSocketConfig, service user.Service) *httptest.Server {
+	t.Helper()
+
+	cfg := &config.ServerConfig{
+		Port:         "8080",
+		ReadTimeout:  30 * time.Second,
+		WriteTimeout: 30 * time.Second,
+		IdleTimeout:  120 * time.Second,
+		WebSocket:    wsCfg,
+	}
+	logger := slog.New(slog.NewTextHandler(os.Stdout, nil))
+
+	server := NewServer(cfg, resolver.NewResolver(service, logger), logger)
+	ts := httptest.NewServer(server.router)
+	t.Cleanup(ts.Close)
+
+	return ts
+}
+
+// dialGraphQL opens a graphql-transport-ws connection and sends connection_init
+func dialGraphQL(t *testing.T, ts *httptest.Server, initPayload map[string]interface{}) *websocket.Conn {
+	t.Helper()
+
+	dialer := websocket.Dialer{Subprotocols: []string{"graphql-transport-ws"}}
+	conn, _, err := dialer.Dial("ws"+strings.TrimPrefix(ts.URL, "http")+"/query", nil)
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+
+	require.NoError(t, conn.WriteJSON(wsMessage{Type: "connection_init", Payload: initPayload}))
+	return conn
+}
+
+// readMessage reads the next protocol message
+func readMessage(t *testing.T, conn *websocket.Conn) (wsMessage, error) {
+	t.Helper()
+
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(2*time.Second)))
+	var msg wsMessage
+	err := conn.ReadJSON(&msg)
+	return msg, err
+}
+
+func TestServerSubscriptions(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockUserService := mocks.NewMockService(ctrl)
+
+	events := make(chan *user.UserEventDTO, 1)
+	mockUserService.EXPECT().
+		SubscribeUserEvents(gomock.Any(), user.SubscribeUserEventsRequest{Type: user.EventUserCreated}).
+		Return(&user.SubscribeUserEventsResponse{Events: events}, nil)
+
+	ts := newWebSocketTestServer(t, config.WebSocketConfig{}, mockUserService)
+	conn := dialGraphQL(t, ts, nil)
+
+	msg, err := readMessage(t, conn)
+	require.NoError(t, err)
+	require.Equal(t, "connection_ack", msg.Type)
+
+	require.NoError(t, conn.WriteJSON(wsMessage{
+		ID:      "1",
+		Type:    "subscribe",
+		Payload: map[string]interface{}{"query": "subscription { userCreated { id name } }"},
+	}))
+
+	events <- &user.UserEventDTO{
+		Type:       user.EventUserCreated,
+		User:       &user.UserDTO{ID: "123e4567-e89b-12d3-a456-426614174000", Name: "Alice", CreatedAt: time.Now(), UpdatedAt: time.Now()},
+		OccurredAt: time.Now(),
+	}
+
+	msg, err = readMessage(t, conn)
+	require.NoError(t, err)
+	assert.Equal(t, "next", msg.Type)
+	assert.Equal(t, "1", msg.ID)
+	assert.Equal(t, map[string]interface{}{
+		"userCreated": map[string]interface{}{
+			"id":   relay.ToGlobalID("User", "123e4567-e89b-12d3-a456-426614174000"),
+			"name": "Alice",
+		},
+	}, msg.Payload["data"])
+
+	// Closing the event stream completes the subscription
+	close(events)
+
+	msg, err = readMessage(t, conn)
+	require.NoError(t, err)
+	assert.Equal(t, "complete", msg.Type)
+	assert.Equal(t, "1", msg.ID)
+}
+
+func TestServerSubscriptionAuth(t *testing.T) {
+	ctrl := gomock.NewController(t)
+	mockUserService := mocks.NewMockService(ctrl)
+
+	ts := newWebSocketTestServer(t, config.WebSocketConfig{AuthToken: "s3cret"}, mockUserService)
+
+	t.Run("accepts the configured bearer token", func(t *testing.T) {
+		conn := dialGraphQL(t, ts, map[string]interface{}{"Authorization": "Bearer s3cret"})
+
+		msg, err := readMessage(t, conn)
+		require.NoError(t, err)
+		assert.Equal(t, "connection_ack", msg.Type)
+	})
+
+	for name, payload := range map[string]map[string]interface{}{
+		"rejects a missing token": nil,
+		"rejects a wrong token":   {"Authorization": "Bearer guess"},
+	} {
+		t.Run(name, func(t *testing.T) {
+			conn := dialGraphQL(t, ts, payload)
+
+			msg, err := readMessage(t, conn)
+			if err == nil {
+				assert.NotEqual(t, "connection_ack", msg.Type)
+			}
+		})
+	}
+}
+
+func TestBearerTokenAuth(t *testing.T) {
+	hook := BearerTokenAuth("s3cret")
+
+	tests := []struct {
+		name    string
+		payload transport.InitPayload
+		wantErr bool
+	}{
+		{name: "matching token", payload: transport.InitPayload{"Authorization": "Bearer s3cret"}},
+		{name: "lowercase key", payload: transport.InitPayload{"authorization": "Bearer s3cret"}},
+		{name: "missing scheme", payload: transport.InitPayload{"Authorization": "s3cret"}, wantErr: true},
+		{name: "wrong token", payload: transport.InitPayload{"Authorization": "Bearer nope"}, wantErr: true},
+		{name: "no payload", payload: nil, wantErr: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := hook(context.Background(), tt.payload)
+			if tt.wantErr {
+				assert.ErrorIs(t, err, ErrUnauthorized)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
@@ -0,0 +1,9 @@
+-- Restore the single-column timestamp indexes
+CREATE INDEX IF NOT EXISTS idx_users_created_at ON users(created_at);
+CREATE INDEX IF NOT EXISTS idx_users_updated_at ON users(updated_at);
+
+-- Drop the keyset pagination indexes
+DROP INDEX IF EXISTS idx_users_updated_at_id;
+DROP INDEX IF EXISTS idx_users_created_at_id;
+DROP INDEX IF EXISTS idx_users_email_id;
+DROP INDEX IF EXISTS idx_users_name_id;
@@ -0,0 +1,12 @@
+-- Composite indexes backing keyset pagination for every users sort order.
+-- Each pairs the sort column with id, the tie-breaker, so (column, id) row
+-- comparisons and ORDER BY column, id can be answered from the index in
+-- either direction.
+CREATE INDEX idx_users_name_id ON users(name, id);
+CREATE INDEX idx_users_email_id ON users(email, id);
+CREATE INDEX idx_users_created_at_id ON users(created_at, id);
+CREATE INDEX idx_users_updated_at_id ON users(updated_at, id);
+
+-- The single-column timestamp indexes are prefixes of the composite ones
+DROP INDEX IF EXISTS idx_users_created_at;
+DROP INDEX IF EXISTS idx_users_updated_at;
@@ -0,0 +1,8 @@
+-- Soft-deleted rows may share emails with live users and would violate the
+-- table-wide constraint, so they are purged before it is restored
+DELETE FROM users WHERE deleted_at IS NOT NULL;
+
+DROP INDEX IF EXISTS users_email_live_key;
+ALTER TABLE users ADD CONSTRAINT users_email_key UNIQUE (email);
+
+ALTER TABLE users DROP COLUMN deleted_at;
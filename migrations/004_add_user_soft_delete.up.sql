@@ -0,0 +1,7 @@
+-- Soft delete: a deleted user keeps its row with deleted_at set until purged
+ALTER TABLE users ADD COLUMN deleted_at TIMESTAMP WITH TIME ZONE;
+
+-- Emails only need to be unique among live users, so a deleted user's email
+-- can be reused. The partial unique index replaces the table-wide constraint.
+ALTER TABLE users DROP CONSTRAINT users_email_key;
+CREATE UNIQUE INDEX users_email_live_key ON users(email) WHERE deleted_at IS NULL;
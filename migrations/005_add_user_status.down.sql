@@ -0,0 +1,4 @@
+DROP TABLE IF EXISTS user_status_transitions;
+
+DROP INDEX IF EXISTS idx_users_status;
+ALTER TABLE users DROP COLUMN status;
@@ -0,0 +1,20 @@
+-- Account lifecycle status; existing users are active
+ALTER TABLE users
+    ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'ACTIVE'
+    CONSTRAINT users_status_check CHECK (status IN ('INVITED', 'ACTIVE', 'SUSPENDED', 'DEACTIVATED'));
+
+CREATE INDEX idx_users_status ON users(status);
+
+-- Audit trail of status changes: who made each one, why and when.
+-- History goes with the user when it is purged.
+CREATE TABLE user_status_transitions (
+    id BIGSERIAL PRIMARY KEY,
+    user_id UUID NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    from_status VARCHAR(20) NOT NULL,
+    to_status VARCHAR(20) NOT NULL,
+    actor VARCHAR(255) NOT NULL,
+    reason TEXT NOT NULL,
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX idx_user_status_transitions_user_id ON user_status_transitions(user_id, occurred_at);
@@ -0,0 +1,3 @@
+-- Optimistic concurrency: every write increments the version, and versioned
+-- writes only apply to the version they read
+ALTER TABLE users ADD COLUMN version BIGINT NOT NULL DEFAULT 1;
@@ -0,0 +1,19 @@
+-- Transactional outbox: events are written in the same transaction as the
+-- change that raised them, then relayed to the event publisher
+CREATE TABLE outbox (
+    id BIGSERIAL PRIMARY KEY,
+    event_name VARCHAR(100) NOT NULL,
+    aggregate_id VARCHAR(100) NOT NULL,
+    payload JSONB NOT NULL,
+    occurred_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    attempts INTEGER NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    last_error TEXT,
+    delivered_at TIMESTAMP WITH TIME ZONE,
+    dead_lettered_at TIMESTAMP WITH TIME ZONE
+);
+
+-- The relay only ever scans messages still waiting for delivery
+CREATE INDEX idx_outbox_pending ON outbox(next_attempt_at, id)
+    WHERE delivered_at IS NULL AND dead_lettered_at IS NULL;
@@ -0,0 +1,8 @@
+-- Drop the search indexes and column
+DROP INDEX IF EXISTS idx_users_email_trgm;
+DROP INDEX IF EXISTS idx_users_name_trgm;
+DROP INDEX IF EXISTS idx_users_search_vector;
+
+ALTER TABLE users DROP COLUMN IF EXISTS search_vector;
+
+-- pg_trgm is left installed: other objects in the database may use it
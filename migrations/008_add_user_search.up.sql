@@ -0,0 +1,19 @@
+-- Full-text and fuzzy search over user names and emails.
+--
+-- search_vector holds the words of the name (weight A) and of the email split
+-- at its punctuation (weight B), so "doe" finds john.doe@example.com and name
+-- matches rank above email matches. The trigram indexes answer the pg_trgm
+-- word similarity operator (<%), which tolerates typos in either field.
+--
+-- pg_trgm ships with Postgres; creating it needs the CREATE privilege on the
+-- database, or an administrator can create it ahead of the migration.
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+ALTER TABLE users ADD COLUMN search_vector tsvector GENERATED ALWAYS AS (
+    setweight(to_tsvector('simple', name), 'A') ||
+    setweight(to_tsvector('simple', translate(email, '@.+_-', '     ')), 'B')
+) STORED;
+
+CREATE INDEX idx_users_search_vector ON users USING GIN (search_vector);
+CREATE INDEX idx_users_name_trgm ON users USING GIN (name gin_trgm_ops);
+CREATE INDEX idx_users_email_trgm ON users USING GIN (email gin_trgm_ops);
@@ -0,0 +1,2 @@
+-- Intentionally empty: rolling back must not put the development users back into
+-- staging or production. Use the seed command where they are wanted.
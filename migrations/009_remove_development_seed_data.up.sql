@@ -0,0 +1,17 @@
+-- Remove the development users that 002_seed_development_data inserted into every
+-- environment. 002 is kept as it shipped, so databases that already applied it keep a
+-- matching history; sample data now comes from the seed command (cmd/seed), whose
+-- profiles are limited to the environments they suit. In development, run
+-- `seed -profile dev` after migrating to get these users back.
+DELETE FROM users WHERE id IN (
+    '550e8400-e29b-41d4-a716-446655440001',
+    '550e8400-e29b-41d4-a716-446655440002',
+    '550e8400-e29b-41d4-a716-446655440003',
+    '550e8400-e29b-41d4-a716-446655440004',
+    '550e8400-e29b-41d4-a716-446655440005',
+    '550e8400-e29b-41d4-a716-446655440006',
+    '550e8400-e29b-41d4-a716-446655440007',
+    '550e8400-e29b-41d4-a716-446655440008',
+    '550e8400-e29b-41d4-a716-446655440009',
+    '550e8400-e29b-41d4-a716-446655440010'
+);
@@ -0,0 +1,41 @@
+// Package migrations embeds the SQL migrations so every binary carries the schema it expects
+// and can migrate from any working directory
+package migrations
+
+import (
+	"embed"
+	"fmt"
+	"io/fs"
+)
+
+// Postgres holds the Postgres migrations, kept at the top of this directory
+//
+//go:embed *.sql
+var Postgres embed.FS
+
+//go:embed sqlite/*.sql
+var sqliteFiles embed.FS
+
+// SQLite holds the SQLite migrations from the sqlite directory
+var SQLite = mustSub(sqliteFiles, "sqlite")
+
+// For returns the migrations of the given database driver; "" means postgres
+func For(driver string) (fs.FS, error) {
+	switch driver {
+	case "", "postgres":
+		return Postgres, nil
+	case "sqlite":
+		return SQLite, nil
+	default:
+		return nil, fmt.Errorf("no migrations for database driver %s", driver)
+	}
+}
+
+// mustSub returns the subtree of an embedded directory, which always exists
+func mustSub(fsys fs.FS, dir string) fs.FS {
+	sub, err := fs.Sub(fsys, dir)
+	if err != nil {
+		panic(err)
+	}
+	return sub
+}
@@ -0,0 +1,38 @@
+package migrations
+
+import (
+	"io/fs"
+	"testing"
+)
+
+func TestFor(t *testing.T) {
+	tests := []struct {
+		driver  string
+		present string
+		absent  string
+	}{
+		{"", "009_remove_development_seed_data.up.sql", "sqlite"},
+		{"postgres", "007_create_outbox.up.sql", "sqlite"},
+		{"sqlite", "002_create_user_status_transitions.up.sql", "007_create_outbox.up.sql"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.driver, func(t *testing.T) {
+			migrations, err := For(tt.driver)
+			if err != nil {
+				t.Fatalf("For(%q) returned error: %v", tt.driver, err)
+			}
+
+			if _, err := fs.Stat(migrations, tt.present); err != nil {
+				t.Errorf("expected %s among the migrations: %v", tt.present, err)
+			}
+			if _, err := fs.Stat(migrations, tt.absent); err == nil {
+				t.Errorf("expected %s not to be among the migrations", tt.absent)
+			}
+		})
+	}
+
+	if _, err := For("mysql"); err == nil {
+		t.Error("expected an error for a driver without migrations")
+	}
+}
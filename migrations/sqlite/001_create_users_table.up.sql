@@ -0,0 +1,26 @@
+-- Users table for the SQLite backend, matching the Postgres schema after all
+-- of its migrations. SQLite has no timestamp type: times are TEXT in the
+-- fixed-width UTC layout 2006-01-02T15:04:05.000000000Z so they compare and
+-- sort in time order.
+CREATE TABLE users (
+    id TEXT PRIMARY KEY NOT NULL,
+    email TEXT NOT NULL,
+    name TEXT NOT NULL,
+    created_at TEXT NOT NULL,
+    updated_at TEXT NOT NULL,
+    deleted_at TEXT,
+    status TEXT NOT NULL DEFAULT 'ACTIVE'
+        CONSTRAINT users_status_check CHECK (status IN ('INVITED', 'ACTIVE', 'SUSPENDED', 'DEACTIVATED')),
+    version INTEGER NOT NULL DEFAULT 1
+);
+
+-- Emails only need to be unique among live users
+CREATE UNIQUE INDEX users_email_live_key ON users(email) WHERE deleted_at IS NULL;
+
+-- Composite indexes backing keyset pagination for every users sort order
+CREATE INDEX idx_users_name_id ON users(name, id);
+CREATE INDEX idx_users_email_id ON users(email, id);
+CREATE INDEX idx_users_created_at_id ON users(created_at, id);
+CREATE INDEX idx_users_updated_at_id ON users(updated_at, id);
+
+CREATE INDEX idx_users_status ON users(status);
@@ -0,0 +1,14 @@
+-- Audit trail of status changes. Foreign keys are only enforced when the
+-- connection enables them, so the repository also deletes a purged user's
+-- history itself.
+CREATE TABLE user_status_transitions (
+    id INTEGER PRIMARY KEY AUTOINCREMENT,
+    user_id TEXT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    from_status TEXT NOT NULL,
+    to_status TEXT NOT NULL,
+    actor TEXT NOT NULL,
+    reason TEXT NOT NULL,
+    occurred_at TEXT NOT NULL
+);
+
+CREATE INDEX idx_user_status_transitions_user_id ON user_status_transitions(user_id, occurred_at);